@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewAddColumn().Model(new(persistance.CartProduct)).ColumnExpr("price INT NOT NULL DEFAULT 0").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.NewDropColumn().Model(new(persistance.CartProduct)).Column("price").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewCreateTable().Model(new(persistance.Wishlist)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewCreateTable().Model(new(persistance.WishlistProduct)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.NewDropTable().Model(new(persistance.Wishlist)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewDropTable().Model(new(persistance.WishlistProduct)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewAddColumn().Model(new(persistance.Cart)).ColumnExpr("updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewAddColumn().Model(new(persistance.Cart)).ColumnExpr("reminder_sent_at DATETIME NULL").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewAddColumn().Model(new(persistance.Account)).ColumnExpr("marketing_email_opt_out BOOLEAN NOT NULL DEFAULT FALSE").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.NewDropColumn().Model(new(persistance.Cart)).Column("updated_at").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewDropColumn().Model(new(persistance.Cart)).Column("reminder_sent_at").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewDropColumn().Model(new(persistance.Account)).Column("marketing_email_opt_out").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewCreateTable().Model(new(persistance.ProductSearchDocument)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		// 日本語を検索できるようにngramパーサーのFULLTEXTインデックスを作成する
+		_, err = db.ExecContext(ctx, "ALTER TABLE product_search_documents ADD FULLTEXT INDEX product_search_documents_name_idx (name) WITH PARSER ngram")
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, "ALTER TABLE product_search_documents ADD FULLTEXT INDEX product_search_documents_content_idx (content) WITH PARSER ngram")
+		if err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.NewDropTable().Model(new(persistance.ProductSearchDocument)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
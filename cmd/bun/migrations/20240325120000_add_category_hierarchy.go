@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+// 既存のカテゴリーは親カテゴリーを持たない最上位のカテゴリーとし、スラッグにはカテゴリーIDを設定する
+// カテゴリーIDは変更しないため、商品のカテゴリーIDはそのまま有効である
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewAddColumn().Model(new(persistance.Category)).ColumnExpr("parent_id VARCHAR(255) NULL").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewAddColumn().Model(new(persistance.Category)).ColumnExpr("sort_order INT NOT NULL DEFAULT 0").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewAddColumn().Model(new(persistance.Category)).ColumnExpr("slug VARCHAR(255) NULL").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewUpdate().Model(new(persistance.Category)).Set("slug = id").Where("slug IS NULL").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, "ALTER TABLE categories MODIFY slug VARCHAR(255) NOT NULL")
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, "ALTER TABLE categories ADD UNIQUE INDEX categories_slug_idx (slug)")
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, "ALTER TABLE categories ADD INDEX categories_parent_id_idx (parent_id)")
+		if err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.ExecContext(ctx, "ALTER TABLE categories DROP INDEX categories_parent_id_idx")
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, "ALTER TABLE categories DROP INDEX categories_slug_idx")
+		if err != nil {
+			return err
+		}
+		for _, column := range []string{"parent_id", "sort_order", "slug"} {
+			_, err = db.NewDropColumn().Model(new(persistance.Category)).Column(column).Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
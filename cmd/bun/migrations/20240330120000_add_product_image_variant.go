@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewCreateTable().Model(new(persistance.ProductImageVariant)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, "ALTER TABLE product_image_variants ADD INDEX product_image_variants_product_image_id_idx (product_image_id)")
+		if err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.NewDropTable().Model(new(persistance.ProductImageVariant)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
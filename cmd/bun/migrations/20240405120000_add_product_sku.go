@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+// 既存の商品はバリエーションを持たない商品とし、商品自体の在庫数をそのまま使用する
+// 既存のカート商品・あとで買う商品のSKU IDは空文字とし、バリエーションを持たない商品を指す
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		for _, model := range []any{new(persistance.ProductOptionAxis), new(persistance.ProductSKU), new(persistance.ProductSKUOption)} {
+			_, err := db.NewCreateTable().Model(model).IfNotExists().Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		_, err := db.NewAddColumn().Model(new(persistance.ProductImage)).ColumnExpr("sku_id VARCHAR(255) NULL").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewAddColumn().Model(new(persistance.CartProduct)).ColumnExpr("sku_id VARCHAR(255) NOT NULL DEFAULT ''").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewAddColumn().Model(new(persistance.SavedCartProduct)).ColumnExpr("sku_id VARCHAR(255) NOT NULL DEFAULT ''").Exec(ctx)
+		if err != nil {
+			return err
+		}
+		for _, query := range []string{
+			"ALTER TABLE product_option_axes ADD INDEX product_option_axes_product_id_idx (product_id)",
+			"ALTER TABLE product_skus ADD INDEX product_skus_product_id_idx (product_id)",
+			"ALTER TABLE product_sku_options ADD INDEX product_sku_options_sku_id_idx (sku_id)",
+			"ALTER TABLE product_images ADD INDEX product_images_sku_id_idx (sku_id)",
+		} {
+			_, err = db.ExecContext(ctx, query)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.ExecContext(ctx, "ALTER TABLE product_images DROP INDEX product_images_sku_id_idx")
+		if err != nil {
+			return err
+		}
+		for _, model := range []any{new(persistance.ProductImage), new(persistance.CartProduct), new(persistance.SavedCartProduct)} {
+			_, err = db.NewDropColumn().Model(model).Column("sku_id").Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		for _, model := range []any{new(persistance.ProductSKUOption), new(persistance.ProductSKU), new(persistance.ProductOptionAxis)} {
+			_, err = db.NewDropTable().Model(model).IfExists().Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
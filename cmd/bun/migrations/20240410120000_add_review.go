@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewCreateTable().Model(new(persistance.Review)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewCreateTable().Model(new(persistance.ReviewPhoto)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		for _, query := range []string{
+			"ALTER TABLE reviews ADD INDEX reviews_product_id_status_idx (product_id, status, create_date_time)",
+			"ALTER TABLE reviews ADD INDEX reviews_account_id_idx (account_id)",
+			"ALTER TABLE reviews ADD INDEX reviews_status_idx (status, create_date_time)",
+			"ALTER TABLE review_photos ADD INDEX review_photos_review_id_idx (review_id)",
+		} {
+			_, err = db.ExecContext(ctx, query)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.NewDropTable().Model(new(persistance.ReviewPhoto)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewDropTable().Model(new(persistance.Review)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
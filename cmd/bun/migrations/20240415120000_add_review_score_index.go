@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// レビュー点数は商品ごとに1日1行のため、重複して登録されないよう一意制約を追加する
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.ExecContext(ctx, "ALTER TABLE review_scores ADD UNIQUE INDEX review_scores_date_product_id_idx (date, product_id)")
+		if err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.ExecContext(ctx, "ALTER TABLE review_scores DROP INDEX review_scores_date_product_id_idx")
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewCreateTable().Model(new(persistance.ReviewVote)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewCreateTable().Model(new(persistance.ReviewReport)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		for _, query := range []string{
+			"ALTER TABLE reviews ADD COLUMN helpful_count BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE reviews ADD COLUMN unhelpful_count BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE reviews ADD COLUMN report_count BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE reviews ADD INDEX reviews_product_id_status_helpful_count_idx (product_id, status, helpful_count)",
+			"ALTER TABLE reviews ADD INDEX reviews_product_id_status_rating_idx (product_id, status, rating)",
+		} {
+			_, err = db.ExecContext(ctx, query)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		for _, query := range []string{
+			"ALTER TABLE reviews DROP INDEX reviews_product_id_status_rating_idx",
+			"ALTER TABLE reviews DROP INDEX reviews_product_id_status_helpful_count_idx",
+			"ALTER TABLE reviews DROP COLUMN report_count",
+			"ALTER TABLE reviews DROP COLUMN unhelpful_count",
+			"ALTER TABLE reviews DROP COLUMN helpful_count",
+		} {
+			_, err := db.ExecContext(ctx, query)
+			if err != nil {
+				return err
+			}
+		}
+		_, err := db.NewDropTable().Model(new(persistance.ReviewReport)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewDropTable().Model(new(persistance.ReviewVote)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewCreateTable().Model(new(persistance.ProductQuestion)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewCreateTable().Model(new(persistance.ProductAnswer)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		for _, query := range []string{
+			"ALTER TABLE product_questions ADD INDEX product_questions_product_id_status_idx (product_id, status, create_date_time)",
+			"ALTER TABLE product_questions ADD INDEX product_questions_status_idx (status, create_date_time)",
+			"ALTER TABLE product_answers ADD INDEX product_answers_question_id_idx (question_id)",
+		} {
+			_, err = db.ExecContext(ctx, query)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.NewDropTable().Model(new(persistance.ProductAnswer)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewDropTable().Model(new(persistance.ProductQuestion)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
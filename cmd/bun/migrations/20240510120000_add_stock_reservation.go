@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewCreateTable().Model(new(persistance.StockReservation)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewCreateTable().Model(new(persistance.StockReservationItem)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		for _, query := range []string{
+			"ALTER TABLE stock_reservations ADD INDEX stock_reservations_status_expires_at_idx (status, expires_at)",
+			"ALTER TABLE stock_reservations ADD INDEX stock_reservations_account_id_status_idx (account_id, status)",
+			"ALTER TABLE stock_reservations ADD INDEX stock_reservations_status_update_date_time_idx (status, update_date_time)",
+			"ALTER TABLE stock_reservation_items ADD INDEX stock_reservation_items_reservation_id_idx (reservation_id)",
+		} {
+			_, err = db.ExecContext(ctx, query)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.NewDropTable().Model(new(persistance.StockReservationItem)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = db.NewDropTable().Model(new(persistance.StockReservation)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [up migration] ")
+		_, err := db.NewCreateTable().Model(new(persistance.StockMovement)).IfNotExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		for _, query := range []string{
+			"ALTER TABLE stock_movements ADD INDEX stock_movements_product_id_sku_id_idx (product_id, sku_id)",
+			"ALTER TABLE stock_movements ADD INDEX stock_movements_reference_type_reference_id_idx (reference_type, reference_id)",
+			// 在庫台帳の導入時点の在庫数を手動調整として記録し、以降の在庫移動の合計が在庫数と一致するようにする
+			`INSERT INTO stock_movements (id, product_id, sku_id, type, quantity, before_count, after_count, reason, reference_type, reference_id, create_date_time)
+				SELECT UUID(), product_id, id, 'adjustment', stock_count, 0, stock_count, '在庫台帳の導入時の在庫数', '', '', UTC_TIMESTAMP()
+				FROM product_skus WHERE stock_count <> 0`,
+			`INSERT INTO stock_movements (id, product_id, sku_id, type, quantity, before_count, after_count, reason, reference_type, reference_id, create_date_time)
+				SELECT UUID(), id, NULL, 'adjustment', stock_count, 0, stock_count, '在庫台帳の導入時の在庫数', '', '', UTC_TIMESTAMP()
+				FROM products WHERE stock_count <> 0 AND NOT EXISTS (SELECT 1 FROM product_skus WHERE product_skus.product_id = products.id)`,
+		} {
+			_, err = db.ExecContext(ctx, query)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		fmt.Print(" [down migration] ")
+		_, err := db.NewDropTable().Model(new(persistance.StockMovement)).IfExists().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/config"
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/registory"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/dig"
+)
+
+const (
+	exitCodeIssueFound = 1 // 不整合が見つかった
+	exitCodeError      = 2 // 検査を実行できなかった
+)
+
+type (
+	// 検査結果のJSON出力
+	reportOutput struct {
+		From                string                         `json:"from"`
+		To                  string                         `json:"to"`
+		CheckedProductCount int                            `json:"checkedProductCount"`
+		IssueCount          int                            `json:"issueCount"`
+		Issues              []entity.ProductIntegrityIssue `json:"issues"`
+	}
+
+	// 在庫台帳の照合結果のJSON出力
+	stockReconciliationOutput struct {
+		CheckedCount int                 `json:"checkedCount"`
+		DriftCount   int                 `json:"driftCount"`
+		Drifts       []entity.StockDrift `json:"drifts"`
+	}
+)
+
+// 商品データの整合性を検査する
+// 不整合が見つかった場合は終了コード1、検査を実行できなかった場合は終了コード2で終了するため、デプロイ前のチェックに使用できる
+// 例）go run cmd/integrity/main.go check --from 2024-04-01 --to 2024-04-30 --format json
+// 例）go run cmd/integrity/main.go reconcile-stock --format text（在庫台帳から再計算した在庫数と現在の在庫数の差異を出力する）
+func main() {
+	err := config.SetupEnv()
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+
+	container, err := registory.NewContainer()
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+
+	app := cli.App{
+		Name:     "integrity",
+		Usage:    "product data integrity checker",
+		Commands: newIntegrityCommands(container),
+	}
+
+	if err = app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newIntegrityCommands(container *dig.Container) []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "check",
+			Usage: "report timeline gaps and overlaps, missing categories, duplicate image orders and negative stock",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "from", Usage: "first date to check (YYYY-MM-DD, default today)"},
+				&cli.StringFlag{Name: "to", Usage: "last date to check (YYYY-MM-DD, default 30 days after from)"},
+				&cli.StringFlag{Name: "format", Usage: "json or text", Value: "json"},
+			},
+			Action: func(ctx *cli.Context) error {
+				var report usecase.ProductIntegrityReport
+				err := container.Invoke(func(productIntegrityUsecase usecase.ProductIntegrityUsecase) error {
+					from, to, err := parsePeriod(ctx.String("from"), ctx.String("to"))
+					if err != nil {
+						return err
+					}
+
+					report, err = productIntegrityUsecase.Check(ctx.Context, from, to)
+					return err
+				})
+				if err != nil {
+					return cli.Exit(fmt.Sprintf("%+v", err), exitCodeError)
+				}
+
+				err = printReport(report, ctx.String("format"))
+				if err != nil {
+					return cli.Exit(fmt.Sprintf("%+v", err), exitCodeError)
+				}
+
+				if len(report.Issues) > 0 {
+					return cli.Exit("", exitCodeIssueFound)
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "reconcile-stock",
+			Usage: "recompute stock counts from the stock ledger and report drift from the current stock counts",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "format", Usage: "json or text", Value: "json"},
+			},
+			Action: func(ctx *cli.Context) error {
+				var report usecase.StockReconciliationReport
+				err := container.Invoke(func(stockMovementUsecase usecase.StockMovementUsecase) error {
+					var err error
+					report, err = stockMovementUsecase.Reconcile(ctx.Context)
+					return err
+				})
+				if err != nil {
+					return cli.Exit(fmt.Sprintf("%+v", err), exitCodeError)
+				}
+
+				err = printStockReconciliationReport(report, ctx.String("format"))
+				if err != nil {
+					return cli.Exit(fmt.Sprintf("%+v", err), exitCodeError)
+				}
+
+				if len(report.Drifts) > 0 {
+					return cli.Exit("", exitCodeIssueFound)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// 検査する期間を返却する
+func parsePeriod(fromValue string, toValue string) (time.Time, time.Time, error) {
+	from := util.NewTimeUtils().NowJP()
+	if fromValue != "" {
+		parsed, err := time.Parse(time.DateOnly, fromValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, 30)
+	if toValue != "" {
+		parsed, err := time.Parse(time.DateOnly, toValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to (%s) must not be before from (%s)", to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+	return from, to, nil
+}
+
+// 検査結果を標準出力に出力する
+func printReport(report usecase.ProductIntegrityReport, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reportOutput{
+			From:                report.From.Format(time.DateOnly),
+			To:                  report.To.Format(time.DateOnly),
+			CheckedProductCount: report.CheckedProductCount,
+			IssueCount:          len(report.Issues),
+			Issues:              report.Issues,
+		})
+	case "text":
+		for _, issue := range report.Issues {
+			fmt.Printf("%s\t%s\t%s\n", issue.ProductID, issue.Type, issue.Message)
+		}
+		fmt.Printf("%s〜%s: %d件の商品を検査し、%d件の不整合が見つかりました\n", report.From.Format(time.DateOnly), report.To.Format(time.DateOnly), report.CheckedProductCount, len(report.Issues))
+		return nil
+	}
+
+	return fmt.Errorf("unknown format: %s", format)
+}
+
+// 在庫台帳の照合結果を標準出力に出力する
+func printStockReconciliationReport(report usecase.StockReconciliationReport, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stockReconciliationOutput{
+			CheckedCount: report.CheckedCount,
+			DriftCount:   len(report.Drifts),
+			Drifts:       report.Drifts,
+		})
+	case "text":
+		for _, drift := range report.Drifts {
+			fmt.Printf("%s\t%s\tstock=%d\tledger=%d\tdrift=%+d\n", drift.ProductID, drift.SKUID, drift.StockCount, drift.LedgerStockCount, drift.Drift)
+		}
+		fmt.Printf("%d件の商品・SKUを照合し、%d件の在庫数の差異が見つかりました\n", report.CheckedCount, len(report.Drifts))
+		return nil
+	}
+
+	return fmt.Errorf("unknown format: %s", format)
+}
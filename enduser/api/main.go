@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -29,6 +31,27 @@ func customHTTPErrorHandler(err error, c echo.Context) {
 	c.Response().Writer.WriteHeader(http.StatusInternalServerError)
 }
 
+// X-Forwarded-ForヘッダーからクライアントのIPアドレスを取得する関数を返却する
+// 信頼するプロキシ（ループバック・リンクローカル・プライベートIPアドレスと環境変数TRUSTED_PROXY_CIDRSのIPアドレス範囲）が付加した値のみ使用し、
+// クライアントが送信したX-Forwarded-For・X-Real-IPヘッダーでIPアドレスを偽装してレート制限を回避できないようにする
+func newIPExtractor() (echo.IPExtractor, error) {
+	options := []echo.TrustOption{}
+	for _, cidr := range strings.Split(os.Getenv("TRUSTED_PROXY_CIDRS"), ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
 func main() {
 	e := echo.New()
 
@@ -43,6 +66,12 @@ func main() {
 		e.Logger.Fatal("コンテナ作成失敗\n", fmt.Sprintf("%+v", err))
 	}
 
+	// クライアントのIPアドレスの取得方法を設定する（レート制限に使用する）
+	e.IPExtractor, err = newIPExtractor()
+	if err != nil {
+		e.Logger.Fatal("IPアドレス取得方法設定失敗\n", fmt.Sprintf("%+v", err))
+	}
+
 	e, loginG, err := middleware.SetupMiddleware(e, container)
 	if err != nil {
 		e.Logger.Fatal("ミドルウェア設定失敗\n", fmt.Sprintf("%+v", err))
@@ -72,11 +72,31 @@ func (au AccountUsecase) AuthenticateEmail(ctx context.Context, tokenString stri
 
 		// セッションアカウントを作成する
 		sessionCart, existsSessionCart := middleware.SessionCartFromContext(ctx)
+		sessionWishlist, existsSessionWishlist := middleware.SessionWishlistFromContext(ctx)
+		recentlyViewedSessionID, _ := middleware.RecentlyViewedSessionIDFromContext(ctx)
 		var sessionAccount entity.SessionAccount
-		accountSessionCookie, sessionAccount = entity.CreateSessionAccount(account, sessionCart, existsSessionCart, tx, ctxt)
+		accountSessionCookie, sessionAccount = entity.CreateSessionAccount(account, sessionCart, existsSessionCart, sessionWishlist, existsSessionWishlist, recentlyViewedSessionID, tx, ctxt)
 		err = au.sessionAccountRepository.Insert(ctxt, &sessionAccount, entity.SessionAccountExpiration, au.domainEventPublisher)
 		return err
 	})
 
 	return accountSessionCookie, err
 }
+
+// ログイン中のアカウントの宣伝メール配信停止フラグを更新する
+func (au AccountUsecase) UpdateMarketingEmailOptOut(ctx context.Context, optOut bool) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return au.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		account, ok, err := au.accountRepository.FindByID(tx, ctxt, sessionAccount.AccountID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return share.CreateOriginalError(share.ErrorCodeOther, []string{"アカウントが見つかりません"})
+		}
+
+		account.SetMarketingEmailOptOut(optOut)
+		return au.accountRepository.Update(tx, ctxt, &account, au.domainEventPublisher)
+	})
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/middleware"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+type (
+	BackInStockUsecase struct {
+		backInStockNotificationDomainService service.BackInStockNotificationDomainService
+		backInStockSubscriptionRepository    repository.BackInStockSubscriptionRepository
+		productRepository                    repository.ProductRepository
+		accountRepository                    repository.AccountRepository
+		domainEventPublisher                 share.DomainEventPublisher
+		timeUtils                            util.TimeUtils
+		logger                               echo.Logger
+		db                                   bun.IDB
+	}
+
+	// 入荷通知メールの送信の流量制限
+	BackInStockNotificationRateLimit struct {
+		BatchSize     int           // 1バッチで送信する通知メールの最大件数
+		BatchInterval time.Duration // バッチ間の待機時間
+	}
+)
+
+func NewBackInStockUsecase(
+	backInStockNotificationDomainService service.BackInStockNotificationDomainService,
+	backInStockSubscriptionRepository repository.BackInStockSubscriptionRepository,
+	productRepository repository.ProductRepository,
+	accountRepository repository.AccountRepository,
+	domainEventPublisher share.DomainEventPublisher,
+	timeUtils util.TimeUtils,
+	logger echo.Logger,
+	db bun.IDB,
+) BackInStockUsecase {
+	return BackInStockUsecase{
+		backInStockNotificationDomainService: backInStockNotificationDomainService,
+		backInStockSubscriptionRepository:    backInStockSubscriptionRepository,
+		productRepository:                    productRepository,
+		accountRepository:                    accountRepository,
+		domainEventPublisher:                 domainEventPublisher,
+		timeUtils:                            timeUtils,
+		logger:                               logger,
+		db:                                   db,
+	}
+}
+
+// ログイン中のアカウントで購入できない商品の入荷通知を購読する（購読済みの場合は何もしない）
+func (bu BackInStockUsecase) Subscribe(ctx context.Context, productID string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	product, ok, err := bu.productRepository.FindByID(bu.db, ctx, productID, false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"商品が見つかりません"})
+	}
+
+	subscription, err := entity.CreateBackInStockSubscription(sessionAccount.AccountID, product, bu.timeUtils.NowJP())
+	if err != nil {
+		return err
+	}
+
+	exists, err := bu.backInStockSubscriptionRepository.Exists(bu.db, ctx, sessionAccount.AccountID, productID)
+	if err != nil || exists {
+		return err
+	}
+
+	// 同時に購読された場合は一意制約違反となるため購読済みとして扱う
+	err = bu.backInStockSubscriptionRepository.Insert(bu.db, ctx, subscription)
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return nil
+	}
+	return err
+}
+
+// ログイン中のアカウントの商品の入荷通知の購読を解除する
+func (bu BackInStockUsecase) Unsubscribe(ctx context.Context, productID string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+	return bu.backInStockSubscriptionRepository.Delete(bu.db, ctx, sessionAccount.AccountID, productID)
+}
+
+// 入荷通知が購読されている商品のうち、販売中に戻るなどして購入できるようになった商品の再入荷イベントを発行し、発行した件数を返却する
+// 商品ステータスは適用期間により変わり、在庫数の変更のように変更時にイベントを発行できないため定期的に確認する
+func (bu BackInStockUsecase) PublishBackInStockEvents(ctx context.Context) (int, error) {
+	productIDs, err := bu.backInStockSubscriptionRepository.FindUnrequestedProductIDs(bu.db, ctx)
+	if err != nil || len(productIDs) == 0 {
+		return 0, err
+	}
+
+	products, err := bu.productRepository.FindByIDs(bu.db, ctx, productIDs, false)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, product := range products {
+		if !product.IsPurchasable() {
+			continue
+		}
+
+		err = bu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+			return bu.domainEventPublisher.Publish([]share.DomainEvent{entity.ProductBackInStockEvent{ProductID: product.ID, DB: tx, Ctx: ctxt}})
+		})
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// 通知メールの送信待ちの入荷通知を流量を制限して送信し、送信件数を返却する
+// 送信したアカウントの購読は削除し、送信前に再び購入できなくなった商品の購読は次の再入荷まで送信待ちを取り消す
+// 1件の送信に失敗した場合もログ出力して残りの送信を続ける（失敗した購読は次回の実行で再度送信する）
+// 送信に失敗し続ける購読が後続の購読の送信を妨げないよう、1回の実行では送信を試みた購読より後の購読を順に取得する
+func (bu BackInStockUsecase) SendNotifications(ctx context.Context, rateLimit BackInStockNotificationRateLimit) (int, error) {
+	if rateLimit.BatchSize < 1 {
+		return 0, share.CreateOriginalError(share.ErrorCodeValidation, []string{"1バッチで送信する通知メールの件数は1以上を指定してください"})
+	}
+
+	var sent int
+	var after *entity.BackInStockSubscription
+	for {
+		subscriptions, err := bu.backInStockSubscriptionRepository.FindRequested(bu.db, ctx, after, rateLimit.BatchSize)
+		if err != nil || len(subscriptions) == 0 {
+			return sent, err
+		}
+
+		productIDs := make([]string, 0, len(subscriptions))
+		accountIDs := make([]string, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			productIDs = append(productIDs, subscription.ProductID)
+			accountIDs = append(accountIDs, subscription.AccountID)
+		}
+
+		products, err := bu.productRepository.FindByIDs(bu.db, ctx, productIDs, false)
+		if err != nil {
+			return sent, err
+		}
+		productMap := make(map[string]entity.Product, len(products))
+		for _, product := range products {
+			productMap[product.ID] = product
+		}
+		// 当日の商品ステータス・商品価格・商品セール価格が存在しない商品も商品配列に含まれないため、商品の行が存在するかを別途確認する
+		existingProductIDs, err := bu.productRepository.FindCategoryIDs(bu.db, ctx, productIDs)
+		if err != nil {
+			return sent, err
+		}
+
+		accounts, err := bu.accountRepository.FindByIDs(bu.db, ctx, accountIDs)
+		if err != nil {
+			return sent, err
+		}
+		accountMap := make(map[string]entity.Account, len(accounts))
+		for _, account := range accounts {
+			accountMap[account.ID] = account
+		}
+
+		// 送信した購読と、商品・アカウントが削除され送信できない購読を削除する
+		// 商品集約を構成できないだけの商品（当日の商品ステータス等が存在しない商品）の購読は削除せず、次回の実行で再度送信する
+		doneIDs := []string{}
+		canceledProductIDs := map[string]bool{}
+		for _, subscription := range subscriptions {
+			_, productExists := existingProductIDs[subscription.ProductID]
+			account, accountOK := accountMap[subscription.AccountID]
+			if !productExists || !accountOK {
+				doneIDs = append(doneIDs, subscription.ID)
+				continue
+			}
+
+			product, productOK := productMap[subscription.ProductID]
+			if !productOK {
+				continue
+			}
+
+			if !product.IsPurchasable() {
+				canceledProductIDs[product.ID] = true
+				continue
+			}
+
+			err = bu.backInStockNotificationDomainService.SendNotification(account, product)
+			if err != nil {
+				bu.logger.Error(fmt.Sprintf("入荷通知メール（購読ID: %s）の送信に失敗しました\n%+v", subscription.ID, err))
+				continue
+			}
+			doneIDs = append(doneIDs, subscription.ID)
+			sent++
+		}
+
+		err = bu.backInStockSubscriptionRepository.DeleteByIDs(bu.db, ctx, doneIDs)
+		if err != nil {
+			return sent, err
+		}
+		for productID := range canceledProductIDs {
+			err = bu.backInStockSubscriptionRepository.CancelNotificationRequest(bu.db, ctx, productID)
+			if err != nil {
+				return sent, err
+			}
+		}
+
+		if len(subscriptions) < rateLimit.BatchSize {
+			return sent, nil
+		}
+		after = &subscriptions[len(subscriptions)-1]
+
+		select {
+		case <-ctx.Done():
+			return sent, nil
+		case <-time.After(rateLimit.BatchInterval):
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/middleware"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/uptrace/bun"
+)
+
+type (
+	CartUsecase struct {
+		cartRepository    repository.CartRepository
+		productRepository repository.ProductRepository
+		db                bun.IDB
+	}
+
+	// カート画面に表示するカート
+	CartDetail struct {
+		Cart     entity.Cart
+		Products []entity.Product
+		Warnings []entity.CartWarning
+	}
+)
+
+var errCartNotFound = errors.New("カートが見つかりません")
+
+func NewCartUsecase(
+	cartRepository repository.CartRepository,
+	productRepository repository.ProductRepository,
+	db bun.IDB,
+) CartUsecase {
+	return CartUsecase{
+		cartRepository:    cartRepository,
+		productRepository: productRepository,
+		db:                db,
+	}
+}
+
+// ログイン中のアカウントのカートを取得する
+// カート追加時から価格・商品ステータス・在庫数が変化した商品の警告も返却する
+func (cu CartUsecase) FindCart(ctx context.Context) (CartDetail, error) {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	cart, ok, err := cu.cartRepository.FindByAccountID(cu.db, ctx, sessionAccount.AccountID)
+	if err != nil {
+		return CartDetail{}, err
+	}
+	if !ok {
+		return CartDetail{}, errors.WithStack(errCartNotFound)
+	}
+
+	products, err := cu.productRepository.FindByIDs(cu.db, ctx, cart.ProductIDs(), true)
+	if err != nil {
+		return CartDetail{}, err
+	}
+
+	return CartDetail{
+		Cart:     cart,
+		Products: products,
+		Warnings: cart.Warnings(products),
+	}, nil
+}
+
+// ログイン中のアカウントのカートに商品のSKUを追加する（バリエーションを持たない商品の場合はskuIDに空文字を渡す）
+func (cu CartUsecase) AddProduct(ctx context.Context, productID string, skuID string, count int) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return cu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		cart, ok, err := cu.cartRepository.FindByAccountID(tx, ctxt, sessionAccount.AccountID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.WithStack(errCartNotFound)
+		}
+
+		products, err := cu.productRepository.FindByIDs(tx, ctxt, []string{productID}, false)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			return share.CreateOriginalError(share.ErrorCodeOther, []string{"商品が見つかりません"})
+		}
+
+		err = cart.AddProduct(products[0], skuID, count)
+		if err != nil {
+			return err
+		}
+
+		return cu.cartRepository.Update(tx, ctxt, cart)
+	})
+}
+
+// ログイン中のアカウントのカート内の商品を「あとで買う」に移動する
+func (cu CartUsecase) MoveToSaved(ctx context.Context, cartProductID string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return cu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		cart, ok, err := cu.cartRepository.FindByAccountID(tx, ctxt, sessionAccount.AccountID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.WithStack(errCartNotFound)
+		}
+
+		err = cart.MoveToSaved(cartProductID)
+		if err != nil {
+			return err
+		}
+
+		return cu.cartRepository.Update(tx, ctxt, cart)
+	})
+}
+
+// ログイン中のアカウントの「あとで買う」の商品をカートに移動する
+func (cu CartUsecase) MoveToCart(ctx context.Context, savedProductID string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return cu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		cart, ok, err := cu.cartRepository.FindByAccountID(tx, ctxt, sessionAccount.AccountID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.WithStack(errCartNotFound)
+		}
+
+		savedProduct, ok := cart.FindSavedProduct(savedProductID)
+		if !ok {
+			return share.CreateOriginalError(share.ErrorCodeOther, []string{"あとで買う商品が見つかりません"})
+		}
+
+		products, err := cu.productRepository.FindByIDs(tx, ctxt, []string{savedProduct.ProductID}, false)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			return share.CreateOriginalError(share.ErrorCodeOther, []string{"商品が見つかりません"})
+		}
+
+		err = cart.MoveToCart(savedProductID, products[0])
+		if err != nil {
+			return err
+		}
+
+		return cu.cartRepository.Update(tx, ctxt, cart)
+	})
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+type CartReminderUsecase struct {
+	cartReminderDomainService service.CartReminderDomainService
+	cartRepository            repository.CartRepository
+	accountRepository         repository.AccountRepository
+	productRepository         repository.ProductRepository
+	timeUtils                 util.TimeUtils
+	logger                    echo.Logger
+	db                        bun.IDB
+}
+
+func NewCartReminderUsecase(
+	cartReminderDomainService service.CartReminderDomainService,
+	cartRepository repository.CartRepository,
+	accountRepository repository.AccountRepository,
+	productRepository repository.ProductRepository,
+	timeUtils util.TimeUtils,
+	logger echo.Logger,
+	db bun.IDB,
+) CartReminderUsecase {
+	return CartReminderUsecase{
+		cartReminderDomainService: cartReminderDomainService,
+		cartRepository:            cartRepository,
+		accountRepository:         accountRepository,
+		productRepository:         productRepository,
+		timeUtils:                 timeUtils,
+		logger:                    logger,
+		db:                        db,
+	}
+}
+
+// 最終更新日時からperiod以上経過したカートの所有者にカート放置リマインドメールを送信し、送信件数を返却する
+// 宣伝メールの配信を停止しているアカウントには送信しない
+// 1つのカートの送信に失敗した場合もログ出力して残りのカートの送信を続ける
+func (cu CartReminderUsecase) SendAbandonedCartReminders(ctx context.Context, period time.Duration) (int, error) {
+	now := cu.timeUtils.NowJP()
+
+	carts, err := cu.cartRepository.FindAbandoned(cu.db, ctx, now.Add(-period))
+	if err != nil {
+		return 0, err
+	}
+
+	accountIDs := make([]string, 0, len(carts))
+	productIDs := []string{}
+	for _, cart := range carts {
+		accountIDs = append(accountIDs, cart.AccountID)
+		productIDs = append(productIDs, cart.ProductIDs()...)
+	}
+
+	accounts, err := cu.accountRepository.FindByIDs(cu.db, ctx, accountIDs)
+	if err != nil {
+		return 0, err
+	}
+	accountMap := make(map[string]entity.Account, len(accounts))
+	for _, account := range accounts {
+		accountMap[account.ID] = account
+	}
+
+	products := []entity.Product{}
+	if len(productIDs) > 0 {
+		products, err = cu.productRepository.FindByIDs(cu.db, ctx, productIDs, false)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	sentCount := 0
+	for _, cart := range carts {
+		account, ok := accountMap[cart.AccountID]
+		if !ok || !account.CanReceiveMarketingEmail() || !cart.NeedsAbandonedReminder(now, period) {
+			continue
+		}
+
+		sent, err := cu.sendReminder(ctx, account, cart, products, now)
+		if err != nil {
+			cu.logger.Error(fmt.Sprintf("カート放置リマインドメールの送信に失敗しました cartID=%s\n%+v", cart.ID, err))
+			continue
+		}
+		if sent {
+			sentCount++
+		}
+	}
+
+	return sentCount, nil
+}
+
+// 送信日時を記録してコミットした後にカート放置リマインドメールを送信する
+// 送信日時を記録する前に送信すると、記録に失敗した場合に次回のバッチで二重に送信されるため、送信はコミット後に行う
+// 同時に実行された他のバッチが先に送信日時を記録した場合は送信しない
+// 送信に失敗した場合は送信日時を元に戻し、次回のバッチで再送信する
+func (cu CartReminderUsecase) sendReminder(ctx context.Context, account entity.Account, cart entity.Cart, products []entity.Product, now time.Time) (bool, error) {
+	previousReminderSentAt := cart.ReminderSentAt
+	var claimed bool
+	err := cu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		cart.MarkReminderSent(now)
+		var err error
+		claimed, err = cu.cartRepository.UpdateReminderSentAt(tx, ctxt, cart)
+		return err
+	})
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	sent, err := cu.cartReminderDomainService.SendReminder(account, cart, products)
+	if err != nil {
+		cart.ReminderSentAt = previousReminderSentAt
+		resetErr := cu.cartRepository.RestoreReminderSentAt(cu.db, ctx, cart)
+		if resetErr != nil {
+			cu.logger.Error(fmt.Sprintf("カート放置リマインドメールの送信日時を元に戻せませんでした cartID=%s\n%+v", cart.ID, resetErr))
+		}
+		return false, err
+	}
+
+	return sent, nil
+}
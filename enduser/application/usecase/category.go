@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/uptrace/bun"
+)
+
+type CategoryUsecase struct {
+	categoryRepository repository.CategoryRepository
+	db                 bun.IDB
+}
+
+func NewCategoryUsecase(categoryRepository repository.CategoryRepository, db bun.IDB) CategoryUsecase {
+	return CategoryUsecase{
+		categoryRepository: categoryRepository,
+		db:                 db,
+	}
+}
+
+// すべてのカテゴリーをカテゴリーツリーとして取得する
+func (cu CategoryUsecase) FindTree(ctx context.Context) ([]entity.CategoryNode, error) {
+	categories, err := cu.categoryRepository.FindAll(cu.db, ctx)
+	if err != nil {
+		return []entity.CategoryNode{}, err
+	}
+
+	return entity.BuildCategoryTree(categories), nil
+}
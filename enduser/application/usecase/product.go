@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/validator"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	ProductUsecase struct {
+		productRepository          repository.ProductRepository
+		productSearchRepository    repository.ProductSearchRepository
+		searchSuggestionRepository repository.SearchSuggestionRepository
+		categoryRepository         repository.CategoryRepository
+		validationUtils            util.ValidationUtils
+		timeUtils                  util.TimeUtils
+		db                         bun.IDB
+	}
+
+	// 商品一覧の検索条件の入力値
+	ProductCatalogInput struct {
+		CategoryID  string
+		MinPrice    int
+		MaxPrice    int
+		OnSaleOnly  bool
+		InStockOnly bool
+		Sort        string
+		Cursor      string
+		Limit       int
+	}
+
+	// 商品検索の検索条件の入力値
+	ProductSearchInput struct {
+		Keyword     string
+		CategoryID  string
+		MinPrice    int
+		MaxPrice    int
+		OnSaleOnly  bool
+		InStockOnly bool
+		Page        int
+		Limit       int
+	}
+
+	// 商品詳細
+	ProductDetail struct {
+		Product     entity.Product
+		Breadcrumbs []entity.Category // 最上位のカテゴリーから商品のカテゴリーまで順に並べたカテゴリー
+	}
+)
+
+func NewProductUsecase(
+	productRepository repository.ProductRepository,
+	productSearchRepository repository.ProductSearchRepository,
+	searchSuggestionRepository repository.SearchSuggestionRepository,
+	categoryRepository repository.CategoryRepository,
+	validationUtils util.ValidationUtils,
+	timeUtils util.TimeUtils,
+	db bun.IDB,
+) ProductUsecase {
+	return ProductUsecase{
+		productRepository:          productRepository,
+		productSearchRepository:    productSearchRepository,
+		searchSuggestionRepository: searchSuggestionRepository,
+		categoryRepository:         categoryRepository,
+		validationUtils:            validationUtils,
+		timeUtils:                  timeUtils,
+		db:                         db,
+	}
+}
+
+// 検索条件に一致する商品一覧の1ページと、先頭ページの場合は絞り込み条件ごとの商品数を取得する
+func (pu ProductUsecase) FindCatalog(ctx context.Context, input ProductCatalogInput) (entity.ProductCatalogPage, error) {
+	condition, err := pu.toCatalogCondition(input)
+	if err != nil {
+		return entity.ProductCatalogPage{}, err
+	}
+
+	page, err := pu.productRepository.FindCatalog(pu.db, ctx, condition)
+	if err != nil {
+		return entity.ProductCatalogPage{}, err
+	}
+
+	// 絞り込み条件ごとの商品数はページによって変わらないため、先頭ページの取得時のみ集計する
+	if condition.Cursor == nil {
+		facets, err := pu.productRepository.FindFacets(pu.db, ctx, condition)
+		if err != nil {
+			return entity.ProductCatalogPage{}, err
+		}
+		page.Facets = &facets
+	}
+
+	return page, nil
+}
+
+// 入力値を検証し、商品一覧の検索条件に変換する
+// 並び順・取得件数が未指定の場合は新着順・既定の件数とする
+func (pu ProductUsecase) toCatalogCondition(input ProductCatalogInput) (entity.ProductCatalogCondition, error) {
+	if input.Limit == 0 {
+		input.Limit = entity.ProductCatalogDefaultLimit
+	}
+
+	err := pu.validationUtils.Struct(validator.ValidationProductCatalogCondition{
+		MinPrice: input.MinPrice,
+		MaxPrice: input.MaxPrice,
+		Limit:    input.Limit,
+	})
+	if err != nil {
+		return entity.ProductCatalogCondition{}, pu.validationUtils.CreateValidationMessages(err)
+	}
+
+	sort := enum.ProductSort(input.Sort)
+	if input.Sort == "" {
+		sort = enum.ProductSortNewest
+	}
+	if !sort.IsValid() {
+		return entity.ProductCatalogCondition{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"並び順が不正です"})
+	}
+
+	var cursor *entity.ProductCatalogCursor
+	if input.Cursor != "" {
+		c, ok := entity.DecodeProductCatalogCursor(input.Cursor)
+		if !ok {
+			return entity.ProductCatalogCondition{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"カーソルが不正です"})
+		}
+		cursor = &c
+	}
+
+	return entity.ProductCatalogCondition{
+		CategoryID:  input.CategoryID,
+		MinPrice:    input.MinPrice,
+		MaxPrice:    input.MaxPrice,
+		OnSaleOnly:  input.OnSaleOnly,
+		InStockOnly: input.InStockOnly,
+		Sort:        sort,
+		Cursor:      cursor,
+		Limit:       input.Limit,
+	}, nil
+}
+
+// 商品IDに一致する商品と、商品のカテゴリーのパンくずリストを取得する
+// 商品が存在しない場合や当日の商品ステータスが存在しない場合はfalseを返却する
+func (pu ProductUsecase) FindProduct(ctx context.Context, productID string) (ProductDetail, bool, error) {
+	product, ok, err := pu.productRepository.FindByID(pu.db, ctx, productID, true)
+	if err != nil || !ok {
+		return ProductDetail{}, ok, err
+	}
+
+	breadcrumbs, err := pu.categoryRepository.FindAncestorPath(pu.db, ctx, product.CategoryID)
+	if err != nil {
+		return ProductDetail{}, false, err
+	}
+
+	return ProductDetail{Product: product, Breadcrumbs: breadcrumbs}, true, nil
+}
+
+// キーワードに一致する商品を関連度の高い順に検索し、検索結果の1ページを取得する
+// 検索結果は商品一覧と同じ条件（カテゴリー・価格帯・販売中・在庫あり）で絞り込む
+// 検索結果が存在するキーワードは検索候補として検索回数を記録する
+func (pu ProductUsecase) Search(ctx context.Context, input ProductSearchInput) (entity.ProductSearchPage, error) {
+	if input.Page == 0 {
+		input.Page = 1
+	}
+	if input.Limit == 0 {
+		input.Limit = entity.ProductSearchDefaultLimit
+	}
+
+	err := pu.validationUtils.Struct(validator.ValidationProductSearchCondition{
+		Keyword:  input.Keyword,
+		MinPrice: input.MinPrice,
+		MaxPrice: input.MaxPrice,
+		Page:     input.Page,
+		Limit:    input.Limit,
+	})
+	if err != nil {
+		return entity.ProductSearchPage{}, pu.validationUtils.CreateValidationMessages(err)
+	}
+
+	hits, err := pu.productSearchRepository.Search(pu.db, ctx, input.Keyword, entity.ProductSearchMaxHits)
+	if err != nil {
+		return entity.ProductSearchPage{}, err
+	}
+
+	hitIDs := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		hitIDs = append(hitIDs, hit.ProductID)
+	}
+
+	filteredIDs, err := pu.productRepository.FilterIDs(pu.db, ctx, hitIDs, entity.ProductCatalogCondition{
+		CategoryID:  input.CategoryID,
+		MinPrice:    input.MinPrice,
+		MaxPrice:    input.MaxPrice,
+		OnSaleOnly:  input.OnSaleOnly,
+		InStockOnly: input.InStockOnly,
+	})
+	if err != nil {
+		return entity.ProductSearchPage{}, err
+	}
+
+	// 絞り込み後の商品IDを関連度の高い順に並べる
+	filteredIDMap := make(map[string]struct{}, len(filteredIDs))
+	for _, id := range filteredIDs {
+		filteredIDMap[id] = struct{}{}
+	}
+	rankedIDs := make([]string, 0, len(filteredIDs))
+	for _, id := range hitIDs {
+		if _, ok := filteredIDMap[id]; ok {
+			rankedIDs = append(rankedIDs, id)
+		}
+	}
+
+	// 1ページ目の検索時のみ検索回数を記録する
+	if len(rankedIDs) > 0 && input.Page == 1 {
+		err = pu.searchSuggestionRepository.IncrementQuery(ctx, strings.TrimSpace(input.Keyword), pu.timeUtils.NowJP())
+		if err != nil {
+			return entity.ProductSearchPage{}, err
+		}
+	}
+
+	start := min((input.Page-1)*input.Limit, len(rankedIDs))
+	end := min(start+input.Limit, len(rankedIDs))
+	products, err := pu.findProductsInOrder(ctx, rankedIDs[start:end])
+	if err != nil {
+		return entity.ProductSearchPage{}, err
+	}
+
+	return entity.ProductSearchPage{
+		Products:   products,
+		TotalCount: len(rankedIDs),
+		Page:       input.Page,
+		HasNext:    end < len(rankedIDs),
+	}, nil
+}
+
+// すべての商品の検索用ドキュメントを商品検索のインデックスに登録し、登録件数を返却する
+func (pu ProductUsecase) RebuildSearchIndex(ctx context.Context) (int, error) {
+	documents, err := pu.productRepository.FindSearchDocuments(pu.db, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, document := range documents {
+		err = pu.productSearchRepository.Save(pu.db, ctx, document)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(documents), nil
+}
+
+// 商品ID配列に一致する商品配列を商品ID配列の順序で取得する（存在しない商品は含めない）
+func (pu ProductUsecase) findProductsInOrder(ctx context.Context, ids []string) ([]entity.Product, error) {
+	products, err := pu.productRepository.FindByIDs(pu.db, ctx, ids, true)
+	if err != nil {
+		return []entity.Product{}, err
+	}
+
+	productMap := make(map[string]entity.Product, len(products))
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	orderedProducts := make([]entity.Product, 0, len(ids))
+	for _, id := range ids {
+		if product, ok := productMap[id]; ok {
+			orderedProducts = append(orderedProducts, product)
+		}
+	}
+	return orderedProducts, nil
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+type ProductImageUsecase struct {
+	productImageVariantDomainService service.ProductImageVariantDomainService
+	productImageRepository           repository.ProductImageRepository
+	timeUtils                        util.TimeUtils
+	logger                           echo.Logger
+	db                               bun.IDB
+}
+
+const productImageVariantBatchSize = 100 // 1度に取得する派生画像が存在しない商品画像の件数
+
+func NewProductImageUsecase(
+	productImageVariantDomainService service.ProductImageVariantDomainService,
+	productImageRepository repository.ProductImageRepository,
+	timeUtils util.TimeUtils,
+	logger echo.Logger,
+	db bun.IDB,
+) ProductImageUsecase {
+	return ProductImageUsecase{
+		productImageVariantDomainService: productImageVariantDomainService,
+		productImageRepository:           productImageRepository,
+		timeUtils:                        timeUtils,
+		logger:                           logger,
+		db:                               db,
+	}
+}
+
+// 派生画像が存在しない商品画像の派生画像を生成し、生成した商品画像の件数を返却する
+// 商品画像の登録時は元画像のみ保存し、派生画像はこのバッチ処理で非同期に生成する
+// 1つの商品画像の生成に失敗した場合もログ出力して残りの商品画像の生成を続ける（失敗した商品画像は次回の実行で再度生成する）
+// 生成に失敗した回数が上限に達した商品画像はデッドレターとして以降の生成の対象から除外する
+func (pu ProductImageUsecase) GenerateVariants(ctx context.Context) (int, error) {
+	var count int
+	var afterID string
+	for {
+		images, err := pu.productImageRepository.FindWithoutVariants(pu.db, ctx, afterID, productImageVariantBatchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(images) == 0 {
+			return count, nil
+		}
+
+		imageIDs := make([]string, 0, len(images))
+		for _, image := range images {
+			imageIDs = append(imageIDs, image.ID)
+		}
+		failures, err := pu.productImageRepository.FindVariantFailures(pu.db, ctx, imageIDs)
+		if err != nil {
+			return count, err
+		}
+		failureMap := make(map[string]entity.ProductImageVariantFailure, len(failures))
+		for _, failure := range failures {
+			failureMap[failure.ProductImageID] = failure
+		}
+
+		for _, image := range images {
+			afterID = image.ID
+			failure, failed := failureMap[image.ID]
+
+			variants, err := pu.productImageVariantDomainService.Generate(image)
+			if err != nil {
+				if !failed {
+					failure = entity.CreateProductImageVariantFailure(image.ID)
+				}
+				failure.RecordAttempt(err.Error(), pu.timeUtils.NowJP())
+				if failure.IsDeadLettered() {
+					pu.logger.Error(fmt.Sprintf("商品画像（%s）の派生画像の生成に%d回失敗したため、生成の対象から除外しました\n%+v", image.ID, failure.AttemptCount, err))
+				} else {
+					pu.logger.Error(fmt.Sprintf("商品画像（%s）の派生画像の生成に失敗しました（%d回目）\n%+v", image.ID, failure.AttemptCount, err))
+				}
+
+				err = pu.productImageRepository.SaveVariantFailure(pu.db, ctx, failure)
+				if err != nil {
+					return count, err
+				}
+				continue
+			}
+
+			err = pu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+				err := pu.productImageRepository.InsertVariants(tx, ctxt, variants)
+				if err != nil || !failed {
+					return err
+				}
+				return pu.productImageRepository.DeleteVariantFailure(tx, ctxt, image.ID)
+			})
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+}
+
+// 生成の対象から除外された（デッドレターの）商品画像を再び派生画像の生成の対象にし、対象にした件数を返却する
+// 元画像を差し替えるなど失敗の原因を取り除いた後に実行する
+func (pu ProductImageUsecase) RequeueDeadLetterVariants(ctx context.Context) (int, error) {
+	return pu.productImageRepository.DeleteDeadLetterVariantFailures(pu.db, ctx)
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	ProductIntegrityUsecase struct {
+		productIntegrityRepository repository.ProductIntegrityRepository
+		timeUtils                  util.TimeUtils
+		db                         bun.IDB
+	}
+
+	// 商品データの整合性検査の結果
+	ProductIntegrityReport struct {
+		From                time.Time
+		To                  time.Time
+		CheckedProductCount int
+		Issues              []entity.ProductIntegrityIssue
+	}
+)
+
+const productIntegrityBatchSize = 100 // 1度に取得する商品の件数
+
+func NewProductIntegrityUsecase(productIntegrityRepository repository.ProductIntegrityRepository, timeUtils util.TimeUtils, db bun.IDB) ProductIntegrityUsecase {
+	return ProductIntegrityUsecase{
+		productIntegrityRepository: productIntegrityRepository,
+		timeUtils:                  timeUtils,
+		db:                         db,
+	}
+}
+
+// すべての商品について引数の期間（開始日・終了日を含む）の商品データの不整合を検査する
+func (pu ProductIntegrityUsecase) Check(ctx context.Context, from time.Time, to time.Time) (ProductIntegrityReport, error) {
+	from = pu.timeUtils.DateJP(from.Year(), from.Month(), from.Day())
+	to = pu.timeUtils.DateJP(to.Year(), to.Month(), to.Day())
+	report := ProductIntegrityReport{From: from, To: to, Issues: []entity.ProductIntegrityIssue{}}
+
+	var afterID string
+	for {
+		snapshots, err := pu.productIntegrityRepository.FindSnapshots(pu.db, ctx, afterID, productIntegrityBatchSize)
+		if err != nil {
+			return ProductIntegrityReport{}, err
+		}
+
+		for _, snapshot := range snapshots {
+			report.Issues = append(report.Issues, snapshot.Check(from, to)...)
+		}
+		report.CheckedProductCount += len(snapshots)
+
+		if len(snapshots) < productIntegrityBatchSize {
+			return report, nil
+		}
+		afterID = snapshots[len(snapshots)-1].ProductID
+	}
+}
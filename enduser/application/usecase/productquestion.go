@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/validator"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/middleware"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+type ProductQuestionUsecase struct {
+	productQuestionDomainService service.ProductQuestionDomainService
+	productQuestionRepository    repository.ProductQuestionRepository
+	accountRepository            repository.AccountRepository
+	productRepository            repository.ProductRepository
+	validationUtils              util.ValidationUtils
+	logger                       echo.Logger
+	db                           bun.IDB
+}
+
+func NewProductQuestionUsecase(
+	productQuestionDomainService service.ProductQuestionDomainService,
+	productQuestionRepository repository.ProductQuestionRepository,
+	accountRepository repository.AccountRepository,
+	productRepository repository.ProductRepository,
+	validationUtils util.ValidationUtils,
+	logger echo.Logger,
+	db bun.IDB,
+) ProductQuestionUsecase {
+	return ProductQuestionUsecase{
+		productQuestionDomainService: productQuestionDomainService,
+		productQuestionRepository:    productQuestionRepository,
+		accountRepository:            accountRepository,
+		productRepository:            productRepository,
+		validationUtils:              validationUtils,
+		logger:                       logger,
+		db:                           db,
+	}
+}
+
+// 商品の公開中の質問一覧の1ページを取得する
+func (qu ProductQuestionUsecase) FindProductQuestions(ctx context.Context, productID string, page int, limit int) (entity.ProductQuestionPage, error) {
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = entity.ProductQuestionDefaultLimit
+	}
+
+	err := qu.validationUtils.Struct(validator.ValidationProductQuestionListCondition{Page: page, Limit: limit})
+	if err != nil {
+		return entity.ProductQuestionPage{}, qu.validationUtils.CreateValidationMessages(err)
+	}
+
+	return qu.productQuestionRepository.FindPublishedByProductID(qu.db, ctx, productID, page, limit)
+}
+
+// ログイン中のアカウントで商品に質問を投稿する
+func (qu ProductQuestionUsecase) PostQuestion(ctx context.Context, productID string, body string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return qu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		_, err := qu.productQuestionDomainService.PostQuestion(tx, ctxt, sessionAccount.AccountID, productID, body)
+		return err
+	})
+}
+
+// ログイン中のアカウントで質問に回答し、質問者に回答通知メールを送信する
+func (qu ProductQuestionUsecase) AnswerQuestion(ctx context.Context, questionID string, body string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	var question entity.ProductQuestion
+	var answer entity.ProductAnswer
+	err := qu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		var err error
+		question, answer, err = qu.productQuestionDomainService.AnswerAsBuyer(tx, ctxt, sessionAccount.AccountID, questionID, body)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	qu.notifyAnswer(ctx, question, answer)
+	return nil
+}
+
+// ショップスタッフとして質問に回答し、質問者に回答通知メールを送信する
+func (qu ProductQuestionUsecase) AnswerAsStaff(ctx context.Context, questionID string, body string) error {
+	var question entity.ProductQuestion
+	var answer entity.ProductAnswer
+	err := qu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		var err error
+		question, answer, err = qu.productQuestionDomainService.AnswerAsStaff(tx, ctxt, questionID, body)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	qu.notifyAnswer(ctx, question, answer)
+	return nil
+}
+
+// 確認待ちの質問を投稿日時の古い順に最大limit件取得する
+func (qu ProductQuestionUsecase) FindPendingQuestions(ctx context.Context, limit int) ([]entity.ProductQuestionListItem, error) {
+	return qu.productQuestionRepository.FindByStatus(qu.db, ctx, enum.ProductQuestionStatusPending, limit)
+}
+
+// 質問の公開状態を変更する（管理者による確認）
+func (qu ProductQuestionUsecase) Moderate(ctx context.Context, questionID string, status enum.ProductQuestionStatus) error {
+	return qu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		return qu.productQuestionDomainService.Moderate(tx, ctxt, questionID, status)
+	})
+}
+
+// 質問者に回答通知メールを送信する
+// 回答の登録は完了しているため、送信に失敗した場合もログ出力のみ行う
+func (qu ProductQuestionUsecase) notifyAnswer(ctx context.Context, question entity.ProductQuestion, answer entity.ProductAnswer) {
+	err := qu.sendAnswerNotification(ctx, question, answer)
+	if err != nil {
+		qu.logger.Error(fmt.Sprintf("回答通知メールの送信に失敗しました questionID=%s\n%+v", question.ID, err))
+	}
+}
+
+// 退会済み・未有効化のアカウントと販売されていない商品の場合は送信しない
+func (qu ProductQuestionUsecase) sendAnswerNotification(ctx context.Context, question entity.ProductQuestion, answer entity.ProductAnswer) error {
+	account, ok, err := qu.accountRepository.FindByID(qu.db, ctx, question.AccountID)
+	if err != nil || !ok || !account.IsActive {
+		return err
+	}
+
+	product, ok, err := qu.productRepository.FindByID(qu.db, ctx, question.ProductID, false)
+	if err != nil || !ok {
+		return err
+	}
+
+	return qu.productQuestionDomainService.SendAnswerNotification(account, product, question, answer)
+}
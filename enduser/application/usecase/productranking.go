@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/validator"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	ProductRankingUsecase struct {
+		productRankingRepository  repository.ProductRankingRepository
+		purchaseHistoryRepository repository.PurchaseHistoryRepository
+		productViewRepository     repository.ProductViewRepository
+		productRepository         repository.ProductRepository
+		categoryRepository        repository.CategoryRepository
+		validationUtils           util.ValidationUtils
+		timeUtils                 util.TimeUtils
+		db                        bun.IDB
+	}
+
+	// ランキングの集計の入力値
+	ProductRankingAggregationInput struct {
+		Date        time.Time // 集計日（集計日の前日までの販売数・閲覧数から集計する）
+		SalesWeight int
+	}
+
+	// ランキングの取得の入力値
+	ProductRankingInput struct {
+		Period     enum.RankingPeriod
+		CategoryID string // サイト全体のランキングの場合は空文字
+		Limit      int
+	}
+)
+
+func NewProductRankingUsecase(
+	productRankingRepository repository.ProductRankingRepository,
+	purchaseHistoryRepository repository.PurchaseHistoryRepository,
+	productViewRepository repository.ProductViewRepository,
+	productRepository repository.ProductRepository,
+	categoryRepository repository.CategoryRepository,
+	validationUtils util.ValidationUtils,
+	timeUtils util.TimeUtils,
+	db bun.IDB,
+) ProductRankingUsecase {
+	return ProductRankingUsecase{
+		productRankingRepository:  productRankingRepository,
+		purchaseHistoryRepository: purchaseHistoryRepository,
+		productViewRepository:     productViewRepository,
+		productRepository:         productRepository,
+		categoryRepository:        categoryRepository,
+		validationUtils:           validationUtils,
+		timeUtils:                 timeUtils,
+		db:                        db,
+	}
+}
+
+// 集計日の前日までの販売数・閲覧数から日間・週間・月間のランキングを集計してスナップショットとして登録し、登録した件数を返却する
+// 集計日のランキングは1日を通して変わらないよう、当日の販売数・閲覧数は含めない（同じ集計日で再実行した場合は置き換える）
+func (pu ProductRankingUsecase) Aggregate(ctx context.Context, input ProductRankingAggregationInput) (int, error) {
+	calculator, err := entity.NewProductRankingCalculator(input.SalesWeight, entity.ProductRankingMaxLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	date := pu.timeUtils.DateJP(input.Date.Year(), input.Date.Month(), input.Date.Day())
+	categories, err := pu.categoryRepository.FindAll(pu.db, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, period := range enum.RankingPeriods {
+		activities, err := pu.findActivities(ctx, date.AddDate(0, 0, -period.Days()), period.Days())
+		if err != nil {
+			return count, err
+		}
+
+		rankings := calculator.Calculate(activities, categories, date, period)
+		err = pu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+			return pu.productRankingRepository.ReplaceByDate(tx, ctxt, date, period, rankings)
+		})
+		if err != nil {
+			return count, err
+		}
+		count += len(rankings)
+	}
+
+	return count, nil
+}
+
+// 最新の集計日のランキングを取得する
+// 価格・商品ステータス・在庫数は集計時点ではなく現在の値を返却し、ランキングの集計後に削除された商品は含めない
+func (pu ProductRankingUsecase) FindRanking(ctx context.Context, input ProductRankingInput) (entity.ProductRankingPage, error) {
+	if input.Period == "" {
+		input.Period = enum.RankingPeriodDaily
+	}
+	if !input.Period.IsValid() {
+		return entity.ProductRankingPage{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"集計期間はdaily・weekly・monthlyのいずれかを指定してください"})
+	}
+	if input.Limit == 0 {
+		input.Limit = entity.ProductRankingDefaultLimit
+	}
+
+	err := pu.validationUtils.Struct(validator.ValidationProductRankingCondition{Limit: input.Limit})
+	if err != nil {
+		return entity.ProductRankingPage{}, pu.validationUtils.CreateValidationMessages(err)
+	}
+
+	page := entity.ProductRankingPage{Period: input.Period, CategoryID: input.CategoryID, Items: []entity.ProductRankingItem{}}
+	date, ok, err := pu.productRankingRepository.FindLatestDate(pu.db, ctx, input.Period, pu.timeUtils.NowJP())
+	if err != nil || !ok {
+		return page, err
+	}
+	page.Date = date
+
+	rankings, err := pu.productRankingRepository.FindByDate(pu.db, ctx, date, input.Period, input.CategoryID, input.Limit)
+	if err != nil || len(rankings) == 0 {
+		return page, err
+	}
+
+	productIDs := make([]string, 0, len(rankings))
+	for _, ranking := range rankings {
+		productIDs = append(productIDs, ranking.ProductID)
+	}
+
+	products, err := pu.productRepository.FindByIDs(pu.db, ctx, productIDs, true)
+	if err != nil {
+		return entity.ProductRankingPage{}, err
+	}
+
+	productMap := make(map[string]entity.Product, len(products))
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	for _, ranking := range rankings {
+		if product, ok := productMap[ranking.ProductID]; ok {
+			page.Items = append(page.Items, entity.ProductRankingItem{Rank: ranking.Rank, Product: product})
+		}
+	}
+	return page, nil
+}
+
+// 引数fromの日付からdays日間の商品ごとの販売数と閲覧数を返却する（削除された商品は含めない）
+func (pu ProductRankingUsecase) findActivities(ctx context.Context, from time.Time, days int) ([]entity.ProductActivity, error) {
+	baskets, err := pu.purchaseHistoryRepository.FindBasketsBetween(pu.db, ctx, from, from.AddDate(0, 0, days))
+	if err != nil {
+		return []entity.ProductActivity{}, err
+	}
+
+	viewCounts, err := pu.productViewRepository.FindCounts(ctx, from, days)
+	if err != nil {
+		return []entity.ProductActivity{}, err
+	}
+
+	salesCounts := map[string]int{}
+	for _, basket := range baskets {
+		for _, item := range basket.Items {
+			salesCounts[item.ProductID] += item.Quantity
+		}
+	}
+
+	productIDs := make([]string, 0, len(salesCounts)+len(viewCounts))
+	for productID := range salesCounts {
+		productIDs = append(productIDs, productID)
+	}
+	for productID := range viewCounts {
+		if _, ok := salesCounts[productID]; !ok {
+			productIDs = append(productIDs, productID)
+		}
+	}
+
+	categoryIDs, err := pu.productRepository.FindCategoryIDs(pu.db, ctx, productIDs)
+	if err != nil {
+		return []entity.ProductActivity{}, err
+	}
+
+	activities := make([]entity.ProductActivity, 0, len(categoryIDs))
+	for _, productID := range productIDs {
+		categoryID, ok := categoryIDs[productID]
+		if !ok {
+			continue
+		}
+
+		activities = append(activities, entity.ProductActivity{
+			ProductID:  productID,
+			CategoryID: categoryID,
+			SalesCount: salesCounts[productID],
+			ViewCount:  viewCounts[productID],
+		})
+	}
+	return activities, nil
+}
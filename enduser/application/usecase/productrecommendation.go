@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	ProductRecommendationUsecase struct {
+		productRecommendationRepository repository.ProductRecommendationRepository
+		purchaseHistoryRepository       repository.PurchaseHistoryRepository
+		productRepository               repository.ProductRepository
+		timeUtils                       util.TimeUtils
+		db                              bun.IDB
+	}
+
+	// おすすめ商品の算出の入力値
+	ProductRecommendationComputationInput struct {
+		Days               int // 何日前までの注文履歴から算出するか
+		MaxNeighbors       int
+		MinCoPurchaseCount int
+	}
+
+	// おすすめ商品の算出結果
+	ProductRecommendationComputationResult struct {
+		BasketCount         int // 算出に使用した注文数
+		RecommendationCount int // 登録した一緒に購入されている商品の件数
+		BestsellerCount     int // 登録したカテゴリーごとの売れ筋商品の件数
+	}
+)
+
+func NewProductRecommendationUsecase(
+	productRecommendationRepository repository.ProductRecommendationRepository,
+	purchaseHistoryRepository repository.PurchaseHistoryRepository,
+	productRepository repository.ProductRepository,
+	timeUtils util.TimeUtils,
+	db bun.IDB,
+) ProductRecommendationUsecase {
+	return ProductRecommendationUsecase{
+		productRecommendationRepository: productRecommendationRepository,
+		purchaseHistoryRepository:       purchaseHistoryRepository,
+		productRepository:               productRepository,
+		timeUtils:                       timeUtils,
+		db:                              db,
+	}
+}
+
+// 期間内の注文履歴から商品ごとの一緒に購入されている商品とカテゴリーごとの売れ筋商品を算出し、既存のおすすめ商品を置き換える
+// 販売状況や在庫は日々変わるため、算出時には絞り込まず表示時に絞り込む
+func (pu ProductRecommendationUsecase) Compute(ctx context.Context, input ProductRecommendationComputationInput) (ProductRecommendationComputationResult, error) {
+	miner, err := entity.NewProductRecommendationMiner(input.MaxNeighbors, input.MinCoPurchaseCount)
+	if err != nil {
+		return ProductRecommendationComputationResult{}, err
+	}
+
+	now := pu.timeUtils.NowJP()
+	baskets, err := pu.purchaseHistoryRepository.FindBasketsBetween(pu.db, ctx, now.AddDate(0, 0, -input.Days), now)
+	if err != nil {
+		return ProductRecommendationComputationResult{}, err
+	}
+
+	recommendations := miner.Mine(baskets)
+	bestsellers := entity.RankCategoryBestsellers(baskets, entity.CategoryBestsellerLimit)
+
+	err = pu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		err := pu.productRecommendationRepository.ReplaceAll(tx, ctxt, recommendations)
+		if err != nil {
+			return err
+		}
+
+		return pu.productRecommendationRepository.ReplaceAllCategoryBestsellers(tx, ctxt, bestsellers)
+	})
+	if err != nil {
+		return ProductRecommendationComputationResult{}, err
+	}
+
+	return ProductRecommendationComputationResult{
+		BasketCount:         len(baskets),
+		RecommendationCount: len(recommendations),
+		BestsellerCount:     len(bestsellers),
+	}, nil
+}
+
+// 商品詳細に表示するおすすめ商品を取得する
+// 一緒に購入されている商品のうち販売中かつ在庫ありの商品を返却し、該当する商品が存在しない場合は同じカテゴリーの売れ筋商品を返却する
+// 商品が存在しない場合はfalseを返却する
+func (pu ProductRecommendationUsecase) FindRecommendations(ctx context.Context, productID string) (entity.ProductRecommendations, bool, error) {
+	product, ok, err := pu.productRepository.FindByID(pu.db, ctx, productID, false)
+	if err != nil || !ok {
+		return entity.ProductRecommendations{}, ok, err
+	}
+
+	recommendedIDs, err := pu.productRecommendationRepository.FindRecommendedProductIDs(pu.db, ctx, product.ID)
+	if err != nil {
+		return entity.ProductRecommendations{}, false, err
+	}
+
+	products, err := pu.findRecommendableProducts(ctx, product.ID, recommendedIDs)
+	if err != nil {
+		return entity.ProductRecommendations{}, false, err
+	}
+	if len(products) > 0 {
+		return entity.ProductRecommendations{Source: enum.ProductRecommendationSourceCoPurchase, Products: products}, true, nil
+	}
+
+	bestsellerIDs, err := pu.productRecommendationRepository.FindCategoryBestsellerIDs(pu.db, ctx, product.CategoryID)
+	if err != nil {
+		return entity.ProductRecommendations{}, false, err
+	}
+
+	products, err = pu.findRecommendableProducts(ctx, product.ID, bestsellerIDs)
+	if err != nil {
+		return entity.ProductRecommendations{}, false, err
+	}
+	return entity.ProductRecommendations{Source: enum.ProductRecommendationSourceCategoryBestseller, Products: products}, true, nil
+}
+
+// 商品ID配列の順序を保ったまま、表示中の商品を除いた販売中かつ在庫ありの商品を最大件数まで返却する
+func (pu ProductRecommendationUsecase) findRecommendableProducts(ctx context.Context, productID string, ids []string) ([]entity.Product, error) {
+	if len(ids) == 0 {
+		return []entity.Product{}, nil
+	}
+
+	products, err := pu.productRepository.FindByIDs(pu.db, ctx, ids, true)
+	if err != nil {
+		return []entity.Product{}, err
+	}
+
+	productMap := make(map[string]entity.Product, len(products))
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	recommendableProducts := make([]entity.Product, 0, entity.ProductRecommendationLimit)
+	for _, id := range ids {
+		product, ok := productMap[id]
+		if !ok || product.ID == productID || !product.IsRecommendable() {
+			continue
+		}
+
+		recommendableProducts = append(recommendableProducts, product)
+		if len(recommendableProducts) >= entity.ProductRecommendationLimit {
+			break
+		}
+	}
+	return recommendableProducts, nil
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/uptrace/bun"
+)
+
+type (
+	ProductTimelineUsecase struct {
+		productTimelineDomainService service.ProductTimelineDomainService
+		db                           bun.IDB
+	}
+
+	// 商品価格・商品セール価格・商品ステータスの変更を予約する際の入力値
+	ProductTimelineScheduleInput struct {
+		ProductID string
+		Type      enum.ProductTimelineType
+		Value     int // 税込価格または商品ステータス
+		StartDate time.Time
+		EndDate   time.Time
+	}
+)
+
+func NewProductTimelineUsecase(productTimelineDomainService service.ProductTimelineDomainService, db bun.IDB) ProductTimelineUsecase {
+	return ProductTimelineUsecase{
+		productTimelineDomainService: productTimelineDomainService,
+		db:                           db,
+	}
+}
+
+// 商品価格・商品セール価格・商品ステータスの変更を予約し、予約後の適用期間の一覧を返却する
+func (pu ProductTimelineUsecase) Schedule(ctx context.Context, input ProductTimelineScheduleInput) (entity.ProductTimeline, error) {
+	var timeline entity.ProductTimeline
+	err := pu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		var err error
+		timeline, err = pu.productTimelineDomainService.Schedule(tx, ctxt, input.ProductID, input.Type, input.Value, input.StartDate, input.EndDate)
+		return err
+	})
+	if err != nil {
+		return entity.ProductTimeline{}, err
+	}
+
+	return timeline, nil
+}
+
+// 商品の商品価格・商品セール価格・商品ステータスの適用期間の一覧を取得する
+func (pu ProductTimelineUsecase) FindTimelines(ctx context.Context, productID string) ([]entity.ProductTimeline, error) {
+	return pu.productTimelineDomainService.FindTimelines(pu.db, ctx, productID)
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/middleware"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+type RecentlyViewedUsecase struct {
+	recentlyViewedRepository repository.RecentlyViewedRepository
+	productViewRepository    repository.ProductViewRepository
+	productRepository        repository.ProductRepository
+	timeUtils                util.TimeUtils
+	logger                   echo.Logger
+	db                       bun.IDB
+}
+
+func NewRecentlyViewedUsecase(
+	recentlyViewedRepository repository.RecentlyViewedRepository,
+	productViewRepository repository.ProductViewRepository,
+	productRepository repository.ProductRepository,
+	timeUtils util.TimeUtils,
+	logger echo.Logger,
+	db bun.IDB,
+) RecentlyViewedUsecase {
+	return RecentlyViewedUsecase{
+		recentlyViewedRepository: recentlyViewedRepository,
+		productViewRepository:    productViewRepository,
+		productRepository:        productRepository,
+		timeUtils:                timeUtils,
+		logger:                   logger,
+		db:                       db,
+	}
+}
+
+// 商品の閲覧をランキング用の閲覧数と閲覧履歴に記録する
+// ログイン中の場合はアカウントの閲覧履歴に、ゲストの場合はセッションの閲覧履歴に記録し、ゲストの閲覧履歴の有効期限を伸ばしたクッキーを返却する
+// 閲覧数・閲覧履歴は商品詳細の表示に付随する処理のため、記録に失敗した場合もログ出力のみ行う
+func (ru RecentlyViewedUsecase) RecordView(ctx context.Context, productID string) *http.Cookie {
+	now := ru.timeUtils.NowJP()
+
+	err := ru.productViewRepository.Increment(ctx, productID, now)
+	if err != nil {
+		ru.logger.Error(fmt.Sprintf("商品の閲覧数の記録に失敗しました\n%+v", err))
+	}
+
+	if sessionAccount, ok := middleware.SessionAccountFromContext(ctx); ok {
+		err = ru.recentlyViewedRepository.AddByAccountID(ctx, sessionAccount.AccountID, productID, now)
+		if err != nil {
+			ru.logger.Error(fmt.Sprintf("閲覧履歴の記録に失敗しました\n%+v", err))
+		}
+		return nil
+	}
+
+	sessionID, ok := middleware.RecentlyViewedSessionIDFromContext(ctx)
+	var cookie http.Cookie
+	if ok {
+		cookie = util.CookieUtils.CreateCookie(entity.RecentlyViewedCookieName, sessionID, now.Add(entity.RecentlyViewedExpiration))
+	} else {
+		cookie, sessionID = entity.CreateRecentlyViewedSession(now)
+	}
+
+	err = ru.recentlyViewedRepository.AddBySessionID(ctx, sessionID, productID, now)
+	if err != nil {
+		ru.logger.Error(fmt.Sprintf("閲覧履歴の記録に失敗しました\n%+v", err))
+		return nil
+	}
+	return &cookie
+}
+
+// 閲覧履歴の商品配列を閲覧日時の降順で取得する（販売されていない商品は含めない）
+func (ru RecentlyViewedUsecase) FindProducts(ctx context.Context) ([]entity.Product, error) {
+	var productIDs []string
+	var err error
+	if sessionAccount, ok := middleware.SessionAccountFromContext(ctx); ok {
+		productIDs, err = ru.recentlyViewedRepository.FindByAccountID(ctx, sessionAccount.AccountID)
+	} else if sessionID, ok := middleware.RecentlyViewedSessionIDFromContext(ctx); ok {
+		productIDs, err = ru.recentlyViewedRepository.FindBySessionID(ctx, sessionID)
+	}
+	if err != nil {
+		return []entity.Product{}, err
+	}
+	if len(productIDs) == 0 {
+		return []entity.Product{}, nil
+	}
+
+	products, err := ru.productRepository.FindByIDs(ru.db, ctx, productIDs, true)
+	if err != nil {
+		return []entity.Product{}, err
+	}
+
+	productMap := make(map[string]entity.Product, len(products))
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	orderedProducts := make([]entity.Product, 0, len(productIDs))
+	for _, id := range productIDs {
+		if product, ok := productMap[id]; ok {
+			orderedProducts = append(orderedProducts, product)
+		}
+	}
+	return orderedProducts, nil
+}
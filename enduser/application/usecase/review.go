@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/validator"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/middleware"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+type (
+	ReviewUsecase struct {
+		reviewDomainService service.ReviewDomainService
+		reviewRepository    repository.ReviewRepository
+		validationUtils     util.ValidationUtils
+		logger              echo.Logger
+		db                  bun.IDB
+	}
+
+	// 商品のレビュー一覧の1ページと評価ごとのレビュー数
+	ProductReviews struct {
+		Page         entity.ReviewPage
+		Distribution entity.ReviewRatingDistribution
+	}
+
+	// レビューの投稿・編集時の入力値
+	ReviewInput struct {
+		Rating        int
+		Title         string
+		Body          string
+		Photos        []service.ReviewPhotoFile
+		ReplacePhotos bool // 編集時に写真を置き換える場合true（投稿時は無視する）
+	}
+)
+
+func NewReviewUsecase(
+	reviewDomainService service.ReviewDomainService,
+	reviewRepository repository.ReviewRepository,
+	validationUtils util.ValidationUtils,
+	logger echo.Logger,
+	db bun.IDB,
+) ReviewUsecase {
+	return ReviewUsecase{
+		reviewDomainService: reviewDomainService,
+		reviewRepository:    reviewRepository,
+		validationUtils:     validationUtils,
+		logger:              logger,
+		db:                  db,
+	}
+}
+
+// 商品の公開中のレビュー一覧の1ページと評価ごとのレビュー数を取得する
+// 並び順が空文字の場合は新しい順とする
+func (ru ReviewUsecase) FindProductReviews(ctx context.Context, productID string, sort enum.ReviewSort, page int, limit int) (ProductReviews, error) {
+	if sort == "" {
+		sort = enum.ReviewSortNewest
+	}
+	if !sort.IsValid() {
+		return ProductReviews{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"並び順が不正です"})
+	}
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = entity.ReviewDefaultLimit
+	}
+
+	err := ru.validationUtils.Struct(validator.ValidationReviewListCondition{Page: page, Limit: limit})
+	if err != nil {
+		return ProductReviews{}, ru.validationUtils.CreateValidationMessages(err)
+	}
+
+	reviewPage, err := ru.reviewRepository.FindPublishedByProductID(ru.db, ctx, productID, sort, page, limit)
+	if err != nil {
+		return ProductReviews{}, err
+	}
+
+	distribution, err := ru.reviewRepository.FindRatingDistribution(ru.db, ctx, productID)
+	if err != nil {
+		return ProductReviews{}, err
+	}
+
+	return ProductReviews{Page: reviewPage, Distribution: distribution}, nil
+}
+
+// ログイン中のアカウントが投稿したレビュー配列を取得する（確認待ち・非公開のレビューを含む）
+func (ru ReviewUsecase) FindMyReviews(ctx context.Context) ([]entity.Review, error) {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return ru.reviewRepository.FindByAccountID(ru.db, ctx, sessionAccount.AccountID)
+}
+
+// ログイン中のアカウントで商品のレビューを投稿する
+func (ru ReviewUsecase) PostReview(ctx context.Context, productID string, input ReviewInput) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return ru.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		_, err := ru.reviewDomainService.PostReview(tx, ctxt, sessionAccount.AccountID, productID, ru.toContent(input))
+		return err
+	})
+}
+
+// ログイン中のアカウントのレビューを編集する
+func (ru ReviewUsecase) EditReview(ctx context.Context, reviewID string, input ReviewInput) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	var removedPhotos []entity.ReviewPhoto
+	err := ru.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		var err error
+		removedPhotos, err = ru.reviewDomainService.EditReview(tx, ctxt, sessionAccount.AccountID, reviewID, ru.toContent(input), input.ReplacePhotos)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	ru.deletePhotos(removedPhotos)
+	return nil
+}
+
+// ログイン中のアカウントのレビューを削除する
+func (ru ReviewUsecase) DeleteReview(ctx context.Context, reviewID string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	var removedPhotos []entity.ReviewPhoto
+	err := ru.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		var err error
+		removedPhotos, err = ru.reviewDomainService.DeleteReview(tx, ctxt, sessionAccount.AccountID, reviewID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	ru.deletePhotos(removedPhotos)
+	return nil
+}
+
+// ログイン中のアカウントでレビューに参考になった・参考にならなかったの投票をする
+func (ru ReviewUsecase) Vote(ctx context.Context, reviewID string, isHelpful bool) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return ru.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		return ru.reviewDomainService.Vote(tx, ctxt, sessionAccount.AccountID, reviewID, isHelpful)
+	})
+}
+
+// ログイン中のアカウントのレビューへの投票を取り消す
+func (ru ReviewUsecase) Unvote(ctx context.Context, reviewID string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return ru.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		return ru.reviewDomainService.Unvote(tx, ctxt, sessionAccount.AccountID, reviewID)
+	})
+}
+
+// ログイン中のアカウントでレビューを不適切なレビューとして報告する
+func (ru ReviewUsecase) Report(ctx context.Context, reviewID string, reason string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return ru.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		return ru.reviewDomainService.Report(tx, ctxt, sessionAccount.AccountID, reviewID, reason)
+	})
+}
+
+// 確認待ちのレビューを投稿日時の古い順に最大limit件取得する
+func (ru ReviewUsecase) FindPendingReviews(ctx context.Context, limit int) ([]entity.ReviewListItem, error) {
+	return ru.reviewRepository.FindByStatus(ru.db, ctx, enum.ReviewStatusPending, limit)
+}
+
+// レビューの公開状態を変更する（管理者による確認）
+func (ru ReviewUsecase) Moderate(ctx context.Context, reviewID string, status enum.ReviewStatus) error {
+	return ru.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		return ru.reviewDomainService.Moderate(tx, ctxt, reviewID, status)
+	})
+}
+
+// 不要になった写真を画像ストレージから削除する
+// レビューの更新は完了しているため、削除に失敗した場合もログ出力のみ行う
+func (ru ReviewUsecase) deletePhotos(photos []entity.ReviewPhoto) {
+	err := ru.reviewDomainService.DeletePhotos(photos)
+	if err != nil {
+		ru.logger.Error(fmt.Sprintf("レビューの写真の削除に失敗しました\n%+v", err))
+	}
+}
+
+func (ru ReviewUsecase) toContent(input ReviewInput) service.ReviewContent {
+	return service.ReviewContent{
+		Rating: input.Rating,
+		Title:  input.Title,
+		Body:   input.Body,
+		Photos: input.Photos,
+	}
+}
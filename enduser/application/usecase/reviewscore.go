@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	ReviewScoreUsecase struct {
+		reviewRepository      repository.ReviewRepository
+		reviewScoreRepository repository.ReviewScoreRepository
+		timeUtils             util.TimeUtils
+		db                    bun.IDB
+	}
+
+	// レビュー点数の集計の入力値
+	ReviewScoreAggregationInput struct {
+		From       time.Time // 集計する最初の日付
+		To         time.Time // 集計する最後の日付
+		Method     enum.ReviewScoreMethod
+		PriorCount int
+	}
+)
+
+func NewReviewScoreUsecase(
+	reviewRepository repository.ReviewRepository,
+	reviewScoreRepository repository.ReviewScoreRepository,
+	timeUtils util.TimeUtils,
+	db bun.IDB,
+) ReviewScoreUsecase {
+	return ReviewScoreUsecase{
+		reviewRepository:      reviewRepository,
+		reviewScoreRepository: reviewScoreRepository,
+		timeUtils:             timeUtils,
+		db:                    db,
+	}
+}
+
+// 期間の日付ごとに公開中のレビューから商品のレビュー点数を算出して登録し、集計した日数を返却する
+// 日付のレビュー点数は前日までに投稿されたレビューから算出し、既存のレビュー点数は置き換える（同じ日付で再実行しても結果は変わらない）
+// 過去の日付を集計する場合も、レビューの公開状態は現在の公開状態を使用する
+func (ru ReviewScoreUsecase) Aggregate(ctx context.Context, input ReviewScoreAggregationInput) (int, error) {
+	calculator, err := entity.NewReviewScoreCalculator(input.Method, input.PriorCount)
+	if err != nil {
+		return 0, err
+	}
+
+	from := ru.timeUtils.DateJP(input.From.Year(), input.From.Month(), input.From.Day())
+	to := ru.timeUtils.DateJP(input.To.Year(), input.To.Month(), input.To.Day())
+
+	var count int
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		err = ru.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+			summaries, err := ru.reviewRepository.FindRatingSummaries(tx, ctxt, date)
+			if err != nil {
+				return err
+			}
+
+			return ru.reviewScoreRepository.ReplaceByDate(tx, ctxt, date, calculator.Calculate(summaries, date))
+		})
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type SearchSuggestionUsecase struct {
+	searchSuggestionRepository repository.SearchSuggestionRepository
+	productRepository          repository.ProductRepository
+	categoryRepository         repository.CategoryRepository
+	timeUtils                  util.TimeUtils
+	db                         bun.IDB
+}
+
+func NewSearchSuggestionUsecase(
+	searchSuggestionRepository repository.SearchSuggestionRepository,
+	productRepository repository.ProductRepository,
+	categoryRepository repository.CategoryRepository,
+	timeUtils util.TimeUtils,
+	db bun.IDB,
+) SearchSuggestionUsecase {
+	return SearchSuggestionUsecase{
+		searchSuggestionRepository: searchSuggestionRepository,
+		productRepository:          productRepository,
+		categoryRepository:         categoryRepository,
+		timeUtils:                  timeUtils,
+		db:                         db,
+	}
+}
+
+// 入力文字列に前方一致する検索候補を取得する
+// 最近よく検索されたキーワードを優先し、商品名・カテゴリー名の検索候補を続ける
+// 入力文字列は全角・半角、カタカナ・ひらがなを区別せず、英字の読みのひらがなでも一致する（例「てぃーしゃつ」→「Tシャツ」）
+func (su SearchSuggestionUsecase) Suggest(ctx context.Context, input string) ([]entity.SearchSuggestion, error) {
+	prefix := util.TextUtils.NormalizeForSearch(input)
+	if prefix == "" || len([]rune(prefix)) > entity.SearchSuggestionMaxPrefixLength {
+		return []entity.SearchSuggestion{}, nil
+	}
+
+	popularQueries, err := su.searchSuggestionRepository.FindPopularQueries(ctx, su.timeUtils.NowJP(), entity.SearchSuggestionPopularDays, entity.SearchSuggestionMaxCount*10)
+	if err != nil {
+		return []entity.SearchSuggestion{}, err
+	}
+
+	matchedQueries := []entity.SearchSuggestion{}
+	for _, query := range popularQueries {
+		if query.HasPrefix(prefix) {
+			matchedQueries = append(matchedQueries, query)
+		}
+	}
+
+	names, err := su.searchSuggestionRepository.FindNamesByPrefix(ctx, prefix, entity.SearchSuggestionMaxCount)
+	if err != nil {
+		return []entity.SearchSuggestion{}, err
+	}
+
+	return entity.MergeSearchSuggestions(matchedQueries, names), nil
+}
+
+// すべての商品名・カテゴリー名を検索候補に登録し、登録件数を返却する
+func (su SearchSuggestionUsecase) RebuildNames(ctx context.Context) (int, error) {
+	documents, err := su.productRepository.FindSearchDocuments(su.db, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	categories, err := su.categoryRepository.FindAll(su.db, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	suggestions := make([]entity.SearchSuggestion, 0, len(documents)+len(categories))
+	for _, document := range documents {
+		suggestions = append(suggestions, entity.SearchSuggestion{Text: document.Name, Type: enum.SearchSuggestionTypeProduct})
+	}
+	for _, category := range categories {
+		suggestions = append(suggestions, entity.SearchSuggestion{Text: category.Name, Type: enum.SearchSuggestionTypeCategory})
+	}
+
+	err = su.searchSuggestionRepository.ReplaceNames(ctx, suggestions)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(suggestions), nil
+}
@@ -66,8 +66,10 @@ func (sau SessionAccountUsecase) LoginByEmailAndPassword(ctx context.Context, em
 
 		// セッションアカウントを作成する
 		sessionCart, existsSessionCart := middleware.SessionCartFromContext(ctx)
+		sessionWishlist, existsSessionWishlist := middleware.SessionWishlistFromContext(ctx)
+		recentlyViewedSessionID, _ := middleware.RecentlyViewedSessionIDFromContext(ctx)
 		var sessionAccount entity.SessionAccount
-		sessionAccountCookie, sessionAccount = entity.CreateSessionAccount(account, sessionCart, existsSessionCart, tx, ctxt)
+		sessionAccountCookie, sessionAccount = entity.CreateSessionAccount(account, sessionCart, existsSessionCart, sessionWishlist, existsSessionWishlist, recentlyViewedSessionID, tx, ctxt)
 		return sau.sessionAccountRepository.Insert(ctxt, &sessionAccount, entity.SessionAccountExpiration, sau.domainEventPublisher)
 	})
 
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/uptrace/bun"
+)
+
+type (
+	StockMovementUsecase struct {
+		stockDomainService      service.StockDomainService
+		productStockRepository  repository.ProductStockRepository
+		stockMovementRepository repository.StockMovementRepository
+		db                      bun.IDB
+	}
+
+	// ショップスタッフによる在庫移動（入荷・返品・手動調整）の内容
+	ManualStockMovementInput struct {
+		ProductID      string
+		SKUID          string
+		Type           enum.StockMovementType
+		Quantity       int // 在庫数の増減
+		Reason         string
+		StaffAccountID string
+		OrderID        string // 返品の場合の返品元の注文ID
+	}
+
+	// 在庫台帳と現在の在庫数の照合結果
+	StockReconciliationReport struct {
+		CheckedCount int // 照合した商品・SKUの件数
+		Drifts       []entity.StockDrift
+	}
+)
+
+func NewStockMovementUsecase(
+	stockDomainService service.StockDomainService,
+	productStockRepository repository.ProductStockRepository,
+	stockMovementRepository repository.StockMovementRepository,
+	db bun.IDB,
+) StockMovementUsecase {
+	return StockMovementUsecase{
+		stockDomainService:      stockDomainService,
+		productStockRepository:  productStockRepository,
+		stockMovementRepository: stockMovementRepository,
+		db:                      db,
+	}
+}
+
+// ショップスタッフによる入荷・返品・手動調整で在庫数を変更し、在庫台帳に記録する
+// 返品は返品元の注文を、それ以外は操作したショップスタッフのアカウントを参照先とする
+func (su StockMovementUsecase) RecordManual(ctx context.Context, input ManualStockMovementInput) (entity.StockMovement, error) {
+	if !input.Type.IsManual() {
+		return entity.StockMovement{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{fmt.Sprintf("在庫移動の種別（%s）は手動で記録できません", input.Type)})
+	}
+	if input.Reason == "" || input.StaffAccountID == "" {
+		return entity.StockMovement{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"在庫移動の理由と操作したショップスタッフのアカウントIDは必須です"})
+	}
+	if input.Type == enum.StockMovementTypeReturn && input.OrderID == "" {
+		return entity.StockMovement{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"返品の場合は返品元の注文IDは必須です"})
+	}
+
+	referenceType, referenceID := enum.StockMovementReferenceStaffAccount, input.StaffAccountID
+	if input.Type == enum.StockMovementTypeReturn {
+		referenceType, referenceID = enum.StockMovementReferenceOrder, input.OrderID
+	}
+
+	var movement entity.StockMovement
+	err := su.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		var ok bool
+		var err error
+		movement, ok, err = su.stockDomainService.Move(tx, ctxt, service.StockMovementInput{
+			ProductID:     input.ProductID,
+			SKUID:         input.SKUID,
+			Type:          input.Type,
+			Quantity:      input.Quantity,
+			Reason:        input.Reason,
+			ReferenceType: referenceType,
+			ReferenceID:   referenceID,
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return share.CreateOriginalError(share.ErrorCodeOther, []string{"在庫数が不足しているため在庫数を減らせません"})
+		}
+		return nil
+	})
+	return movement, err
+}
+
+// 在庫台帳の在庫数の増減を合計して在庫数を再計算し、現在の在庫数との差異を返却する
+// 照合中の在庫移動で差異が検出されないよう、在庫台帳と在庫数を同じスナップショットから取得する
+func (su StockMovementUsecase) Reconcile(ctx context.Context) (StockReconciliationReport, error) {
+	var report StockReconciliationReport
+	err := su.db.RunInTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, func(ctxt context.Context, tx bun.Tx) error {
+		levels, err := su.productStockRepository.FindStockLevels(tx, ctxt)
+		if err != nil {
+			return err
+		}
+
+		balances, err := su.stockMovementRepository.FindBalances(tx, ctxt)
+		if err != nil {
+			return err
+		}
+
+		report = StockReconciliationReport{
+			CheckedCount: len(levels),
+			Drifts:       entity.ReconcileStock(levels, balances),
+		}
+		return nil
+	})
+	return report, err
+}
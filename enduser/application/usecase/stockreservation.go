@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/middleware"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+type StockReservationUsecase struct {
+	stockReservationDomainService service.StockReservationDomainService
+	stockReservationRepository    repository.StockReservationRepository
+	cartRepository                repository.CartRepository
+	productRepository             repository.ProductRepository
+	timeUtils                     util.TimeUtils
+	logger                        echo.Logger
+	db                            bun.IDB
+}
+
+const stockReservationReleaseBatchSize = 100 // 1回の実行で解放する有効期限切れの在庫引当の最大件数
+
+var errStockReservationNotFound = share.CreateOriginalError(share.ErrorCodeOther, []string{"在庫引当が見つかりません"})
+
+func NewStockReservationUsecase(
+	stockReservationDomainService service.StockReservationDomainService,
+	stockReservationRepository repository.StockReservationRepository,
+	cartRepository repository.CartRepository,
+	productRepository repository.ProductRepository,
+	timeUtils util.TimeUtils,
+	logger echo.Logger,
+	db bun.IDB,
+) StockReservationUsecase {
+	return StockReservationUsecase{
+		stockReservationDomainService: stockReservationDomainService,
+		stockReservationRepository:    stockReservationRepository,
+		cartRepository:                cartRepository,
+		productRepository:             productRepository,
+		timeUtils:                     timeUtils,
+		logger:                        logger,
+		db:                            db,
+	}
+}
+
+// 購入手続きを開始し、ログイン中のアカウントのカート内の商品の在庫を引き当てる
+// 購入手続きをやり直した場合に在庫を二重に確保しないよう、アカウントの引当中の在庫引当は解放してから引き当てる
+func (su StockReservationUsecase) StartCheckout(ctx context.Context) (entity.StockReservation, error) {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	var reservation entity.StockReservation
+	err := su.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		reservedReservations, err := su.stockReservationRepository.FindReservedByAccountIDForUpdate(tx, ctxt, sessionAccount.AccountID)
+		if err != nil {
+			return err
+		}
+		for _, reserved := range reservedReservations {
+			_, err = su.stockReservationDomainService.Release(tx, ctxt, reserved)
+			if err != nil {
+				return err
+			}
+		}
+
+		cart, ok, err := su.cartRepository.FindByAccountID(tx, ctxt, sessionAccount.AccountID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.WithStack(errCartNotFound)
+		}
+
+		products, err := su.productRepository.FindByIDs(tx, ctxt, cart.ProductIDs(), false)
+		if err != nil {
+			return err
+		}
+
+		reservation, err = su.stockReservationDomainService.Reserve(tx, ctxt, sessionAccount.AccountID, cart, products)
+		return err
+	})
+	return reservation, err
+}
+
+// ログイン中のアカウントの在庫引当をキャンセルし、在庫を戻す
+func (su StockReservationUsecase) Cancel(ctx context.Context, reservationID string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return su.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		reservation, ok, err := su.stockReservationRepository.FindByIDForUpdate(tx, ctxt, reservationID)
+		if err != nil {
+			return err
+		}
+		if !ok || reservation.AccountID != sessionAccount.AccountID {
+			return errStockReservationNotFound
+		}
+
+		_, err = su.stockReservationDomainService.Release(tx, ctxt, reservation)
+		return err
+	})
+}
+
+// 決済の完了時に在庫引当を確定し、差し引いた在庫を確定した引き落としにする
+// 購入した商品は同じトランザクションでアカウントのカートから取り除く
+// 決済処理（決済システムの導入前はcomplete-stock-reservationバッチ）から決済が完了した注文の注文IDを指定して呼び出す
+func (su StockReservationUsecase) Complete(ctx context.Context, reservationID string, orderID string) (entity.StockReservation, error) {
+	var reservation entity.StockReservation
+	err := su.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		var err error
+		reservation, err = su.stockReservationDomainService.Commit(tx, ctxt, reservationID, orderID)
+		if err != nil {
+			return err
+		}
+
+		cart, ok, err := su.cartRepository.FindByAccountID(tx, ctxt, reservation.AccountID)
+		if err != nil || !ok {
+			return err
+		}
+
+		cart.RemovePurchasedProducts(reservation.Items)
+		return su.cartRepository.Update(tx, ctxt, cart)
+	})
+	return reservation, err
+}
+
+// 有効期限を過ぎた引当中の在庫引当を解放し、解放した件数を返却する
+// 在庫引当ごとにトランザクションを分け、取得から解放までの間に確定された在庫引当は解放しない
+func (su StockReservationUsecase) ReleaseExpired(ctx context.Context) (int, error) {
+	ids, err := su.stockReservationRepository.FindExpiredIDs(su.db, ctx, su.timeUtils.NowJP(), stockReservationReleaseBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, id := range ids {
+		var released bool
+		err = su.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+			reservation, ok, err := su.stockReservationRepository.FindByIDForUpdate(tx, ctxt, id)
+			if err != nil || !ok || !reservation.IsExpired(su.timeUtils.NowJP()) {
+				return err
+			}
+
+			_, err = su.stockReservationDomainService.Release(tx, ctxt, reservation)
+			released = err == nil
+			return err
+		})
+		if err != nil {
+			// 1件の解放に失敗しても他の在庫引当の解放は続ける（次回の実行で再度解放する）
+			su.logger.Error(fmt.Sprintf("在庫引当（%s）の解放に失敗しました\n%+v", id, err))
+			continue
+		}
+		if released {
+			count++
+		}
+	}
+
+	return count, nil
+}
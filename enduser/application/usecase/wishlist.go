@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/middleware"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	WishlistUsecase struct {
+		wishlistDomainService     service.WishlistDomainService
+		wishlistRepository        repository.WishlistRepository
+		sessionWishlistRepository repository.SessionWishlistRepository
+		productRepository         repository.ProductRepository
+		timeUtils                 util.TimeUtils
+		db                        bun.IDB
+	}
+
+	// お気に入りリスト画面に表示するお気に入りリスト
+	// Productsには現在の価格・在庫数を含む商品集約が格納される（存在しない商品は含まれない）
+	WishlistDetail struct {
+		Wishlist entity.Wishlist
+		Products []entity.Product
+	}
+)
+
+var (
+	errWishlistNotFound        = share.CreateOriginalError(share.ErrorCodeOther, []string{"お気に入りリストが見つかりません"})
+	errWishlistProductNotFound = share.CreateOriginalError(share.ErrorCodeOther, []string{"商品が見つかりません"})
+)
+
+func NewWishlistUsecase(
+	wishlistDomainService service.WishlistDomainService,
+	wishlistRepository repository.WishlistRepository,
+	sessionWishlistRepository repository.SessionWishlistRepository,
+	productRepository repository.ProductRepository,
+	timeUtils util.TimeUtils,
+	db bun.IDB,
+) WishlistUsecase {
+	return WishlistUsecase{
+		wishlistDomainService:     wishlistDomainService,
+		wishlistRepository:        wishlistRepository,
+		sessionWishlistRepository: sessionWishlistRepository,
+		productRepository:         productRepository,
+		timeUtils:                 timeUtils,
+		db:                        db,
+	}
+}
+
+// ログイン中のアカウントのお気に入りリスト配列を取得する
+func (wu WishlistUsecase) FindWishlists(ctx context.Context) ([]WishlistDetail, error) {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	wishlists, err := wu.wishlistRepository.FindByAccountID(wu.db, ctx, sessionAccount.AccountID)
+	if err != nil {
+		return []WishlistDetail{}, err
+	}
+
+	// すべてのお気に入りリストの商品をまとめて取得する
+	productIDs := []string{}
+	for _, wishlist := range wishlists {
+		productIDs = append(productIDs, wishlist.ProductIDs()...)
+	}
+	products, err := wu.findProducts(ctx, productIDs)
+	if err != nil {
+		return []WishlistDetail{}, err
+	}
+
+	wishlistDetails := make([]WishlistDetail, 0, len(wishlists))
+	for _, wishlist := range wishlists {
+		wishlistDetails = append(wishlistDetails, WishlistDetail{
+			Wishlist: wishlist,
+			Products: products,
+		})
+	}
+	return wishlistDetails, nil
+}
+
+// ログイン中のアカウントの名前付きお気に入りリストを作成する
+func (wu WishlistUsecase) CreateWishlist(ctx context.Context, name string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return wu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		wishlist, err := wu.wishlistDomainService.CreateWishlist(tx, ctxt, sessionAccount.AccountID, name)
+		if err != nil {
+			return err
+		}
+
+		return wu.wishlistRepository.Insert(tx, ctxt, wishlist)
+	})
+}
+
+// ログイン中のアカウントのお気に入りリストを削除する
+// 既定のお気に入りリストは削除できない
+func (wu WishlistUsecase) DeleteWishlist(ctx context.Context, wishlistID string) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return wu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		wishlist, ok, err := wu.wishlistRepository.FindByIDAndAccountID(tx, ctxt, wishlistID, sessionAccount.AccountID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errWishlistNotFound
+		}
+
+		if wishlist.IsDefault {
+			return share.CreateOriginalError(share.ErrorCodeOther, []string{"既定のお気に入りリストは削除できません"})
+		}
+
+		return wu.wishlistRepository.Delete(tx, ctxt, wishlist)
+	})
+}
+
+// ログイン中のアカウントのお気に入りリストに商品を追加する
+func (wu WishlistUsecase) AddProduct(ctx context.Context, wishlistID string, productID string) error {
+	return wu.updateWishlist(ctx, wishlistID, func(ctxt context.Context, tx bun.Tx, wishlist *entity.Wishlist) error {
+		products, err := wu.productRepository.FindByIDs(tx, ctxt, []string{productID}, false)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			return errWishlistProductNotFound
+		}
+
+		return wishlist.AddProduct(productID, wu.timeUtils.NowJP())
+	})
+}
+
+// ログイン中のアカウントのお気に入りリストから商品を削除する
+func (wu WishlistUsecase) RemoveProduct(ctx context.Context, wishlistID string, productID string) error {
+	return wu.updateWishlist(ctx, wishlistID, func(ctxt context.Context, tx bun.Tx, wishlist *entity.Wishlist) error {
+		return wishlist.RemoveProduct(productID)
+	})
+}
+
+// ログイン中のアカウントのお気に入りリストを公開し、共有トークンを返却する
+func (wu WishlistUsecase) Publish(ctx context.Context, wishlistID string) (string, error) {
+	var shareToken string
+	err := wu.updateWishlist(ctx, wishlistID, func(ctxt context.Context, tx bun.Tx, wishlist *entity.Wishlist) error {
+		shareToken = wishlist.Publish()
+		return nil
+	})
+
+	return shareToken, err
+}
+
+// ログイン中のアカウントのお気に入りリストを非公開にする
+func (wu WishlistUsecase) Unpublish(ctx context.Context, wishlistID string) error {
+	return wu.updateWishlist(ctx, wishlistID, func(ctxt context.Context, tx bun.Tx, wishlist *entity.Wishlist) error {
+		wishlist.Unpublish()
+		return nil
+	})
+}
+
+// 共有トークンに一致する公開中のお気に入りリストを取得する
+func (wu WishlistUsecase) FindSharedWishlist(ctx context.Context, shareToken string) (WishlistDetail, bool, error) {
+	wishlist, ok, err := wu.wishlistRepository.FindByShareToken(wu.db, ctx, shareToken)
+	if err != nil || !ok {
+		return WishlistDetail{}, false, err
+	}
+
+	products, err := wu.findProducts(ctx, wishlist.ProductIDs())
+	if err != nil {
+		return WishlistDetail{}, false, err
+	}
+
+	return WishlistDetail{Wishlist: wishlist, Products: products}, true, nil
+}
+
+// ゲストのセッションお気に入りリストの商品配列を取得する
+func (wu WishlistUsecase) FindSessionWishlistProducts(ctx context.Context) ([]entity.Product, error) {
+	sessionWishlist, ok := middleware.SessionWishlistFromContext(ctx)
+	if !ok {
+		return []entity.Product{}, nil
+	}
+
+	return wu.findProducts(ctx, sessionWishlist.ProductIDs)
+}
+
+// ゲストのセッションお気に入りリストに商品を追加する
+// セッションお気に入りリストが存在しない場合は作成し、セッションお気に入りリストのクッキーを返却する
+func (wu WishlistUsecase) AddProductToSessionWishlist(ctx context.Context, productID string) (*http.Cookie, error) {
+	products, err := wu.productRepository.FindByIDs(wu.db, ctx, []string{productID}, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, errWishlistProductNotFound
+	}
+
+	var cookie *http.Cookie
+	sessionWishlist, ok := middleware.SessionWishlistFromContext(ctx)
+	if !ok {
+		var newCookie http.Cookie
+		newCookie, sessionWishlist = entity.CreateSessionWishlist()
+		cookie = &newCookie
+	}
+
+	err = sessionWishlist.AddProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = wu.sessionWishlistRepository.Save(ctx, sessionWishlist, entity.SessionWishlistExpiration)
+	if err != nil {
+		return nil, err
+	}
+
+	return cookie, nil
+}
+
+// ゲストのセッションお気に入りリストから商品を削除する
+func (wu WishlistUsecase) RemoveProductFromSessionWishlist(ctx context.Context, productID string) error {
+	sessionWishlist, ok := middleware.SessionWishlistFromContext(ctx)
+	if !ok {
+		return errWishlistNotFound
+	}
+
+	err := sessionWishlist.RemoveProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	return wu.sessionWishlistRepository.Save(ctx, sessionWishlist, entity.SessionWishlistExpiration)
+}
+
+// ログイン中のアカウントのお気に入りリストを取得し、引数updateで変更したお気に入りリストを更新する
+func (wu WishlistUsecase) updateWishlist(ctx context.Context, wishlistID string, update func(ctxt context.Context, tx bun.Tx, wishlist *entity.Wishlist) error) error {
+	sessionAccount, _ := middleware.SessionAccountFromContext(ctx)
+
+	return wu.db.RunInTx(ctx, nil, func(ctxt context.Context, tx bun.Tx) error {
+		wishlist, ok, err := wu.wishlistRepository.FindByIDAndAccountID(tx, ctxt, wishlistID, sessionAccount.AccountID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errWishlistNotFound
+		}
+
+		err = update(ctxt, tx, &wishlist)
+		if err != nil {
+			return err
+		}
+
+		return wu.wishlistRepository.Update(tx, ctxt, wishlist)
+	})
+}
+
+// 商品ID配列に一致する商品配列を取得する（商品IDが空の場合はDBに問い合わせない）
+func (wu WishlistUsecase) findProducts(ctx context.Context, productIDs []string) ([]entity.Product, error) {
+	if len(productIDs) == 0 {
+		return []entity.Product{}, nil
+	}
+
+	return wu.productRepository.FindByIDs(wu.db, ctx, productIDs, true)
+}
@@ -0,0 +1,595 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/config"
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/registory"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/dig"
+)
+
+// 定期実行するバッチ処理・運用コマンド
+// 例）go run enduser/batch/main.go abandoned-cart-reminder --period 24h
+// 例）go run enduser/batch/main.go generate-product-image-variants --interval 10s（常駐して10秒ごとに実行する）
+// 例）go run enduser/batch/main.go requeue-product-image-variants（生成に失敗し続けて除外された商品画像を再び生成の対象にする）
+// 例）go run enduser/batch/main.go aggregate-review-scores --from 2024-04-01 --to 2024-04-10（期間のレビュー点数を再集計する）
+// 例）go run enduser/batch/main.go compute-product-recommendations --days 90（直近90日の注文履歴からおすすめ商品を算出する）
+// 例）go run enduser/batch/main.go aggregate-product-rankings（毎日0時過ぎに実行し、前日までの販売数・閲覧数からランキングを集計する）
+// 例）go run enduser/batch/main.go release-expired-stock-reservations --interval 1m（常駐して1分ごとに有効期限切れの在庫引当を解放する）
+// 例）go run enduser/batch/main.go complete-stock-reservation --reservation-id 1 --order-id 1（決済が完了した在庫引当を確定する）
+// 例）go run enduser/batch/main.go record-stock-movement --product-id 1 --type receipt --quantity 20 --reason 入荷 --staff-account-id 1
+// 例）go run enduser/batch/main.go send-back-in-stock-notifications --interval 5m --batch-size 100 --batch-interval 1s（常駐して5分ごとに入荷通知メールを1秒あたり100件までに制限して送信する）
+// 例）go run enduser/batch/main.go schedule-product-timeline --product-id 1 --type sale_price --value 800 --start 2024-04-08 --end 2024-04-14
+func main() {
+	err := config.SetupEnv()
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+
+	container, err := registory.NewContainer()
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+
+	app := cli.App{
+		Name:     "batch",
+		Usage:    "enduser batch",
+		Commands: newBatchCommands(container),
+	}
+
+	if err = app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func exit(err error) error {
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("%+v", err), 1)
+	} else {
+		return nil
+	}
+}
+
+func newBatchCommands(container *dig.Container) []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "abandoned-cart-reminder",
+			Usage: "send reminder emails to accounts with abandoned carts",
+			Flags: []cli.Flag{
+				&cli.DurationFlag{
+					Name:    "period",
+					Usage:   "period since the cart was last updated",
+					EnvVars: []string{"ABANDONED_CART_REMINDER_PERIOD"},
+					Value:   24 * time.Hour,
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(cartReminderUsecase usecase.CartReminderUsecase) error {
+					count, err := cartReminderUsecase.SendAbandonedCartReminders(ctx.Context, ctx.Duration("period"))
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("カート放置リマインドメールを%d件送信しました\n", count)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "rebuild-product-search-index",
+			Usage: "register all products to the product search index",
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productUsecase usecase.ProductUsecase) error {
+					count, err := productUsecase.RebuildSearchIndex(ctx.Context)
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("商品検索インデックスに%d件登録しました\n", count)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "rebuild-search-suggestion",
+			Usage: "register all product and category names as search suggestions",
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(searchSuggestionUsecase usecase.SearchSuggestionUsecase) error {
+					count, err := searchSuggestionUsecase.RebuildNames(ctx.Context)
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("検索候補に%d件登録しました\n", count)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "generate-product-image-variants",
+			Usage: "generate resized and webp variants of product images that have no variants",
+			Flags: []cli.Flag{
+				&cli.DurationFlag{
+					Name:  "interval",
+					Usage: "run repeatedly at this interval until SIGINT/SIGTERM (0 runs once)",
+					Value: 0,
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productImageUsecase usecase.ProductImageUsecase) error {
+					sigCtx, stop := signal.NotifyContext(ctx.Context, syscall.SIGINT, syscall.SIGTERM)
+					defer stop()
+
+					interval := ctx.Duration("interval")
+					for {
+						count, err := productImageUsecase.GenerateVariants(sigCtx)
+						if err != nil {
+							return err
+						}
+						fmt.Printf("商品画像%d件の派生画像を生成しました\n", count)
+
+						if interval <= 0 {
+							return nil
+						}
+						select {
+						case <-sigCtx.Done():
+							return nil
+						case <-time.After(interval):
+						}
+					}
+				}))
+			},
+		},
+		{
+			Name:  "requeue-product-image-variants",
+			Usage: "requeue product images whose variant generation failed too many times",
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productImageUsecase usecase.ProductImageUsecase) error {
+					count, err := productImageUsecase.RequeueDeadLetterVariants(ctx.Context)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("商品画像%d件を派生画像の生成の対象に戻しました\n", count)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "schedule-product-timeline",
+			Usage: "schedule a future price, sale price or status of a product",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "product-id", Required: true},
+				&cli.StringFlag{Name: "type", Usage: "price, sale_price or status", Required: true},
+				&cli.IntFlag{Name: "value", Usage: "tax inclusive price or product status", Required: true},
+				&cli.StringFlag{Name: "start", Usage: "effective start date (YYYY-MM-DD)", Required: true},
+				&cli.StringFlag{Name: "end", Usage: "effective end date (YYYY-MM-DD)", Required: true},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productTimelineUsecase usecase.ProductTimelineUsecase) error {
+					startDate, err := time.Parse(time.DateOnly, ctx.String("start"))
+					if err != nil {
+						return err
+					}
+					endDate, err := time.Parse(time.DateOnly, ctx.String("end"))
+					if err != nil {
+						return err
+					}
+
+					timeline, err := productTimelineUsecase.Schedule(ctx.Context, usecase.ProductTimelineScheduleInput{
+						ProductID: ctx.String("product-id"),
+						Type:      enum.ProductTimelineType(ctx.String("type")),
+						Value:     ctx.Int("value"),
+						StartDate: startDate,
+						EndDate:   endDate,
+					})
+					if err != nil {
+						return err
+					}
+
+					printProductTimeline(timeline)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "product-timeline",
+			Usage: "show the price, sale price and status timelines of a product",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "product-id", Required: true},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productTimelineUsecase usecase.ProductTimelineUsecase) error {
+					timelines, err := productTimelineUsecase.FindTimelines(ctx.Context, ctx.String("product-id"))
+					if err != nil {
+						return err
+					}
+
+					for _, timeline := range timelines {
+						printProductTimeline(timeline)
+					}
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "pending-reviews",
+			Usage: "list reviews waiting for moderation",
+			Flags: []cli.Flag{
+				&cli.IntFlag{Name: "limit", Value: 100},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(reviewUsecase usecase.ReviewUsecase) error {
+					reviews, err := reviewUsecase.FindPendingReviews(ctx.Context, ctx.Int("limit"))
+					if err != nil {
+						return err
+					}
+
+					for _, review := range reviews {
+						fmt.Printf("%s\t%s\t%s\t%s\tNGワード: %s\t報告数: %d\n", review.ID, review.ProductID, review.ReviewNickname, review.Title, strings.Join(review.FlaggedWords, ", "), review.ReportCount)
+					}
+					fmt.Printf("確認待ちのレビューが%d件あります\n", len(reviews))
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "moderate-review",
+			Usage: "publish or reject a review",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "id", Required: true},
+				&cli.StringFlag{Name: "status", Usage: "published or rejected", Required: true},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(reviewUsecase usecase.ReviewUsecase) error {
+					err := reviewUsecase.Moderate(ctx.Context, ctx.String("id"), enum.ReviewStatus(ctx.String("status")))
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("レビュー（%s）の公開状態を%sに変更しました\n", ctx.String("id"), ctx.String("status"))
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "aggregate-review-scores",
+			Usage: "compute daily product review scores from published reviews (defaults to today)",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "from", Usage: "first date to aggregate (YYYY-MM-DD, default today)"},
+				&cli.StringFlag{Name: "to", Usage: "last date to aggregate (YYYY-MM-DD, default from)"},
+				&cli.StringFlag{
+					Name:    "method",
+					Usage:   "average or bayesian",
+					EnvVars: []string{"REVIEW_SCORE_METHOD"},
+					Value:   string(enum.ReviewScoreMethodBayesian),
+				},
+				&cli.IntFlag{
+					Name:    "prior-count",
+					Usage:   "number of reviews with the overall average rating added to each product (bayesian only)",
+					EnvVars: []string{"REVIEW_SCORE_PRIOR_COUNT"},
+					Value:   5,
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(reviewScoreUsecase usecase.ReviewScoreUsecase, timeUtils util.TimeUtils) error {
+					from := timeUtils.NowJP()
+					if ctx.String("from") != "" {
+						parsed, err := time.Parse(time.DateOnly, ctx.String("from"))
+						if err != nil {
+							return err
+						}
+						from = parsed
+					}
+					to := from
+					if ctx.String("to") != "" {
+						parsed, err := time.Parse(time.DateOnly, ctx.String("to"))
+						if err != nil {
+							return err
+						}
+						to = parsed
+					}
+
+					count, err := reviewScoreUsecase.Aggregate(ctx.Context, usecase.ReviewScoreAggregationInput{
+						From:       from,
+						To:         to,
+						Method:     enum.ReviewScoreMethod(ctx.String("method")),
+						PriorCount: ctx.Int("prior-count"),
+					})
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("%d日分のレビュー点数を集計しました\n", count)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "pending-questions",
+			Usage: "list product questions waiting for moderation",
+			Flags: []cli.Flag{
+				&cli.IntFlag{Name: "limit", Value: 100},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productQuestionUsecase usecase.ProductQuestionUsecase) error {
+					questions, err := productQuestionUsecase.FindPendingQuestions(ctx.Context, ctx.Int("limit"))
+					if err != nil {
+						return err
+					}
+
+					for _, question := range questions {
+						fmt.Printf("%s\t%s\t%s\t%s\n", question.ID, question.ProductID, question.ReviewNickname, question.Body)
+					}
+					fmt.Printf("確認待ちの質問が%d件あります\n", len(questions))
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "moderate-question",
+			Usage: "publish or reject a product question",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "id", Required: true},
+				&cli.StringFlag{Name: "status", Usage: "published or rejected", Required: true},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productQuestionUsecase usecase.ProductQuestionUsecase) error {
+					err := productQuestionUsecase.Moderate(ctx.Context, ctx.String("id"), enum.ProductQuestionStatus(ctx.String("status")))
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("質問（%s）の公開状態を%sに変更しました\n", ctx.String("id"), ctx.String("status"))
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "answer-question",
+			Usage: "answer a product question as shop staff and notify the asker by email (publishes the question)",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "id", Required: true},
+				&cli.StringFlag{Name: "body", Required: true},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productQuestionUsecase usecase.ProductQuestionUsecase) error {
+					err := productQuestionUsecase.AnswerAsStaff(ctx.Context, ctx.String("id"), ctx.String("body"))
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("質問（%s）に回答しました\n", ctx.String("id"))
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "compute-product-recommendations",
+			Usage: "mine frequently-bought-together products and category bestsellers from order history",
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:    "days",
+					Usage:   "number of days of order history to use",
+					EnvVars: []string{"PRODUCT_RECOMMENDATION_DAYS"},
+					Value:   90,
+				},
+				&cli.IntFlag{
+					Name:    "max-neighbors",
+					Usage:   "maximum number of recommended products stored per product",
+					EnvVars: []string{"PRODUCT_RECOMMENDATION_MAX_NEIGHBORS"},
+					Value:   30,
+				},
+				&cli.IntFlag{
+					Name:    "min-co-purchase-count",
+					Usage:   "minimum number of orders containing both products",
+					EnvVars: []string{"PRODUCT_RECOMMENDATION_MIN_CO_PURCHASE_COUNT"},
+					Value:   2,
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productRecommendationUsecase usecase.ProductRecommendationUsecase) error {
+					result, err := productRecommendationUsecase.Compute(ctx.Context, usecase.ProductRecommendationComputationInput{
+						Days:               ctx.Int("days"),
+						MaxNeighbors:       ctx.Int("max-neighbors"),
+						MinCoPurchaseCount: ctx.Int("min-co-purchase-count"),
+					})
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("%d件の注文から一緒に購入されている商品を%d件、売れ筋商品を%d件登録しました\n", result.BasketCount, result.RecommendationCount, result.BestsellerCount)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "aggregate-product-rankings",
+			Usage: "write daily, weekly and monthly ranking snapshots from sales and view counts up to the previous day (defaults to today)",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "date", Usage: "snapshot date (YYYY-MM-DD, default today)"},
+				&cli.IntFlag{
+					Name:    "sales-weight",
+					Usage:   "number of views one sale counts as",
+					EnvVars: []string{"PRODUCT_RANKING_SALES_WEIGHT"},
+					Value:   10,
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(productRankingUsecase usecase.ProductRankingUsecase, timeUtils util.TimeUtils) error {
+					date := timeUtils.NowJP()
+					if ctx.String("date") != "" {
+						parsed, err := time.Parse(time.DateOnly, ctx.String("date"))
+						if err != nil {
+							return err
+						}
+						date = parsed
+					}
+
+					count, err := productRankingUsecase.Aggregate(ctx.Context, usecase.ProductRankingAggregationInput{
+						Date:        date,
+						SalesWeight: ctx.Int("sales-weight"),
+					})
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("%sのランキングを%d件登録しました\n", date.Format(time.DateOnly), count)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "release-expired-stock-reservations",
+			Usage: "release stock reservations whose checkout was not paid before they expired",
+			Flags: []cli.Flag{
+				&cli.DurationFlag{
+					Name:  "interval",
+					Usage: "run repeatedly at this interval until SIGINT/SIGTERM (0 runs once)",
+					Value: 0,
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(stockReservationUsecase usecase.StockReservationUsecase) error {
+					sigCtx, stop := signal.NotifyContext(ctx.Context, syscall.SIGINT, syscall.SIGTERM)
+					defer stop()
+
+					interval := ctx.Duration("interval")
+					for {
+						count, err := stockReservationUsecase.ReleaseExpired(sigCtx)
+						if err != nil {
+							return err
+						}
+						fmt.Printf("有効期限切れの在庫引当%d件を解放しました\n", count)
+
+						if interval <= 0 {
+							return nil
+						}
+						select {
+						case <-sigCtx.Done():
+							return nil
+						case <-time.After(interval):
+						}
+					}
+				}))
+			},
+		},
+		{
+			Name:  "complete-stock-reservation",
+			Usage: "commit a stock reservation whose payment has succeeded and remove the purchased items from the cart",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "reservation-id", Required: true},
+				&cli.StringFlag{Name: "order-id", Usage: "order ID of the successful payment", Required: true},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(stockReservationUsecase usecase.StockReservationUsecase) error {
+					reservation, err := stockReservationUsecase.Complete(ctx.Context, ctx.String("reservation-id"), ctx.String("order-id"))
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("在庫引当（%s）を注文（%s）として確定しました\n", reservation.ID, *reservation.OrderID)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "record-stock-movement",
+			Usage: "record a receipt, return or manual adjustment of stock in the stock ledger",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "product-id", Required: true},
+				&cli.StringFlag{Name: "sku-id", Usage: "required for products with variations"},
+				&cli.StringFlag{Name: "type", Usage: "receipt, return or adjustment", Required: true},
+				&cli.IntFlag{Name: "quantity", Usage: "stock count change (negative to decrease)", Required: true},
+				&cli.StringFlag{Name: "reason", Required: true},
+				&cli.StringFlag{Name: "staff-account-id", Required: true},
+				&cli.StringFlag{Name: "order-id", Usage: "order the returned items belong to (required for return)"},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(stockMovementUsecase usecase.StockMovementUsecase) error {
+					movement, err := stockMovementUsecase.RecordManual(ctx.Context, usecase.ManualStockMovementInput{
+						ProductID:      ctx.String("product-id"),
+						SKUID:          ctx.String("sku-id"),
+						Type:           enum.StockMovementType(ctx.String("type")),
+						Quantity:       ctx.Int("quantity"),
+						Reason:         ctx.String("reason"),
+						StaffAccountID: ctx.String("staff-account-id"),
+						OrderID:        ctx.String("order-id"),
+					})
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("%s\t%s\t%s\t%s\t%+d\t%d -> %d\n", movement.ID, movement.ProductID, movement.SKUID, movement.Type, movement.Quantity, movement.BeforeCount, movement.AfterCount)
+					return nil
+				}))
+			},
+		},
+		{
+			Name:  "send-back-in-stock-notifications",
+			Usage: "send back-in-stock emails to accounts subscribed to products that can be purchased again",
+			Flags: []cli.Flag{
+				&cli.IntFlag{Name: "batch-size", Usage: "maximum number of emails sent in one batch", Value: 100},
+				&cli.DurationFlag{Name: "batch-interval", Usage: "wait between batches to limit the sending rate", Value: time.Second},
+				&cli.DurationFlag{
+					Name:  "interval",
+					Usage: "run repeatedly at this interval until SIGINT/SIGTERM (0 runs once)",
+					Value: 0,
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				return exit(container.Invoke(func(backInStockUsecase usecase.BackInStockUsecase) error {
+					sigCtx, stop := signal.NotifyContext(ctx.Context, syscall.SIGINT, syscall.SIGTERM)
+					defer stop()
+
+					interval := ctx.Duration("interval")
+					for {
+						// 販売中に戻った商品は在庫数の変更時のように再入荷イベントが発行されないため、送信前に確認して発行する
+						published, err := backInStockUsecase.PublishBackInStockEvents(sigCtx)
+						if err != nil {
+							return err
+						}
+						sent, err := backInStockUsecase.SendNotifications(sigCtx, usecase.BackInStockNotificationRateLimit{
+							BatchSize:     ctx.Int("batch-size"),
+							BatchInterval: ctx.Duration("batch-interval"),
+						})
+						if err != nil {
+							return err
+						}
+						fmt.Printf("%d件の商品が購入できるようになり、入荷通知メールを%d件送信しました\n", published, sent)
+
+						if interval <= 0 {
+							return nil
+						}
+						select {
+						case <-sigCtx.Done():
+							return nil
+						case <-time.After(interval):
+						}
+					}
+				}))
+			},
+		},
+	}
+}
+
+// 適用期間の一覧と不整合を出力する
+func printProductTimeline(timeline entity.ProductTimeline) {
+	fmt.Printf("[%s]\n", timeline.Type)
+	for _, entry := range timeline.Entries {
+		fmt.Printf("%s〜%s\t%d\n", entry.EffectiveStartDate.Format(time.DateOnly), entry.EffectiveEndDate.Format(time.DateOnly), entry.Value)
+	}
+	for _, problem := range timeline.Problems() {
+		fmt.Printf("警告: %s\n", problem.Message())
+	}
+}
@@ -0,0 +1,16 @@
+//go:generate mockery --name ImageProcessorAdapter
+package adapter
+
+import (
+	"image"
+	"io"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+)
+
+type ImageProcessorAdapter interface {
+	// 画像をデコードする（EXIFの向きの情報がある場合は画像を回転させる）
+	Decode(body io.Reader) (image.Image, error)
+	// 画像を幅widthに縮小し、画像形式でエンコードする（EXIFなどのメタデータは含めない）
+	Encode(img image.Image, width int, format enum.ImageFormat) ([]byte, error)
+}
@@ -0,0 +1,18 @@
+//go:generate mockery --name ImageStorageAdapter
+package adapter
+
+import (
+	"io"
+	"time"
+)
+
+type ImageStorageAdapter interface {
+	// 画像をパスに保存する（同じパスの画像が存在する場合は上書きする）
+	Upload(path string, body io.Reader, contentType string) error
+	// パスの画像を取得する（呼び出し元で閉じる必要がある）
+	Download(path string) (io.ReadCloser, error)
+	// パスの画像を削除する（画像が存在しない場合は何もしない）
+	Delete(path string) error
+	// パスの画像を取得できる有効期限付きのURLを返却する
+	PresignedURL(path string, expiry time.Duration) (string, error)
+}
@@ -19,6 +19,8 @@ type (
 		IsActive          bool          `json:"isActive"`
 		StripeCustomerID  *string       `json:"stripeCustmerID"`
 		ReviewNickname    string        `json:"reviewNickname"`
+		// 宣伝メール（カート放置リマインドメールなど）の配信を停止している場合true
+		MarketingEmailOptOut bool `json:"marketingEmailOptOut"`
 
 		Events []share.DomainEvent
 	}
@@ -52,6 +54,16 @@ func (account *Account) SetStripeCustomerID(stripeCustomerID string) {
 	account.StripeCustomerID = &stripeCustomerID
 }
 
+// 宣伝メールの配信を停止する場合はtrueを、配信する場合はfalseを設定する
+func (account *Account) SetMarketingEmailOptOut(optOut bool) {
+	account.MarketingEmailOptOut = optOut
+}
+
+// 宣伝メールを送信できる場合（有効化済みかつ配信停止していない場合）trueを返却する
+func (account Account) CanReceiveMarketingEmail() bool {
+	return account.IsActive && !account.MarketingEmailOptOut
+}
+
 // ドメインイベント配列を削除し、ドメインイベント配列を返却する
 func (account *Account) ClearEvents() []share.DomainEvent {
 	events := account.Events
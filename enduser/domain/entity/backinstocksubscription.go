@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 入荷通知の購読（商品が購入できるようになったときにメールで通知する）
+	// 通知したアカウントの購読は削除する
+	BackInStockSubscription struct {
+		ID                    string
+		AccountID             string
+		ProductID             string
+		NotifyRequestDateTime *time.Time // 商品が購入できるようになり通知メールの送信待ちになった日時（送信待ちでない場合はnil）
+		CreateDateTime        time.Time
+	}
+
+	// 商品の再入荷イベント（在庫数が0から増えた、または販売中に戻った）
+	// 購入できるかは発行後に変わりうるため、通知メールの送信時に再度確認する
+	ProductBackInStockEvent struct {
+		ProductID string
+		DB        bun.IDB
+		Ctx       context.Context
+	}
+)
+
+const productBackInStockEventName share.DomainEventName = "ProductBackInStockEvent"
+
+func (pe ProductBackInStockEvent) Name() share.DomainEventName {
+	return productBackInStockEventName
+}
+
+// 入荷通知の購読を作成する
+// 購入できる商品（販売中かつ在庫あり）は購読できない
+func CreateBackInStockSubscription(accountID string, product Product, now time.Time) (BackInStockSubscription, error) {
+	if product.IsPurchasable() {
+		return BackInStockSubscription{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"購入できる商品は入荷通知を登録できません"})
+	}
+
+	return BackInStockSubscription{
+		ID:             util.IDutils.GenerateID(),
+		AccountID:      accountID,
+		ProductID:      product.ID,
+		CreateDateTime: now,
+	}, nil
+}
+
+// 商品が購入できる場合（販売中かつ在庫あり）trueを返却する
+func (product Product) IsPurchasable() bool {
+	return product.isOnSale() && product.StockCount > 0
+}
+
+// 在庫のあるSKUを返却する（バリエーションを持たない商品は在庫がある場合に商品全体のSKUを1件返却する）
+func (product Product) InStockSKUs() []ProductSKU {
+	if len(product.SKUs) == 0 {
+		sku, _ := product.FindSKU("")
+		if sku.StockCount <= 0 {
+			return []ProductSKU{}
+		}
+		return []ProductSKU{sku}
+	}
+
+	skus := []ProductSKU{}
+	for _, sku := range product.SKUs {
+		if sku.StockCount > 0 {
+			skus = append(skus, sku)
+		}
+	}
+	return skus
+}
@@ -0,0 +1,75 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateBackInStockSubscription(t *testing.T) {
+	now := time.Date(2024, 5, 20, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name      string
+		Product   entity.Product
+		ExpectErr bool
+	}{
+		{Name: "在庫なしの商品の場合、購読を作成する", Product: entity.Product{ID: "1", Status: enum.OnSale, StockCount: 0}},
+		{Name: "販売停止中の商品の場合、購読を作成する", Product: entity.Product{ID: "1", Status: enum.SalesSuspend, StockCount: 3}},
+		{Name: "購入できる商品の場合、エラーを返却する", Product: entity.Product{ID: "1", Status: enum.OnSale, StockCount: 3}, ExpectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			subscription, err := entity.CreateBackInStockSubscription("account", tt.Product, now)
+
+			// then（期待する結果）
+			if tt.ExpectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.NotEmpty(t, subscription.ID)
+			assert.Equal(t, "account", subscription.AccountID)
+			assert.Equal(t, "1", subscription.ProductID)
+			assert.Nil(t, subscription.NotifyRequestDateTime)
+		})
+	}
+}
+
+func TestProductInStockSKUs(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Product  entity.Product
+		Expected []string
+	}{
+		{Name: "バリエーションを持たない在庫ありの商品の場合、商品全体のSKUを返却する", Product: entity.Product{ID: "1", StockCount: 2}, Expected: []string{""}},
+		{Name: "バリエーションを持たない在庫なしの商品の場合、空配列を返却する", Product: entity.Product{ID: "1", StockCount: 0}, Expected: []string{}},
+		{
+			Name: "SKUを持つ商品の場合、在庫のあるSKUのみ返却する",
+			Product: entity.Product{ID: "1", StockCount: 3, SKUs: []entity.ProductSKU{
+				{ID: "sku1", ProductID: "1", StockCount: 0},
+				{ID: "sku2", ProductID: "1", StockCount: 3},
+			}},
+			Expected: []string{"sku2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			skus := tt.Product.InStockSKUs()
+
+			// then（期待する結果）
+			ids := []string{}
+			for _, sku := range skus {
+				ids = append(ids, sku.ID)
+			}
+			assert.Equal(t, tt.Expected, ids)
+		})
+	}
+}
@@ -1,17 +1,25 @@
 package entity
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
 	"github.com/kuritaeiji/ec_backend/util"
 )
 
 type (
 	//カート集約
 	Cart struct {
-		ID        string
-		AccountID string
-		Version   int
+		ID             string
+		AccountID      string
+		Version        int
+		UpdatedAt      time.Time  // カートを最後に更新した日時
+		ReminderSentAt *time.Time // カート放置リマインドメールを最後に送信した日時
 
-		CartProducts []CartProduct // TODO ポインター型にする CartProductを更新できるようにするため
+		CartProducts  []CartProduct  // TODO ポインター型にする CartProductを更新できるようにするため
+		SavedProducts []SavedProduct // あとで買う商品（購入金額・個数の集計には含めない）
 	}
 
 	//カート商品
@@ -19,22 +27,49 @@ type (
 		ID        string
 		CartID    string
 		ProductID string
+		SKUID     string // バリエーションを持たない商品の場合は空文字
+		Count     int
+		Price     int // カートに追加した時点の販売価格（税込）
+	}
+
+	// あとで買う商品
+	SavedProduct struct {
+		ID        string
+		CartID    string
+		ProductID string
+		SKUID     string // バリエーションを持たない商品の場合は空文字
 		Count     int
+		Price     int // あとで買うに追加した時点の販売価格（税込）
+	}
+
+	// カート商品の警告
+	// カート追加時から価格・商品ステータス・在庫数が変化した場合に作成する
+	CartWarning struct {
+		CartProductID string
+		ProductID     string
+		SKUID         string
+		Type          enum.CartWarningType
+		PreviousPrice int // カート追加時の販売価格
+		CurrentPrice  int // 現在の販売価格
+		StockCount    int // 現在のSKUの在庫数
+		Message       string
 	}
 )
 
 // カート集約を作成する
 func CreateCart(accountID string) Cart {
 	return Cart{
-		ID:           util.IDutils.GenerateID(),
-		AccountID:    accountID,
-		Version:      1,
-		CartProducts: []CartProduct{},
+		ID:            util.IDutils.GenerateID(),
+		AccountID:     accountID,
+		Version:       1,
+		CartProducts:  []CartProduct{},
+		SavedProducts: []SavedProduct{},
 	}
 }
 
 // セッションカートカート内の商品をカート集約に移動する
 // セッションカート内の商品が販売中かつ在庫が存在することをチェックするためにセッションカートの商品集約リストを引数に取る
+// 同じ商品でもSKUが異なる場合は別のカート商品として扱い、在庫数はSKUごとにチェックする
 func (cart *Cart) MoveSessionCartProductsToCart(sessionCart SessionCart, products []Product) {
 	for _, sessionCartProduct := range sessionCart.SessionCartProducts {
 		product, ok := findProduct(products, sessionCartProduct.ProductID)
@@ -43,42 +78,55 @@ func (cart *Cart) MoveSessionCartProductsToCart(sessionCart SessionCart, product
 			continue
 		}
 
-		//カート集約内にセッションカートの商品と一致する商品が存在する場合取得する
-		cartProduct, index, ok := cart.findCartProductByProductID(sessionCartProduct.ProductID)
+		//SKUが存在しない場合はcontinueする
+		sku, ok := product.FindSKU(sessionCartProduct.SKUID)
+		if !ok {
+			continue
+		}
+
+		//カート集約内にセッションカートの商品と一致するSKUの商品が存在する場合取得する
+		cart.normalizeSKUID(product)
+		cartProduct, index, ok := cart.findCartProductBySKU(product.ID, sku.ID)
 
 		// 商品が販売中の場合のみセッションカートに商品を追加する
 		if product.isOnSale() {
-			// 在庫が追加したい個数以上の場合は商品を追加したい個数分セッションカートに追加する
-			if product.StockCount >= sessionCartProduct.Count {
-				// 既に同じ商品がカート内に存在する場合はカート内の商品の個数にセッションカートの商品の個数分だけ追加する
+			// SKUの在庫が追加したい個数以上の場合は商品を追加したい個数分セッションカートに追加する
+			if sku.StockCount >= sessionCartProduct.Count {
+				// 既に同じSKUの商品がカート内に存在する場合はカート内の商品の個数にセッションカートの商品の個数分だけ追加する
 				if ok {
 					cartProduct.Count += sessionCartProduct.Count
+					cartProduct.Price = product.SKUPrice(sku)
 					cart.CartProducts[index] = cartProduct
 				} else {
-					// 同じ商品がカート内に存在しない場合はカート内の商品をセッションカートの商品の個数分だけ追加する
+					// 同じSKUの商品がカート内に存在しない場合はカート内の商品をセッションカートの商品の個数分だけ追加する
 					cart.CartProducts = append(cart.CartProducts, CartProduct{
 						ID:        util.IDutils.GenerateID(),
 						CartID:    cart.ID,
 						ProductID: sessionCartProduct.ProductID,
+						SKUID:     sku.ID,
 						Count:     sessionCartProduct.Count,
+						Price:     product.SKUPrice(sku),
 					})
 				}
 				continue
 			}
 
-			//在庫が追加したい個数より少ないが1個以上の在庫を持つ場合は在庫分だけセッションカートに追加する
-			if product.StockCount >= 1 {
-				// 既に同じ商品がカート内に存在する場合はカート内の商品の個数に在庫数だけ追加する
+			//SKUの在庫が追加したい個数より少ないが1個以上の在庫を持つ場合は在庫分だけセッションカートに追加する
+			if sku.StockCount >= 1 {
+				// 既に同じSKUの商品がカート内に存在する場合はカート内の商品の個数に在庫数だけ追加する
 				if ok {
-					cartProduct.Count += product.StockCount
+					cartProduct.Count += sku.StockCount
+					cartProduct.Price = product.SKUPrice(sku)
 					cart.CartProducts[index] = cartProduct
 				} else {
-					// 同じ商品がカート内に存在しない場合はカート内の商品を在庫数分だけ追加する
+					// 同じSKUの商品がカート内に存在しない場合はカート内の商品を在庫数分だけ追加する
 					cart.CartProducts = append(cart.CartProducts, CartProduct{
 						ID:        util.IDutils.GenerateID(),
 						CartID:    cart.ID,
 						ProductID: sessionCartProduct.ProductID,
-						Count:     product.StockCount,
+						SKUID:     sku.ID,
+						Count:     sku.StockCount,
+						Price:     product.SKUPrice(sku),
 					})
 				}
 				continue
@@ -87,12 +135,334 @@ func (cart *Cart) MoveSessionCartProductsToCart(sessionCart SessionCart, product
 			//在庫が存在しない場合は何もしない
 		}
 	}
+
+	// セッションカートのあとで買う商品をカート集約のあとで買う商品に移動する
+	// あとで買う商品は購入対象ではないため商品ステータス・在庫はチェックしない
+	for _, sessionSavedProduct := range sessionCart.SessionSavedProducts {
+		product, ok := findProduct(products, sessionSavedProduct.ProductID)
+		//商品が存在しない場合はcontinueする
+		if !ok {
+			continue
+		}
+
+		//SKUが存在しない場合はcontinueする
+		sku, ok := product.FindSKU(sessionSavedProduct.SKUID)
+		if !ok {
+			continue
+		}
+
+		// 既にカート内に同じSKUの商品が存在する場合はあとで買うに追加しない
+		cart.normalizeSKUID(product)
+		if _, _, ok := cart.findCartProductBySKU(product.ID, sku.ID); ok {
+			continue
+		}
+
+		cart.addSavedProduct(product, sku, sessionSavedProduct.Count)
+	}
+}
+
+// カートに商品のSKUを追加する
+// 既に同じSKUの商品がカート内に存在する場合は個数を加算し、カート追加時の価格を現在の販売価格で更新する
+func (cart *Cart) AddProduct(product Product, skuID string, count int) error {
+	if count < 1 {
+		return share.CreateOriginalError(share.ErrorCodeValidation, []string{"個数は1以上にしてください"})
+	}
+
+	if !product.isOnSale() {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"販売中ではない商品はカートに追加できません"})
+	}
+
+	sku, ok := product.FindSKU(skuID)
+	if !ok {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"商品のバリエーションを選択してください"})
+	}
+
+	cart.normalizeSKUID(product)
+	cartProduct, index, ok := cart.findCartProductBySKU(product.ID, sku.ID)
+
+	// カート内の個数と追加する個数の合計がSKUの在庫数を超える場合はエラーにする
+	if cartProduct.Count+count > sku.StockCount {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"在庫が不足しています"})
+	}
+
+	if ok {
+		cartProduct.Count += count
+		cartProduct.Price = product.SKUPrice(sku)
+		cart.CartProducts[index] = cartProduct
+		return nil
+	}
+
+	cart.CartProducts = append(cart.CartProducts, CartProduct{
+		ID:        util.IDutils.GenerateID(),
+		CartID:    cart.ID,
+		ProductID: product.ID,
+		SKUID:     sku.ID,
+		Count:     count,
+		Price:     product.SKUPrice(sku),
+	})
+	return nil
+}
+
+// カート内の商品を「あとで買う」に移動する
+func (cart *Cart) MoveToSaved(cartProductID string) error {
+	index, ok := cart.findCartProductIndexByID(cartProductID)
+	if !ok {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"カート内に商品が見つかりません"})
+	}
+
+	cartProduct := cart.CartProducts[index]
+	cart.CartProducts = append(cart.CartProducts[:index], cart.CartProducts[index+1:]...)
+
+	// 同じSKUのあとで買う商品が存在する場合は個数を合算し、価格はカートに追加した際の新しい価格に更新する
+	savedProduct, savedIndex, ok := cart.findSavedProductBySKU(cartProduct.ProductID, cartProduct.SKUID)
+	if ok {
+		savedProduct.Count += cartProduct.Count
+		savedProduct.Price = cartProduct.Price
+		cart.SavedProducts[savedIndex] = savedProduct
+		return nil
+	}
+
+	cart.SavedProducts = append(cart.SavedProducts, SavedProduct{
+		ID:        util.IDutils.GenerateID(),
+		CartID:    cart.ID,
+		ProductID: cartProduct.ProductID,
+		SKUID:     cartProduct.SKUID,
+		Count:     cartProduct.Count,
+		Price:     cartProduct.Price,
+	})
+	return nil
+}
+
+// 「あとで買う」の商品をカートに移動する
+// カートに追加する際と同様に商品が販売中かつ在庫が存在することをチェックするために商品集約を引数に取る
+func (cart *Cart) MoveToCart(savedProductID string, product Product) error {
+	index, ok := cart.findSavedProductIndexByID(savedProductID)
+	if !ok || cart.SavedProducts[index].ProductID != product.ID {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"あとで買う商品が見つかりません"})
+	}
+
+	// カートへの追加時にあとで買う商品のSKU IDが変換・合算されるため、先にあとで買う商品から取り除いてから追加する
+	savedProduct := cart.SavedProducts[index]
+	savedProducts := cart.SavedProducts
+	cart.SavedProducts = append(append(make([]SavedProduct, 0, len(savedProducts)-1), savedProducts[:index]...), savedProducts[index+1:]...)
+
+	err := cart.AddProduct(product, savedProduct.SKUID, savedProduct.Count)
+	if err != nil {
+		cart.SavedProducts = savedProducts
+		return err
+	}
+
+	return nil
+}
+
+// 購入が確定した在庫引当の商品をカートから取り除く
+// カート内の個数が購入した個数より多い場合（購入手続きの開始後に追加した場合）は差分の個数を残す
+// SKU IDが空文字のカート商品はSKUが1件のみの商品をSKU指定なしで追加したものであるため、同じ商品の在庫引当の商品と一致させる
+func (cart *Cart) RemovePurchasedProducts(items []StockReservationItem) {
+	for _, item := range items {
+		remaining := item.Count
+		cartProducts := make([]CartProduct, 0, len(cart.CartProducts))
+		for _, cartProduct := range cart.CartProducts {
+			if remaining > 0 && cartProduct.ProductID == item.ProductID && (cartProduct.SKUID == item.SKUID || cartProduct.SKUID == "") {
+				removed := min(cartProduct.Count, remaining)
+				cartProduct.Count -= removed
+				remaining -= removed
+			}
+			if cartProduct.Count > 0 {
+				cartProducts = append(cartProducts, cartProduct)
+			}
+		}
+		cart.CartProducts = cartProducts
+	}
+}
+
+// 引数savedProductIDに一致する「あとで買う」の商品を返却する
+func (cart Cart) FindSavedProduct(savedProductID string) (SavedProduct, bool) {
+	index, ok := cart.findSavedProductIndexByID(savedProductID)
+	if !ok {
+		return SavedProduct{}, false
+	}
+
+	return cart.SavedProducts[index], true
+}
+
+// カート内の商品の合計個数を返却する（あとで買う商品は含めない）
+func (cart Cart) TotalCount() int {
+	total := 0
+	for _, cartProduct := range cart.CartProducts {
+		total += cartProduct.Count
+	}
+
+	return total
 }
 
-// 引数productIDに一致するカート内の商品を返却する
-func (cart Cart) findCartProductByProductID(productID string) (CartProduct, int, bool) {
+// カート内の販売中の商品の現在のSKUの販売価格による合計金額を返却する（あとで買う商品は含めない）
+func (cart Cart) TotalPrice(products []Product) int {
+	total := 0
+	for _, cartProduct := range cart.CartProducts {
+		product, ok := findProduct(products, cartProduct.ProductID)
+		if !ok || !product.isOnSale() {
+			continue
+		}
+
+		sku, ok := product.FindSKU(cartProduct.SKUID)
+		if !ok {
+			continue
+		}
+
+		total += product.SKUPrice(sku) * cartProduct.Count
+	}
+
+	return total
+}
+
+// あとで買う商品の合計個数を返却する
+func (cart Cart) SavedCount() int {
+	total := 0
+	for _, savedProduct := range cart.SavedProducts {
+		total += savedProduct.Count
+	}
+
+	return total
+}
+
+// カート内の商品とあとで買う商品の商品ID配列を返却する
+func (cart Cart) ProductIDs() []string {
+	ids := make([]string, 0, len(cart.CartProducts)+len(cart.SavedProducts))
+	for _, cartProduct := range cart.CartProducts {
+		ids = append(ids, cartProduct.ProductID)
+	}
+	for _, savedProduct := range cart.SavedProducts {
+		ids = append(ids, savedProduct.ProductID)
+	}
+
+	return ids
+}
+
+// カート追加時から価格が変化した商品・販売中ではなくなった商品・在庫数を超えている商品の警告配列を返却する
+// 引数productsにはカート内の商品の商品集約リストを渡す
+func (cart Cart) Warnings(products []Product) []CartWarning {
+	warnings := []CartWarning{}
+	for _, cartProduct := range cart.CartProducts {
+		product, ok := findProduct(products, cartProduct.ProductID)
+		// 商品が存在しない場合は警告を作成しない
+		if !ok {
+			continue
+		}
+
+		// SKUが削除された場合は販売終了の警告を作成する
+		sku, ok := product.FindSKU(cartProduct.SKUID)
+		if !ok {
+			warnings = append(warnings, CartWarning{
+				CartProductID: cartProduct.ID,
+				ProductID:     cartProduct.ProductID,
+				SKUID:         cartProduct.SKUID,
+				Type:          enum.CartWarningSalesEnded,
+				PreviousPrice: cartProduct.Price,
+				Message:       fmt.Sprintf("%sの選択したバリエーションは販売を終了しました", product.Name),
+			})
+			continue
+		}
+		name := product.SKUDisplayName(sku)
+
+		warning := CartWarning{
+			CartProductID: cartProduct.ID,
+			ProductID:     cartProduct.ProductID,
+			SKUID:         cartProduct.SKUID,
+			PreviousPrice: cartProduct.Price,
+			CurrentPrice:  product.SKUPrice(sku),
+			StockCount:    sku.StockCount,
+		}
+
+		// 販売停止中・販売終了の場合は価格・在庫数の警告は作成しない
+		switch product.Status {
+		case enum.SalesSuspend:
+			warning.Type = enum.CartWarningSalesSuspend
+			warning.Message = fmt.Sprintf("%sは現在販売を停止しています", name)
+			warnings = append(warnings, warning)
+			continue
+		case enum.SalesEnded:
+			warning.Type = enum.CartWarningSalesEnded
+			warning.Message = fmt.Sprintf("%sは販売を終了しました", name)
+			warnings = append(warnings, warning)
+			continue
+		}
+
+		// カート追加時の価格を記録していない商品（価格記録前にカートに追加された商品）は価格の警告を作成しない
+		if warning.PreviousPrice > 0 && warning.CurrentPrice > warning.PreviousPrice {
+			warning.Type = enum.CartWarningPriceIncreased
+			warning.Message = fmt.Sprintf("%sの価格が%d円から%d円に値上がりしました", name, warning.PreviousPrice, warning.CurrentPrice)
+			warnings = append(warnings, warning)
+		}
+
+		if warning.PreviousPrice > 0 && warning.CurrentPrice < warning.PreviousPrice {
+			warning.Type = enum.CartWarningPriceDecreased
+			warning.Message = fmt.Sprintf("%sの価格が%d円から%d円に値下がりしました", name, warning.PreviousPrice, warning.CurrentPrice)
+			warnings = append(warnings, warning)
+		}
+
+		if cartProduct.Count > sku.StockCount {
+			warning.Type = enum.CartWarningStockShortage
+			warning.Message = fmt.Sprintf("%sの在庫が%d個しかありません", name, sku.StockCount)
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings
+}
+
+// SKU IDが空文字で保存されたカート商品・あとで買う商品（SKUが1件のみの商品をSKU指定なしで追加したもの）のSKU IDを、
+// SKU指定なしで解決される商品のSKU IDに変換する
+// 変換により同じSKUの商品が重複する場合は個数を合算して1件にまとめる
+func (cart *Cart) normalizeSKUID(product Product) {
+	sku, ok := product.FindSKU("")
+	if !ok || sku.ID == "" {
+		return
+	}
+
+	cartProducts := make([]CartProduct, 0, len(cart.CartProducts))
+	for _, cartProduct := range cart.CartProducts {
+		if cartProduct.ProductID == product.ID && cartProduct.SKUID == "" {
+			cartProduct.SKUID = sku.ID
+		}
+		merged := false
+		for i := range cartProducts {
+			if cartProducts[i].ProductID == cartProduct.ProductID && cartProducts[i].SKUID == cartProduct.SKUID {
+				cartProducts[i].Count += cartProduct.Count
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			cartProducts = append(cartProducts, cartProduct)
+		}
+	}
+	cart.CartProducts = cartProducts
+
+	savedProducts := make([]SavedProduct, 0, len(cart.SavedProducts))
+	for _, savedProduct := range cart.SavedProducts {
+		if savedProduct.ProductID == product.ID && savedProduct.SKUID == "" {
+			savedProduct.SKUID = sku.ID
+		}
+		merged := false
+		for i := range savedProducts {
+			if savedProducts[i].ProductID == savedProduct.ProductID && savedProducts[i].SKUID == savedProduct.SKUID {
+				savedProducts[i].Count += savedProduct.Count
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			savedProducts = append(savedProducts, savedProduct)
+		}
+	}
+	cart.SavedProducts = savedProducts
+}
+
+// 引数productID・skuIDに一致するカート内の商品を返却する
+func (cart Cart) findCartProductBySKU(productID string, skuID string) (CartProduct, int, bool) {
 	for i, cartProduct := range cart.CartProducts {
-		if cartProduct.ProductID == productID {
+		if cartProduct.ProductID == productID && cartProduct.SKUID == skuID {
 			return cartProduct, i, true
 		}
 	}
@@ -100,6 +470,59 @@ func (cart Cart) findCartProductByProductID(productID string) (CartProduct, int,
 	return CartProduct{}, 0, false
 }
 
+// 引数cartProductIDに一致するカート内の商品のインデックスを返却する
+func (cart Cart) findCartProductIndexByID(cartProductID string) (int, bool) {
+	for i, cartProduct := range cart.CartProducts {
+		if cartProduct.ID == cartProductID {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// 引数productID・skuIDに一致するあとで買う商品を返却する
+func (cart Cart) findSavedProductBySKU(productID string, skuID string) (SavedProduct, int, bool) {
+	for i, savedProduct := range cart.SavedProducts {
+		if savedProduct.ProductID == productID && savedProduct.SKUID == skuID {
+			return savedProduct, i, true
+		}
+	}
+
+	return SavedProduct{}, 0, false
+}
+
+// 引数savedProductIDに一致するあとで買う商品のインデックスを返却する
+func (cart Cart) findSavedProductIndexByID(savedProductID string) (int, bool) {
+	for i, savedProduct := range cart.SavedProducts {
+		if savedProduct.ID == savedProductID {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// あとで買うに商品のSKUを追加する
+// 既に同じSKUの商品が存在する場合は個数を加算する
+func (cart *Cart) addSavedProduct(product Product, sku ProductSKU, count int) {
+	savedProduct, index, ok := cart.findSavedProductBySKU(product.ID, sku.ID)
+	if ok {
+		savedProduct.Count += count
+		cart.SavedProducts[index] = savedProduct
+		return
+	}
+
+	cart.SavedProducts = append(cart.SavedProducts, SavedProduct{
+		ID:        util.IDutils.GenerateID(),
+		CartID:    cart.ID,
+		ProductID: product.ID,
+		SKUID:     sku.ID,
+		Count:     count,
+		Price:     product.SKUPrice(sku),
+	})
+}
+
 // 引数productsから引数productIDに一致する商品を返却する
 func findProduct(products []Product, productID string) (Product, bool) {
 	for _, product := range products {
@@ -110,3 +533,22 @@ func findProduct(products []Product, productID string) (Product, bool) {
 
 	return Product{}, false
 }
+
+// カート放置リマインドメールを送信する必要がある場合trueを返却する
+// カート内に商品が存在し、最終更新日時からperiod以上経過していて、最終更新後にリマインドメールを送信していない場合に送信する
+func (cart Cart) NeedsAbandonedReminder(now time.Time, period time.Duration) bool {
+	if len(cart.CartProducts) == 0 {
+		return false
+	}
+
+	if cart.UpdatedAt.After(now.Add(-period)) {
+		return false
+	}
+
+	return cart.ReminderSentAt == nil || cart.ReminderSentAt.Before(cart.UpdatedAt)
+}
+
+// カート放置リマインドメールを送信した日時を記録する
+func (cart *Cart) MarkReminderSent(now time.Time) {
+	cart.ReminderSentAt = &now
+}
@@ -2,6 +2,7 @@ package entity_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
 	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
@@ -103,3 +104,425 @@ func TestMoveSessionCartToCart(t *testing.T) {
 		})
 	}
 }
+
+// 商品ステータス          在庫とカート内の商品数+追加個数      既にカートに商品が存在する
+// 販売中・停止中          在庫>=商品数・在庫<商品数            カートに同一商品が存在する・存在しない
+func TestAddProduct(t *testing.T) {
+	// given（前提条件）
+	cartID := "1"
+	productID := "2"
+
+	type params struct {
+		cart    entity.Cart
+		product entity.Product
+		count   int
+	}
+
+	type expected struct {
+		cartProducts []entity.CartProduct
+		isErr        bool
+	}
+
+	tests := []struct {
+		Name     string
+		Params   params
+		Expected expected
+	}{
+		{
+			Name: "商品が販売中かつ在庫が存在しカートに同一商品が存在しない場合、販売価格を記録してカートに商品を追加する",
+			Params: params{
+				cart:    entity.Cart{ID: cartID, CartProducts: []entity.CartProduct{}},
+				product: entity.Product{ID: productID, Status: enum.OnSale, StockCount: 2, Price: 100, SalePrice: 80},
+				count:   2,
+			},
+			Expected: expected{cartProducts: []entity.CartProduct{{CartID: cartID, ProductID: productID, Count: 2, Price: 80}}},
+		},
+		{
+			Name: "カートに同一商品が存在する場合、個数を加算し販売価格を更新する",
+			Params: params{
+				cart:    entity.Cart{ID: cartID, CartProducts: []entity.CartProduct{{ID: "1", CartID: cartID, ProductID: productID, Count: 1, Price: 120}}},
+				product: entity.Product{ID: productID, Status: enum.OnSale, StockCount: 3, Price: 100, SalePrice: 100},
+				count:   2,
+			},
+			Expected: expected{cartProducts: []entity.CartProduct{{ID: "1", CartID: cartID, ProductID: productID, Count: 3, Price: 100}}},
+		},
+		{
+			Name: "カート内の個数と追加する個数の合計が在庫数を超える場合、エラーを返却する",
+			Params: params{
+				cart:    entity.Cart{ID: cartID, CartProducts: []entity.CartProduct{{ID: "1", CartID: cartID, ProductID: productID, Count: 2, Price: 100}}},
+				product: entity.Product{ID: productID, Status: enum.OnSale, StockCount: 3, Price: 100},
+				count:   2,
+			},
+			Expected: expected{cartProducts: []entity.CartProduct{{ID: "1", CartID: cartID, ProductID: productID, Count: 2, Price: 100}}, isErr: true},
+		},
+		{
+			Name: "商品が販売中ではない場合、エラーを返却する",
+			Params: params{
+				cart:    entity.Cart{ID: cartID, CartProducts: []entity.CartProduct{}},
+				product: entity.Product{ID: productID, Status: enum.SalesSuspend, StockCount: 3, Price: 100},
+				count:   1,
+			},
+			Expected: expected{cartProducts: []entity.CartProduct{}, isErr: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			err := tt.Params.cart.AddProduct(tt.Params.product, "", tt.Params.count)
+
+			// then（期待する結果）
+			assert.Equal(t, tt.Expected.isErr, err != nil)
+			assert.Equal(t, len(tt.Expected.cartProducts), len(tt.Params.cart.CartProducts))
+			for i, expectedCartProduct := range tt.Expected.cartProducts {
+				acturalCartProduct := tt.Params.cart.CartProducts[i]
+				assert.Equal(t, expectedCartProduct.ProductID, acturalCartProduct.ProductID, "商品ID")
+				assert.Equal(t, expectedCartProduct.Count, acturalCartProduct.Count, "個数")
+				assert.Equal(t, expectedCartProduct.Price, acturalCartProduct.Price, "カート追加時の販売価格")
+			}
+		})
+	}
+}
+
+func TestMoveSessionCartToCartPerSKU(t *testing.T) {
+	// given（前提条件）同一商品の異なるSKUがカートとセッションカートに存在する
+	product := newSKUProduct()
+	product.Status = enum.OnSale
+	cart := entity.Cart{ID: "1", CartProducts: []entity.CartProduct{{ID: "1", CartID: "1", ProductID: product.ID, SKUID: "sku1", Count: 1}}}
+	sessionCart := entity.SessionCart{SessionCartProducts: []entity.SessionCartProduct{
+		{ProductID: product.ID, SKUID: "sku3", Count: 2},
+		{ProductID: product.ID, SKUID: "sku2", Count: 1},
+		{ProductID: product.ID, SKUID: "sku1", Count: 5},
+	}}
+
+	// when（操作）
+	cart.MoveSessionCartProductsToCart(sessionCart, []entity.Product{product})
+
+	// then（期待する結果）SKUごとに在庫を確認し、同一SKUのみ個数を合算する
+	assert.Equal(t, 2, len(cart.CartProducts))
+	assert.Equal(t, "sku1", cart.CartProducts[0].SKUID)
+	assert.Equal(t, 4, cart.CartProducts[0].Count)
+	assert.Equal(t, "sku3", cart.CartProducts[1].SKUID)
+	assert.Equal(t, 2, cart.CartProducts[1].Count)
+}
+
+func TestAddProductPerSKU(t *testing.T) {
+	// given（前提条件）
+	product := newSKUProduct()
+	product.Status = enum.OnSale
+	cart := entity.Cart{ID: "1", CartProducts: []entity.CartProduct{{ID: "1", CartID: "1", ProductID: product.ID, SKUID: "sku1", Count: 3, Price: 1000}}}
+
+	// when（操作）同一商品の異なるSKUを追加する
+	err := cart.AddProduct(product, "sku3", 5)
+
+	// then（期待する結果）別のカート商品として追加する
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(cart.CartProducts))
+	assert.Equal(t, "sku3", cart.CartProducts[1].SKUID)
+
+	// when（操作）SKUの在庫を超える個数を追加する
+	err = cart.AddProduct(product, "sku1", 1)
+
+	// then（期待する結果）
+	assert.NotNil(t, err)
+
+	// when（操作）SKUを指定せずに複数のSKUを持つ商品を追加する
+	err = cart.AddProduct(product, "", 1)
+
+	// then（期待する結果）
+	assert.NotNil(t, err)
+}
+
+func TestAddProductWithLegacyEmptySKUID(t *testing.T) {
+	// given（前提条件）SKUが1件のみの商品がSKU IDを空文字としてカートとセッションカートに保存されている
+	product := entity.Product{ID: "1", Status: enum.OnSale, Price: 1000, SKUs: []entity.ProductSKU{{ID: "sku1", StockCount: 5}}}
+	cart := entity.Cart{ID: "1", CartProducts: []entity.CartProduct{{ID: "1", CartID: "1", ProductID: product.ID, SKUID: "", Count: 2, Price: 1000}}}
+	sessionCart := entity.SessionCart{SessionCartProducts: []entity.SessionCartProduct{{ProductID: product.ID, SKUID: "", Count: 1}}}
+
+	// when（操作）SKUを指定して追加する
+	err := cart.AddProduct(product, "sku1", 2)
+
+	// then（期待する結果）SKU IDを変換して同じカート商品の個数を加算する
+	assert.Nil(t, err)
+	assert.Equal(t, []entity.CartProduct{{ID: "1", CartID: "1", ProductID: product.ID, SKUID: "sku1", Count: 4, Price: 1000}}, cart.CartProducts)
+
+	// when（操作）在庫数を超える個数を追加する
+	err = cart.AddProduct(product, "", 2)
+
+	// then（期待する結果）SKU IDが空文字のカート商品の個数も含めて在庫数を確認する
+	assert.NotNil(t, err)
+
+	// when（操作）SKU IDが空文字のセッションカートの商品を移動する
+	cart.MoveSessionCartProductsToCart(sessionCart, []entity.Product{product})
+
+	// then（期待する結果）同じカート商品の個数を加算する
+	assert.Equal(t, []entity.CartProduct{{ID: "1", CartID: "1", ProductID: product.ID, SKUID: "sku1", Count: 5, Price: 1000}}, cart.CartProducts)
+}
+
+func TestAddProductMergesLegacyEmptySKUIDCartProduct(t *testing.T) {
+	// given（前提条件）SKU IDが空文字のカート商品とSKU IDを持つカート商品が同じSKUを指している
+	product := entity.Product{ID: "1", Status: enum.OnSale, Price: 1000, SKUs: []entity.ProductSKU{{ID: "sku1", StockCount: 5}}}
+	cart := entity.Cart{ID: "1", CartProducts: []entity.CartProduct{
+		{ID: "1", CartID: "1", ProductID: product.ID, SKUID: "", Count: 1, Price: 1000},
+		{ID: "2", CartID: "1", ProductID: product.ID, SKUID: "sku1", Count: 2, Price: 1000},
+	}}
+
+	// when（操作）
+	err := cart.AddProduct(product, "sku1", 3)
+
+	// then（期待する結果）1件のカート商品にまとめて在庫数を確認する
+	assert.NotNil(t, err)
+	assert.Equal(t, []entity.CartProduct{{ID: "1", CartID: "1", ProductID: product.ID, SKUID: "sku1", Count: 3, Price: 1000}}, cart.CartProducts)
+}
+
+func TestCartWarnings(t *testing.T) {
+	// given（前提条件）
+	cartID := "1"
+	productID := "2"
+
+	tests := []struct {
+		Name          string
+		CartProduct   entity.CartProduct
+		Product       entity.Product
+		ExpectedTypes []enum.CartWarningType
+	}{
+		{
+			Name:          "価格・商品ステータス・在庫数が変化していない場合、警告を返却しない",
+			CartProduct:   entity.CartProduct{ID: "1", CartID: cartID, ProductID: productID, Count: 1, Price: 100},
+			Product:       entity.Product{ID: productID, Status: enum.OnSale, StockCount: 1, Price: 100, SalePrice: 100},
+			ExpectedTypes: []enum.CartWarningType{},
+		},
+		{
+			Name:          "販売価格が上がった場合、値上がりの警告を返却する",
+			CartProduct:   entity.CartProduct{ID: "1", CartID: cartID, ProductID: productID, Count: 1, Price: 80},
+			Product:       entity.Product{ID: productID, Status: enum.OnSale, StockCount: 1, Price: 100, SalePrice: 100},
+			ExpectedTypes: []enum.CartWarningType{enum.CartWarningPriceIncreased},
+		},
+		{
+			Name:          "販売価格が下がりかつ在庫数を超えている場合、値下がりと在庫不足の警告を返却する",
+			CartProduct:   entity.CartProduct{ID: "1", CartID: cartID, ProductID: productID, Count: 3, Price: 100},
+			Product:       entity.Product{ID: productID, Status: enum.OnSale, StockCount: 2, Price: 100, SalePrice: 90},
+			ExpectedTypes: []enum.CartWarningType{enum.CartWarningPriceDecreased, enum.CartWarningStockShortage},
+		},
+		{
+			Name:          "販売停止中の場合、販売停止の警告のみを返却する",
+			CartProduct:   entity.CartProduct{ID: "1", CartID: cartID, ProductID: productID, Count: 3, Price: 80},
+			Product:       entity.Product{ID: productID, Status: enum.SalesSuspend, StockCount: 0, Price: 100},
+			ExpectedTypes: []enum.CartWarningType{enum.CartWarningSalesSuspend},
+		},
+		{
+			Name:          "販売終了の場合、販売終了の警告のみを返却する",
+			CartProduct:   entity.CartProduct{ID: "1", CartID: cartID, ProductID: productID, Count: 1, Price: 100},
+			Product:       entity.Product{ID: productID, Status: enum.SalesEnded, StockCount: 0, Price: 100},
+			ExpectedTypes: []enum.CartWarningType{enum.CartWarningSalesEnded},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			cart := entity.Cart{ID: cartID, CartProducts: []entity.CartProduct{tt.CartProduct}}
+
+			// when（操作）
+			warnings := cart.Warnings([]entity.Product{tt.Product})
+
+			// then（期待する結果）
+			types := make([]enum.CartWarningType, 0, len(warnings))
+			for _, warning := range warnings {
+				types = append(types, warning.Type)
+				assert.Equal(t, tt.CartProduct.ID, warning.CartProductID, "カート商品ID")
+				assert.NotEmpty(t, warning.Message, "メッセージ")
+			}
+			assert.Equal(t, tt.ExpectedTypes, types)
+		})
+	}
+}
+
+func TestMoveToSavedAndMoveToCart(t *testing.T) {
+	// given（前提条件）
+	cartID := "1"
+	productID := "2"
+	product := entity.Product{ID: productID, Status: enum.OnSale, StockCount: 5, Price: 100}
+	cart := entity.Cart{ID: cartID, CartProducts: []entity.CartProduct{{ID: "10", CartID: cartID, ProductID: productID, Count: 2, Price: 100}}}
+
+	// when（操作）カート内の商品をあとで買うに移動する
+	err := cart.MoveToSaved("10")
+
+	// then（期待する結果）
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(cart.CartProducts))
+	assert.Equal(t, 1, len(cart.SavedProducts))
+	assert.Equal(t, 0, cart.TotalCount(), "あとで買う商品は合計個数に含めない")
+	assert.Equal(t, 0, cart.TotalPrice([]entity.Product{product}), "あとで買う商品は合計金額に含めない")
+	assert.Equal(t, 2, cart.SavedCount())
+
+	// when（操作）あとで買う商品をカートに移動する
+	err = cart.MoveToCart(cart.SavedProducts[0].ID, product)
+
+	// then（期待する結果）
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(cart.CartProducts))
+	assert.Equal(t, 0, len(cart.SavedProducts))
+	assert.Equal(t, 2, cart.TotalCount())
+	assert.Equal(t, 200, cart.TotalPrice([]entity.Product{product}))
+}
+
+func TestMoveToSavedMergesSavedProduct(t *testing.T) {
+	// given（前提条件）同じSKUのあとで買う商品が以前の価格で保存されている
+	cart := entity.Cart{
+		ID:            "1",
+		CartProducts:  []entity.CartProduct{{ID: "10", CartID: "1", ProductID: "2", SKUID: "sku1", Count: 2, Price: 80}},
+		SavedProducts: []entity.SavedProduct{{ID: "20", CartID: "1", ProductID: "2", SKUID: "sku1", Count: 1, Price: 100}},
+	}
+
+	// when（操作）
+	err := cart.MoveToSaved("10")
+
+	// then（期待する結果）個数を合算し、価格をカート商品の価格に更新する
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(cart.CartProducts))
+	assert.Equal(t, []entity.SavedProduct{{ID: "20", CartID: "1", ProductID: "2", SKUID: "sku1", Count: 3, Price: 80}}, cart.SavedProducts)
+}
+
+func TestMoveToCartWhenProductIsNotOnSale(t *testing.T) {
+	// given（前提条件）
+	cartID := "1"
+	productID := "2"
+	product := entity.Product{ID: productID, Status: enum.SalesEnded, StockCount: 5, Price: 100}
+	cart := entity.Cart{ID: cartID, SavedProducts: []entity.SavedProduct{{ID: "10", CartID: cartID, ProductID: productID, Count: 1, Price: 100}}}
+
+	// when（操作）
+	err := cart.MoveToCart("10", product)
+
+	// then（期待する結果）販売中ではない商品はカートに移動せず、あとで買うに残す
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(cart.CartProducts))
+	assert.Equal(t, 1, len(cart.SavedProducts))
+}
+
+func TestMoveToCartWhenSavedProductsAreMerged(t *testing.T) {
+	// given（前提条件）SKU IDが空文字のあとで買う商品とSKU IDを持つあとで買う商品が同じSKUを指している
+	product := entity.Product{ID: "1", Status: enum.OnSale, Price: 1000, SKUs: []entity.ProductSKU{{ID: "sku1", StockCount: 5}}}
+	cart := entity.Cart{ID: "1", SavedProducts: []entity.SavedProduct{
+		{ID: "10", CartID: "1", ProductID: "9", SKUID: "sku9", Count: 1, Price: 500},
+		{ID: "11", CartID: "1", ProductID: product.ID, SKUID: "", Count: 1, Price: 1000},
+		{ID: "12", CartID: "1", ProductID: product.ID, SKUID: "sku1", Count: 2, Price: 1000},
+	}}
+
+	// when（操作）SKU IDを持つあとで買う商品をカートに移動する
+	err := cart.MoveToCart("12", product)
+
+	// then（期待する結果）移動したあとで買う商品のみを取り除き、他のあとで買う商品はSKU IDを変換して残す
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(cart.CartProducts))
+	assert.Equal(t, "sku1", cart.CartProducts[0].SKUID)
+	assert.Equal(t, 2, cart.CartProducts[0].Count)
+	assert.Equal(t, []entity.SavedProduct{
+		{ID: "10", CartID: "1", ProductID: "9", SKUID: "sku9", Count: 1, Price: 500},
+		{ID: "11", CartID: "1", ProductID: product.ID, SKUID: "sku1", Count: 1, Price: 1000},
+	}, cart.SavedProducts)
+}
+
+func TestMoveSessionCartSavedProductsToCart(t *testing.T) {
+	// given（前提条件）
+	cartID := "1"
+	cart := entity.Cart{
+		ID:            cartID,
+		CartProducts:  []entity.CartProduct{{ID: "10", CartID: cartID, ProductID: "2", Count: 1, Price: 100}},
+		SavedProducts: []entity.SavedProduct{{ID: "20", CartID: cartID, ProductID: "3", Count: 1, Price: 100}},
+	}
+	sessionCart := entity.SessionCart{SessionSavedProducts: []entity.SessionCartProduct{
+		{ProductID: "2", Count: 1}, // カート内に存在する商品
+		{ProductID: "3", Count: 2}, // あとで買うに存在する商品
+		{ProductID: "4", Count: 1}, // 販売停止中の商品
+		{ProductID: "5", Count: 1}, // 存在しない商品
+	}}
+	products := []entity.Product{
+		{ID: "2", Status: enum.OnSale, StockCount: 10, Price: 100},
+		{ID: "3", Status: enum.OnSale, StockCount: 10, Price: 100},
+		{ID: "4", Status: enum.SalesSuspend, StockCount: 0, Price: 300},
+	}
+
+	// when（操作）
+	cart.MoveSessionCartProductsToCart(sessionCart, products)
+
+	// then（期待する結果）
+	assert.Equal(t, 1, len(cart.CartProducts))
+	assert.Equal(t, 2, len(cart.SavedProducts))
+	assert.Equal(t, "3", cart.SavedProducts[0].ProductID)
+	assert.Equal(t, 3, cart.SavedProducts[0].Count)
+	assert.Equal(t, "4", cart.SavedProducts[1].ProductID)
+	assert.Equal(t, 300, cart.SavedProducts[1].Price)
+}
+
+func TestNeedsAbandonedReminder(t *testing.T) {
+	// given（前提条件）
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.Local)
+	period := 24 * time.Hour
+	cartProducts := []entity.CartProduct{{ID: "10", CartID: "1", ProductID: "2", Count: 1, Price: 100}}
+	beforeUpdate := now.Add(-72 * time.Hour)
+	afterUpdate := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		Name     string
+		Cart     entity.Cart
+		Expected bool
+	}{
+		{
+			Name:     "カート内に商品が存在しない場合、送信しない",
+			Cart:     entity.Cart{ID: "1", UpdatedAt: now.Add(-48 * time.Hour)},
+			Expected: false,
+		},
+		{
+			Name:     "最終更新日時から期間が経過していない場合、送信しない",
+			Cart:     entity.Cart{ID: "1", UpdatedAt: now.Add(-time.Hour), CartProducts: cartProducts},
+			Expected: false,
+		},
+		{
+			Name:     "最終更新日時から期間が経過していてリマインドメールを送信していない場合、送信する",
+			Cart:     entity.Cart{ID: "1", UpdatedAt: now.Add(-48 * time.Hour), CartProducts: cartProducts},
+			Expected: true,
+		},
+		{
+			Name:     "最終更新後にリマインドメールを送信済みの場合、送信しない",
+			Cart:     entity.Cart{ID: "1", UpdatedAt: now.Add(-48 * time.Hour), ReminderSentAt: &afterUpdate, CartProducts: cartProducts},
+			Expected: false,
+		},
+		{
+			Name:     "リマインドメール送信後にカートが更新された場合、再度送信する",
+			Cart:     entity.Cart{ID: "1", UpdatedAt: now.Add(-48 * time.Hour), ReminderSentAt: &beforeUpdate, CartProducts: cartProducts},
+			Expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			result := tt.Cart.NeedsAbandonedReminder(now, period)
+
+			// then（期待する結果）
+			assert.Equal(t, tt.Expected, result)
+		})
+	}
+}
+
+func TestRemovePurchasedProducts(t *testing.T) {
+	// given（前提条件）
+	cart := entity.Cart{ID: "cart", CartProducts: []entity.CartProduct{
+		{ID: "1", ProductID: "1", SKUID: "sku1", Count: 2},
+		{ID: "2", ProductID: "1", SKUID: "sku2", Count: 3},
+		{ID: "3", ProductID: "2", SKUID: "", Count: 1},
+		{ID: "4", ProductID: "3", SKUID: "", Count: 1}, // SKUが1件のみの商品をSKU指定なしで追加した
+	}}
+	items := []entity.StockReservationItem{
+		{ProductID: "1", SKUID: "sku1", Count: 2},
+		{ProductID: "1", SKUID: "sku2", Count: 1},
+		{ProductID: "2", SKUID: "", Count: 1},
+		{ProductID: "3", SKUID: "sku3", Count: 1},
+	}
+
+	// when（操作）
+	cart.RemovePurchasedProducts(items)
+
+	// then（期待する結果）購入した個数分を取り除き、購入手続きの開始後に追加した個数は残す
+	assert.Equal(t, []entity.CartProduct{{ID: "2", ProductID: "1", SKUID: "sku2", Count: 2}}, cart.CartProducts)
+}
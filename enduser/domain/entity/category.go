@@ -0,0 +1,76 @@
+package entity
+
+import "sort"
+
+type (
+	// カテゴリー
+	Category struct {
+		ID        string
+		ParentID  *string // 最上位のカテゴリーの場合はnil
+		Name      string
+		Slug      string // URLに使用するカテゴリーの識別子
+		SortOrder int    // 同じ親カテゴリーを持つカテゴリーの表示順
+	}
+
+	// カテゴリーツリーの節点
+	CategoryNode struct {
+		Category
+		Children []CategoryNode
+	}
+)
+
+// カテゴリー配列からカテゴリーツリーを作成し、最上位のカテゴリーの節点配列を返却する
+// 同じ親カテゴリーを持つカテゴリーは表示順・IDの順に並べる。親カテゴリーが存在しないカテゴリーは最上位のカテゴリーとして扱う
+func BuildCategoryTree(categories []Category) []CategoryNode {
+	exists := make(map[string]struct{}, len(categories))
+	for _, category := range categories {
+		exists[category.ID] = struct{}{}
+	}
+
+	childrenMap := map[string][]Category{}
+	roots := []Category{}
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		if _, ok := exists[*category.ParentID]; !ok {
+			roots = append(roots, category)
+			continue
+		}
+		childrenMap[*category.ParentID] = append(childrenMap[*category.ParentID], category)
+	}
+
+	visited := map[string]struct{}{}
+	return buildCategoryNodes(roots, childrenMap, visited)
+}
+
+// カテゴリー配列の節点配列を子孫のカテゴリーを含めて作成する（循環している場合は一度訪れたカテゴリーを含めない）
+func buildCategoryNodes(categories []Category, childrenMap map[string][]Category, visited map[string]struct{}) []CategoryNode {
+	sortCategories(categories)
+
+	nodes := make([]CategoryNode, 0, len(categories))
+	for _, category := range categories {
+		if _, ok := visited[category.ID]; ok {
+			continue
+		}
+		visited[category.ID] = struct{}{}
+
+		nodes = append(nodes, CategoryNode{
+			Category: category,
+			Children: buildCategoryNodes(childrenMap[category.ID], childrenMap, visited),
+		})
+	}
+
+	return nodes
+}
+
+// カテゴリー配列を表示順・IDの順に並べる
+func sortCategories(categories []Category) {
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].SortOrder != categories[j].SortOrder {
+			return categories[i].SortOrder < categories[j].SortOrder
+		}
+		return categories[i].ID < categories[j].ID
+	})
+}
@@ -0,0 +1,72 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCategoryTree(t *testing.T) {
+	fashion := "fashion"
+	tops := "tops"
+	unknown := "unknown"
+
+	tests := []struct {
+		Name       string
+		Categories []entity.Category
+		Expected   []entity.CategoryNode
+	}{
+		{
+			Name: "親カテゴリーの子として表示順・IDの順に並べる",
+			Categories: []entity.Category{
+				{ID: "shirts", ParentID: &tops, Name: "シャツ", SortOrder: 2},
+				{ID: "food", Name: "食品", SortOrder: 2},
+				{ID: "tops", ParentID: &fashion, Name: "トップス", SortOrder: 1},
+				{ID: "tshirts", ParentID: &tops, Name: "Tシャツ", SortOrder: 1},
+				{ID: "fashion", Name: "ファッション", SortOrder: 1},
+				{ID: "bottoms", ParentID: &fashion, Name: "ボトムス", SortOrder: 1},
+			},
+			Expected: []entity.CategoryNode{
+				{
+					Category: entity.Category{ID: "fashion", Name: "ファッション", SortOrder: 1},
+					Children: []entity.CategoryNode{
+						{Category: entity.Category{ID: "bottoms", ParentID: &fashion, Name: "ボトムス", SortOrder: 1}, Children: []entity.CategoryNode{}},
+						{
+							Category: entity.Category{ID: "tops", ParentID: &fashion, Name: "トップス", SortOrder: 1},
+							Children: []entity.CategoryNode{
+								{Category: entity.Category{ID: "tshirts", ParentID: &tops, Name: "Tシャツ", SortOrder: 1}, Children: []entity.CategoryNode{}},
+								{Category: entity.Category{ID: "shirts", ParentID: &tops, Name: "シャツ", SortOrder: 2}, Children: []entity.CategoryNode{}},
+							},
+						},
+					},
+				},
+				{Category: entity.Category{ID: "food", Name: "食品", SortOrder: 2}, Children: []entity.CategoryNode{}},
+			},
+		},
+		{
+			Name: "親カテゴリーが存在しない場合は最上位のカテゴリーとして扱う",
+			Categories: []entity.Category{
+				{ID: "orphan", ParentID: &unknown, Name: "迷子"},
+			},
+			Expected: []entity.CategoryNode{
+				{Category: entity.Category{ID: "orphan", ParentID: &unknown, Name: "迷子"}, Children: []entity.CategoryNode{}},
+			},
+		},
+		{
+			Name:       "カテゴリーが存在しない場合は空配列を返却する",
+			Categories: []entity.Category{},
+			Expected:   []entity.CategoryNode{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			result := entity.BuildCategoryTree(tt.Categories)
+
+			// then（期待する結果）
+			assert.Equal(t, tt.Expected, result)
+		})
+	}
+}
@@ -22,7 +22,9 @@ type (
 		Version        int
 		CreateDateTime time.Time
 
-		ProductImages []ProductImage
+		ProductImages []ProductImage      // 商品全体の画像（SKUごとの画像は含めない）
+		OptionAxes    []ProductOptionAxis // バリエーションの軸（例：サイズ・カラー）
+		SKUs          []ProductSKU        // バリエーションごとの在庫管理単位（バリエーションを持たない商品の場合は空配列）
 	}
 
 	// 商品画像
@@ -31,11 +33,67 @@ type (
 		ProductID string
 		Order     int
 		Path      string
-		Image     string // TODO 型を正しくする必要あり
+		Image     string // 画像のURL（画像を取得しない場合は空文字）
+
+		Variants []ProductImageVariant // 派生画像（生成前の場合は空配列）
+	}
+
+	// 商品画像を縮小・変換した派生画像
+	ProductImageVariant struct {
+		ID             string
+		ProductImageID string
+		Width          int
+		Format         enum.ImageFormat
+		Path           string
+		Image          string // 画像のURL（画像を取得しない場合は空文字）
 	}
 )
 
+const ProductFewStockThreshold = 5 // 在庫数がこの値以下の場合は残りわずかとする
+
+var (
+	ProductImageVariantWidths  = []int{200, 600, 1200}                                          // 派生画像の幅（px）
+	ProductImageVariantFormats = []enum.ImageFormat{enum.ImageFormatWebP, enum.ImageFormatJPEG} // 派生画像の画像形式
+)
+
 // 商品が販売中の場合trueを、そうでない場合falseを返却する
 func (product Product) isOnSale() bool {
 	return product.Status == enum.OnSale
 }
+
+// 実際に販売される価格（税込）を返却する
+// セール価格が設定されており通常価格より安い場合はセール価格を、そうでない場合は通常価格を返却する
+func (product Product) EffectivePrice() int {
+	if product.SalePrice > 0 && product.SalePrice < product.Price {
+		return product.SalePrice
+	}
+
+	return product.Price
+}
+
+// セール価格による割引率（%、小数点以下切り捨て）を返却する
+// セール価格が適用されない場合は0を返却する
+func (product Product) DiscountRate() int {
+	if product.Price <= 0 {
+		return 0
+	}
+
+	return (product.Price - product.EffectivePrice()) * 100 / product.Price
+}
+
+// 在庫数に応じた在庫状況を返却する
+func (product Product) StockStatus() enum.StockStatus {
+	return stockStatus(product.StockCount)
+}
+
+func stockStatus(stockCount int) enum.StockStatus {
+	if stockCount <= 0 {
+		return enum.StockStatusSoldOut
+	}
+
+	if stockCount <= ProductFewStockThreshold {
+		return enum.StockStatusFewLeft
+	}
+
+	return enum.StockStatusInStock
+}
@@ -0,0 +1,54 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscountRate(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Product  entity.Product
+		Expected int
+	}{
+		{Name: "セール価格が通常価格より安い場合、割引率を返却する", Product: entity.Product{Price: 1000, SalePrice: 800}, Expected: 20},
+		{Name: "割引率の小数点以下は切り捨てる", Product: entity.Product{Price: 300, SalePrice: 200}, Expected: 33},
+		{Name: "セール価格が通常価格以上の場合、0を返却する", Product: entity.Product{Price: 1000, SalePrice: 1000}, Expected: 0},
+		{Name: "セール価格が設定されていない場合、0を返却する", Product: entity.Product{Price: 1000, SalePrice: 0}, Expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			result := tt.Product.DiscountRate()
+
+			// then（期待する結果）
+			assert.Equal(t, tt.Expected, result)
+		})
+	}
+}
+
+func TestStockStatus(t *testing.T) {
+	tests := []struct {
+		Name       string
+		StockCount int
+		Expected   enum.StockStatus
+	}{
+		{Name: "在庫数が0の場合、在庫なし", StockCount: 0, Expected: enum.StockStatusSoldOut},
+		{Name: "在庫数が閾値以下の場合、残りわずか", StockCount: entity.ProductFewStockThreshold, Expected: enum.StockStatusFewLeft},
+		{Name: "在庫数が閾値より多い場合、在庫あり", StockCount: entity.ProductFewStockThreshold + 1, Expected: enum.StockStatusInStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			result := entity.Product{StockCount: tt.StockCount}.StockStatus()
+
+			// then（期待する結果）
+			assert.Equal(t, tt.Expected, result)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+)
+
+type (
+	// 商品一覧の検索条件
+	ProductCatalogCondition struct {
+		CategoryID  string // 空文字の場合は絞り込まない
+		MinPrice    int    // 販売価格（税込）の下限（0の場合は絞り込まない）
+		MaxPrice    int    // 販売価格（税込）の上限（0の場合は絞り込まない）
+		OnSaleOnly  bool   // trueの場合は販売中の商品のみ
+		InStockOnly bool   // trueの場合は在庫が存在する商品のみ
+		Sort        enum.ProductSort
+		Cursor      *ProductCatalogCursor // 前ページの最後の商品の位置（nilの場合は先頭ページ）
+		Limit       int
+	}
+
+	// 商品一覧のキーセットページネーションのカーソル
+	// 並び順のキーとなる値と商品IDの組で前ページの最後の商品の位置を表す
+	ProductCatalogCursor struct {
+		Value          int       `json:"v"` // 販売価格（税込）またはレビュー点数
+		CreateDateTime time.Time `json:"t"`
+		ProductID      string    `json:"id"`
+	}
+
+	// 商品一覧の1ページ
+	ProductCatalogPage struct {
+		Products   []Product
+		NextCursor *ProductCatalogCursor // 次ページが存在しない場合はnil
+		Facets     *ProductFacets        // 絞り込み条件ごとの商品数（先頭ページ以外はnil）
+	}
+)
+
+const (
+	ProductCatalogDefaultLimit = 20
+	ProductCatalogMaxLimit     = 100
+)
+
+// 商品一覧の次ページのカーソルを商品から作成する
+func CreateProductCatalogCursor(product Product, sort enum.ProductSort) ProductCatalogCursor {
+	return CreateProductCatalogCursorFromKeys(product.ID, product.EffectivePrice(), product.ReviewScore, product.CreateDateTime, sort)
+}
+
+// 商品一覧の次ページのカーソルを並び順のキーとなる値から作成する
+func CreateProductCatalogCursorFromKeys(productID string, effectivePrice int, reviewScore int, createDateTime time.Time, sort enum.ProductSort) ProductCatalogCursor {
+	cursor := ProductCatalogCursor{
+		CreateDateTime: createDateTime,
+		ProductID:      productID,
+	}
+
+	switch sort {
+	case enum.ProductSortPriceAsc, enum.ProductSortPriceDesc:
+		cursor.Value = effectivePrice
+	case enum.ProductSortReviewScore:
+		cursor.Value = reviewScore
+	}
+
+	return cursor
+}
+
+// カーソルをURLに含められる文字列に変換する
+func (cursor ProductCatalogCursor) Encode() string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// 文字列をカーソルに変換する（不正な文字列の場合はfalseを返却する）
+func DecodeProductCatalogCursor(s string) (ProductCatalogCursor, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ProductCatalogCursor{}, false
+	}
+
+	var cursor ProductCatalogCursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || cursor.ProductID == "" {
+		return ProductCatalogCursor{}, false
+	}
+
+	return cursor, true
+}
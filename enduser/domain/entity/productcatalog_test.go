@@ -0,0 +1,59 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProductCatalogCursor(t *testing.T) {
+	// given（前提条件）
+	createDateTime := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	product := entity.Product{ID: "1", Price: 1000, SalePrice: 800, ReviewScore: 4, CreateDateTime: createDateTime}
+
+	tests := []struct {
+		Name          string
+		Sort          enum.ProductSort
+		ExpectedValue int
+	}{
+		{Name: "新着順の場合、並び順のキーの値は0", Sort: enum.ProductSortNewest, ExpectedValue: 0},
+		{Name: "価格の安い順の場合、販売価格をキーにする", Sort: enum.ProductSortPriceAsc, ExpectedValue: 800},
+		{Name: "価格の高い順の場合、販売価格をキーにする", Sort: enum.ProductSortPriceDesc, ExpectedValue: 800},
+		{Name: "レビュー点数順の場合、レビュー点数をキーにする", Sort: enum.ProductSortReviewScore, ExpectedValue: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			cursor := entity.CreateProductCatalogCursor(product, tt.Sort)
+
+			// then（期待する結果）
+			assert.Equal(t, tt.ExpectedValue, cursor.Value)
+			assert.Equal(t, "1", cursor.ProductID)
+			assert.True(t, createDateTime.Equal(cursor.CreateDateTime))
+		})
+	}
+}
+
+func TestDecodeProductCatalogCursor(t *testing.T) {
+	// given（前提条件）
+	cursor := entity.ProductCatalogCursor{Value: 800, CreateDateTime: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), ProductID: "1"}
+
+	// when（操作）
+	decoded, ok := entity.DecodeProductCatalogCursor(cursor.Encode())
+
+	// then（期待する結果）
+	assert.True(t, ok)
+	assert.Equal(t, cursor.Value, decoded.Value)
+	assert.Equal(t, cursor.ProductID, decoded.ProductID)
+	assert.True(t, cursor.CreateDateTime.Equal(decoded.CreateDateTime))
+
+	// when（操作）不正な文字列の場合
+	_, ok = entity.DecodeProductCatalogCursor("invalid cursor")
+
+	// then（期待する結果）
+	assert.False(t, ok)
+}
@@ -0,0 +1,74 @@
+package entity
+
+type (
+	// 商品一覧の絞り込み条件ごとの商品数
+	// 各絞り込み条件の商品数は、その絞り込み条件以外の現在の検索条件に一致する商品数とする
+	ProductFacets struct {
+		Categories       []CategoryFacet
+		PriceBuckets     []PriceBucketFacet
+		ReviewScoreBands []ReviewScoreBandFacet
+		InStockCount     int
+		OutOfStockCount  int
+	}
+
+	// カテゴリーごとの商品数
+	CategoryFacet struct {
+		CategoryID   string
+		CategoryName string
+		Count        int
+	}
+
+	// 価格帯
+	PriceBucket struct {
+		MinPrice int // 下限（この値を含む）
+		MaxPrice int // 上限（この値を含まない。0の場合は上限なし）
+	}
+
+	// 価格帯ごとの商品数
+	PriceBucketFacet struct {
+		PriceBucket
+		Count int
+	}
+
+	// レビュー点数がMinScore以上の商品数
+	ReviewScoreBandFacet struct {
+		MinScore int
+		Count    int
+	}
+)
+
+// 商品一覧の絞り込みに使用する価格帯（販売価格・税込）
+var ProductPriceBuckets = []PriceBucket{
+	{MinPrice: 0, MaxPrice: 1000},
+	{MinPrice: 1000, MaxPrice: 3000},
+	{MinPrice: 3000, MaxPrice: 5000},
+	{MinPrice: 5000, MaxPrice: 10000},
+	{MinPrice: 10000, MaxPrice: 0},
+}
+
+// 商品一覧の絞り込みに使用するレビュー点数の下限（「4点以上」など）
+var ProductReviewScoreBands = []int{4, 3, 2, 1}
+
+// 価格帯の番号ごとの商品数から価格帯ごとの商品数を作成する
+func CreatePriceBucketFacets(countsByBucket map[int]int) []PriceBucketFacet {
+	facets := make([]PriceBucketFacet, 0, len(ProductPriceBuckets))
+	for i, bucket := range ProductPriceBuckets {
+		facets = append(facets, PriceBucketFacet{PriceBucket: bucket, Count: countsByBucket[i]})
+	}
+	return facets
+}
+
+// レビュー点数ごとの商品数から、レビュー点数の下限ごとの商品数（その点数以上の商品数）を作成する
+func CreateReviewScoreBandFacets(countsByScore map[int]int) []ReviewScoreBandFacet {
+	facets := make([]ReviewScoreBandFacet, 0, len(ProductReviewScoreBands))
+	for _, minScore := range ProductReviewScoreBands {
+		count := 0
+		for score, c := range countsByScore {
+			if score >= minScore {
+				count += c
+			}
+		}
+		facets = append(facets, ReviewScoreBandFacet{MinScore: minScore, Count: count})
+	}
+	return facets
+}
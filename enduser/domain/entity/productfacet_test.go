@@ -0,0 +1,39 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePriceBucketFacets(t *testing.T) {
+	// given（前提条件）価格帯の番号ごとの商品数（集計対象外の-1を含む）
+	countsByBucket := map[int]int{0: 3, 2: 5, -1: 1}
+
+	// when（操作）
+	facets := entity.CreatePriceBucketFacets(countsByBucket)
+
+	// then（期待する結果）すべての価格帯を順番どおりに返却し、商品が存在しない価格帯は0件にする
+	assert.Equal(t, len(entity.ProductPriceBuckets), len(facets))
+	for i, facet := range facets {
+		assert.Equal(t, entity.ProductPriceBuckets[i], facet.PriceBucket)
+		assert.Equal(t, countsByBucket[i], facet.Count)
+	}
+}
+
+func TestCreateReviewScoreBandFacets(t *testing.T) {
+	// given（前提条件）レビュー点数ごとの商品数（0点はレビューなし）
+	countsByScore := map[int]int{0: 10, 1: 1, 2: 2, 3: 3, 4: 4, 5: 5}
+
+	// when（操作）
+	facets := entity.CreateReviewScoreBandFacets(countsByScore)
+
+	// then（期待する結果）下限以上の点数の商品数を合計する
+	assert.Equal(t, []entity.ReviewScoreBandFacet{
+		{MinScore: 4, Count: 9},
+		{MinScore: 3, Count: 12},
+		{MinScore: 2, Count: 14},
+		{MinScore: 1, Count: 15},
+	}, facets)
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+// 派生画像の生成に失敗した商品画像の失敗記録
+// 失敗回数が上限に達した商品画像は生成不能（デッドレター）として派生画像の生成の対象から除外する
+type ProductImageVariantFailure struct {
+	ProductImageID      string
+	AttemptCount        int    // 生成に失敗した回数
+	LastError           string // 最後に失敗したときのエラー
+	LastAttemptDateTime time.Time
+	DeadLetterDateTime  *time.Time // 生成の対象から除外した日時（除外していない場合はnil）
+}
+
+const ProductImageVariantMaxAttempts = 5 // 派生画像の生成を試みる最大回数
+
+// 商品画像の派生画像の生成に失敗した記録を作成する（失敗回数は0件）
+func CreateProductImageVariantFailure(productImageID string) ProductImageVariantFailure {
+	return ProductImageVariantFailure{ProductImageID: productImageID}
+}
+
+// 派生画像の生成の失敗を記録し、失敗回数が上限に達した場合は生成の対象から除外する
+func (failure *ProductImageVariantFailure) RecordAttempt(errMessage string, now time.Time) {
+	failure.AttemptCount++
+	failure.LastError = errMessage
+	failure.LastAttemptDateTime = now
+	if failure.AttemptCount >= ProductImageVariantMaxAttempts && failure.DeadLetterDateTime == nil {
+		failure.DeadLetterDateTime = &now
+	}
+}
+
+// 生成の対象から除外されている場合trueを返却する
+func (failure ProductImageVariantFailure) IsDeadLettered() bool {
+	return failure.DeadLetterDateTime != nil
+}
+
+// 元画像の幅から生成する派生画像の幅を返却する
+// 元画像より大きい幅には拡大しない。元画像がすべての幅より小さい場合は元画像の幅の派生画像のみ生成する
+func ProductImageVariantWidthsFor(originalWidth int) []int {
+	widths := []int{}
+	for _, width := range ProductImageVariantWidths {
+		if width <= originalWidth {
+			widths = append(widths, width)
+		}
+	}
+
+	if len(widths) == 0 && originalWidth > 0 {
+		widths = append(widths, originalWidth)
+	}
+	return widths
+}
+
+// 商品画像の派生画像を作成する
+// 派生画像は元画像と同じディレクトリに「元画像のファイル名_幅w.拡張子」のパスで保存する（例：/products/1/image.jpg → /products/1/image_200w.webp）
+func (image ProductImage) CreateVariant(width int, format enum.ImageFormat) ProductImageVariant {
+	ext := path.Ext(image.Path)
+	base := strings.TrimSuffix(image.Path, ext)
+
+	return ProductImageVariant{
+		ID:             util.IDutils.GenerateID(),
+		ProductImageID: image.ID,
+		Width:          width,
+		Format:         format,
+		Path:           fmt.Sprintf("%s_%dw%s", base, width, format.Extension()),
+	}
+}
+
+// 派生画像のURLを画像形式ごとにsrcset属性の形式（例「https://.../image_200w.webp 200w, https://.../image_600w.webp 600w」）で返却する
+// 派生画像のURLを取得していない場合は空のマップを返却する
+func (image ProductImage) Srcset() map[enum.ImageFormat]string {
+	variantsByFormat := map[enum.ImageFormat][]ProductImageVariant{}
+	for _, variant := range image.Variants {
+		if variant.Image == "" {
+			continue
+		}
+		variantsByFormat[variant.Format] = append(variantsByFormat[variant.Format], variant)
+	}
+
+	srcset := make(map[enum.ImageFormat]string, len(variantsByFormat))
+	for format, variants := range variantsByFormat {
+		sort.Slice(variants, func(i, j int) bool {
+			return variants[i].Width < variants[j].Width
+		})
+
+		candidates := make([]string, 0, len(variants))
+		for _, variant := range variants {
+			candidates = append(candidates, fmt.Sprintf("%s %dw", variant.Image, variant.Width))
+		}
+		srcset[format] = strings.Join(candidates, ", ")
+	}
+	return srcset
+}
@@ -0,0 +1,121 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductImageVariantWidthsFor(t *testing.T) {
+	tests := []struct {
+		Name          string
+		OriginalWidth int
+		Expected      []int
+	}{
+		{Name: "元画像がすべての幅以上の場合はすべての幅", OriginalWidth: 2000, Expected: []int{200, 600, 1200}},
+		{Name: "元画像より大きい幅には拡大しない", OriginalWidth: 800, Expected: []int{200, 600}},
+		{Name: "元画像と同じ幅は含める", OriginalWidth: 600, Expected: []int{200, 600}},
+		{Name: "元画像がすべての幅より小さい場合は元画像の幅", OriginalWidth: 150, Expected: []int{150}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			result := entity.ProductImageVariantWidthsFor(tt.OriginalWidth)
+
+			// then（期待する結果）
+			assert.Equal(t, tt.Expected, result)
+		})
+	}
+}
+
+func TestProductImageCreateVariant(t *testing.T) {
+	// given（前提条件）
+	image := entity.ProductImage{ID: "image1", ProductID: "1", Path: "/products/1/image.png"}
+
+	// when（操作）
+	variant := image.CreateVariant(600, enum.ImageFormatWebP)
+
+	// then（期待する結果）元画像と同じディレクトリに保存する
+	assert.NotEmpty(t, variant.ID)
+	assert.Equal(t, "image1", variant.ProductImageID)
+	assert.Equal(t, 600, variant.Width)
+	assert.Equal(t, enum.ImageFormatWebP, variant.Format)
+	assert.Equal(t, "/products/1/image_600w.webp", variant.Path)
+}
+
+func TestProductImageSrcset(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Variants []entity.ProductImageVariant
+		Expected map[enum.ImageFormat]string
+	}{
+		{
+			Name: "画像形式ごとに幅の小さい順に並べる",
+			Variants: []entity.ProductImageVariant{
+				{Width: 600, Format: enum.ImageFormatWebP, Image: "https://example.com/a_600w.webp"},
+				{Width: 200, Format: enum.ImageFormatWebP, Image: "https://example.com/a_200w.webp"},
+				{Width: 200, Format: enum.ImageFormatJPEG, Image: "https://example.com/a_200w.jpg"},
+			},
+			Expected: map[enum.ImageFormat]string{
+				enum.ImageFormatWebP: "https://example.com/a_200w.webp 200w, https://example.com/a_600w.webp 600w",
+				enum.ImageFormatJPEG: "https://example.com/a_200w.jpg 200w",
+			},
+		},
+		{
+			Name: "URLを取得していない場合は空",
+			Variants: []entity.ProductImageVariant{
+				{Width: 200, Format: enum.ImageFormatWebP},
+			},
+			Expected: map[enum.ImageFormat]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// given（前提条件）
+			image := entity.ProductImage{Variants: tt.Variants}
+
+			// when（操作）
+			result := image.Srcset()
+
+			// then（期待する結果）
+			assert.Equal(t, tt.Expected, result)
+		})
+	}
+}
+
+func TestProductImageVariantFailureRecordAttempt(t *testing.T) {
+	// given（前提条件）
+	now := time.Date(2024, 5, 25, 12, 0, 0, 0, time.Local)
+	failure := entity.CreateProductImageVariantFailure("1")
+
+	// when（操作）上限未満の回数失敗する
+	for i := 1; i < entity.ProductImageVariantMaxAttempts; i++ {
+		failure.RecordAttempt("decode error", now)
+	}
+
+	// then（期待する結果）生成の対象から除外しない
+	assert.Equal(t, entity.ProductImageVariantMaxAttempts-1, failure.AttemptCount)
+	assert.Equal(t, "decode error", failure.LastError)
+	assert.False(t, failure.IsDeadLettered())
+
+	// when（操作）上限の回数失敗する
+	deadLetterAt := now.Add(time.Hour)
+	failure.RecordAttempt("upload error", deadLetterAt)
+
+	// then（期待する結果）生成の対象から除外する
+	assert.Equal(t, entity.ProductImageVariantMaxAttempts, failure.AttemptCount)
+	assert.Equal(t, "upload error", failure.LastError)
+	assert.True(t, failure.IsDeadLettered())
+	assert.Equal(t, deadLetterAt, *failure.DeadLetterDateTime)
+
+	// when（操作）除外した後に再び失敗する
+	failure.RecordAttempt("upload error", deadLetterAt.Add(time.Hour))
+
+	// then（期待する結果）除外した日時は変更しない
+	assert.Equal(t, deadLetterAt, *failure.DeadLetterDateTime)
+}
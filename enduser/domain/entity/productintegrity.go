@@ -0,0 +1,153 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+)
+
+type (
+	// 商品データの整合性を検査するための商品集約を構成するテーブルのデータ
+	// 商品集約は当日のデータが揃っていることを前提とするため、検査には適用期間を絞り込まないデータを使用する
+	ProductIntegritySnapshot struct {
+		ProductID      string
+		CategoryID     string
+		CategoryExists bool
+		StockCount     int
+		SKUs           []ProductIntegritySKU
+		Images         []ProductIntegrityImage
+		Timelines      []ProductTimeline
+	}
+
+	// 整合性の検査に使用するSKUのデータ
+	ProductIntegritySKU struct {
+		ID         string
+		StockCount int
+	}
+
+	// 整合性の検査に使用する商品画像のデータ
+	ProductIntegrityImage struct {
+		ID    string
+		SKUID string // 商品全体の画像の場合は空文字
+		Order int
+	}
+
+	// 商品データの不整合
+	ProductIntegrityIssue struct {
+		ProductID    string                         `json:"productID"`
+		Type         enum.ProductIntegrityIssueType `json:"type"`
+		TimelineType enum.ProductTimelineType       `json:"timelineType,omitempty"`
+		SKUID        string                         `json:"skuID,omitempty"`
+		IDs          []string                       `json:"ids"` // 不整合の原因となった行のID
+		StartDate    *time.Time                     `json:"startDate,omitempty"`
+		EndDate      *time.Time                     `json:"endDate,omitempty"`
+		Message      string                         `json:"message"`
+	}
+)
+
+// 引数の期間（開始日・終了日を含む）について商品データの不整合を検査して返却する
+func (snapshot ProductIntegritySnapshot) Check(from time.Time, to time.Time) []ProductIntegrityIssue {
+	issues := []ProductIntegrityIssue{}
+
+	for _, timeline := range snapshot.Timelines {
+		for _, problem := range timeline.ProblemsBetween(from, to) {
+			startDate, endDate := problem.StartDate, problem.EndDate
+			issues = append(issues, ProductIntegrityIssue{
+				ProductID:    snapshot.ProductID,
+				Type:         toProductIntegrityIssueType(problem.Type),
+				TimelineType: timeline.Type,
+				IDs:          problem.EntryIDs,
+				StartDate:    &startDate,
+				EndDate:      &endDate,
+				Message:      fmt.Sprintf("%s: %s", timeline.Type, problem.Message()),
+			})
+		}
+	}
+
+	if !snapshot.CategoryExists {
+		issues = append(issues, ProductIntegrityIssue{
+			ProductID: snapshot.ProductID,
+			Type:      enum.ProductIntegrityIssueMissingCategory,
+			IDs:       []string{snapshot.CategoryID},
+			Message:   fmt.Sprintf("カテゴリー（%s）が存在しません", snapshot.CategoryID),
+		})
+	}
+
+	issues = append(issues, snapshot.checkImageOrders()...)
+
+	if snapshot.StockCount < 0 {
+		issues = append(issues, ProductIntegrityIssue{
+			ProductID: snapshot.ProductID,
+			Type:      enum.ProductIntegrityIssueNegativeStock,
+			IDs:       []string{snapshot.ProductID},
+			Message:   fmt.Sprintf("在庫数が負の値です（%d）", snapshot.StockCount),
+		})
+	}
+	for _, sku := range snapshot.SKUs {
+		if sku.StockCount < 0 {
+			issues = append(issues, ProductIntegrityIssue{
+				ProductID: snapshot.ProductID,
+				Type:      enum.ProductIntegrityIssueNegativeStock,
+				SKUID:     sku.ID,
+				IDs:       []string{sku.ID},
+				Message:   fmt.Sprintf("SKU（%s）の在庫数が負の値です（%d）", sku.ID, sku.StockCount),
+			})
+		}
+	}
+
+	return issues
+}
+
+// 商品全体の画像・SKUごとの画像それぞれについて表示順の重複を検査する
+func (snapshot ProductIntegritySnapshot) checkImageOrders() []ProductIntegrityIssue {
+	type key struct {
+		skuID string
+		order int
+	}
+	imageIDs := map[key][]string{}
+	keys := []key{}
+	for _, image := range snapshot.Images {
+		k := key{skuID: image.SKUID, order: image.Order}
+		if _, ok := imageIDs[k]; !ok {
+			keys = append(keys, k)
+		}
+		imageIDs[k] = append(imageIDs[k], image.ID)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].skuID != keys[j].skuID {
+			return keys[i].skuID < keys[j].skuID
+		}
+		return keys[i].order < keys[j].order
+	})
+
+	issues := []ProductIntegrityIssue{}
+	for _, k := range keys {
+		ids := imageIDs[k]
+		if len(ids) < 2 {
+			continue
+		}
+
+		issues = append(issues, ProductIntegrityIssue{
+			ProductID: snapshot.ProductID,
+			Type:      enum.ProductIntegrityIssueDuplicateImageOrder,
+			SKUID:     k.skuID,
+			IDs:       ids,
+			Message:   fmt.Sprintf("商品画像の表示順（%d）が重複しています", k.order),
+		})
+	}
+
+	return issues
+}
+
+func toProductIntegrityIssueType(problemType enum.ProductTimelineProblemType) enum.ProductIntegrityIssueType {
+	switch problemType {
+	case enum.ProductTimelineProblemGap:
+		return enum.ProductIntegrityIssueTimelineGap
+	case enum.ProductTimelineProblemOverlap:
+		return enum.ProductIntegrityIssueTimelineOverlap
+	default:
+		return enum.ProductIntegrityIssueTimelineInvalid
+	}
+}
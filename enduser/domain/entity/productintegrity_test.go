@@ -0,0 +1,107 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductIntegritySnapshotCheck(t *testing.T) {
+	// given（前提条件）
+	from, to := timelineDate(4, 1), timelineDate(4, 30)
+	fullTimelines := func(productID string) []entity.ProductTimeline {
+		return []entity.ProductTimeline{
+			entity.NewProductTimeline(productID, enum.ProductTimelineTypePrice, []entity.ProductTimelineEntry{{ID: "p1", Value: 100, EffectiveStartDate: timelineDate(3, 1), EffectiveEndDate: timelineDate(5, 31)}}),
+			entity.NewProductTimeline(productID, enum.ProductTimelineTypeSalePrice, []entity.ProductTimelineEntry{{ID: "s1", Value: 0, EffectiveStartDate: timelineDate(3, 1), EffectiveEndDate: timelineDate(5, 31)}}),
+			entity.NewProductTimeline(productID, enum.ProductTimelineTypeStatus, []entity.ProductTimelineEntry{{ID: "t1", Value: enum.OnSale, EffectiveStartDate: timelineDate(3, 1), EffectiveEndDate: timelineDate(5, 31)}}),
+		}
+	}
+
+	tests := []struct {
+		Name          string
+		Snapshot      entity.ProductIntegritySnapshot
+		ExpectedTypes []enum.ProductIntegrityIssueType
+	}{
+		{
+			Name:          "不整合が存在しない場合、空配列を返却する",
+			Snapshot:      entity.ProductIntegritySnapshot{ProductID: "1", CategoryID: "c1", CategoryExists: true, StockCount: 1, Timelines: fullTimelines("1")},
+			ExpectedTypes: []enum.ProductIntegrityIssueType{},
+		},
+		{
+			Name: "期間の末尾に適用期間が存在しない場合、空白を返却する",
+			Snapshot: entity.ProductIntegritySnapshot{ProductID: "1", CategoryID: "c1", CategoryExists: true, Timelines: []entity.ProductTimeline{
+				entity.NewProductTimeline("1", enum.ProductTimelineTypePrice, []entity.ProductTimelineEntry{{ID: "p1", Value: 100, EffectiveStartDate: timelineDate(3, 1), EffectiveEndDate: timelineDate(4, 20)}}),
+			}},
+			ExpectedTypes: []enum.ProductIntegrityIssueType{enum.ProductIntegrityIssueTimelineGap},
+		},
+		{
+			Name: "期間外の重複は返却しない",
+			Snapshot: entity.ProductIntegritySnapshot{ProductID: "1", CategoryID: "c1", CategoryExists: true, Timelines: []entity.ProductTimeline{
+				entity.NewProductTimeline("1", enum.ProductTimelineTypeStatus, []entity.ProductTimelineEntry{
+					{ID: "t1", Value: enum.OnSale, EffectiveStartDate: timelineDate(3, 1), EffectiveEndDate: timelineDate(5, 10)},
+					{ID: "t2", Value: enum.OnSale, EffectiveStartDate: timelineDate(5, 5), EffectiveEndDate: timelineDate(5, 31)},
+				}),
+			}},
+			ExpectedTypes: []enum.ProductIntegrityIssueType{},
+		},
+		{
+			Name: "カテゴリーが存在しない・画像の表示順が重複・在庫数が負の値の場合、それぞれ返却する",
+			Snapshot: entity.ProductIntegritySnapshot{
+				ProductID:  "1",
+				CategoryID: "c1",
+				StockCount: -1,
+				SKUs:       []entity.ProductIntegritySKU{{ID: "sku1", StockCount: -2}},
+				Images: []entity.ProductIntegrityImage{
+					{ID: "i1", Order: 1}, {ID: "i2", Order: 1},
+					// SKUごとの画像は商品全体の画像と表示順が重複してもよい
+					{ID: "i3", SKUID: "sku1", Order: 1},
+				},
+				Timelines: fullTimelines("1"),
+			},
+			ExpectedTypes: []enum.ProductIntegrityIssueType{
+				enum.ProductIntegrityIssueMissingCategory,
+				enum.ProductIntegrityIssueDuplicateImageOrder,
+				enum.ProductIntegrityIssueNegativeStock,
+				enum.ProductIntegrityIssueNegativeStock,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			issues := tt.Snapshot.Check(from, to)
+
+			// then（期待する結果）
+			types := []enum.ProductIntegrityIssueType{}
+			for _, issue := range issues {
+				types = append(types, issue.Type)
+			}
+			assert.Equal(t, tt.ExpectedTypes, types)
+		})
+	}
+}
+
+func TestProductTimelineProblemsBetween(t *testing.T) {
+	// given（前提条件）4/5〜4/25のみ適用期間が存在する
+	timeline := entity.NewProductTimeline("1", enum.ProductTimelineTypePrice, []entity.ProductTimelineEntry{
+		{ID: "1", Value: 100, EffectiveStartDate: timelineDate(4, 5), EffectiveEndDate: timelineDate(4, 25)},
+	})
+
+	// when（操作）
+	problems := timeline.ProblemsBetween(timelineDate(4, 1), timelineDate(4, 30))
+
+	// then（期待する結果）期間の先頭と末尾の空白を返却する
+	assert.Equal(t, []entity.ProductTimelineProblem{
+		{Type: enum.ProductTimelineProblemGap, EntryIDs: []string{"1"}, StartDate: timelineDate(4, 1), EndDate: timelineDate(4, 4)},
+		{Type: enum.ProductTimelineProblemGap, EntryIDs: []string{"1"}, StartDate: timelineDate(4, 26), EndDate: timelineDate(4, 30)},
+	}, problems)
+
+	// when・then（操作・期待する結果）適用期間が存在しない場合は期間全体を空白として返却する
+	empty := entity.NewProductTimeline("1", enum.ProductTimelineTypePrice, []entity.ProductTimelineEntry{})
+	assert.Equal(t, []entity.ProductTimelineProblem{
+		{Type: enum.ProductTimelineProblemGap, EntryIDs: []string{}, StartDate: timelineDate(4, 1), EndDate: timelineDate(4, 30)},
+	}, empty.ProblemsBetween(timelineDate(4, 1), timelineDate(4, 30)))
+}
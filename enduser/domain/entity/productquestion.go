@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+type (
+	// 商品の質問集約
+	// 投稿された質問は確認待ちとし、確認して公開するかショップスタッフが回答するまで公開しない
+	ProductQuestion struct {
+		ID             string
+		ProductID      string
+		AccountID      string
+		Body           string
+		Status         enum.ProductQuestionStatus
+		Version        int
+		CreateDateTime time.Time
+		UpdateDateTime time.Time
+
+		Answers []ProductAnswer // 回答日時の昇順
+	}
+
+	// 商品の質問への回答
+	ProductAnswer struct {
+		ID                string
+		QuestionID        string
+		ResponderType     enum.ProductAnswerResponderType
+		AccountID         string // 回答したお客様のアカウントID（ショップスタッフの回答の場合は空文字）
+		ResponderNickname string // 回答者名（ショップスタッフの回答の場合は空文字、取得していない場合も空文字）
+		Body              string
+		CreateDateTime    time.Time
+	}
+
+	// 質問一覧に表示する質問
+	ProductQuestionListItem struct {
+		ProductQuestion
+		ReviewNickname string // 質問者のレビュー投稿者名
+	}
+
+	// 質問一覧の1ページ
+	ProductQuestionPage struct {
+		Questions  []ProductQuestionListItem
+		TotalCount int // 公開中の質問数
+		Page       int
+		HasNext    bool
+	}
+)
+
+const ProductQuestionDefaultLimit = 10
+
+// 商品の質問集約を確認待ちの状態で作成する
+func CreateProductQuestion(productID string, accountID string, body string, now time.Time) ProductQuestion {
+	return ProductQuestion{
+		ID:             util.IDutils.GenerateID(),
+		ProductID:      productID,
+		AccountID:      accountID,
+		Body:           body,
+		Status:         enum.ProductQuestionStatusPending,
+		Version:        1,
+		CreateDateTime: now,
+		UpdateDateTime: now,
+		Answers:        []ProductAnswer{},
+	}
+}
+
+// 質問の公開状態を変更する（管理者による確認）
+func (question *ProductQuestion) Moderate(status enum.ProductQuestionStatus, now time.Time) error {
+	if status != enum.ProductQuestionStatusPublished && status != enum.ProductQuestionStatusRejected {
+		return share.CreateOriginalError(share.ErrorCodeValidation, []string{"公開状態は公開中または非公開を指定してください"})
+	}
+
+	question.Status = status
+	question.UpdateDateTime = now
+	return nil
+}
+
+// 質問に回答し、回答を返却する
+// ショップスタッフは確認待ちの質問にも回答でき、回答した質問は公開中にする（回答時に内容を確認したものとする）
+// 購入済みのお客様は公開中の他のアカウントの質問にのみ回答できる
+func (question *ProductQuestion) Answer(responderType enum.ProductAnswerResponderType, accountID string, body string, now time.Time) (ProductAnswer, error) {
+	switch responderType {
+	case enum.ProductAnswerResponderTypeStaff:
+		if question.Status == enum.ProductQuestionStatusRejected {
+			return ProductAnswer{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"非公開の質問には回答できません"})
+		}
+		question.Status = enum.ProductQuestionStatusPublished
+		accountID = ""
+	case enum.ProductAnswerResponderTypeBuyer:
+		if question.Status != enum.ProductQuestionStatusPublished {
+			return ProductAnswer{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"質問が見つかりません"})
+		}
+		if question.IsAskedBy(accountID) {
+			return ProductAnswer{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"自分の質問には回答できません"})
+		}
+	default:
+		return ProductAnswer{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"回答者の種別が不正です"})
+	}
+
+	answer := ProductAnswer{
+		ID:             util.IDutils.GenerateID(),
+		QuestionID:     question.ID,
+		ResponderType:  responderType,
+		AccountID:      accountID,
+		Body:           body,
+		CreateDateTime: now,
+	}
+	question.Answers = append(question.Answers, answer)
+	question.UpdateDateTime = now
+	return answer, nil
+}
+
+// 回答が存在する場合trueを返却する
+func (question ProductQuestion) IsAnswered() bool {
+	return len(question.Answers) > 0
+}
+
+// 引数accountIDのアカウントが投稿した質問の場合trueを返却する
+func (question ProductQuestion) IsAskedBy(accountID string) bool {
+	return question.AccountID == accountID
+}
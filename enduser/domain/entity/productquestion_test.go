@@ -0,0 +1,69 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProductQuestion(t *testing.T) {
+	// when（操作）
+	question := entity.CreateProductQuestion("product1", "account1", "サイズ感を教えてください", time.Now())
+
+	// then（期待する結果）確認が終わるまで公開しない
+	assert.Equal(t, enum.ProductQuestionStatusPending, question.Status)
+	assert.False(t, question.IsAnswered())
+	assert.Equal(t, 1, question.Version)
+}
+
+func TestProductQuestionAnswer(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Status         enum.ProductQuestionStatus
+		ResponderType  enum.ProductAnswerResponderType
+		AccountID      string
+		ExpectedErr    bool
+		ExpectedStatus enum.ProductQuestionStatus
+	}{
+		{Name: "ショップスタッフが確認待ちの質問に回答した場合、公開中にする", Status: enum.ProductQuestionStatusPending, ResponderType: enum.ProductAnswerResponderTypeStaff, ExpectedStatus: enum.ProductQuestionStatusPublished},
+		{Name: "ショップスタッフは非公開の質問に回答できない", Status: enum.ProductQuestionStatusRejected, ResponderType: enum.ProductAnswerResponderTypeStaff, ExpectedErr: true, ExpectedStatus: enum.ProductQuestionStatusRejected},
+		{Name: "お客様は公開中の他のアカウントの質問に回答できる", Status: enum.ProductQuestionStatusPublished, ResponderType: enum.ProductAnswerResponderTypeBuyer, AccountID: "account2", ExpectedStatus: enum.ProductQuestionStatusPublished},
+		{Name: "お客様は確認待ちの質問に回答できない", Status: enum.ProductQuestionStatusPending, ResponderType: enum.ProductAnswerResponderTypeBuyer, AccountID: "account2", ExpectedErr: true, ExpectedStatus: enum.ProductQuestionStatusPending},
+		{Name: "お客様は自分の質問に回答できない", Status: enum.ProductQuestionStatusPublished, ResponderType: enum.ProductAnswerResponderTypeBuyer, AccountID: "account1", ExpectedErr: true, ExpectedStatus: enum.ProductQuestionStatusPublished},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// given（前提条件）
+			question := entity.ProductQuestion{ID: "question1", AccountID: "account1", Status: tt.Status, Answers: []entity.ProductAnswer{}}
+
+			// when（操作）
+			answer, err := question.Answer(tt.ResponderType, tt.AccountID, "Mサイズで丁度良いです", time.Now())
+
+			// then（期待する結果）
+			assert.Equal(t, tt.ExpectedStatus, question.Status)
+			if tt.ExpectedErr {
+				assert.NotNil(t, err)
+				assert.False(t, question.IsAnswered())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, []entity.ProductAnswer{answer}, question.Answers)
+			assert.Equal(t, "question1", answer.QuestionID)
+			assert.Equal(t, tt.AccountID, answer.AccountID)
+		})
+	}
+}
+
+func TestProductQuestionModerate(t *testing.T) {
+	// given（前提条件）
+	question := entity.ProductQuestion{Status: enum.ProductQuestionStatusPending}
+
+	// when・then（操作・期待する結果）
+	assert.Nil(t, question.Moderate(enum.ProductQuestionStatusPublished, time.Now()))
+	assert.Equal(t, enum.ProductQuestionStatusPublished, question.Status)
+	assert.NotNil(t, question.Moderate(enum.ProductQuestionStatusPending, time.Now()))
+}
@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+type (
+	// 集計期間の商品ごとの販売数と閲覧数
+	ProductActivity struct {
+		ProductID  string
+		CategoryID string
+		SalesCount int
+		ViewCount  int
+	}
+
+	// ランキングのスナップショットの1行（集計日・集計期間・カテゴリーごとに順位の昇順で保存する）
+	ProductRanking struct {
+		ID         string
+		Date       time.Time // 集計日（集計日の前日までの販売数・閲覧数から集計する）
+		Period     enum.RankingPeriod
+		CategoryID string // サイト全体のランキングの場合は空文字
+		ProductID  string
+		Rank       int // 1始まりの順位
+		SalesCount int
+		ViewCount  int
+		Score      int
+	}
+
+	// ランキングの算出方法
+	ProductRankingCalculator struct {
+		SalesWeight int // 販売数1件を閲覧数何件分として数えるか
+		Limit       int // ランキングごとに保存する最大件数
+	}
+
+	// ランキングに表示する商品
+	ProductRankingItem struct {
+		Rank    int
+		Product Product
+	}
+
+	// ランキング
+	ProductRankingPage struct {
+		Date       time.Time // 集計日（ランキングが集計されていない場合はゼロ値）
+		Period     enum.RankingPeriod
+		CategoryID string
+		Items      []ProductRankingItem
+	}
+)
+
+const (
+	ProductRankingMaxLimit     = 100 // ランキングごとに保存・取得する最大件数
+	ProductRankingDefaultLimit = 20  // ランキングの取得件数の初期値
+)
+
+// ランキングの算出方法を作成する
+func NewProductRankingCalculator(salesWeight int, limit int) (ProductRankingCalculator, error) {
+	if salesWeight < 0 {
+		return ProductRankingCalculator{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"販売数の重みは0以上を指定してください"})
+	}
+	if limit < 1 || limit > ProductRankingMaxLimit {
+		return ProductRankingCalculator{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"ランキングの件数は1以上100以下を指定してください"})
+	}
+
+	return ProductRankingCalculator{SalesWeight: salesWeight, Limit: limit}, nil
+}
+
+// 商品ごとの販売数と閲覧数から、サイト全体とカテゴリーごとのランキングを返却する
+// 点数は「販売数×販売数の重み＋閲覧数」とし、点数が同じ商品は販売数の多い順・商品IDの昇順とする
+// 商品は商品のカテゴリーとその祖先のカテゴリーのランキングに含め、点数が0の商品は含めない
+func (calculator ProductRankingCalculator) Calculate(activities []ProductActivity, categories []Category, date time.Time, period enum.RankingPeriod) []ProductRanking {
+	parentIDs := make(map[string]string, len(categories))
+	for _, category := range categories {
+		if category.ParentID != nil {
+			parentIDs[category.ID] = *category.ParentID
+		}
+	}
+
+	// サイト全体（空文字）とカテゴリーごとの商品配列
+	grouped := map[string][]ProductRanking{}
+	for _, activity := range activities {
+		score := activity.SalesCount*calculator.SalesWeight + activity.ViewCount
+		if score <= 0 {
+			continue
+		}
+
+		ranking := ProductRanking{
+			Date:       date,
+			Period:     period,
+			ProductID:  activity.ProductID,
+			SalesCount: activity.SalesCount,
+			ViewCount:  activity.ViewCount,
+			Score:      score,
+		}
+		for _, categoryID := range append([]string{""}, categoryAncestorIDs(activity.CategoryID, parentIDs)...) {
+			ranking.CategoryID = categoryID
+			grouped[categoryID] = append(grouped[categoryID], ranking)
+		}
+	}
+
+	categoryIDs := make([]string, 0, len(grouped))
+	for categoryID := range grouped {
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	sort.Strings(categoryIDs)
+
+	rankings := []ProductRanking{}
+	for _, categoryID := range categoryIDs {
+		group := grouped[categoryID]
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Score != group[j].Score {
+				return group[i].Score > group[j].Score
+			}
+			if group[i].SalesCount != group[j].SalesCount {
+				return group[i].SalesCount > group[j].SalesCount
+			}
+			return group[i].ProductID < group[j].ProductID
+		})
+
+		for i, ranking := range group {
+			if i >= calculator.Limit {
+				break
+			}
+			ranking.ID = util.IDutils.GenerateID()
+			ranking.Rank = i + 1
+			rankings = append(rankings, ranking)
+		}
+	}
+
+	return rankings
+}
+
+// カテゴリーIDとその祖先のカテゴリーID配列を返却する（循環している場合は一度含めたカテゴリーを含めない）
+func categoryAncestorIDs(categoryID string, parentIDs map[string]string) []string {
+	if categoryID == "" {
+		return []string{}
+	}
+
+	ids := []string{categoryID}
+	visited := map[string]struct{}{categoryID: {}}
+	for {
+		parentID, ok := parentIDs[ids[len(ids)-1]]
+		if !ok {
+			return ids
+		}
+		if _, ok := visited[parentID]; ok {
+			return ids
+		}
+		visited[parentID] = struct{}{}
+		ids = append(ids, parentID)
+	}
+}
@@ -0,0 +1,56 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductRankingCalculatorCalculate(t *testing.T) {
+	// given（前提条件）カテゴリーbはカテゴリーaの子カテゴリー
+	date := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	parentID := "a"
+	categories := []entity.Category{{ID: "a"}, {ID: "b", ParentID: &parentID}, {ID: "c"}}
+	activities := []entity.ProductActivity{
+		{ProductID: "1", CategoryID: "a", SalesCount: 1, ViewCount: 5},  // 点数15
+		{ProductID: "2", CategoryID: "b", SalesCount: 2, ViewCount: 0},  // 点数20
+		{ProductID: "3", CategoryID: "b", SalesCount: 0, ViewCount: 20}, // 点数20（販売数が少ないため商品2より下位）
+		{ProductID: "4", CategoryID: "c", SalesCount: 0, ViewCount: 1},  // 点数1
+		{ProductID: "5", CategoryID: "c", SalesCount: 0, ViewCount: 0},  // 点数0のため含めない
+	}
+	calculator, err := entity.NewProductRankingCalculator(10, 3)
+	assert.Nil(t, err)
+
+	// when（操作）
+	rankings := calculator.Calculate(activities, categories, date, enum.RankingPeriodWeekly)
+
+	// then（期待する結果）
+	result := map[string][]string{}
+	for _, ranking := range rankings {
+		assert.Equal(t, date, ranking.Date)
+		assert.Equal(t, enum.RankingPeriodWeekly, ranking.Period)
+		assert.Equal(t, len(result[ranking.CategoryID])+1, ranking.Rank)
+		result[ranking.CategoryID] = append(result[ranking.CategoryID], ranking.ProductID)
+	}
+	assert.Equal(t, map[string][]string{
+		"":  {"2", "3", "1"}, // サイト全体は最大件数まで
+		"a": {"2", "3", "1"}, // 親カテゴリーは子カテゴリーの商品を含める
+		"b": {"2", "3"},
+		"c": {"4"},
+	}, result)
+}
+
+func TestNewProductRankingCalculator(t *testing.T) {
+	// when・then（操作・期待する結果）
+	_, err := entity.NewProductRankingCalculator(-1, 10)
+	assert.NotNil(t, err)
+	_, err = entity.NewProductRankingCalculator(10, 0)
+	assert.NotNil(t, err)
+	_, err = entity.NewProductRankingCalculator(10, entity.ProductRankingMaxLimit+1)
+	assert.NotNil(t, err)
+	_, err = entity.NewProductRankingCalculator(0, entity.ProductRankingMaxLimit)
+	assert.Nil(t, err)
+}
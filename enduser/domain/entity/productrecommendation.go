@@ -0,0 +1,186 @@
+package entity
+
+import (
+	"sort"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+)
+
+type (
+	// 1回の注文で一緒に購入された商品
+	PurchaseBasket struct {
+		OrderID string
+		Items   []PurchaseBasketItem
+	}
+
+	// 注文の購入商品
+	PurchaseBasketItem struct {
+		ProductID  string
+		CategoryID string
+		Quantity   int
+	}
+
+	// 商品ごとの一緒に購入されている商品（商品ごとに順位の昇順で保存する）
+	ProductRecommendation struct {
+		ProductID            string
+		RecommendedProductID string
+		Rank                 int // 1始まりの順位
+		CoPurchaseCount      int // 一緒に購入された注文数
+	}
+
+	// カテゴリーごとの売れ筋商品（カテゴリーごとに順位の昇順で保存する）
+	CategoryBestseller struct {
+		CategoryID string
+		ProductID  string
+		Rank       int // 1始まりの順位
+		SalesCount int // 販売数
+	}
+
+	// 注文履歴からおすすめ商品を算出する方法
+	ProductRecommendationMiner struct {
+		MaxNeighbors       int // 商品ごとに保存する一緒に購入されている商品の最大件数
+		MinCoPurchaseCount int // 一緒に購入されている商品とみなす最小の注文数
+	}
+
+	// 商品詳細に表示するおすすめ商品
+	ProductRecommendations struct {
+		Source   enum.ProductRecommendationSource
+		Products []Product
+	}
+)
+
+const (
+	ProductRecommendationLimit = 10 // 商品詳細に表示するおすすめ商品の最大件数
+	CategoryBestsellerLimit    = 50 // カテゴリーごとに保存する売れ筋商品の最大件数
+)
+
+// 注文履歴からおすすめ商品を算出する方法を作成する
+func NewProductRecommendationMiner(maxNeighbors int, minCoPurchaseCount int) (ProductRecommendationMiner, error) {
+	if maxNeighbors < 1 {
+		return ProductRecommendationMiner{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"保存するおすすめ商品の件数は1以上を指定してください"})
+	}
+	if minCoPurchaseCount < 1 {
+		return ProductRecommendationMiner{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"一緒に購入された最小の注文数は1以上を指定してください"})
+	}
+
+	return ProductRecommendationMiner{MaxNeighbors: maxNeighbors, MinCoPurchaseCount: minCoPurchaseCount}, nil
+}
+
+// 注文ごとに同じ注文で購入された商品の組み合わせを数え、商品ごとに一緒に購入された注文数の多い順に最大MaxNeighbors件のおすすめ商品を返却する
+// 同じ注文に同じ商品が複数含まれる場合（SKU違いなど）も1回として数え、注文数が同じ商品は商品IDの昇順とする
+func (miner ProductRecommendationMiner) Mine(baskets []PurchaseBasket) []ProductRecommendation {
+	pairCounts := map[string]map[string]int{}
+	for _, basket := range baskets {
+		productIDs := basket.productIDs()
+		for _, productID := range productIDs {
+			for _, neighborID := range productIDs {
+				if productID == neighborID {
+					continue
+				}
+				if pairCounts[productID] == nil {
+					pairCounts[productID] = map[string]int{}
+				}
+				pairCounts[productID][neighborID]++
+			}
+		}
+	}
+
+	productIDs := make([]string, 0, len(pairCounts))
+	for productID := range pairCounts {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+
+	recommendations := []ProductRecommendation{}
+	for _, productID := range productIDs {
+		neighbors := make([]ProductRecommendation, 0, len(pairCounts[productID]))
+		for neighborID, count := range pairCounts[productID] {
+			if count < miner.MinCoPurchaseCount {
+				continue
+			}
+			neighbors = append(neighbors, ProductRecommendation{ProductID: productID, RecommendedProductID: neighborID, CoPurchaseCount: count})
+		}
+
+		sort.Slice(neighbors, func(i, j int) bool {
+			if neighbors[i].CoPurchaseCount != neighbors[j].CoPurchaseCount {
+				return neighbors[i].CoPurchaseCount > neighbors[j].CoPurchaseCount
+			}
+			return neighbors[i].RecommendedProductID < neighbors[j].RecommendedProductID
+		})
+
+		for i, neighbor := range neighbors {
+			if i >= miner.MaxNeighbors {
+				break
+			}
+			neighbor.Rank = i + 1
+			recommendations = append(recommendations, neighbor)
+		}
+	}
+
+	return recommendations
+}
+
+// 注文履歴の購入数を商品ごとに合計し、カテゴリーごとに販売数の多い順に最大limit件の売れ筋商品を返却する
+// 販売数が同じ商品は商品IDの昇順とする
+func RankCategoryBestsellers(baskets []PurchaseBasket, limit int) []CategoryBestseller {
+	salesCounts := map[string]map[string]int{}
+	for _, basket := range baskets {
+		for _, item := range basket.Items {
+			if salesCounts[item.CategoryID] == nil {
+				salesCounts[item.CategoryID] = map[string]int{}
+			}
+			salesCounts[item.CategoryID][item.ProductID] += item.Quantity
+		}
+	}
+
+	categoryIDs := make([]string, 0, len(salesCounts))
+	for categoryID := range salesCounts {
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	sort.Strings(categoryIDs)
+
+	bestsellers := []CategoryBestseller{}
+	for _, categoryID := range categoryIDs {
+		ranking := make([]CategoryBestseller, 0, len(salesCounts[categoryID]))
+		for productID, count := range salesCounts[categoryID] {
+			ranking = append(ranking, CategoryBestseller{CategoryID: categoryID, ProductID: productID, SalesCount: count})
+		}
+
+		sort.Slice(ranking, func(i, j int) bool {
+			if ranking[i].SalesCount != ranking[j].SalesCount {
+				return ranking[i].SalesCount > ranking[j].SalesCount
+			}
+			return ranking[i].ProductID < ranking[j].ProductID
+		})
+
+		for i, bestseller := range ranking {
+			if i >= limit {
+				break
+			}
+			bestseller.Rank = i + 1
+			bestsellers = append(bestsellers, bestseller)
+		}
+	}
+
+	return bestsellers
+}
+
+// 注文の購入商品の商品ID配列を重複を除いて返却する
+func (basket PurchaseBasket) productIDs() []string {
+	productIDs := make([]string, 0, len(basket.Items))
+	seen := map[string]bool{}
+	for _, item := range basket.Items {
+		if seen[item.ProductID] {
+			continue
+		}
+		seen[item.ProductID] = true
+		productIDs = append(productIDs, item.ProductID)
+	}
+	return productIDs
+}
+
+// 商品をおすすめ商品として表示できる場合（販売中かつ在庫あり）trueを返却する
+func (product Product) IsRecommendable() bool {
+	return product.IsPurchasable()
+}
@@ -0,0 +1,83 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductRecommendationMinerMine(t *testing.T) {
+	// given（前提条件）商品1は商品2と3回、商品3と2回、商品4と1回一緒に購入されている
+	baskets := []entity.PurchaseBasket{
+		{OrderID: "1", Items: []entity.PurchaseBasketItem{{ProductID: "1"}, {ProductID: "2"}, {ProductID: "3"}}},
+		{OrderID: "2", Items: []entity.PurchaseBasketItem{{ProductID: "1"}, {ProductID: "2"}, {ProductID: "3"}}},
+		{OrderID: "3", Items: []entity.PurchaseBasketItem{{ProductID: "1"}, {ProductID: "2"}, {ProductID: "2"}}},
+		{OrderID: "4", Items: []entity.PurchaseBasketItem{{ProductID: "1"}, {ProductID: "4"}}},
+		{OrderID: "5", Items: []entity.PurchaseBasketItem{{ProductID: "5"}}},
+	}
+	miner, err := entity.NewProductRecommendationMiner(2, 2)
+	assert.Nil(t, err)
+
+	// when（操作）
+	recommendations := miner.Mine(baskets)
+
+	// then（期待する結果）同じ注文の同じ商品は1回として数え、最小注文数未満の組み合わせと最大件数を超える商品は含めない
+	assert.Equal(t, []entity.ProductRecommendation{
+		{ProductID: "1", RecommendedProductID: "2", Rank: 1, CoPurchaseCount: 3},
+		{ProductID: "1", RecommendedProductID: "3", Rank: 2, CoPurchaseCount: 2},
+		{ProductID: "2", RecommendedProductID: "1", Rank: 1, CoPurchaseCount: 3},
+		{ProductID: "2", RecommendedProductID: "3", Rank: 2, CoPurchaseCount: 2},
+		{ProductID: "3", RecommendedProductID: "1", Rank: 1, CoPurchaseCount: 2},
+		{ProductID: "3", RecommendedProductID: "2", Rank: 2, CoPurchaseCount: 2},
+	}, recommendations)
+}
+
+func TestNewProductRecommendationMiner(t *testing.T) {
+	// when・then（操作・期待する結果）
+	_, err := entity.NewProductRecommendationMiner(0, 1)
+	assert.NotNil(t, err)
+	_, err = entity.NewProductRecommendationMiner(1, 0)
+	assert.NotNil(t, err)
+	_, err = entity.NewProductRecommendationMiner(1, 1)
+	assert.Nil(t, err)
+}
+
+func TestRankCategoryBestsellers(t *testing.T) {
+	// given（前提条件）
+	baskets := []entity.PurchaseBasket{
+		{OrderID: "1", Items: []entity.PurchaseBasketItem{{ProductID: "1", CategoryID: "a", Quantity: 1}, {ProductID: "2", CategoryID: "a", Quantity: 3}}},
+		{OrderID: "2", Items: []entity.PurchaseBasketItem{{ProductID: "1", CategoryID: "a", Quantity: 2}, {ProductID: "3", CategoryID: "a", Quantity: 1}}},
+		{OrderID: "3", Items: []entity.PurchaseBasketItem{{ProductID: "4", CategoryID: "b", Quantity: 1}}},
+	}
+
+	// when（操作）
+	bestsellers := entity.RankCategoryBestsellers(baskets, 2)
+
+	// then（期待する結果）販売数が同じ商品は商品IDの昇順とし、カテゴリーごとに最大件数まで返却する
+	assert.Equal(t, []entity.CategoryBestseller{
+		{CategoryID: "a", ProductID: "1", Rank: 1, SalesCount: 3},
+		{CategoryID: "a", ProductID: "2", Rank: 2, SalesCount: 3},
+		{CategoryID: "b", ProductID: "4", Rank: 1, SalesCount: 1},
+	}, bestsellers)
+}
+
+func TestIsRecommendable(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Product  entity.Product
+		Expected bool
+	}{
+		{Name: "販売中かつ在庫ありの場合、trueを返却する", Product: entity.Product{Status: enum.OnSale, StockCount: 1}, Expected: true},
+		{Name: "在庫がない場合、falseを返却する", Product: entity.Product{Status: enum.OnSale, StockCount: 0}, Expected: false},
+		{Name: "販売停止中の場合、falseを返却する", Product: entity.Product{Status: enum.SalesSuspend, StockCount: 1}, Expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when・then（操作・期待する結果）
+			assert.Equal(t, tt.Expected, tt.Product.IsRecommendable())
+		})
+	}
+}
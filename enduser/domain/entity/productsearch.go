@@ -0,0 +1,29 @@
+package entity
+
+type (
+	// 商品検索用のドキュメント
+	ProductSearchDocument struct {
+		ProductID   string
+		Name        string
+		Description string
+	}
+
+	// 商品検索の検索結果（関連度の高い順に並べる）
+	ProductSearchHit struct {
+		ProductID string
+		Score     float64 // 関連度
+	}
+
+	// 商品検索結果の1ページ
+	ProductSearchPage struct {
+		Products   []Product
+		TotalCount int // 検索条件に一致する商品数
+		Page       int
+		HasNext    bool
+	}
+)
+
+const (
+	ProductSearchMaxHits      = 1000 // 関連度の高い順に取得する検索結果の上限数
+	ProductSearchDefaultLimit = 20
+)
@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+)
+
+type (
+	// 商品のバリエーションの軸（例：サイズ・カラー）
+	ProductOptionAxis struct {
+		ID        string
+		ProductID string
+		Name      string
+		Order     int
+	}
+
+	// 商品のバリエーションごとの在庫管理単位（SKU）
+	// バリエーションの軸ごとに1つの値を持ち、在庫数・価格・画像をSKUごとに管理する
+	ProductSKU struct {
+		ID            string
+		ProductID     string
+		Code          string
+		Order         int
+		Options       []ProductSKUOption
+		StockCount    int
+		PriceOverride int // SKU固有の販売価格（税込）（0の場合は商品の販売価格）
+
+		ProductImages []ProductImage // SKU固有の画像
+	}
+
+	// SKUのバリエーションの軸の値
+	ProductSKUOption struct {
+		AxisID string
+		Value  string
+	}
+)
+
+// 引数skuIDに一致するSKUを返却する
+// バリエーションを持たない商品の場合は、SKU IDが空文字のときに商品自体の在庫数を持つSKUを返却する
+// SKU IDが空文字でSKUが1つのみの商品の場合はそのSKUを返却する（SKUの導入前にカートに追加された商品のため）
+func (product Product) FindSKU(skuID string) (ProductSKU, bool) {
+	if len(product.SKUs) == 0 {
+		if skuID != "" {
+			return ProductSKU{}, false
+		}
+
+		return ProductSKU{
+			ProductID:     product.ID,
+			StockCount:    product.StockCount,
+			ProductImages: []ProductImage{},
+		}, true
+	}
+
+	if skuID == "" && len(product.SKUs) == 1 {
+		return product.SKUs[0], true
+	}
+
+	for _, sku := range product.SKUs {
+		if sku.ID == skuID {
+			return sku, true
+		}
+	}
+
+	return ProductSKU{}, false
+}
+
+// SKUの実際に販売される価格（税込）を返却する
+// SKU固有の販売価格が設定されている場合はその価格を、そうでない場合は商品の販売価格を返却する
+func (product Product) SKUPrice(sku ProductSKU) int {
+	if sku.PriceOverride > 0 {
+		return sku.PriceOverride
+	}
+
+	return product.EffectivePrice()
+}
+
+// バリエーションの軸に設定されている値をSKUの表示順に重複なく返却する
+func (product Product) OptionValues(axisID string) []string {
+	values := []string{}
+	seen := map[string]struct{}{}
+	for _, sku := range product.SKUs {
+		value, ok := sku.OptionValue(axisID)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+
+		seen[value] = struct{}{}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// 商品名にSKUのバリエーションの値を付加した表示名を返却する（例「Tシャツ（M / ブラック）」）
+func (product Product) SKUDisplayName(sku ProductSKU) string {
+	values := make([]string, 0, len(product.OptionAxes))
+	for _, axis := range product.OptionAxes {
+		if value, ok := sku.OptionValue(axis.ID); ok {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 0 {
+		return product.Name
+	}
+	return product.Name + "（" + strings.Join(values, " / ") + "）"
+}
+
+// バリエーションの軸に対するSKUの値を返却する
+func (sku ProductSKU) OptionValue(axisID string) (string, bool) {
+	for _, option := range sku.Options {
+		if option.AxisID == axisID {
+			return option.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// SKUの在庫数に応じた在庫状況を返却する
+func (sku ProductSKU) StockStatus() enum.StockStatus {
+	return stockStatus(sku.StockCount)
+}
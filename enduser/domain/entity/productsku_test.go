@@ -0,0 +1,86 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSKUProduct() entity.Product {
+	return entity.Product{
+		ID:    "1",
+		Name:  "Tシャツ",
+		Price: 1000,
+		OptionAxes: []entity.ProductOptionAxis{
+			{ID: "size", Name: "サイズ", Order: 1},
+			{ID: "color", Name: "カラー", Order: 2},
+		},
+		SKUs: []entity.ProductSKU{
+			{ID: "sku1", Options: []entity.ProductSKUOption{{AxisID: "size", Value: "M"}, {AxisID: "color", Value: "黒"}}, StockCount: 3},
+			{ID: "sku2", Options: []entity.ProductSKUOption{{AxisID: "size", Value: "L"}, {AxisID: "color", Value: "黒"}}, StockCount: 0, PriceOverride: 1200},
+			{ID: "sku3", Options: []entity.ProductSKUOption{{AxisID: "size", Value: "M"}, {AxisID: "color", Value: "白"}}, StockCount: 5},
+		},
+	}
+}
+
+func TestFindSKU(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Product       entity.Product
+		SKUID         string
+		ExpectedOK    bool
+		ExpectedSKUID string
+		ExpectedStock int
+	}{
+		{Name: "バリエーションを持たない商品の場合、商品自体の在庫数を持つSKUを返却する", Product: entity.Product{ID: "1", StockCount: 4}, SKUID: "", ExpectedOK: true, ExpectedSKUID: "", ExpectedStock: 4},
+		{Name: "バリエーションを持たない商品にSKU IDを指定した場合、存在しない", Product: entity.Product{ID: "1", StockCount: 4}, SKUID: "sku1", ExpectedOK: false},
+		{Name: "SKU IDに一致するSKUを返却する", Product: newSKUProduct(), SKUID: "sku3", ExpectedOK: true, ExpectedSKUID: "sku3", ExpectedStock: 5},
+		{Name: "複数のSKUを持つ商品でSKU IDが空文字の場合、存在しない", Product: newSKUProduct(), SKUID: "", ExpectedOK: false},
+		{Name: "SKUが1つのみの商品でSKU IDが空文字の場合、そのSKUを返却する", Product: entity.Product{ID: "1", SKUs: []entity.ProductSKU{{ID: "sku1", StockCount: 2}}}, SKUID: "", ExpectedOK: true, ExpectedSKUID: "sku1", ExpectedStock: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			sku, ok := tt.Product.FindSKU(tt.SKUID)
+
+			// then（期待する結果）
+			assert.Equal(t, tt.ExpectedOK, ok)
+			if ok {
+				assert.Equal(t, tt.ExpectedSKUID, sku.ID)
+				assert.Equal(t, tt.ExpectedStock, sku.StockCount)
+			}
+		})
+	}
+}
+
+func TestSKUPrice(t *testing.T) {
+	// given（前提条件）
+	product := newSKUProduct()
+	product.SalePrice = 800
+
+	// when・then（操作・期待する結果）SKU固有の価格が設定されていない場合は商品の販売価格
+	assert.Equal(t, 800, product.SKUPrice(product.SKUs[0]))
+	// SKU固有の価格が設定されている場合はその価格
+	assert.Equal(t, 1200, product.SKUPrice(product.SKUs[1]))
+}
+
+func TestOptionValues(t *testing.T) {
+	// given（前提条件）
+	product := newSKUProduct()
+
+	// when・then（操作・期待する結果）SKUの表示順に重複なく返却する
+	assert.Equal(t, []string{"M", "L"}, product.OptionValues("size"))
+	assert.Equal(t, []string{"黒", "白"}, product.OptionValues("color"))
+}
+
+func TestSKUDisplayName(t *testing.T) {
+	// given（前提条件）
+	product := newSKUProduct()
+
+	// when・then（操作・期待する結果）バリエーションの軸の順に値を付加する
+	assert.Equal(t, "Tシャツ（M / 黒）", product.SKUDisplayName(product.SKUs[0]))
+	// バリエーションを持たない場合は商品名
+	assert.Equal(t, "Tシャツ", entity.Product{Name: "Tシャツ"}.SKUDisplayName(entity.ProductSKU{}))
+}
@@ -0,0 +1,239 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+type (
+	// 商品の値（商品価格・商品セール価格・商品ステータス）の適用期間の一覧
+	// 適用期間は日付単位で、適用開始日・適用終了日を含む
+	ProductTimeline struct {
+		ProductID string
+		Type      enum.ProductTimelineType
+		Entries   []ProductTimelineEntry // 適用開始日の昇順
+	}
+
+	// 適用期間ごとの商品の値
+	ProductTimelineEntry struct {
+		ID                 string
+		Value              int // 税込価格または商品ステータス
+		EffectiveStartDate time.Time
+		EffectiveEndDate   time.Time
+	}
+
+	// 適用期間の不整合
+	ProductTimelineProblem struct {
+		Type      enum.ProductTimelineProblemType
+		EntryIDs  []string
+		StartDate time.Time // 空白・重複している期間の開始日
+		EndDate   time.Time // 空白・重複している期間の終了日
+	}
+)
+
+// 適用期間の一覧を作成する
+// 適用期間は適用開始日の昇順に並べる
+func NewProductTimeline(productID string, timelineType enum.ProductTimelineType, entries []ProductTimelineEntry) ProductTimeline {
+	sorted := append([]ProductTimelineEntry{}, entries...)
+	sortProductTimelineEntries(sorted)
+
+	return ProductTimeline{
+		ProductID: productID,
+		Type:      timelineType,
+		Entries:   sorted,
+	}
+}
+
+// 引数の日付に適用される値を返却する
+func (timeline ProductTimeline) EntryAt(date time.Time) (ProductTimelineEntry, bool) {
+	for _, entry := range timeline.Entries {
+		if entry.covers(date) {
+			return entry, true
+		}
+	}
+
+	return ProductTimelineEntry{}, false
+}
+
+// 適用期間の空白・重複・不正な期間を返却する
+func (timeline ProductTimeline) Problems() []ProductTimelineProblem {
+	problems := []ProductTimelineProblem{}
+	for i, entry := range timeline.Entries {
+		if entry.EffectiveStartDate.After(entry.EffectiveEndDate) {
+			problems = append(problems, ProductTimelineProblem{
+				Type:      enum.ProductTimelineProblemInvalid,
+				EntryIDs:  []string{entry.ID},
+				StartDate: entry.EffectiveStartDate,
+				EndDate:   entry.EffectiveEndDate,
+			})
+			continue
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		previous := timeline.Entries[i-1]
+		nextDate := previous.EffectiveEndDate.AddDate(0, 0, 1)
+		switch {
+		case entry.EffectiveStartDate.After(nextDate):
+			problems = append(problems, ProductTimelineProblem{
+				Type:      enum.ProductTimelineProblemGap,
+				EntryIDs:  []string{previous.ID, entry.ID},
+				StartDate: nextDate,
+				EndDate:   entry.EffectiveStartDate.AddDate(0, 0, -1),
+			})
+		case entry.EffectiveStartDate.Before(nextDate):
+			endDate := previous.EffectiveEndDate
+			if entry.EffectiveEndDate.Before(endDate) {
+				endDate = entry.EffectiveEndDate
+			}
+			problems = append(problems, ProductTimelineProblem{
+				Type:      enum.ProductTimelineProblemOverlap,
+				EntryIDs:  []string{previous.ID, entry.ID},
+				StartDate: entry.EffectiveStartDate,
+				EndDate:   endDate,
+			})
+		}
+	}
+
+	return problems
+}
+
+// 引数の期間（開始日・終了日を含む）に関係する適用期間の空白・重複・不正な期間を返却する
+// 期間の先頭・末尾を適用期間が網羅していない場合も空白として返却する
+func (timeline ProductTimeline) ProblemsBetween(from time.Time, to time.Time) []ProductTimelineProblem {
+	problems := []ProductTimelineProblem{}
+	for _, problem := range timeline.Problems() {
+		if problem.Type == enum.ProductTimelineProblemInvalid || (!problem.EndDate.Before(from) && !problem.StartDate.After(to)) {
+			problems = append(problems, problem)
+		}
+	}
+
+	if len(timeline.Entries) == 0 {
+		return append(problems, ProductTimelineProblem{Type: enum.ProductTimelineProblemGap, EntryIDs: []string{}, StartDate: from, EndDate: to})
+	}
+
+	first := timeline.Entries[0]
+	if first.EffectiveStartDate.After(from) {
+		endDate := first.EffectiveStartDate.AddDate(0, 0, -1)
+		if endDate.After(to) {
+			endDate = to
+		}
+		problems = append([]ProductTimelineProblem{{Type: enum.ProductTimelineProblemGap, EntryIDs: []string{first.ID}, StartDate: from, EndDate: endDate}}, problems...)
+	}
+
+	last := timeline.Entries[len(timeline.Entries)-1]
+	for _, entry := range timeline.Entries {
+		if entry.EffectiveEndDate.After(last.EffectiveEndDate) {
+			last = entry
+		}
+	}
+	if last.EffectiveEndDate.Before(to) {
+		startDate := last.EffectiveEndDate.AddDate(0, 0, 1)
+		if startDate.Before(from) {
+			startDate = from
+		}
+		problems = append(problems, ProductTimelineProblem{Type: enum.ProductTimelineProblemGap, EntryIDs: []string{last.ID}, StartDate: startDate, EndDate: to})
+	}
+
+	return problems
+}
+
+// 適用期間に空白・重複・不正な期間が存在する場合はエラーを返却する
+func (timeline ProductTimeline) Validate() error {
+	problems := timeline.Problems()
+	if len(problems) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		messages = append(messages, problem.Message())
+	}
+	return share.CreateOriginalError(share.ErrorCodeValidation, messages)
+}
+
+// 引数の適用期間に値を適用するよう予約する
+// 予約する期間と重なる既存の適用期間は、予約する期間を除いた期間に自動で分割・短縮し、予約する期間に完全に含まれる場合は削除する
+// 当日以前の値は変更できず、既存の適用期間との間に空白ができる場合はエラーを返却する
+func (timeline *ProductTimeline) Schedule(value int, startDate time.Time, endDate time.Time, today time.Time) error {
+	if startDate.After(endDate) {
+		return share.CreateOriginalError(share.ErrorCodeValidation, []string{"適用開始日は適用終了日以前の日付を指定してください"})
+	}
+	if !startDate.After(today) {
+		return share.CreateOriginalError(share.ErrorCodeValidation, []string{"適用開始日は翌日以降の日付を指定してください"})
+	}
+
+	entries := make([]ProductTimelineEntry, 0, len(timeline.Entries)+2)
+	for _, entry := range timeline.Entries {
+		// 予約する期間と重ならない場合はそのまま
+		if entry.EffectiveEndDate.Before(startDate) || entry.EffectiveStartDate.After(endDate) {
+			entries = append(entries, entry)
+			continue
+		}
+
+		// 予約する期間より前の部分を残す
+		if entry.EffectiveStartDate.Before(startDate) {
+			before := entry
+			before.EffectiveEndDate = startDate.AddDate(0, 0, -1)
+			entries = append(entries, before)
+		}
+
+		// 予約する期間より後の部分を残す（前の部分も残す場合は別の適用期間に分割する）
+		if entry.EffectiveEndDate.After(endDate) {
+			after := entry
+			if entry.EffectiveStartDate.Before(startDate) {
+				after.ID = util.IDutils.GenerateID()
+			}
+			after.EffectiveStartDate = endDate.AddDate(0, 0, 1)
+			entries = append(entries, after)
+		}
+	}
+
+	entries = append(entries, ProductTimelineEntry{
+		ID:                 util.IDutils.GenerateID(),
+		Value:              value,
+		EffectiveStartDate: startDate,
+		EffectiveEndDate:   endDate,
+	})
+	sortProductTimelineEntries(entries)
+
+	scheduled := ProductTimeline{ProductID: timeline.ProductID, Type: timeline.Type, Entries: entries}
+	err := scheduled.Validate()
+	if err != nil {
+		return err
+	}
+
+	timeline.Entries = entries
+	return nil
+}
+
+// 不整合の内容を表すメッセージを返却する
+func (problem ProductTimelineProblem) Message() string {
+	period := fmt.Sprintf("%s〜%s", problem.StartDate.Format(time.DateOnly), problem.EndDate.Format(time.DateOnly))
+	switch problem.Type {
+	case enum.ProductTimelineProblemGap:
+		return fmt.Sprintf("%sに適用される値が存在しません", period)
+	case enum.ProductTimelineProblemOverlap:
+		return fmt.Sprintf("%sの適用期間が重複しています", period)
+	default:
+		return fmt.Sprintf("%sの適用開始日が適用終了日より後です", period)
+	}
+}
+
+// 引数の日付が適用期間に含まれる場合trueを返却する
+func (entry ProductTimelineEntry) covers(date time.Time) bool {
+	return !date.Before(entry.EffectiveStartDate) && !date.After(entry.EffectiveEndDate)
+}
+
+func sortProductTimelineEntries(entries []ProductTimelineEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].EffectiveStartDate.Before(entries[j].EffectiveStartDate)
+	})
+}
@@ -0,0 +1,157 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func timelineDate(month time.Month, day int) time.Time {
+	return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+type timelinePeriod struct {
+	Value int
+	Start time.Time
+	End   time.Time
+}
+
+func toTimelinePeriods(timeline entity.ProductTimeline) []timelinePeriod {
+	periods := []timelinePeriod{}
+	for _, entry := range timeline.Entries {
+		periods = append(periods, timelinePeriod{Value: entry.Value, Start: entry.EffectiveStartDate, End: entry.EffectiveEndDate})
+	}
+	return periods
+}
+
+func TestProductTimelineSchedule(t *testing.T) {
+	// given（前提条件）4/1〜4/30に100円が適用されている
+	today := timelineDate(4, 5)
+	entries := []entity.ProductTimelineEntry{{ID: "1", Value: 100, EffectiveStartDate: timelineDate(4, 1), EffectiveEndDate: timelineDate(4, 30)}}
+
+	tests := []struct {
+		Name     string
+		Entries  []entity.ProductTimelineEntry
+		Start    time.Time
+		End      time.Time
+		Expected []timelinePeriod
+		IsErr    bool
+	}{
+		{
+			Name:    "既存の期間の途中を予約する場合、既存の期間を前後に分割する",
+			Entries: entries,
+			Start:   timelineDate(4, 8), End: timelineDate(4, 14),
+			Expected: []timelinePeriod{
+				{Value: 100, Start: timelineDate(4, 1), End: timelineDate(4, 7)},
+				{Value: 80, Start: timelineDate(4, 8), End: timelineDate(4, 14)},
+				{Value: 100, Start: timelineDate(4, 15), End: timelineDate(4, 30)},
+			},
+		},
+		{
+			Name:    "既存の期間の末尾から延長して予約する場合、既存の期間を短縮する",
+			Entries: entries,
+			Start:   timelineDate(4, 20), End: timelineDate(5, 31),
+			Expected: []timelinePeriod{
+				{Value: 100, Start: timelineDate(4, 1), End: timelineDate(4, 19)},
+				{Value: 80, Start: timelineDate(4, 20), End: timelineDate(5, 31)},
+			},
+		},
+		{
+			Name: "予約する期間に完全に含まれる既存の期間は削除する",
+			Entries: []entity.ProductTimelineEntry{
+				{ID: "1", Value: 100, EffectiveStartDate: timelineDate(4, 1), EffectiveEndDate: timelineDate(4, 9)},
+				{ID: "2", Value: 90, EffectiveStartDate: timelineDate(4, 10), EffectiveEndDate: timelineDate(4, 12)},
+				{ID: "3", Value: 100, EffectiveStartDate: timelineDate(4, 13), EffectiveEndDate: timelineDate(4, 30)},
+			},
+			Start: timelineDate(4, 8), End: timelineDate(4, 14),
+			Expected: []timelinePeriod{
+				{Value: 100, Start: timelineDate(4, 1), End: timelineDate(4, 7)},
+				{Value: 80, Start: timelineDate(4, 8), End: timelineDate(4, 14)},
+				{Value: 100, Start: timelineDate(4, 15), End: timelineDate(4, 30)},
+			},
+		},
+		{
+			Name:    "既存の期間との間に空白ができる場合、エラーを返却する",
+			Entries: entries,
+			Start:   timelineDate(5, 2), End: timelineDate(5, 31),
+			IsErr: true,
+		},
+		{
+			Name:    "適用開始日が当日以前の場合、エラーを返却する",
+			Entries: entries,
+			Start:   today, End: timelineDate(4, 14),
+			IsErr: true,
+		},
+		{
+			Name:    "適用開始日が適用終了日より後の場合、エラーを返却する",
+			Entries: entries,
+			Start:   timelineDate(4, 14), End: timelineDate(4, 8),
+			IsErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// given（前提条件）
+			timeline := entity.NewProductTimeline("1", enum.ProductTimelineTypeSalePrice, tt.Entries)
+
+			// when（操作）
+			err := timeline.Schedule(80, tt.Start, tt.End, today)
+
+			// then（期待する結果）
+			assert.Equal(t, tt.IsErr, err != nil)
+			if tt.IsErr {
+				// 予約に失敗した場合は適用期間を変更しない
+				assert.Equal(t, len(tt.Entries), len(timeline.Entries))
+				return
+			}
+			assert.Equal(t, tt.Expected, toTimelinePeriods(timeline))
+			assert.Empty(t, timeline.Problems())
+		})
+	}
+}
+
+func TestProductTimelineScheduleSplitIDs(t *testing.T) {
+	// given（前提条件）
+	timeline := entity.NewProductTimeline("1", enum.ProductTimelineTypePrice, []entity.ProductTimelineEntry{
+		{ID: "1", Value: 100, EffectiveStartDate: timelineDate(4, 1), EffectiveEndDate: timelineDate(4, 30)},
+	})
+
+	// when（操作）
+	err := timeline.Schedule(80, timelineDate(4, 8), timelineDate(4, 14), timelineDate(4, 5))
+
+	// then（期待する結果）分割した後の期間は別のIDを持つ
+	assert.Nil(t, err)
+	assert.Equal(t, "1", timeline.Entries[0].ID)
+	assert.NotEqual(t, "1", timeline.Entries[2].ID)
+	assert.NotEqual(t, timeline.Entries[1].ID, timeline.Entries[2].ID)
+}
+
+func TestProductTimelineProblems(t *testing.T) {
+	// given（前提条件）4/10〜4/11が空白、4/20〜4/21が重複している
+	timeline := entity.NewProductTimeline("1", enum.ProductTimelineTypeStatus, []entity.ProductTimelineEntry{
+		{ID: "3", Value: enum.OnSale, EffectiveStartDate: timelineDate(4, 20), EffectiveEndDate: timelineDate(4, 30)},
+		{ID: "1", Value: enum.OnSale, EffectiveStartDate: timelineDate(4, 1), EffectiveEndDate: timelineDate(4, 9)},
+		{ID: "2", Value: enum.SalesSuspend, EffectiveStartDate: timelineDate(4, 12), EffectiveEndDate: timelineDate(4, 21)},
+	})
+
+	// when（操作）
+	problems := timeline.Problems()
+
+	// then（期待する結果）
+	assert.Equal(t, []entity.ProductTimelineProblem{
+		{Type: enum.ProductTimelineProblemGap, EntryIDs: []string{"1", "2"}, StartDate: timelineDate(4, 10), EndDate: timelineDate(4, 11)},
+		{Type: enum.ProductTimelineProblemOverlap, EntryIDs: []string{"2", "3"}, StartDate: timelineDate(4, 20), EndDate: timelineDate(4, 21)},
+	}, problems)
+	assert.NotNil(t, timeline.Validate())
+
+	// when・then（操作・期待する結果）日付に適用される値を返却する
+	entry, ok := timeline.EntryAt(timelineDate(4, 5))
+	assert.True(t, ok)
+	assert.Equal(t, "1", entry.ID)
+	_, ok = timeline.EntryAt(timelineDate(4, 10))
+	assert.False(t, ok)
+}
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+const (
+	RecentlyViewedMaxProducts = 20                  // 閲覧履歴に保持する商品数の上限（超えた場合は古い商品から削除する）
+	RecentlyViewedExpiration  = 30 * 24 * time.Hour // 閲覧履歴の有効期限は最後に閲覧してから30日
+	RecentlyViewedCookieName  = "RecentlyViewedSessionID"
+)
+
+// ゲストの閲覧履歴のセッションIDと、引数nowから有効期限までのクッキーを作成する
+func CreateRecentlyViewedSession(now time.Time) (http.Cookie, string) {
+	sessionID := util.IDutils.GenerateID()
+	cookie := util.CookieUtils.CreateCookie(RecentlyViewedCookieName, sessionID, now.Add(RecentlyViewedExpiration))
+	return cookie, sessionID
+}
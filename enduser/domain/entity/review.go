@@ -0,0 +1,290 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"golang.org/x/text/unicode/norm"
+)
+
+type (
+	// レビュー集約
+	// 1つのアカウントは1つの商品に1件のみレビューを投稿できる
+	Review struct {
+		ID                 string
+		ProductID          string
+		AccountID          string
+		Rating             int // 評価（1〜5）
+		Title              string
+		Body               string
+		IsVerifiedPurchase bool // レビュー投稿者が商品を購入済みの場合true
+		Status             enum.ReviewStatus
+		FlaggedWords       []string // NGワードフィルターで検出された語（確認待ちの理由）
+		HelpfulCount       int      // 参考になった票の数（レビュー投票から集計した値）
+		UnhelpfulCount     int      // 参考にならなかった票の数（レビュー投票から集計した値）
+		ReportCount        int      // 最後に確認してからの不適切なレビューとしての報告数
+		Version            int
+		CreateDateTime     time.Time
+		UpdateDateTime     time.Time
+
+		Photos []ReviewPhoto
+	}
+
+	// レビューの写真
+	ReviewPhoto struct {
+		ID       string
+		ReviewID string
+		Order    int
+		Path     string
+		Image    string // 画像のURL（取得していない場合は空文字）
+	}
+
+	// レビューへの投票（参考になった・参考にならなかった）
+	// 1つのアカウントは1件のレビューに1票のみ投票できる
+	ReviewVote struct {
+		ID             string
+		ReviewID       string
+		AccountID      string
+		IsHelpful      bool
+		CreateDateTime time.Time
+	}
+
+	// 不適切なレビューとしての報告
+	ReviewReport struct {
+		ID             string
+		ReviewID       string
+		AccountID      string
+		Reason         string
+		CreateDateTime time.Time
+	}
+
+	// 商品の公開中のレビューの評価ごとのレビュー数
+	ReviewRatingDistribution struct {
+		Counts     map[int]int // 評価（1〜5）ごとのレビュー数
+		TotalCount int
+	}
+
+	// レビュー一覧に表示するレビュー
+	ReviewListItem struct {
+		Review
+		ReviewNickname string // レビュー投稿者名
+	}
+
+	// レビュー一覧の1ページ
+	ReviewPage struct {
+		Reviews    []ReviewListItem
+		TotalCount int // 公開中のレビュー数
+		Page       int
+		HasNext    bool
+	}
+)
+
+const (
+	ReviewMaxPhotos       = 3 // 1件のレビューに添付できる写真の上限
+	ReviewDefaultLimit    = 20
+	ReviewReportThreshold = 3 // 公開中のレビューを確認待ちに戻す報告数
+)
+
+// レビューの写真に使用できる画像形式（Content-Type）とファイル拡張子
+var ReviewPhotoContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// レビュー集約を作成する
+// NGワードを含む場合は確認待ち、そうでない場合は公開中にする
+func CreateReview(productID string, accountID string, rating int, title string, body string, isVerifiedPurchase bool, ngWords []string, now time.Time) Review {
+	review := Review{
+		ID:                 util.IDutils.GenerateID(),
+		ProductID:          productID,
+		AccountID:          accountID,
+		Rating:             rating,
+		Title:              title,
+		Body:               body,
+		IsVerifiedPurchase: isVerifiedPurchase,
+		Version:            1,
+		CreateDateTime:     now,
+		UpdateDateTime:     now,
+		Photos:             []ReviewPhoto{},
+	}
+	review.applyNGWordFilter(ngWords, false)
+	return review
+}
+
+// レビューを編集する
+// 非公開のレビューを編集した場合はNGワードを含まなくても確認待ちにする
+func (review *Review) Edit(rating int, title string, body string, ngWords []string, now time.Time) {
+	wasRejected := review.Status == enum.ReviewStatusRejected
+
+	review.Rating = rating
+	review.Title = title
+	review.Body = body
+	review.UpdateDateTime = now
+	review.applyNGWordFilter(ngWords, wasRejected)
+}
+
+// レビューの写真を置き換え、保存先のパスを設定した写真配列を返却する
+// 引数contentTypesは写真ごとの画像形式
+func (review *Review) ReplacePhotos(contentTypes []string) ([]ReviewPhoto, error) {
+	if len(contentTypes) > ReviewMaxPhotos {
+		return []ReviewPhoto{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{fmt.Sprintf("写真は%d枚まで添付できます", ReviewMaxPhotos)})
+	}
+
+	photos := make([]ReviewPhoto, 0, len(contentTypes))
+	for i, contentType := range contentTypes {
+		extension, ok := ReviewPhotoContentTypes[contentType]
+		if !ok {
+			return []ReviewPhoto{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"写真はJPEG・PNG・WebP形式の画像を添付してください"})
+		}
+
+		id := util.IDutils.GenerateID()
+		photos = append(photos, ReviewPhoto{
+			ID:       id,
+			ReviewID: review.ID,
+			Order:    i + 1,
+			Path:     path.Join("/reviews", review.ID, id+extension),
+		})
+	}
+
+	review.Photos = photos
+	return photos, nil
+}
+
+// レビューの公開状態を変更する（管理者による確認）
+// 確認済みのため報告数を0に戻す
+func (review *Review) Moderate(status enum.ReviewStatus, now time.Time) error {
+	if status != enum.ReviewStatusPublished && status != enum.ReviewStatusRejected {
+		return share.CreateOriginalError(share.ErrorCodeValidation, []string{"公開状態は公開中または非公開を指定してください"})
+	}
+
+	review.Status = status
+	review.ReportCount = 0
+	review.UpdateDateTime = now
+	return nil
+}
+
+// 不適切なレビューとして報告し、報告を返却する
+// 報告数が閾値に達した公開中のレビューは確認待ちに戻す（1件の報告で非表示にできないようにするため）
+func (review *Review) Report(accountID string, reason string, now time.Time) (ReviewReport, error) {
+	if review.IsWrittenBy(accountID) {
+		return ReviewReport{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"自分のレビューは報告できません"})
+	}
+
+	review.ReportCount++
+	if review.Status == enum.ReviewStatusPublished && review.ReportCount >= ReviewReportThreshold {
+		review.Status = enum.ReviewStatusPending
+		review.UpdateDateTime = now
+	}
+
+	return ReviewReport{
+		ID:             util.IDutils.GenerateID(),
+		ReviewID:       review.ID,
+		AccountID:      accountID,
+		Reason:         reason,
+		CreateDateTime: now,
+	}, nil
+}
+
+// レビューに投票する
+// 公開中のレビューのみ投票でき、自分のレビューには投票できない
+func (review Review) CreateVote(accountID string, isHelpful bool, now time.Time) (ReviewVote, error) {
+	if review.Status != enum.ReviewStatusPublished {
+		return ReviewVote{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"レビューが見つかりません"})
+	}
+	if review.IsWrittenBy(accountID) {
+		return ReviewVote{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"自分のレビューには投票できません"})
+	}
+
+	return ReviewVote{
+		ID:             util.IDutils.GenerateID(),
+		ReviewID:       review.ID,
+		AccountID:      accountID,
+		IsHelpful:      isHelpful,
+		CreateDateTime: now,
+	}, nil
+}
+
+// レビューの投稿者の場合trueを返却する
+func (review Review) IsWrittenBy(accountID string) bool {
+	return review.AccountID == accountID
+}
+
+// NGワードを含む場合は確認待ちにする
+func (review *Review) applyNGWordFilter(ngWords []string, forcePending bool) {
+	review.FlaggedWords = DetectNGWords(review.Title+"\n"+review.Body, ngWords)
+	if len(review.FlaggedWords) > 0 || forcePending {
+		review.Status = enum.ReviewStatusPending
+		return
+	}
+
+	review.Status = enum.ReviewStatusPublished
+}
+
+// 文章に含まれるNGワードを返却する
+// 全角・半角や大文字・小文字の違いを無視して比較する
+func DetectNGWords(text string, ngWords []string) []string {
+	normalizedText := normalizeForNGWord(text)
+
+	detected := []string{}
+	for _, ngWord := range ngWords {
+		normalizedNGWord := normalizeForNGWord(ngWord)
+		if normalizedNGWord == "" {
+			continue
+		}
+
+		if strings.Contains(normalizedText, normalizedNGWord) {
+			detected = append(detected, ngWord)
+		}
+	}
+
+	return detected
+}
+
+func normalizeForNGWord(text string) string {
+	return strings.ToLower(norm.NFKC.String(strings.TrimSpace(text)))
+}
+
+// 評価ごとのレビュー数からレビュー数の分布を作成する（レビューが存在しない評価は0件とする）
+func NewReviewRatingDistribution(counts map[int]int) ReviewRatingDistribution {
+	distribution := ReviewRatingDistribution{Counts: map[int]int{}}
+	for rating := 1; rating <= 5; rating++ {
+		distribution.Counts[rating] = counts[rating]
+		distribution.TotalCount += counts[rating]
+	}
+
+	return distribution
+}
+
+// 評価の平均（小数点以下第2位を四捨五入）を返却する。レビューが存在しない場合は0を返却する。
+func (distribution ReviewRatingDistribution) Average() float64 {
+	if distribution.TotalCount == 0 {
+		return 0
+	}
+
+	var sum int
+	for rating, count := range distribution.Counts {
+		sum += rating * count
+	}
+	return math.Round(float64(sum)/float64(distribution.TotalCount)*10) / 10
+}
+
+// 評価ごとのレビュー数の割合（%、四捨五入）を返却する
+func (distribution ReviewRatingDistribution) Percentages() map[int]int {
+	percentages := make(map[int]int, len(distribution.Counts))
+	for rating, count := range distribution.Counts {
+		if distribution.TotalCount == 0 {
+			percentages[rating] = 0
+			continue
+		}
+		percentages[rating] = int(math.Round(float64(count) * 100 / float64(distribution.TotalCount)))
+	}
+
+	return percentages
+}
@@ -0,0 +1,206 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+var reviewNGWords = []string{"詐欺", "http://"}
+
+func TestCreateReview(t *testing.T) {
+	tests := []struct {
+		Name                 string
+		Title                string
+		Body                 string
+		ExpectedStatus       enum.ReviewStatus
+		ExpectedFlaggedWords []string
+	}{
+		{Name: "NGワードを含まない場合、公開中にする", Title: "良い商品", Body: "とても満足しています", ExpectedStatus: enum.ReviewStatusPublished, ExpectedFlaggedWords: []string{}},
+		{Name: "NGワードを含む場合、確認待ちにする", Title: "良い商品", Body: "詳しくはHTTP://example.comへ", ExpectedStatus: enum.ReviewStatusPending, ExpectedFlaggedWords: []string{"http://"}},
+		{Name: "全角のNGワードも検出する", Title: "ｈｔｔｐ：／／example.com", Body: "これは詐欺です", ExpectedStatus: enum.ReviewStatusPending, ExpectedFlaggedWords: []string{"詐欺", "http://"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			review := entity.CreateReview("product1", "account1", 5, tt.Title, tt.Body, false, reviewNGWords, time.Now())
+
+			// then（期待する結果）
+			assert.Equal(t, tt.ExpectedStatus, review.Status)
+			assert.Equal(t, tt.ExpectedFlaggedWords, review.FlaggedWords)
+			assert.Equal(t, 1, review.Version)
+		})
+	}
+}
+
+func TestReviewEdit(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Status         enum.ReviewStatus
+		Body           string
+		ExpectedStatus enum.ReviewStatus
+	}{
+		{Name: "確認待ちのレビューからNGワードを除いた場合、公開中にする", Status: enum.ReviewStatusPending, Body: "満足しています", ExpectedStatus: enum.ReviewStatusPublished},
+		{Name: "公開中のレビューにNGワードを含めた場合、確認待ちにする", Status: enum.ReviewStatusPublished, Body: "詐欺でした", ExpectedStatus: enum.ReviewStatusPending},
+		{Name: "非公開のレビューを編集した場合、NGワードを含まなくても確認待ちにする", Status: enum.ReviewStatusRejected, Body: "満足しています", ExpectedStatus: enum.ReviewStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// given（前提条件）
+			review := entity.Review{ID: "1", Rating: 1, Title: "タイトル", Body: "本文", Status: tt.Status}
+
+			// when（操作）
+			review.Edit(4, "タイトル", tt.Body, reviewNGWords, time.Now())
+
+			// then（期待する結果）
+			assert.Equal(t, tt.ExpectedStatus, review.Status)
+			assert.Equal(t, 4, review.Rating)
+		})
+	}
+}
+
+func TestReviewReplacePhotos(t *testing.T) {
+	// given（前提条件）
+	review := entity.Review{ID: "review1"}
+
+	// when（操作）
+	photos, err := review.ReplacePhotos([]string{"image/jpeg", "image/webp"})
+
+	// then（期待する結果）表示順と画像形式に応じた拡張子のパスを設定する
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(photos))
+	assert.Equal(t, photos, review.Photos)
+	assert.Equal(t, 1, photos[0].Order)
+	assert.Equal(t, "/reviews/review1/"+photos[0].ID+".jpg", photos[0].Path)
+	assert.Equal(t, "/reviews/review1/"+photos[1].ID+".webp", photos[1].Path)
+
+	// when・then（操作・期待する結果）上限を超える枚数・画像以外の形式はエラー
+	_, err = review.ReplacePhotos([]string{"image/jpeg", "image/jpeg", "image/jpeg", "image/jpeg"})
+	assert.NotNil(t, err)
+	_, err = review.ReplacePhotos([]string{"application/pdf"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(review.Photos), "エラーの場合は写真を変更しない")
+}
+
+func TestReviewModerate(t *testing.T) {
+	// given（前提条件）
+	review := entity.Review{Status: enum.ReviewStatusPending}
+
+	// when・then（操作・期待する結果）
+	assert.Nil(t, review.Moderate(enum.ReviewStatusRejected, time.Now()))
+	assert.Equal(t, enum.ReviewStatusRejected, review.Status)
+	assert.NotNil(t, review.Moderate(enum.ReviewStatusPending, time.Now()))
+}
+
+func TestReviewCreateVote(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Status      enum.ReviewStatus
+		AccountID   string
+		ExpectedErr bool
+	}{
+		{Name: "公開中の他のアカウントのレビューの場合、投票を作成する", Status: enum.ReviewStatusPublished, AccountID: "account2", ExpectedErr: false},
+		{Name: "自分のレビューの場合、エラーを返却する", Status: enum.ReviewStatusPublished, AccountID: "account1", ExpectedErr: true},
+		{Name: "確認待ちのレビューの場合、エラーを返却する", Status: enum.ReviewStatusPending, AccountID: "account2", ExpectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// given（前提条件）
+			review := entity.Review{ID: "review1", AccountID: "account1", Status: tt.Status}
+
+			// when（操作）
+			vote, err := review.CreateVote(tt.AccountID, true, time.Now())
+
+			// then（期待する結果）
+			if tt.ExpectedErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, "review1", vote.ReviewID)
+			assert.Equal(t, tt.AccountID, vote.AccountID)
+			assert.True(t, vote.IsHelpful)
+		})
+	}
+}
+
+func TestReviewReport(t *testing.T) {
+	tests := []struct {
+		Name                string
+		ReportCount         int
+		ExpectedStatus      enum.ReviewStatus
+		ExpectedReportCount int
+	}{
+		{Name: "報告数が閾値未満の場合、公開中のままにする", ReportCount: entity.ReviewReportThreshold - 2, ExpectedStatus: enum.ReviewStatusPublished, ExpectedReportCount: entity.ReviewReportThreshold - 1},
+		{Name: "報告数が閾値に達した場合、確認待ちに戻す", ReportCount: entity.ReviewReportThreshold - 1, ExpectedStatus: enum.ReviewStatusPending, ExpectedReportCount: entity.ReviewReportThreshold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// given（前提条件）
+			review := entity.Review{ID: "review1", AccountID: "account1", Status: enum.ReviewStatusPublished, ReportCount: tt.ReportCount}
+
+			// when（操作）
+			report, err := review.Report("account2", "広告です", time.Now())
+
+			// then（期待する結果）
+			assert.Nil(t, err)
+			assert.Equal(t, tt.ExpectedStatus, review.Status)
+			assert.Equal(t, tt.ExpectedReportCount, review.ReportCount)
+			assert.Equal(t, "review1", report.ReviewID)
+			assert.Equal(t, "広告です", report.Reason)
+		})
+	}
+
+	t.Run("自分のレビューの場合、エラーを返却する", func(t *testing.T) {
+		// given（前提条件）
+		review := entity.Review{ID: "review1", AccountID: "account1", Status: enum.ReviewStatusPublished}
+
+		// when（操作）
+		_, err := review.Report("account1", "広告です", time.Now())
+
+		// then（期待する結果）
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, review.ReportCount)
+	})
+}
+
+func TestReviewRatingDistribution(t *testing.T) {
+	tests := []struct {
+		Name                string
+		Counts              map[int]int
+		ExpectedAverage     float64
+		ExpectedPercentages map[int]int
+	}{
+		{
+			Name:                "評価ごとのレビュー数から平均と割合を算出し、レビューが存在しない評価は0件とする",
+			Counts:              map[int]int{5: 2, 4: 1, 1: 1},
+			ExpectedAverage:     3.8,
+			ExpectedPercentages: map[int]int{5: 50, 4: 25, 3: 0, 2: 0, 1: 25},
+		},
+		{
+			Name:                "レビューが存在しない場合、平均と割合を0にする",
+			Counts:              map[int]int{},
+			ExpectedAverage:     0,
+			ExpectedPercentages: map[int]int{5: 0, 4: 0, 3: 0, 2: 0, 1: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			distribution := entity.NewReviewRatingDistribution(tt.Counts)
+
+			// then（期待する結果）
+			assert.Equal(t, 5, len(distribution.Counts))
+			assert.Equal(t, tt.ExpectedAverage, distribution.Average())
+			assert.Equal(t, tt.ExpectedPercentages, distribution.Percentages())
+		})
+	}
+}
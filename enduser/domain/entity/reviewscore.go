@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"math"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+type (
+	// 日ごとの商品のレビュー点数（0〜5）
+	ReviewScore struct {
+		ID        string
+		ProductID string
+		Score     int
+		Date      time.Time
+	}
+
+	// 商品ごとの公開中のレビューの評価の集計
+	ReviewRatingSummary struct {
+		ProductID string
+		Count     int // レビュー数
+		Sum       int // 評価の合計
+	}
+
+	// レビュー点数の算出方法
+	ReviewScoreCalculator struct {
+		Method     enum.ReviewScoreMethod
+		PriorCount int // ベイズ平均で全商品の評価の平均を何件分のレビューとして加えるか
+	}
+)
+
+// レビュー点数の算出方法を作成する
+func NewReviewScoreCalculator(method enum.ReviewScoreMethod, priorCount int) (ReviewScoreCalculator, error) {
+	if !method.IsValid() {
+		return ReviewScoreCalculator{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"レビュー点数の算出方法はaverageまたはbayesianを指定してください"})
+	}
+	if method == enum.ReviewScoreMethodBayesian && priorCount < 1 {
+		return ReviewScoreCalculator{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{"ベイズ平均の事前レビュー数は1以上を指定してください"})
+	}
+
+	return ReviewScoreCalculator{Method: method, PriorCount: priorCount}, nil
+}
+
+// 商品ごとのレビューの評価の集計から日付のレビュー点数配列を返却する
+// レビュー点数は四捨五入した整数とし、レビューが存在しない商品のレビュー点数は作成しない
+func (calculator ReviewScoreCalculator) Calculate(summaries []ReviewRatingSummary, date time.Time) []ReviewScore {
+	var totalCount, totalSum int
+	for _, summary := range summaries {
+		totalCount += summary.Count
+		totalSum += summary.Sum
+	}
+
+	scores := make([]ReviewScore, 0, len(summaries))
+	for _, summary := range summaries {
+		if summary.Count == 0 {
+			continue
+		}
+
+		var score float64
+		switch calculator.Method {
+		case enum.ReviewScoreMethodBayesian:
+			globalMean := float64(totalSum) / float64(totalCount)
+			score = (globalMean*float64(calculator.PriorCount) + float64(summary.Sum)) / float64(calculator.PriorCount+summary.Count)
+		default:
+			score = float64(summary.Sum) / float64(summary.Count)
+		}
+
+		scores = append(scores, ReviewScore{
+			ID:        util.IDutils.GenerateID(),
+			ProductID: summary.ProductID,
+			Score:     int(math.Round(score)),
+			Date:      date,
+		})
+	}
+
+	return scores
+}
@@ -0,0 +1,60 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewScoreCalculatorCalculate(t *testing.T) {
+	// given（前提条件）全商品の評価の平均は3（30/10）
+	date := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	summaries := []entity.ReviewRatingSummary{
+		{ProductID: "1", Count: 1, Sum: 5},
+		{ProductID: "2", Count: 8, Sum: 20},
+		{ProductID: "3", Count: 1, Sum: 5},
+	}
+
+	tests := []struct {
+		Name       string
+		Method     enum.ReviewScoreMethod
+		PriorCount int
+		Expected   map[string]int
+	}{
+		{Name: "平均の場合、評価の平均を四捨五入する", Method: enum.ReviewScoreMethodAverage, Expected: map[string]int{"1": 5, "2": 3, "3": 5}},
+		{Name: "ベイズ平均の場合、レビュー数が少ない商品は全商品の平均に近づく", Method: enum.ReviewScoreMethodBayesian, PriorCount: 5, Expected: map[string]int{"1": 3, "2": 3, "3": 3}},
+		{Name: "ベイズ平均の事前レビュー数が小さい場合、商品の評価の平均に近づく", Method: enum.ReviewScoreMethodBayesian, PriorCount: 1, Expected: map[string]int{"1": 4, "2": 3, "3": 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// given（前提条件）
+			calculator, err := entity.NewReviewScoreCalculator(tt.Method, tt.PriorCount)
+			assert.Nil(t, err)
+
+			// when（操作）
+			scores := calculator.Calculate(summaries, date)
+
+			// then（期待する結果）
+			result := map[string]int{}
+			for _, score := range scores {
+				assert.Equal(t, date, score.Date)
+				result[score.ProductID] = score.Score
+			}
+			assert.Equal(t, tt.Expected, result)
+		})
+	}
+}
+
+func TestNewReviewScoreCalculator(t *testing.T) {
+	// when・then（操作・期待する結果）
+	_, err := entity.NewReviewScoreCalculator("median", 5)
+	assert.NotNil(t, err)
+	_, err = entity.NewReviewScoreCalculator(enum.ReviewScoreMethodBayesian, 0)
+	assert.NotNil(t, err)
+	_, err = entity.NewReviewScoreCalculator(enum.ReviewScoreMethodAverage, 0)
+	assert.Nil(t, err)
+}
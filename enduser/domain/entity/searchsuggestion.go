@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+// 検索候補
+type SearchSuggestion struct {
+	Text string
+	Type enum.SearchSuggestionType
+}
+
+const (
+	SearchSuggestionMaxCount        = 10 // 返却する検索候補の上限数
+	SearchSuggestionPopularDays     = 7  // 最近よく検索されたキーワードを集計する日数
+	SearchSuggestionMaxPrefixLength = 50 // 検索候補を取得する入力文字列の上限文字数
+)
+
+// 検索候補を前方一致で検索するためのキー配列を返却する
+// 検索用に正規化した文字列と、英字を読みのひらがなに変換した文字列をキーにする
+func (suggestion SearchSuggestion) Keys() []string {
+	normalized := util.TextUtils.NormalizeForSearch(suggestion.Text)
+	reading := util.TextUtils.ToReading(suggestion.Text)
+	if normalized == reading {
+		return []string{normalized}
+	}
+
+	return []string{normalized, reading}
+}
+
+// 正規化した入力文字列にキーのいずれかが前方一致する場合trueを返却する
+func (suggestion SearchSuggestion) HasPrefix(prefix string) bool {
+	for _, key := range suggestion.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 検索候補を重複を除いて上限数まで連結する（表示文字列を正規化した文字列が同じ検索候補は重複とみなす）
+func MergeSearchSuggestions(suggestionsList ...[]SearchSuggestion) []SearchSuggestion {
+	seen := map[string]struct{}{}
+	merged := []SearchSuggestion{}
+	for _, suggestions := range suggestionsList {
+		for _, suggestion := range suggestions {
+			normalized := util.TextUtils.NormalizeForSearch(suggestion.Text)
+			if _, ok := seen[normalized]; ok {
+				continue
+			}
+			seen[normalized] = struct{}{}
+
+			merged = append(merged, suggestion)
+			if len(merged) == SearchSuggestionMaxCount {
+				return merged
+			}
+		}
+	}
+
+	return merged
+}
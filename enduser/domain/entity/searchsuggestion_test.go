@@ -0,0 +1,57 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchSuggestionHasPrefix(t *testing.T) {
+	// given（前提条件）
+	suggestion := entity.SearchSuggestion{Text: "Tシャツ", Type: enum.SearchSuggestionTypeProduct}
+
+	tests := []struct {
+		Name     string
+		Input    string
+		Expected bool
+	}{
+		{Name: "英字の読みのひらがなで一致する", Input: "てぃーしゃつ", Expected: true},
+		{Name: "英字の読みの途中まででも一致する", Input: "てぃー", Expected: true},
+		{Name: "全角英字・ひらがなで一致する", Input: "Ｔしゃ", Expected: true},
+		{Name: "半角カタカナで一致する", Input: "tｼｬﾂ", Expected: true},
+		{Name: "前方一致しない場合は一致しない", Input: "しゃつ", Expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			result := suggestion.HasPrefix(util.TextUtils.NormalizeForSearch(tt.Input))
+
+			// then（期待する結果）
+			assert.Equal(t, tt.Expected, result)
+		})
+	}
+}
+
+func TestMergeSearchSuggestions(t *testing.T) {
+	// given（前提条件）
+	queries := []entity.SearchSuggestion{{Text: "tシャツ", Type: enum.SearchSuggestionTypeQuery}}
+	names := []entity.SearchSuggestion{
+		{Text: "Tシャツ", Type: enum.SearchSuggestionTypeCategory},
+		{Text: "Tシャツ メンズ", Type: enum.SearchSuggestionTypeProduct},
+	}
+	for i := 0; i < entity.SearchSuggestionMaxCount; i++ {
+		names = append(names, entity.SearchSuggestion{Text: "Tシャツ" + string(rune('a'+i)), Type: enum.SearchSuggestionTypeProduct})
+	}
+
+	// when（操作）
+	merged := entity.MergeSearchSuggestions(queries, names)
+
+	// then（期待する結果）正規化した文字列が同じ検索候補は先に連結した検索候補のみ残し、上限数までにする
+	assert.Equal(t, entity.SearchSuggestionMaxCount, len(merged))
+	assert.Equal(t, entity.SearchSuggestion{Text: "tシャツ", Type: enum.SearchSuggestionTypeQuery}, merged[0])
+	assert.Equal(t, "Tシャツ メンズ", merged[1].Text)
+}
@@ -21,11 +21,15 @@ type (
 
 	// セッションアカウント作成イベント
 	SessionAccountCreatedEvent struct {
-		AccountID         string
-		SessionCart       SessionCart
-		ExistsSessionCart bool
-		DB                bun.IDB
-		Ctx               context.Context
+		AccountID             string
+		SessionCart           SessionCart
+		ExistsSessionCart     bool
+		SessionWishlist       SessionWishlist
+		ExistsSessionWishlist bool
+		// ゲストの閲覧履歴のセッションID（閲覧履歴が存在しない場合は空文字）
+		RecentlyViewedSessionID string
+		DB                      bun.IDB
+		Ctx                     context.Context
 	}
 )
 
@@ -40,7 +44,16 @@ func (event SessionAccountCreatedEvent) Name() share.DomainEventName {
 }
 
 // セッションアカウントを作成する
-func CreateSessionAccount(account Account, sessionCart SessionCart, existsSessionCart bool, db bun.IDB, ctx context.Context) (http.Cookie, SessionAccount) {
+func CreateSessionAccount(
+	account Account,
+	sessionCart SessionCart,
+	existsSessionCart bool,
+	sessionWishlist SessionWishlist,
+	existsSessionWishlist bool,
+	recentlyViewedSessionID string,
+	db bun.IDB,
+	ctx context.Context,
+) (http.Cookie, SessionAccount) {
 	// Cookieを作成する
 	sessionID := util.IDutils.GenerateID()
 	cookie := util.CookieUtils.CreateCookie(SessionAccountCookieName, sessionID, time.Now().Add(SessionAccountExpiration))
@@ -51,11 +64,14 @@ func CreateSessionAccount(account Account, sessionCart SessionCart, existsSessio
 		SessionID: sessionID,
 		Events: []share.DomainEvent{
 			SessionAccountCreatedEvent{
-				AccountID:         account.ID,
-				SessionCart:       sessionCart,
-				ExistsSessionCart: existsSessionCart,
-				DB:                db,
-				Ctx:               ctx,
+				AccountID:               account.ID,
+				SessionCart:             sessionCart,
+				ExistsSessionCart:       existsSessionCart,
+				SessionWishlist:         sessionWishlist,
+				ExistsSessionWishlist:   existsSessionWishlist,
+				RecentlyViewedSessionID: recentlyViewedSessionID,
+				DB:                      db,
+				Ctx:                     ctx,
 			},
 		},
 	}
@@ -5,13 +5,15 @@ import "time"
 type (
 	//セッションカート集約
 	SessionCart struct {
-		SessionID           string
-		SessionCartProducts []SessionCartProduct
+		SessionID            string
+		SessionCartProducts  []SessionCartProduct
+		SessionSavedProducts []SessionCartProduct // あとで買う商品
 	}
 
 	//セッションカート商品
 	SessionCartProduct struct {
 		ProductID string
+		SKUID     string // バリエーションを持たない商品の場合は空文字
 		Count     int
 	}
 )
@@ -21,12 +23,20 @@ const (
 	SessionCartCookieName = "SessionCartSessionID"
 )
 
-// セッションカート内の商品の商品ID配列を返却する
+// セッションカート内の商品とあとで買う商品の商品ID配列を返却する
 func (sessionCart SessionCart) ProductIDs() []string {
-	ids := make([]string, 0, len(sessionCart.SessionCartProducts))
+	ids := make([]string, 0, len(sessionCart.SessionCartProducts)+len(sessionCart.SessionSavedProducts))
 	for _, sessionCartProduct := range sessionCart.SessionCartProducts {
 		ids = append(ids, sessionCartProduct.ProductID)
 	}
+	for _, sessionSavedProduct := range sessionCart.SessionSavedProducts {
+		ids = append(ids, sessionSavedProduct.ProductID)
+	}
 
 	return ids
 }
+
+// セッションカート内に商品とあとで買う商品がどちらも存在しない場合trueを返却する
+func (sessionCart SessionCart) IsEmpty() bool {
+	return len(sessionCart.SessionCartProducts) == 0 && len(sessionCart.SessionSavedProducts) == 0
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+type (
+	// セッションお気に入りリスト集約（ログインしていないゲストのお気に入りリスト）
+	SessionWishlist struct {
+		SessionID  string
+		ProductIDs []string
+	}
+)
+
+const (
+	SessionWishlistExpiration = 30 * 24 * time.Hour // セッションお気に入りリストの有効期限はセッションカートと同じ30日
+	SessionWishlistCookieName = "SessionWishlistSessionID"
+)
+
+// セッションお気に入りリストを作成する
+func CreateSessionWishlist() (http.Cookie, SessionWishlist) {
+	sessionID := util.IDutils.GenerateID()
+	cookie := util.CookieUtils.CreateCookie(SessionWishlistCookieName, sessionID, time.Now().Add(SessionWishlistExpiration))
+
+	return cookie, SessionWishlist{
+		SessionID:  sessionID,
+		ProductIDs: []string{},
+	}
+}
+
+// セッションお気に入りリストに商品を追加する
+// 既に同じ商品が存在する場合は何もしない
+func (sessionWishlist *SessionWishlist) AddProduct(productID string) error {
+	for _, id := range sessionWishlist.ProductIDs {
+		if id == productID {
+			return nil
+		}
+	}
+
+	if len(sessionWishlist.ProductIDs) >= WishlistMaxProducts {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"お気に入りリストに登録できる商品数の上限に達しています"})
+	}
+
+	sessionWishlist.ProductIDs = append(sessionWishlist.ProductIDs, productID)
+	return nil
+}
+
+// セッションお気に入りリストから商品を削除する
+func (sessionWishlist *SessionWishlist) RemoveProduct(productID string) error {
+	for i, id := range sessionWishlist.ProductIDs {
+		if id == productID {
+			sessionWishlist.ProductIDs = append(sessionWishlist.ProductIDs[:i], sessionWishlist.ProductIDs[i+1:]...)
+			return nil
+		}
+	}
+
+	return share.CreateOriginalError(share.ErrorCodeOther, []string{"お気に入りリストに商品が見つかりません"})
+}
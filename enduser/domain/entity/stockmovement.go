@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+type (
+	// 在庫台帳の在庫移動（登録後は変更・削除しない）
+	// SKUを持つ商品はSKUの在庫数の、バリエーションを持たない商品は商品の在庫数の移動を記録する
+	StockMovement struct {
+		ID             string
+		ProductID      string
+		SKUID          string // バリエーションを持たない商品の場合は空文字
+		Type           enum.StockMovementType
+		Quantity       int // 在庫数の増減（販売は在庫引当時に差し引き済みのため0）
+		BeforeCount    int // 移動前の在庫数
+		AfterCount     int // 移動後の在庫数
+		Reason         string
+		ReferenceType  enum.StockMovementReferenceType
+		ReferenceID    string // 注文ID・在庫引当ID・ショップスタッフのアカウントID
+		CreateDateTime time.Time
+	}
+
+	// 現在の在庫数
+	StockLevel struct {
+		ProductID  string
+		SKUID      string
+		StockCount int
+	}
+
+	// 在庫台帳の商品・SKUごとの在庫数の増減の合計
+	StockLedgerBalance struct {
+		ProductID  string
+		SKUID      string
+		Quantity   int
+		EntryCount int
+	}
+
+	// 在庫台帳から算出した在庫数と現在の在庫数の差異
+	StockDrift struct {
+		ProductID        string `json:"productID"`
+		SKUID            string `json:"skuID,omitempty"`
+		StockCount       int    `json:"stockCount"`       // 現在の在庫数
+		LedgerStockCount int    `json:"ledgerStockCount"` // 在庫台帳から算出した在庫数
+		Drift            int    `json:"drift"`            // 現在の在庫数 - 在庫台帳から算出した在庫数
+	}
+)
+
+// 在庫移動の種別に対して在庫数の増減が正しい場合nilを、そうでない場合エラーを返却する
+// 入荷・返品・在庫引当の解放は増加、在庫引当は減少、販売は増減なし、手動調整は0以外とする
+func ValidateStockMovementQuantity(movementType enum.StockMovementType, quantity int) error {
+	var valid bool
+	switch movementType {
+	case enum.StockMovementTypeReceipt, enum.StockMovementTypeReturn, enum.StockMovementTypeRelease:
+		valid = quantity > 0
+	case enum.StockMovementTypeReservation:
+		valid = quantity < 0
+	case enum.StockMovementTypeSale:
+		valid = quantity == 0
+	case enum.StockMovementTypeAdjustment:
+		valid = quantity != 0
+	default:
+		return share.CreateOriginalError(share.ErrorCodeValidation, []string{"在庫移動の種別が不正です"})
+	}
+
+	if !valid {
+		return share.CreateOriginalError(share.ErrorCodeValidation, []string{fmt.Sprintf("在庫移動の種別（%s）に対して在庫数の増減（%d）が不正です", movementType, quantity)})
+	}
+	return nil
+}
+
+// 在庫移動を作成する（移動前の在庫数は移動後の在庫数と増減から算出する）
+func CreateStockMovement(
+	productID string,
+	skuID string,
+	movementType enum.StockMovementType,
+	quantity int,
+	afterCount int,
+	reason string,
+	referenceType enum.StockMovementReferenceType,
+	referenceID string,
+	now time.Time,
+) StockMovement {
+	return StockMovement{
+		ID:             util.IDutils.GenerateID(),
+		ProductID:      productID,
+		SKUID:          skuID,
+		Type:           movementType,
+		Quantity:       quantity,
+		BeforeCount:    afterCount - quantity,
+		AfterCount:     afterCount,
+		Reason:         reason,
+		ReferenceType:  referenceType,
+		ReferenceID:    referenceID,
+		CreateDateTime: now,
+	}
+}
+
+// 在庫移動により在庫数が0から増えた場合trueを返却する
+func (movement StockMovement) IsRestock() bool {
+	return movement.BeforeCount <= 0 && movement.AfterCount > 0
+}
+
+// 現在の在庫数と在庫台帳の増減の合計を比較し、一致しない商品・SKUの差異を商品ID・SKU IDの順に返却する
+// 在庫台帳にのみ存在する商品・SKU（削除された商品など）は現在の在庫数を0として比較する
+func ReconcileStock(levels []StockLevel, balances []StockLedgerBalance) []StockDrift {
+	type key struct{ productID, skuID string }
+
+	ledgerCounts := make(map[key]int, len(balances))
+	for _, balance := range balances {
+		ledgerCounts[key{balance.ProductID, balance.SKUID}] += balance.Quantity
+	}
+
+	drifts := []StockDrift{}
+	for _, level := range levels {
+		k := key{level.ProductID, level.SKUID}
+		ledgerCount := ledgerCounts[k]
+		delete(ledgerCounts, k)
+
+		if level.StockCount != ledgerCount {
+			drifts = append(drifts, StockDrift{
+				ProductID:        level.ProductID,
+				SKUID:            level.SKUID,
+				StockCount:       level.StockCount,
+				LedgerStockCount: ledgerCount,
+				Drift:            level.StockCount - ledgerCount,
+			})
+		}
+	}
+	for k, ledgerCount := range ledgerCounts {
+		if ledgerCount != 0 {
+			drifts = append(drifts, StockDrift{ProductID: k.productID, SKUID: k.skuID, LedgerStockCount: ledgerCount, Drift: -ledgerCount})
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].ProductID != drifts[j].ProductID {
+			return drifts[i].ProductID < drifts[j].ProductID
+		}
+		return drifts[i].SKUID < drifts[j].SKUID
+	})
+	return drifts
+}
@@ -0,0 +1,96 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStockMovementQuantity(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Type      enum.StockMovementType
+		Quantity  int
+		ExpectErr bool
+	}{
+		{Name: "入荷で在庫数が増える場合、エラーを返却しない", Type: enum.StockMovementTypeReceipt, Quantity: 10},
+		{Name: "入荷で在庫数が減る場合、エラーを返却する", Type: enum.StockMovementTypeReceipt, Quantity: -1, ExpectErr: true},
+		{Name: "返品で在庫数が増えない場合、エラーを返却する", Type: enum.StockMovementTypeReturn, Quantity: 0, ExpectErr: true},
+		{Name: "在庫引当で在庫数が減る場合、エラーを返却しない", Type: enum.StockMovementTypeReservation, Quantity: -2},
+		{Name: "在庫引当で在庫数が増える場合、エラーを返却する", Type: enum.StockMovementTypeReservation, Quantity: 2, ExpectErr: true},
+		{Name: "在庫引当の解放で在庫数が増える場合、エラーを返却しない", Type: enum.StockMovementTypeRelease, Quantity: 2},
+		{Name: "販売で在庫数が変わらない場合、エラーを返却しない", Type: enum.StockMovementTypeSale, Quantity: 0},
+		{Name: "販売で在庫数が減る場合、エラーを返却する", Type: enum.StockMovementTypeSale, Quantity: -1, ExpectErr: true},
+		{Name: "手動調整で在庫数が減る場合、エラーを返却しない", Type: enum.StockMovementTypeAdjustment, Quantity: -3},
+		{Name: "手動調整で在庫数が変わらない場合、エラーを返却する", Type: enum.StockMovementTypeAdjustment, Quantity: 0, ExpectErr: true},
+		{Name: "未定義の種別の場合、エラーを返却する", Type: enum.StockMovementType("unknown"), Quantity: 1, ExpectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			err := entity.ValidateStockMovementQuantity(tt.Type, tt.Quantity)
+
+			// then（期待する結果）
+			if tt.ExpectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateStockMovement(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+
+	// when（操作）在庫数を2個差し引いて3個になった
+	movement := entity.CreateStockMovement("1", "sku1", enum.StockMovementTypeReservation, -2, 3, "購入手続きの開始", enum.StockMovementReferenceStockReservation, "reservation1", now)
+
+	// then（期待する結果）移動前の在庫数は移動後の在庫数から算出する
+	assert.NotEmpty(t, movement.ID)
+	assert.Equal(t, 5, movement.BeforeCount)
+	assert.Equal(t, 3, movement.AfterCount)
+	assert.Equal(t, enum.StockMovementReferenceStockReservation, movement.ReferenceType)
+	assert.Equal(t, "reservation1", movement.ReferenceID)
+	assert.Equal(t, now, movement.CreateDateTime)
+}
+
+func TestStockMovementIsRestock(t *testing.T) {
+	now := time.Date(2024, 5, 20, 10, 0, 0, 0, time.UTC)
+
+	// 在庫数が0から増えた場合のみ再入荷とする
+	assert.True(t, entity.CreateStockMovement("1", "", enum.StockMovementTypeReceipt, 5, 5, "入荷", enum.StockMovementReferenceStaffAccount, "staff", now).IsRestock())
+	assert.False(t, entity.CreateStockMovement("1", "", enum.StockMovementTypeReceipt, 5, 8, "入荷", enum.StockMovementReferenceStaffAccount, "staff", now).IsRestock())
+	assert.False(t, entity.CreateStockMovement("1", "", enum.StockMovementTypeReservation, -1, 0, "購入手続きの開始", enum.StockMovementReferenceStockReservation, "reservation", now).IsRestock())
+}
+
+func TestReconcileStock(t *testing.T) {
+	// given（前提条件）
+	levels := []entity.StockLevel{
+		{ProductID: "2", StockCount: 4},
+		{ProductID: "1", SKUID: "sku2", StockCount: 0},
+		{ProductID: "1", SKUID: "sku1", StockCount: 5},
+		{ProductID: "3", StockCount: 0},
+	}
+	balances := []entity.StockLedgerBalance{
+		{ProductID: "1", SKUID: "sku1", Quantity: 5, EntryCount: 3},
+		{ProductID: "1", SKUID: "sku2", Quantity: 2, EntryCount: 1},
+		{ProductID: "2", Quantity: 6, EntryCount: 4},
+		{ProductID: "4", Quantity: 1, EntryCount: 1},
+		{ProductID: "5", Quantity: 0, EntryCount: 2},
+	}
+
+	// when（操作）
+	drifts := entity.ReconcileStock(levels, balances)
+
+	// then（期待する結果）一致しない商品・SKUと、在庫台帳にのみ在庫数が残る商品を返却する
+	assert.Equal(t, []entity.StockDrift{
+		{ProductID: "1", SKUID: "sku2", StockCount: 0, LedgerStockCount: 2, Drift: -2},
+		{ProductID: "2", StockCount: 4, LedgerStockCount: 6, Drift: -2},
+		{ProductID: "4", StockCount: 0, LedgerStockCount: 1, Drift: -1},
+	}, drifts)
+}
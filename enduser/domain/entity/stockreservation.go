@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+type (
+	// 在庫引当集約
+	// 購入手続きの開始時にカート内の商品の在庫を確保し、決済の完了時に確定、有効期限切れ・キャンセル時に解放する
+	// 引当中の数量は商品・SKUの在庫数から差し引いておき、解放時に戻す（在庫数は常に販売可能な数量を表す）
+	StockReservation struct {
+		ID             string
+		AccountID      string
+		Status         enum.StockReservationStatus
+		OrderID        *string // 確定時の注文ID（確定前はnil）
+		ExpiresAt      time.Time
+		CreateDateTime time.Time
+		UpdateDateTime *time.Time
+		Version        int
+
+		Items []StockReservationItem
+	}
+
+	// 在庫引当の商品
+	StockReservationItem struct {
+		ID            string
+		ReservationID string
+		ProductID     string
+		SKUID         string // バリエーションを持たない商品の場合は空文字
+		Count         int
+	}
+)
+
+const StockReservationTTL = 15 * time.Minute // 在庫引当の有効期限（購入手続きの開始からこの時間内に決済が完了しない場合は解放する）
+
+// カート内の商品の在庫引当集約を作成する
+// 販売中ではない商品や在庫が不足している商品が含まれる場合はエラーを返却する（在庫数の最終的な確認は在庫を差し引く際に行う）
+// 引当の商品は在庫を差し引く際にSKUの行を同じ順序でロックするよう、商品ID・SKU IDの順に並べる
+// （商品の行はSKUの順序に関わらず同じ商品の異なるSKU同士で競合するため、在庫を差し引く前にすべてロックする）
+func CreateStockReservation(accountID string, cart Cart, products []Product, now time.Time) (StockReservation, error) {
+	if len(cart.CartProducts) == 0 {
+		return StockReservation{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"カートに商品がありません"})
+	}
+
+	reservation := StockReservation{
+		ID:             util.IDutils.GenerateID(),
+		AccountID:      accountID,
+		Status:         enum.StockReservationStatusReserved,
+		ExpiresAt:      now.Add(StockReservationTTL),
+		CreateDateTime: now,
+		Items:          make([]StockReservationItem, 0, len(cart.CartProducts)),
+	}
+
+	for _, cartProduct := range cart.CartProducts {
+		product, ok := findProduct(products, cartProduct.ProductID)
+		if !ok || !product.isOnSale() {
+			return StockReservation{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"販売中ではない商品がカートに含まれています"})
+		}
+
+		sku, ok := product.FindSKU(cartProduct.SKUID)
+		if !ok {
+			return StockReservation{}, share.CreateOriginalError(share.ErrorCodeOther, []string{fmt.Sprintf("%sのバリエーションが存在しません", product.Name)})
+		}
+		if cartProduct.Count > sku.StockCount {
+			return StockReservation{}, share.CreateOriginalError(share.ErrorCodeOther, []string{fmt.Sprintf("%sの在庫が不足しています", product.Name)})
+		}
+
+		reservation.Items = append(reservation.Items, StockReservationItem{
+			ID:            util.IDutils.GenerateID(),
+			ReservationID: reservation.ID,
+			ProductID:     product.ID,
+			SKUID:         sku.ID,
+			Count:         cartProduct.Count,
+		})
+	}
+
+	sort.Slice(reservation.Items, func(i, j int) bool {
+		if reservation.Items[i].ProductID != reservation.Items[j].ProductID {
+			return reservation.Items[i].ProductID < reservation.Items[j].ProductID
+		}
+		return reservation.Items[i].SKUID < reservation.Items[j].SKUID
+	})
+
+	return reservation, nil
+}
+
+// 引当の商品の商品ID配列を重複なく返却する
+func (reservation StockReservation) ProductIDs() []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, item := range reservation.Items {
+		if !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			ids = append(ids, item.ProductID)
+		}
+	}
+	return ids
+}
+
+// 在庫引当を確定する（決済の完了時）
+// 有効期限を過ぎていても解放前であれば在庫は確保されたままのため確定できる
+func (reservation *StockReservation) Commit(orderID string, now time.Time) error {
+	if reservation.Status != enum.StockReservationStatusReserved {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"引当中ではない在庫引当は確定できません"})
+	}
+
+	reservation.Status = enum.StockReservationStatusCommitted
+	reservation.OrderID = &orderID
+	reservation.UpdateDateTime = &now
+	return nil
+}
+
+// 在庫引当を解放する（有効期限切れ・キャンセル時）
+func (reservation *StockReservation) Release(now time.Time) error {
+	if reservation.Status != enum.StockReservationStatusReserved {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"引当中ではない在庫引当は解放できません"})
+	}
+
+	reservation.Status = enum.StockReservationStatusReleased
+	reservation.UpdateDateTime = &now
+	return nil
+}
+
+// 引当中で有効期限を過ぎている場合trueを返却する
+func (reservation StockReservation) IsExpired(now time.Time) bool {
+	return reservation.Status == enum.StockReservationStatusReserved && !now.Before(reservation.ExpiresAt)
+}
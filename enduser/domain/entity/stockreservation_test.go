@@ -0,0 +1,112 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStockReservation(t *testing.T) {
+	now := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+	products := []entity.Product{
+		{ID: "2", Name: "商品2", Status: enum.OnSale, StockCount: 3},
+		{ID: "1", Name: "商品1", Status: enum.OnSale, StockCount: 5, SKUs: []entity.ProductSKU{
+			{ID: "sku2", ProductID: "1", StockCount: 2},
+			{ID: "sku1", ProductID: "1", StockCount: 3},
+		}},
+		{ID: "3", Name: "商品3", Status: enum.SalesSuspend, StockCount: 3},
+	}
+
+	tests := []struct {
+		Name         string
+		CartProducts []entity.CartProduct
+		ExpectErr    bool
+	}{
+		{Name: "カートが空の場合、エラーを返却する", CartProducts: []entity.CartProduct{}, ExpectErr: true},
+		{Name: "販売中ではない商品が含まれる場合、エラーを返却する", CartProducts: []entity.CartProduct{{ProductID: "3", Count: 1}}, ExpectErr: true},
+		{Name: "存在しないSKUが含まれる場合、エラーを返却する", CartProducts: []entity.CartProduct{{ProductID: "1", SKUID: "sku3", Count: 1}}, ExpectErr: true},
+		{Name: "SKUの在庫数を超える場合、エラーを返却する", CartProducts: []entity.CartProduct{{ProductID: "1", SKUID: "sku2", Count: 3}}, ExpectErr: true},
+		{Name: "在庫数以内の場合、在庫引当を作成する", CartProducts: []entity.CartProduct{{ProductID: "2", Count: 3}, {ProductID: "1", SKUID: "sku2", Count: 2}, {ProductID: "1", SKUID: "sku1", Count: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			reservation, err := entity.CreateStockReservation("account", entity.Cart{CartProducts: tt.CartProducts}, products, now)
+
+			// then（期待する結果）
+			if tt.ExpectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, enum.StockReservationStatusReserved, reservation.Status)
+			assert.Equal(t, now.Add(entity.StockReservationTTL), reservation.ExpiresAt)
+
+			// 在庫を差し引く際にデッドロックしないよう、商品ID・SKU IDの順に並べる
+			keys := []string{}
+			for _, item := range reservation.Items {
+				assert.Equal(t, reservation.ID, item.ReservationID)
+				keys = append(keys, item.ProductID+":"+item.SKUID)
+			}
+			assert.Equal(t, []string{"1:sku1", "1:sku2", "2:"}, keys)
+		})
+	}
+}
+
+func TestStockReservationCommitAndRelease(t *testing.T) {
+	now := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("引当中の場合、有効期限を過ぎていても確定できる", func(t *testing.T) {
+		// given（前提条件）
+		reservation := entity.StockReservation{Status: enum.StockReservationStatusReserved, ExpiresAt: now.Add(-time.Minute)}
+		assert.True(t, reservation.IsExpired(now))
+
+		// when（操作）
+		err := reservation.Commit("order1", now)
+
+		// then（期待する結果）
+		assert.Nil(t, err)
+		assert.Equal(t, enum.StockReservationStatusCommitted, reservation.Status)
+		assert.Equal(t, "order1", *reservation.OrderID)
+		assert.False(t, reservation.IsExpired(now))
+	})
+
+	t.Run("確定済みの場合、解放できない", func(t *testing.T) {
+		// given（前提条件）
+		reservation := entity.StockReservation{Status: enum.StockReservationStatusCommitted}
+
+		// when・then（操作・期待する結果）
+		assert.NotNil(t, reservation.Release(now))
+		assert.Equal(t, enum.StockReservationStatusCommitted, reservation.Status)
+	})
+
+	t.Run("解放済みの場合、確定・解放できない", func(t *testing.T) {
+		// given（前提条件）
+		reservation := entity.StockReservation{Status: enum.StockReservationStatusReserved, ExpiresAt: now}
+		assert.Nil(t, reservation.Release(now))
+
+		// when・then（操作・期待する結果）
+		assert.Equal(t, enum.StockReservationStatusReleased, reservation.Status)
+		assert.NotNil(t, reservation.Commit("order1", now))
+		assert.NotNil(t, reservation.Release(now))
+	})
+}
+
+func TestStockReservationProductIDs(t *testing.T) {
+	// given（前提条件）同じ商品の複数のSKUを含む在庫引当
+	reservation := entity.StockReservation{Items: []entity.StockReservationItem{
+		{ProductID: "1", SKUID: "sku1"},
+		{ProductID: "1", SKUID: "sku2"},
+		{ProductID: "2"},
+	}}
+
+	// when（操作）
+	productIDs := reservation.ProductIDs()
+
+	// then（期待する結果）商品IDを重複なく返却する
+	assert.Equal(t, []string{"1", "2"}, productIDs)
+}
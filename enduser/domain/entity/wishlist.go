@@ -0,0 +1,130 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+type (
+	// お気に入りリスト集約
+	// 1つのアカウントが複数の名前付きリストを持つことができる
+	Wishlist struct {
+		ID             string
+		AccountID      string
+		Name           string
+		IsDefault      bool    // ゲストのお気に入りをログイン時に移動する既定のリストの場合true
+		ShareToken     *string // 公開用の共有トークン（公開していない場合はnil）
+		Version        int
+		CreateDateTime time.Time
+
+		WishlistProducts []WishlistProduct
+	}
+
+	// お気に入り商品
+	WishlistProduct struct {
+		ID             string
+		WishlistID     string
+		ProductID      string
+		CreateDateTime time.Time
+	}
+)
+
+const (
+	DefaultWishlistName = "お気に入り"
+	WishlistMaxProducts = 100 // 1つのリストに登録できる商品数の上限
+)
+
+// お気に入りリスト集約を作成する
+func CreateWishlist(accountID string, name string, isDefault bool, now time.Time) Wishlist {
+	return Wishlist{
+		ID:               util.IDutils.GenerateID(),
+		AccountID:        accountID,
+		Name:             name,
+		IsDefault:        isDefault,
+		ShareToken:       nil,
+		Version:          1,
+		CreateDateTime:   now,
+		WishlistProducts: []WishlistProduct{},
+	}
+}
+
+// お気に入りリストに商品を追加する
+// 既に同じ商品が存在する場合は何もしない
+func (wishlist *Wishlist) AddProduct(productID string, now time.Time) error {
+	if wishlist.Contains(productID) {
+		return nil
+	}
+
+	if len(wishlist.WishlistProducts) >= WishlistMaxProducts {
+		return share.CreateOriginalError(share.ErrorCodeOther, []string{"お気に入りリストに登録できる商品数の上限に達しています"})
+	}
+
+	wishlist.WishlistProducts = append(wishlist.WishlistProducts, WishlistProduct{
+		ID:             util.IDutils.GenerateID(),
+		WishlistID:     wishlist.ID,
+		ProductID:      productID,
+		CreateDateTime: now,
+	})
+	return nil
+}
+
+// お気に入りリストから商品を削除する
+func (wishlist *Wishlist) RemoveProduct(productID string) error {
+	for i, wishlistProduct := range wishlist.WishlistProducts {
+		if wishlistProduct.ProductID == productID {
+			wishlist.WishlistProducts = append(wishlist.WishlistProducts[:i], wishlist.WishlistProducts[i+1:]...)
+			return nil
+		}
+	}
+
+	return share.CreateOriginalError(share.ErrorCodeOther, []string{"お気に入りリストに商品が見つかりません"})
+}
+
+// お気に入りリストに商品が存在する場合trueを返却する
+func (wishlist Wishlist) Contains(productID string) bool {
+	for _, wishlistProduct := range wishlist.WishlistProducts {
+		if wishlistProduct.ProductID == productID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// お気に入りリストを公開し、共有トークンを返却する
+// 既に公開している場合は同じ共有トークンを返却する
+func (wishlist *Wishlist) Publish() string {
+	if wishlist.ShareToken == nil {
+		shareToken := util.IDutils.GenerateID()
+		wishlist.ShareToken = &shareToken
+	}
+
+	return *wishlist.ShareToken
+}
+
+// お気に入りリストを非公開にする（共有トークンを無効にする）
+func (wishlist *Wishlist) Unpublish() {
+	wishlist.ShareToken = nil
+}
+
+// お気に入りリスト内の商品の商品ID配列を返却する
+func (wishlist Wishlist) ProductIDs() []string {
+	ids := make([]string, 0, len(wishlist.WishlistProducts))
+	for _, wishlistProduct := range wishlist.WishlistProducts {
+		ids = append(ids, wishlistProduct.ProductID)
+	}
+
+	return ids
+}
+
+// セッションお気に入りリストの商品をお気に入りリストに移動する
+func (wishlist *Wishlist) MoveSessionWishlistProducts(sessionWishlist SessionWishlist, now time.Time) {
+	for _, productID := range sessionWishlist.ProductIDs {
+		// 上限に達した場合はそれ以上移動しない
+		if err := wishlist.AddProduct(productID, now); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWishlistAddProduct(t *testing.T) {
+	// given（前提条件）
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	fullWishlist := entity.CreateWishlist("1", entity.DefaultWishlistName, true, now)
+	for i := 0; i < entity.WishlistMaxProducts; i++ {
+		fullWishlist.WishlistProducts = append(fullWishlist.WishlistProducts, entity.WishlistProduct{ProductID: string(rune('a' + i))})
+	}
+
+	tests := []struct {
+		Name               string
+		Wishlist           entity.Wishlist
+		ProductID          string
+		ExpectedProductIDs []string
+		IsErr              bool
+	}{
+		{
+			Name:               "お気に入りリストに商品が存在しない場合、商品を追加する",
+			Wishlist:           entity.CreateWishlist("1", entity.DefaultWishlistName, true, now),
+			ProductID:          "10",
+			ExpectedProductIDs: []string{"10"},
+		},
+		{
+			Name:               "お気に入りリストに同じ商品が存在する場合、何もしない",
+			Wishlist:           entity.Wishlist{WishlistProducts: []entity.WishlistProduct{{ProductID: "10"}}},
+			ProductID:          "10",
+			ExpectedProductIDs: []string{"10"},
+		},
+		{
+			Name:               "お気に入りリストの商品数が上限に達している場合、エラーを返却する",
+			Wishlist:           fullWishlist,
+			ProductID:          "10",
+			ExpectedProductIDs: fullWishlist.ProductIDs(),
+			IsErr:              true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			err := tt.Wishlist.AddProduct(tt.ProductID, now)
+
+			// then（期待する結果）
+			assert.Equal(t, tt.IsErr, err != nil)
+			assert.Equal(t, tt.ExpectedProductIDs, tt.Wishlist.ProductIDs())
+		})
+	}
+}
+
+func TestWishlistPublish(t *testing.T) {
+	// given（前提条件）
+	wishlist := entity.CreateWishlist("1", "リスト", false, time.Now())
+
+	// when（操作）
+	shareToken := wishlist.Publish()
+
+	// then（期待する結果）公開済みの場合は同じ共有トークンを返却する
+	assert.NotEmpty(t, shareToken)
+	assert.Equal(t, shareToken, wishlist.Publish())
+
+	// when（操作）
+	wishlist.Unpublish()
+
+	// then（期待する結果）
+	assert.Nil(t, wishlist.ShareToken)
+}
+
+func TestMoveSessionWishlistProducts(t *testing.T) {
+	// given（前提条件）
+	now := time.Now()
+	wishlist := entity.Wishlist{WishlistProducts: []entity.WishlistProduct{{ProductID: "1"}}}
+	sessionWishlist := entity.SessionWishlist{ProductIDs: []string{"1", "2", "3"}}
+
+	// when（操作）
+	wishlist.MoveSessionWishlistProducts(sessionWishlist, now)
+
+	// then（期待する結果）既にお気に入りリストに存在する商品は重複して追加しない
+	assert.Equal(t, []string{"1", "2", "3"}, wishlist.ProductIDs())
+}
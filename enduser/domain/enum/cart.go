@@ -0,0 +1,12 @@
+package enum
+
+// カート商品の警告種別
+type CartWarningType int
+
+const (
+	CartWarningPriceIncreased CartWarningType = iota + 1 // カート追加時より値上がりした
+	CartWarningPriceDecreased                            // カート追加時より値下がりした
+	CartWarningSalesSuspend                              // 販売停止中になった
+	CartWarningSalesEnded                                // 販売終了になった
+	CartWarningStockShortage                             // カート内の個数が在庫数を超えている
+)
@@ -7,3 +7,151 @@ const (
 	SalesSuspend
 	SalesEnded
 )
+
+// 商品一覧の並び順
+type ProductSort string
+
+const (
+	ProductSortNewest      ProductSort = "newest"       // 新着順
+	ProductSortPriceAsc    ProductSort = "price_asc"    // 価格の安い順
+	ProductSortPriceDesc   ProductSort = "price_desc"   // 価格の高い順
+	ProductSortReviewScore ProductSort = "review_score" // レビュー点数の高い順
+)
+
+// 並び順が定義済みの値の場合trueを返却する
+func (sort ProductSort) IsValid() bool {
+	switch sort {
+	case ProductSortNewest, ProductSortPriceAsc, ProductSortPriceDesc, ProductSortReviewScore:
+		return true
+	}
+
+	return false
+}
+
+// 商品の在庫状況
+type StockStatus string
+
+const (
+	StockStatusInStock StockStatus = "in stock" // 在庫あり
+	StockStatusFewLeft StockStatus = "few left" // 残りわずか
+	StockStatusSoldOut StockStatus = "sold out" // 在庫なし
+)
+
+// 検索候補の種別
+type SearchSuggestionType string
+
+const (
+	SearchSuggestionTypeQuery    SearchSuggestionType = "query"    // 最近よく検索されたキーワード
+	SearchSuggestionTypeProduct  SearchSuggestionType = "product"  // 商品名
+	SearchSuggestionTypeCategory SearchSuggestionType = "category" // カテゴリー名
+)
+
+// 商品画像の派生画像の画像形式
+type ImageFormat string
+
+const (
+	ImageFormatJPEG ImageFormat = "jpeg"
+	ImageFormatWebP ImageFormat = "webp"
+)
+
+// 画像形式のファイル拡張子を返却する
+func (format ImageFormat) Extension() string {
+	switch format {
+	case ImageFormatWebP:
+		return ".webp"
+	default:
+		return ".jpg"
+	}
+}
+
+// 画像形式のContent-Typeを返却する
+func (format ImageFormat) ContentType() string {
+	switch format {
+	case ImageFormatWebP:
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
+
+// 適用期間を持つ商品の値の種別
+type ProductTimelineType string
+
+const (
+	ProductTimelineTypePrice     ProductTimelineType = "price"      // 商品価格
+	ProductTimelineTypeSalePrice ProductTimelineType = "sale_price" // 商品セール価格
+	ProductTimelineTypeStatus    ProductTimelineType = "status"     // 商品ステータス
+)
+
+// 種別が定義済みの値の場合trueを返却する
+func (timelineType ProductTimelineType) IsValid() bool {
+	switch timelineType {
+	case ProductTimelineTypePrice, ProductTimelineTypeSalePrice, ProductTimelineTypeStatus:
+		return true
+	}
+
+	return false
+}
+
+// 適用期間の不整合の種別
+type ProductTimelineProblemType string
+
+const (
+	ProductTimelineProblemGap     ProductTimelineProblemType = "gap"     // 適用期間の間に空白がある
+	ProductTimelineProblemOverlap ProductTimelineProblemType = "overlap" // 適用期間が重複している
+	ProductTimelineProblemInvalid ProductTimelineProblemType = "invalid" // 適用開始日が適用終了日より後
+)
+
+// 商品データの不整合の種別
+type ProductIntegrityIssueType string
+
+const (
+	ProductIntegrityIssueTimelineGap         ProductIntegrityIssueType = "timeline_gap"          // 商品価格・商品セール価格・商品ステータスが適用されない日が存在する
+	ProductIntegrityIssueTimelineOverlap     ProductIntegrityIssueType = "timeline_overlap"      // 商品価格・商品セール価格・商品ステータスの適用期間が重複している
+	ProductIntegrityIssueTimelineInvalid     ProductIntegrityIssueType = "timeline_invalid"      // 適用開始日が適用終了日より後
+	ProductIntegrityIssueMissingCategory     ProductIntegrityIssueType = "missing_category"      // 商品のカテゴリーが存在しない
+	ProductIntegrityIssueDuplicateImageOrder ProductIntegrityIssueType = "duplicate_image_order" // 商品画像の表示順が重複している
+	ProductIntegrityIssueNegativeStock       ProductIntegrityIssueType = "negative_stock"        // 在庫数が負の値
+)
+
+// おすすめ商品の算出元
+type ProductRecommendationSource string
+
+const (
+	ProductRecommendationSourceCoPurchase         ProductRecommendationSource = "co_purchase"         // 一緒に購入されている商品
+	ProductRecommendationSourceCategoryBestseller ProductRecommendationSource = "category_bestseller" // 同じカテゴリーの売れ筋商品
+)
+
+// ランキングの集計期間
+type RankingPeriod string
+
+const (
+	RankingPeriodDaily   RankingPeriod = "daily"   // 前日
+	RankingPeriodWeekly  RankingPeriod = "weekly"  // 前日までの7日間
+	RankingPeriodMonthly RankingPeriod = "monthly" // 前日までの30日間
+)
+
+// 集計期間の一覧
+var RankingPeriods = []RankingPeriod{RankingPeriodDaily, RankingPeriodWeekly, RankingPeriodMonthly}
+
+// 集計期間が定義済みの値の場合trueを返却する
+func (period RankingPeriod) IsValid() bool {
+	switch period {
+	case RankingPeriodDaily, RankingPeriodWeekly, RankingPeriodMonthly:
+		return true
+	}
+
+	return false
+}
+
+// 集計期間の日数を返却する
+func (period RankingPeriod) Days() int {
+	switch period {
+	case RankingPeriodWeekly:
+		return 7
+	case RankingPeriodMonthly:
+		return 30
+	default:
+		return 1
+	}
+}
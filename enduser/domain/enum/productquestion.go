@@ -0,0 +1,18 @@
+package enum
+
+// 商品の質問の公開状態
+type ProductQuestionStatus string
+
+const (
+	ProductQuestionStatusPending   ProductQuestionStatus = "pending"   // 確認待ち（公開前に確認が必要）
+	ProductQuestionStatusPublished ProductQuestionStatus = "published" // 公開中
+	ProductQuestionStatusRejected  ProductQuestionStatus = "rejected"  // 非公開（確認の結果、公開しないと判断した）
+)
+
+// 商品の質問への回答者の種別
+type ProductAnswerResponderType string
+
+const (
+	ProductAnswerResponderTypeStaff ProductAnswerResponderType = "staff" // ショップスタッフ
+	ProductAnswerResponderTypeBuyer ProductAnswerResponderType = "buyer" // 商品を購入済みのお客様
+)
@@ -0,0 +1,58 @@
+package enum
+
+// レビューの公開状態
+type ReviewStatus string
+
+const (
+	ReviewStatusPending   ReviewStatus = "pending"   // 確認待ち（NGワードを含むため公開前に確認が必要）
+	ReviewStatusPublished ReviewStatus = "published" // 公開中
+	ReviewStatusRejected  ReviewStatus = "rejected"  // 非公開（確認の結果、公開しないと判断した）
+)
+
+// 公開状態が定義済みの値の場合trueを返却する
+func (status ReviewStatus) IsValid() bool {
+	switch status {
+	case ReviewStatusPending, ReviewStatusPublished, ReviewStatusRejected:
+		return true
+	}
+
+	return false
+}
+
+// レビュー点数の算出方法
+type ReviewScoreMethod string
+
+const (
+	ReviewScoreMethodAverage  ReviewScoreMethod = "average"  // 評価の平均
+	ReviewScoreMethodBayesian ReviewScoreMethod = "bayesian" // 全商品の評価の平均で補正したベイズ平均（レビュー数が少ない商品の点数が極端になりにくい）
+)
+
+// 算出方法が定義済みの値の場合trueを返却する
+func (method ReviewScoreMethod) IsValid() bool {
+	switch method {
+	case ReviewScoreMethodAverage, ReviewScoreMethodBayesian:
+		return true
+	}
+
+	return false
+}
+
+// レビュー一覧の並び順
+type ReviewSort string
+
+const (
+	ReviewSortNewest     ReviewSort = "newest"      // 新しい順
+	ReviewSortHelpful    ReviewSort = "helpful"     // 参考になった数の多い順
+	ReviewSortRatingDesc ReviewSort = "rating_desc" // 評価の高い順
+	ReviewSortRatingAsc  ReviewSort = "rating_asc"  // 評価の低い順
+)
+
+// 並び順が定義済みの値の場合trueを返却する
+func (sort ReviewSort) IsValid() bool {
+	switch sort {
+	case ReviewSortNewest, ReviewSortHelpful, ReviewSortRatingDesc, ReviewSortRatingAsc:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package enum
+
+// 在庫台帳の在庫移動の種別
+type StockMovementType string
+
+const (
+	StockMovementTypeReceipt     StockMovementType = "receipt"     // 入荷
+	StockMovementTypeSale        StockMovementType = "sale"        // 販売（在庫引当の確定）
+	StockMovementTypeReservation StockMovementType = "reservation" // 在庫引当
+	StockMovementTypeRelease     StockMovementType = "release"     // 在庫引当の解放
+	StockMovementTypeReturn      StockMovementType = "return"      // 返品
+	StockMovementTypeAdjustment  StockMovementType = "adjustment"  // 手動調整
+)
+
+// 在庫移動の種別が定義済みの値の場合trueを返却する
+func (movementType StockMovementType) IsValid() bool {
+	switch movementType {
+	case StockMovementTypeReceipt, StockMovementTypeSale, StockMovementTypeReservation, StockMovementTypeRelease, StockMovementTypeReturn, StockMovementTypeAdjustment:
+		return true
+	}
+
+	return false
+}
+
+// 在庫移動の参照先の種別
+type StockMovementReferenceType string
+
+const (
+	StockMovementReferenceNone             StockMovementReferenceType = ""                  // 参照先なし（在庫台帳の導入時の在庫数など）
+	StockMovementReferenceOrder            StockMovementReferenceType = "order"             // 注文
+	StockMovementReferenceStockReservation StockMovementReferenceType = "stock_reservation" // 在庫引当
+	StockMovementReferenceStaffAccount     StockMovementReferenceType = "staff_account"     // 操作したショップスタッフのアカウント
+)
+
+// ショップスタッフが手動で記録できる在庫移動の種別（入荷・返品・手動調整）の場合trueを返却する
+// 在庫引当・解放・販売は購入手続きの中でのみ記録する
+func (movementType StockMovementType) IsManual() bool {
+	switch movementType {
+	case StockMovementTypeReceipt, StockMovementTypeReturn, StockMovementTypeAdjustment:
+		return true
+	}
+
+	return false
+}
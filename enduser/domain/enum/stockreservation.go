@@ -0,0 +1,10 @@
+package enum
+
+// 在庫引当の状態
+type StockReservationStatus string
+
+const (
+	StockReservationStatusReserved  StockReservationStatus = "reserved"  // 引当中（決済が完了するまで在庫を確保している）
+	StockReservationStatusCommitted StockReservationStatus = "committed" // 確定（決済が完了し、在庫の引き落としが確定した）
+	StockReservationStatusReleased  StockReservationStatus = "released"  // 解放（有効期限切れ・キャンセルにより在庫を戻した）
+)
@@ -10,6 +10,8 @@ import (
 
 type AccountRepository interface {
 	FindByEmail(db bun.IDB, ctx context.Context, email string) (entity.Account, bool, error)
+	FindByID(db bun.IDB, ctx context.Context, id string) (entity.Account, bool, error)
+	FindByIDs(db bun.IDB, ctx context.Context, ids []string) ([]entity.Account, error)
 	Insert(db bun.IDB, ctx context.Context, account *entity.Account, domainEventPublisher share.DomainEventPublisher) error
 	Update(db bun.IDB, ctx context.Context, account *entity.Account, domainEventPublisher share.DomainEventPublisher) error
 }
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type BackInStockSubscriptionRepository interface {
+	Exists(db bun.IDB, ctx context.Context, accountID string, productID string) (bool, error)
+	// 通知メールの送信待ちの購読を送信待ちになった日時・IDの順に最大limit件取得する
+	// 引数afterを指定した場合はafterより後の購読を取得する（nilの場合は先頭から取得する）
+	FindRequested(db bun.IDB, ctx context.Context, after *entity.BackInStockSubscription, limit int) ([]entity.BackInStockSubscription, error)
+	// 通知メールの送信待ちでない購読が存在する商品IDを取得する
+	FindUnrequestedProductIDs(db bun.IDB, ctx context.Context) ([]string, error)
+	// 入荷通知の購読を登録する（同じアカウントと商品の購読が存在する場合はErrDuplicateKeyを返却する）
+	Insert(db bun.IDB, ctx context.Context, subscription entity.BackInStockSubscription) error
+	// 商品の送信待ちでない購読を通知メールの送信待ちにする
+	RequestNotification(db bun.IDB, ctx context.Context, productID string, now time.Time) error
+	// 商品の通知メールの送信待ちを取り消す（送信前に再び購入できなくなった場合）
+	CancelNotificationRequest(db bun.IDB, ctx context.Context, productID string) error
+	Delete(db bun.IDB, ctx context.Context, accountID string, productID string) error
+	DeleteByIDs(db bun.IDB, ctx context.Context, ids []string) error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
 	"github.com/uptrace/bun"
@@ -11,4 +12,10 @@ type CartRepository interface {
 	FindByAccountID(db bun.IDB, ctx context.Context, accountID string) (entity.Cart, bool, error)
 	Insert(db bun.IDB, ctx context.Context, cart entity.Cart) error
 	Update(db bun.IDB, ctx context.Context, cart entity.Cart) error
+	FindAbandoned(db bun.IDB, ctx context.Context, updatedBefore time.Time) ([]entity.Cart, error)
+	// 最終更新後にカート放置リマインドメールの送信日時が記録されていない場合のみ送信日時を記録し、記録した場合trueを返却する
+	// 同時に実行された他のバッチが記録済みの場合はfalseを返却する
+	UpdateReminderSentAt(db bun.IDB, ctx context.Context, cart entity.Cart) (bool, error)
+	// カート放置リマインドメールの送信日時を元に戻す（送信に失敗した場合）
+	RestoreReminderSentAt(db bun.IDB, ctx context.Context, cart entity.Cart) error
 }
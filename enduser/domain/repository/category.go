@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type CategoryRepository interface {
+	// すべてのカテゴリーを返却する
+	FindAll(db bun.IDB, ctx context.Context) ([]entity.Category, error)
+	// カテゴリーIDに一致するカテゴリーとその祖先のカテゴリーを、最上位のカテゴリーから順に返却する
+	FindAncestorPath(db bun.IDB, ctx context.Context, id string) ([]entity.Category, error)
+}
@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/cockroachdb/errors"
+
+var (
+	// 一意制約に違反する登録を行った場合にリポジトリが返却するエラー（同時に同じ内容が登録された場合など）
+	ErrDuplicateKey = errors.New("一意制約違反エラー")
+)
@@ -8,6 +8,18 @@ import (
 )
 
 type ProductRepository interface {
-	// 商品ID配列に一致する商品配列を返却する。引数withImageがtrueの場合はS3から画像を取得し、そうでない場合は取得しない。
+	// 商品ID配列に一致する商品配列を返却する。引数withImageがtrueの場合は画像ストレージから画像のURLを取得し、そうでない場合は取得しない。当日の商品ステータス・商品価格・商品セール価格が存在しない商品は含めない。
 	FindByIDs(db bun.IDB, ctx context.Context, ids []string, withImage bool) ([]entity.Product, error)
+	// 商品IDに一致する商品を返却する。商品が存在しない場合や当日の商品ステータスが存在しない場合はfalseを返却する。
+	FindByID(db bun.IDB, ctx context.Context, id string, withImage bool) (entity.Product, bool, error)
+	// 商品ID配列に一致する商品の商品IDをキー、カテゴリーIDを値とするマップを返却する（存在しない商品は含めない）
+	FindCategoryIDs(db bun.IDB, ctx context.Context, ids []string) (map[string]string, error)
+	// 検索条件に一致する商品一覧の1ページを返却する
+	FindCatalog(db bun.IDB, ctx context.Context, condition entity.ProductCatalogCondition) (entity.ProductCatalogPage, error)
+	// 検索条件に一致する商品一覧の絞り込み条件ごとの商品数を返却する
+	FindFacets(db bun.IDB, ctx context.Context, condition entity.ProductCatalogCondition) (entity.ProductFacets, error)
+	// 商品ID配列のうち、検索条件の絞り込みに一致する商品ID配列を返却する（順序は保証しない）
+	FilterIDs(db bun.IDB, ctx context.Context, ids []string, condition entity.ProductCatalogCondition) ([]string, error)
+	// すべての商品の検索用ドキュメントを返却する
+	FindSearchDocuments(db bun.IDB, ctx context.Context) ([]entity.ProductSearchDocument, error)
 }
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type ProductImageRepository interface {
+	// 派生画像が存在しない商品画像のうち、商品画像IDがafterIDより大きい商品画像をID順に最大limit件返却する
+	// 生成の対象から除外された（デッドレターの）商品画像は返却しない
+	FindWithoutVariants(db bun.IDB, ctx context.Context, afterID string, limit int) ([]entity.ProductImage, error)
+	// 派生画像を登録する
+	InsertVariants(db bun.IDB, ctx context.Context, variants []entity.ProductImageVariant) error
+	// 商品画像ID配列に一致する派生画像の生成の失敗記録を返却する
+	FindVariantFailures(db bun.IDB, ctx context.Context, productImageIDs []string) ([]entity.ProductImageVariantFailure, error)
+	// 派生画像の生成の失敗記録を登録・更新する
+	SaveVariantFailure(db bun.IDB, ctx context.Context, failure entity.ProductImageVariantFailure) error
+	// 商品画像IDに一致する派生画像の生成の失敗記録を削除する
+	DeleteVariantFailure(db bun.IDB, ctx context.Context, productImageID string) error
+	// 生成の対象から除外された派生画像の生成の失敗記録をすべて削除して再び生成の対象にし、削除件数を返却する
+	DeleteDeadLetterVariantFailures(db bun.IDB, ctx context.Context) (int, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type ProductIntegrityRepository interface {
+	// 商品IDがafterIDより大きい商品の整合性検査用のデータをID順に最大limit件返却する
+	FindSnapshots(db bun.IDB, ctx context.Context, afterID string, limit int) ([]entity.ProductIntegritySnapshot, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/uptrace/bun"
+)
+
+type ProductQuestionRepository interface {
+	// 質問IDに一致する質問を回答を含めて返却する
+	FindByID(db bun.IDB, ctx context.Context, id string) (entity.ProductQuestion, bool, error)
+	// 商品IDに一致する公開中の質問一覧の1ページを投稿日時の降順で返却する（質問者名と回答者名を含む）
+	FindPublishedByProductID(db bun.IDB, ctx context.Context, productID string, page int, limit int) (entity.ProductQuestionPage, error)
+	// 公開状態に一致する質問配列を投稿日時の昇順で最大limit件返却する（質問者名を含む）
+	FindByStatus(db bun.IDB, ctx context.Context, status enum.ProductQuestionStatus, limit int) ([]entity.ProductQuestionListItem, error)
+	// 商品の質問集約を登録する
+	Insert(db bun.IDB, ctx context.Context, question entity.ProductQuestion) error
+	// 商品の質問集約を更新する（未登録の回答を登録する）
+	Update(db bun.IDB, ctx context.Context, question entity.ProductQuestion) error
+}
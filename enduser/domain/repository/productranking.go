@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/uptrace/bun"
+)
+
+type ProductRankingRepository interface {
+	// 引数date以前で最も新しい集計期間のランキングの集計日を返却する。ランキングが存在しない場合はfalseを返却する
+	FindLatestDate(db bun.IDB, ctx context.Context, period enum.RankingPeriod, date time.Time) (time.Time, bool, error)
+	// 集計日・集計期間・カテゴリーID（サイト全体の場合は空文字）に一致するランキングを順位の昇順で最大limit件返却する
+	FindByDate(db bun.IDB, ctx context.Context, date time.Time, period enum.RankingPeriod, categoryID string, limit int) ([]entity.ProductRanking, error)
+	// 集計日・集計期間のランキングをすべて削除し、引数のランキング配列を登録する
+	ReplaceByDate(db bun.IDB, ctx context.Context, date time.Time, period enum.RankingPeriod, rankings []entity.ProductRanking) error
+}
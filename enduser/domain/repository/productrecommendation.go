@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type ProductRecommendationRepository interface {
+	// 商品IDに一致する一緒に購入されている商品の商品ID配列を順位の昇順で返却する
+	FindRecommendedProductIDs(db bun.IDB, ctx context.Context, productID string) ([]string, error)
+	// カテゴリーIDに一致する売れ筋商品の商品ID配列を順位の昇順で返却する
+	FindCategoryBestsellerIDs(db bun.IDB, ctx context.Context, categoryID string) ([]string, error)
+	// 一緒に購入されている商品をすべて削除し、引数のおすすめ商品配列を登録する
+	ReplaceAll(db bun.IDB, ctx context.Context, recommendations []entity.ProductRecommendation) error
+	// カテゴリーごとの売れ筋商品をすべて削除し、引数の売れ筋商品配列を登録する
+	ReplaceAllCategoryBestsellers(db bun.IDB, ctx context.Context, bestsellers []entity.CategoryBestseller) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type ProductSearchRepository interface {
+	// 商品検索用のドキュメントを登録する（既に登録されている場合は更新する）
+	Save(db bun.IDB, ctx context.Context, document entity.ProductSearchDocument) error
+	// キーワードに一致する商品を関連度の高い順に最大limit件返却する
+	// キーワードは全角・半角、カタカナ・ひらがな、英字の大文字・小文字を区別せず、空白区切りの検索語すべてを含む商品に一致する
+	Search(db bun.IDB, ctx context.Context, keyword string, limit int) ([]entity.ProductSearchHit, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type ProductStockRepository interface {
+	// 商品・SKUの在庫数が引数count以上の場合のみ在庫数を差し引き、差し引いた場合はtrueを返却する
+	// 在庫数の確認と差し引きは1つの条件付き更新で行うため、同時に実行されても在庫数が負の値にならない
+	Decrease(db bun.IDB, ctx context.Context, productID string, skuID string, count int) (bool, error)
+	// 商品・SKUの在庫数に引数countを加算する
+	Increase(db bun.IDB, ctx context.Context, productID string, skuID string, count int) error
+	// 商品がSKUを持つ場合trueを返却する
+	HasSKUs(db bun.IDB, ctx context.Context, productID string) (bool, error)
+	// 商品ID配列に一致する商品の行を商品IDの昇順に排他ロックする
+	LockProductsForUpdate(db bun.IDB, ctx context.Context, productIDs []string) error
+	// 排他ロックを取得してSKUの在庫数（バリエーションを持たない商品の場合は商品の在庫数）を取得する
+	// SKUの在庫数を取得する場合は、SKUの行より先に商品の行を排他ロックする
+	FindStockCountForUpdate(db bun.IDB, ctx context.Context, productID string, skuID string) (int, bool, error)
+	// すべてのSKUとバリエーションを持たない商品の現在の在庫数を取得する
+	FindStockLevels(db bun.IDB, ctx context.Context) ([]entity.StockLevel, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/uptrace/bun"
+)
+
+type ProductTimelineRepository interface {
+	// 商品IDと種別に一致する適用期間の一覧を返却する
+	FindByProductID(db bun.IDB, ctx context.Context, productID string, timelineType enum.ProductTimelineType) (entity.ProductTimeline, error)
+	// 商品IDと種別に一致する適用期間の一覧を排他ロックを取得して返却する（トランザクション内で使用する）
+	FindByProductIDForUpdate(db bun.IDB, ctx context.Context, productID string, timelineType enum.ProductTimelineType) (entity.ProductTimeline, error)
+	// 適用期間の一覧を保存する。一覧に含まれない既存の適用期間は削除する。
+	Save(db bun.IDB, ctx context.Context, timeline entity.ProductTimeline) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type ProductViewRepository interface {
+	// 商品の当日の閲覧数を1増やす
+	Increment(ctx context.Context, productID string, now time.Time) error
+	// 引数fromの日付からdays日間の閲覧数を合算し、商品IDをキー、閲覧数を値とするマップを返却する
+	FindCounts(ctx context.Context, from time.Time, days int) (map[string]int, error)
+}
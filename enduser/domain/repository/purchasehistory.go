@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type PurchaseHistoryRepository interface {
+	// 引数from以上引数to未満の日時に確定した注文ごとの購入商品配列を返却する
+	FindBasketsBetween(db bun.IDB, ctx context.Context, from time.Time, to time.Time) ([]entity.PurchaseBasket, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type RateLimitRepository interface {
+	// キーに対するリクエスト回数を1増やし、期間内のリクエスト回数を返却する（期間の経過後は0からやり直す）
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// 閲覧履歴リポジトリ
+// 閲覧履歴はゲストのセッションごと・アカウントごとに最大RecentlyViewedMaxProducts件の商品IDを保持する
+type RecentlyViewedRepository interface {
+	// ゲストの閲覧履歴に商品を追加する（閲覧済みの商品は閲覧日時を更新する）
+	AddBySessionID(ctx context.Context, sessionID string, productID string, viewedAt time.Time) error
+	// アカウントの閲覧履歴に商品を追加する（閲覧済みの商品は閲覧日時を更新する）
+	AddByAccountID(ctx context.Context, accountID string, productID string, viewedAt time.Time) error
+	// ゲストの閲覧履歴の商品ID配列を閲覧日時の降順で返却する
+	FindBySessionID(ctx context.Context, sessionID string) ([]string, error)
+	// アカウントの閲覧履歴の商品ID配列を閲覧日時の降順で返却する
+	FindByAccountID(ctx context.Context, accountID string) ([]string, error)
+	// ゲストの閲覧履歴をアカウントの閲覧履歴にマージし、ゲストの閲覧履歴を削除する
+	MoveSessionToAccount(ctx context.Context, sessionID string, accountID string) error
+}
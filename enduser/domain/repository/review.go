@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/uptrace/bun"
+)
+
+type ReviewRepository interface {
+	// レビューIDに一致するレビューを返却する
+	FindByID(db bun.IDB, ctx context.Context, id string) (entity.Review, bool, error)
+	// レビューIDに一致するレビューを排他ロックを取得して返却する
+	FindByIDForUpdate(db bun.IDB, ctx context.Context, id string) (entity.Review, bool, error)
+	// 商品IDとアカウントIDに一致するレビューを返却する
+	FindByProductIDAndAccountID(db bun.IDB, ctx context.Context, productID string, accountID string) (entity.Review, bool, error)
+	// アカウントIDに一致するレビュー配列を投稿日時の降順で返却する（写真のURLを含む）
+	FindByAccountID(db bun.IDB, ctx context.Context, accountID string) ([]entity.Review, error)
+	// 商品IDに一致する公開中のレビュー一覧の1ページを並び順に従って返却する（写真のURLとレビュー投稿者名を含む）
+	FindPublishedByProductID(db bun.IDB, ctx context.Context, productID string, sort enum.ReviewSort, page int, limit int) (entity.ReviewPage, error)
+	// 商品IDに一致する公開中のレビューの評価ごとのレビュー数を返却する
+	FindRatingDistribution(db bun.IDB, ctx context.Context, productID string) (entity.ReviewRatingDistribution, error)
+	// 公開状態に一致するレビュー配列を投稿日時の昇順で最大limit件返却する（レビュー投稿者名を含む）
+	FindByStatus(db bun.IDB, ctx context.Context, status enum.ReviewStatus, limit int) ([]entity.ReviewListItem, error)
+	// 引数createdBeforeより前に投稿された公開中のレビューの評価を商品ごとに集計して返却する
+	FindRatingSummaries(db bun.IDB, ctx context.Context, createdBefore time.Time) ([]entity.ReviewRatingSummary, error)
+	// レビュー集約を登録する（同じ商品とアカウントのレビューが存在する場合はErrDuplicateKeyを返却する）
+	Insert(db bun.IDB, ctx context.Context, review entity.Review) error
+	// レビュー集約を更新する（参考になった数・参考にならなかった数は投票と同時に更新されるため更新しない）
+	Update(db bun.IDB, ctx context.Context, review entity.Review) error
+	// レビューへの投票からレビューの参考になった数・参考にならなかった数を集計し直して更新する
+	UpdateVoteCounts(db bun.IDB, ctx context.Context, reviewID string) error
+	// レビュー集約を削除する（レビューへの投票と報告も削除する）
+	Delete(db bun.IDB, ctx context.Context, review entity.Review) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type ReviewReportRepository interface {
+	// レビューIDとアカウントIDに一致する報告が存在する場合trueを返却する
+	ExistsByReviewIDAndAccountID(db bun.IDB, ctx context.Context, reviewID string, accountID string) (bool, error)
+	// 不適切なレビューとしての報告を登録する（同じアカウントの報告が存在する場合はErrDuplicateKeyを返却する）
+	Insert(db bun.IDB, ctx context.Context, report entity.ReviewReport) error
+}
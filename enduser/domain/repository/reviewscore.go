@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type ReviewScoreRepository interface {
+	// 日付のレビュー点数をすべて削除し、引数のレビュー点数配列を登録する
+	ReplaceByDate(db bun.IDB, ctx context.Context, date time.Time, scores []entity.ReviewScore) error
+}
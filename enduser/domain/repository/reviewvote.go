@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type ReviewVoteRepository interface {
+	// レビューIDとアカウントIDに一致するレビューへの投票を返却する
+	FindByReviewIDAndAccountID(db bun.IDB, ctx context.Context, reviewID string, accountID string) (entity.ReviewVote, bool, error)
+	// レビューへの投票を登録する（既に投票済みの場合は投票内容を更新する）
+	Save(db bun.IDB, ctx context.Context, vote entity.ReviewVote) error
+	// レビューへの投票を削除する
+	Delete(db bun.IDB, ctx context.Context, vote entity.ReviewVote) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+)
+
+type SearchSuggestionRepository interface {
+	// 商品名・カテゴリー名の検索候補をすべて置き換える
+	ReplaceNames(ctx context.Context, suggestions []entity.SearchSuggestion) error
+	// 正規化した入力文字列にキーが前方一致する商品名・カテゴリー名の検索候補を最大limit件返却する
+	FindNamesByPrefix(ctx context.Context, prefix string, limit int) ([]entity.SearchSuggestion, error)
+	// 検索されたキーワードの当日の検索回数を1増やす
+	IncrementQuery(ctx context.Context, keyword string, now time.Time) error
+	// 直近days日間に検索された回数の多い順にキーワードを最大limit件返却する
+	FindPopularQueries(ctx context.Context, now time.Time, days int, limit int) ([]entity.SearchSuggestion, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+)
+
+type SessionWishlistRepository interface {
+	FindBySessionID(ctx context.Context, sessionID string) (entity.SessionWishlist, bool, error)
+	Save(ctx context.Context, sessionWishlist entity.SessionWishlist, expiration time.Duration) error
+	Delete(ctx context.Context, sessionWishlist entity.SessionWishlist) error
+	UpdateExpiration(ctx context.Context, sessionWishlist entity.SessionWishlist, expiration time.Duration) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+// 在庫台帳は変更・削除せず、登録と集計のみ行う
+type StockMovementRepository interface {
+	Insert(db bun.IDB, ctx context.Context, movement entity.StockMovement) error
+	// 商品・SKUごとに在庫数の増減を合計する
+	FindBalances(db bun.IDB, ctx context.Context) ([]entity.StockLedgerBalance, error)
+}
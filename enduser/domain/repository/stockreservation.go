@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type StockReservationRepository interface {
+	// 在庫引当IDに一致する在庫引当集約を排他ロックを取得して返却する
+	FindByIDForUpdate(db bun.IDB, ctx context.Context, id string) (entity.StockReservation, bool, error)
+	// アカウントIDに一致する引当中の在庫引当集約配列を排他ロックを取得して返却する
+	FindReservedByAccountIDForUpdate(db bun.IDB, ctx context.Context, accountID string) ([]entity.StockReservation, error)
+	// 引数now時点で有効期限を過ぎている引当中の在庫引当のID配列を有効期限の昇順で最大limit件返却する
+	FindExpiredIDs(db bun.IDB, ctx context.Context, now time.Time, limit int) ([]string, error)
+	// 在庫引当集約を登録する
+	Insert(db bun.IDB, ctx context.Context, reservation entity.StockReservation) error
+	// 在庫引当の状態を更新する（引当の商品は変更しない）
+	Update(db bun.IDB, ctx context.Context, reservation entity.StockReservation) error
+	// アカウントが商品を含む確定済みの在庫引当（購入済みの注文）を持つ場合trueを返却する
+	ExistsCommittedByAccountIDAndProductID(db bun.IDB, ctx context.Context, accountID string, productID string) (bool, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type WishlistRepository interface {
+	// アカウントIDに一致するお気に入りリスト配列を作成日時の昇順で返却する
+	FindByAccountID(db bun.IDB, ctx context.Context, accountID string) ([]entity.Wishlist, error)
+	// お気に入りリストIDとアカウントIDに一致するお気に入りリストを返却する
+	FindByIDAndAccountID(db bun.IDB, ctx context.Context, id string, accountID string) (entity.Wishlist, bool, error)
+	// 共有トークンに一致する公開中のお気に入りリストを返却する
+	FindByShareToken(db bun.IDB, ctx context.Context, shareToken string) (entity.Wishlist, bool, error)
+	Insert(db bun.IDB, ctx context.Context, wishlist entity.Wishlist) error
+	Update(db bun.IDB, ctx context.Context, wishlist entity.Wishlist) error
+	Delete(db bun.IDB, ctx context.Context, wishlist entity.Wishlist) error
+}
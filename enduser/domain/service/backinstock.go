@@ -0,0 +1,82 @@
+package service
+
+import (
+	"bytes"
+	"html/template"
+	"net/url"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/adapter"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/bridge"
+)
+
+type (
+	BackInStockNotificationDomainService struct {
+		emailAdapter adapter.EmailAdapter
+	}
+
+	// 入荷通知メールのテンプレートに渡すデータ
+	backInStockEmailData struct {
+		ProductName string
+		ProductURL  string
+		Items       []backInStockEmailItem
+	}
+
+	// 入荷通知メールに記載する在庫のあるSKU
+	backInStockEmailItem struct {
+		Name         string
+		AddToCartURL string
+	}
+)
+
+const backInStockEmailSubject = "ご登録の商品が入荷しました"
+
+var backInStockEmailTemplate = template.Must(template.New("backInStock").Parse(`<p>入荷通知をご登録いただいた「{{.ProductName}}」が入荷しました。</p>
+<table>
+{{range .Items}}<tr><td>{{.Name}}</td><td><a href="{{.AddToCartURL}}">カートに入れる</a></td></tr>
+{{end}}</table>
+<p><a href="{{.ProductURL}}">商品を確認する</a></p>
+<p>入荷通知のご登録はこのメールの送信をもって解除されました。</p>`))
+
+func NewBackInStockNotificationService(emailAdapter adapter.EmailAdapter) BackInStockNotificationDomainService {
+	return BackInStockNotificationDomainService{
+		emailAdapter: emailAdapter,
+	}
+}
+
+// 入荷通知メールを送信する
+// 在庫のあるSKUごとに商品をカートに追加するリンクを記載する
+func (bs BackInStockNotificationDomainService) SendNotification(account entity.Account, product entity.Product) error {
+	skus := product.InStockSKUs()
+	items := make([]backInStockEmailItem, 0, len(skus))
+	for _, sku := range skus {
+		items = append(items, backInStockEmailItem{
+			Name:         product.SKUDisplayName(sku),
+			AddToCartURL: addToCartURL(product.ID, sku.ID),
+		})
+	}
+
+	var text bytes.Buffer
+	err := backInStockEmailTemplate.Execute(&text, backInStockEmailData{
+		ProductName: product.Name,
+		ProductURL:  os.Getenv("FRONT_URL") + "/products/" + product.ID,
+		Items:       items,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return bs.emailAdapter.SendEmail(bridge.From, account.Email, backInStockEmailSubject, text.String())
+}
+
+// 商品をカートに1点追加するフロントエンドのURLを返却する
+func addToCartURL(productID string, skuID string) string {
+	query := url.Values{}
+	query.Set("productId", productID)
+	if skuID != "" {
+		query.Set("skuId", skuID)
+	}
+	return os.Getenv("FRONT_URL") + "/cart/add?" + query.Encode()
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/adapter"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/bridge"
+)
+
+type (
+	CartReminderDomainService struct {
+		emailAdapter adapter.EmailAdapter
+	}
+
+	// カート放置リマインドメールのテンプレートに渡すデータ
+	cartReminderEmailData struct {
+		Items      []cartReminderEmailItem
+		TotalPrice int
+		CartURL    string
+		SettingURL string
+	}
+
+	// カート放置リマインドメールに記載するカート商品（現在の価格）
+	cartReminderEmailItem struct {
+		Name  string
+		Count int
+		Price int
+	}
+)
+
+const cartReminderEmailSubject = "カートに商品が残っています"
+
+var cartReminderEmailTemplate = template.Must(template.New("cartReminder").Parse(`<p>カートに商品が残っています。</p>
+<table>
+{{range .Items}}<tr><td>{{.Name}}</td><td>{{.Count}}点</td><td>{{.Price}}円</td></tr>
+{{end}}</table>
+<p>合計金額（税込）：{{.TotalPrice}}円</p>
+<p><a href="{{.CartURL}}">カートを確認する</a></p>
+<p>このメールの配信停止は<a href="{{.SettingURL}}">こちら</a>から設定できます</p>`))
+
+func NewCartReminderService(emailAdapter adapter.EmailAdapter) CartReminderDomainService {
+	return CartReminderDomainService{
+		emailAdapter: emailAdapter,
+	}
+}
+
+// カート放置リマインドメールを送信する
+// カート内の販売中の商品を現在の価格で記載し、販売中の商品が存在しない場合は送信せずにfalseを返却する
+func (cs CartReminderDomainService) SendReminder(account entity.Account, cart entity.Cart, products []entity.Product) (bool, error) {
+	productMap := make(map[string]entity.Product, len(products))
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	items := make([]cartReminderEmailItem, 0, len(cart.CartProducts))
+	for _, cartProduct := range cart.CartProducts {
+		product, ok := productMap[cartProduct.ProductID]
+		if !ok || product.Status != enum.OnSale {
+			continue
+		}
+
+		sku, ok := product.FindSKU(cartProduct.SKUID)
+		if !ok {
+			continue
+		}
+
+		items = append(items, cartReminderEmailItem{
+			Name:  product.SKUDisplayName(sku),
+			Count: cartProduct.Count,
+			Price: product.SKUPrice(sku),
+		})
+	}
+
+	if len(items) == 0 {
+		return false, nil
+	}
+
+	var text bytes.Buffer
+	err := cartReminderEmailTemplate.Execute(&text, cartReminderEmailData{
+		Items:      items,
+		TotalPrice: cart.TotalPrice(products),
+		CartURL:    os.Getenv("FRONT_URL") + "/cart",
+		SettingURL: os.Getenv("FRONT_URL") + "/account/setting",
+	})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	err = cs.emailAdapter.SendEmail(bridge.From, account.Email, cartReminderEmailSubject, text.String())
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"bytes"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/adapter"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+)
+
+type ProductImageVariantDomainService struct {
+	imageStorageAdapter   adapter.ImageStorageAdapter
+	imageProcessorAdapter adapter.ImageProcessorAdapter
+}
+
+func NewProductImageVariantService(imageStorageAdapter adapter.ImageStorageAdapter, imageProcessorAdapter adapter.ImageProcessorAdapter) ProductImageVariantDomainService {
+	return ProductImageVariantDomainService{
+		imageStorageAdapter:   imageStorageAdapter,
+		imageProcessorAdapter: imageProcessorAdapter,
+	}
+}
+
+// 商品画像の元画像から幅・画像形式ごとの派生画像を生成して画像ストレージに保存し、保存した派生画像配列を返却する
+// 派生画像は元画像をデコードした画素から生成するため、元画像のEXIFは含まれない
+func (ps ProductImageVariantDomainService) Generate(image entity.ProductImage) ([]entity.ProductImageVariant, error) {
+	body, err := ps.imageStorageAdapter.Download(image.Path)
+	if err != nil {
+		return []entity.ProductImageVariant{}, err
+	}
+	defer body.Close()
+
+	original, err := ps.imageProcessorAdapter.Decode(body)
+	if err != nil {
+		return []entity.ProductImageVariant{}, err
+	}
+
+	variants := []entity.ProductImageVariant{}
+	for _, width := range entity.ProductImageVariantWidthsFor(original.Bounds().Dx()) {
+		for _, format := range entity.ProductImageVariantFormats {
+			variant := image.CreateVariant(width, format)
+
+			data, err := ps.imageProcessorAdapter.Encode(original, width, format)
+			if err != nil {
+				return []entity.ProductImageVariant{}, err
+			}
+
+			err = ps.imageStorageAdapter.Upload(variant.Path, bytes.NewReader(data), format.ContentType())
+			if err != nil {
+				return []entity.ProductImageVariant{}, err
+			}
+
+			variants = append(variants, variant)
+		}
+	}
+
+	return variants, nil
+}
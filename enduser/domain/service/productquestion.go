@@ -0,0 +1,184 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/adapter"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/validator"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/bridge"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	ProductQuestionDomainService struct {
+		productQuestionRepository  repository.ProductQuestionRepository
+		productRepository          repository.ProductRepository
+		stockReservationRepository repository.StockReservationRepository
+		emailAdapter               adapter.EmailAdapter
+		validationUtils            util.ValidationUtils
+		timeUtils                  util.TimeUtils
+	}
+
+	// 回答通知メールのテンプレートに渡すデータ
+	productAnswerEmailData struct {
+		ProductName string
+		Question    string
+		Answer      string
+		ProductURL  string
+	}
+)
+
+const productAnswerEmailSubject = "商品へのご質問に回答がありました"
+
+var (
+	errProductQuestionNotFound = share.CreateOriginalError(share.ErrorCodeOther, []string{"質問が見つかりません"})
+
+	productAnswerEmailTemplate = template.Must(template.New("productAnswer").Parse(`<p>「{{.ProductName}}」へのご質問に回答がありました。</p>
+<p>ご質問：{{.Question}}</p>
+<p>回答：{{.Answer}}</p>
+<p><a href="{{.ProductURL}}">商品ページで確認する</a></p>`))
+)
+
+func NewProductQuestionService(
+	productQuestionRepository repository.ProductQuestionRepository,
+	productRepository repository.ProductRepository,
+	stockReservationRepository repository.StockReservationRepository,
+	emailAdapter adapter.EmailAdapter,
+	validationUtils util.ValidationUtils,
+	timeUtils util.TimeUtils,
+) ProductQuestionDomainService {
+	return ProductQuestionDomainService{
+		productQuestionRepository:  productQuestionRepository,
+		productRepository:          productRepository,
+		stockReservationRepository: stockReservationRepository,
+		emailAdapter:               emailAdapter,
+		validationUtils:            validationUtils,
+		timeUtils:                  timeUtils,
+	}
+}
+
+// 商品に質問を投稿する（確認待ちの状態で登録する）
+func (qs ProductQuestionDomainService) PostQuestion(db bun.IDB, ctx context.Context, accountID string, productID string, body string) (entity.ProductQuestion, error) {
+	err := qs.validationUtils.Struct(validator.ValidationProductQuestion{Body: body})
+	if err != nil {
+		return entity.ProductQuestion{}, qs.validationUtils.CreateValidationMessages(err)
+	}
+
+	_, ok, err := qs.productRepository.FindByID(db, ctx, productID, false)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+	if !ok {
+		return entity.ProductQuestion{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"商品が見つかりません"})
+	}
+
+	question := entity.CreateProductQuestion(productID, accountID, body, qs.timeUtils.NowJP())
+	err = qs.productQuestionRepository.Insert(db, ctx, question)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+	return question, nil
+}
+
+// ショップスタッフとして質問に回答し、回答した質問と回答を返却する
+func (qs ProductQuestionDomainService) AnswerAsStaff(db bun.IDB, ctx context.Context, questionID string, body string) (entity.ProductQuestion, entity.ProductAnswer, error) {
+	question, err := qs.findQuestion(db, ctx, questionID)
+	if err != nil {
+		return entity.ProductQuestion{}, entity.ProductAnswer{}, err
+	}
+
+	return qs.answer(db, ctx, question, enum.ProductAnswerResponderTypeStaff, "", body)
+}
+
+// 購入済みのお客様として質問に回答し、回答した質問と回答を返却する
+func (qs ProductQuestionDomainService) AnswerAsBuyer(db bun.IDB, ctx context.Context, accountID string, questionID string, body string) (entity.ProductQuestion, entity.ProductAnswer, error) {
+	question, err := qs.findQuestion(db, ctx, questionID)
+	if err != nil {
+		return entity.ProductQuestion{}, entity.ProductAnswer{}, err
+	}
+
+	ok, err := qs.isVerifiedBuyer(db, ctx, accountID, question.ProductID)
+	if err != nil {
+		return entity.ProductQuestion{}, entity.ProductAnswer{}, err
+	}
+	if !ok {
+		return entity.ProductQuestion{}, entity.ProductAnswer{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"商品を購入済みのお客様のみ回答できます"})
+	}
+
+	return qs.answer(db, ctx, question, enum.ProductAnswerResponderTypeBuyer, accountID, body)
+}
+
+// 質問の公開状態を変更する（管理者による確認）
+func (qs ProductQuestionDomainService) Moderate(db bun.IDB, ctx context.Context, questionID string, status enum.ProductQuestionStatus) error {
+	question, err := qs.findQuestion(db, ctx, questionID)
+	if err != nil {
+		return err
+	}
+
+	err = question.Moderate(status, qs.timeUtils.NowJP())
+	if err != nil {
+		return err
+	}
+
+	return qs.productQuestionRepository.Update(db, ctx, question)
+}
+
+// 質問者に回答通知メールを送信する
+func (qs ProductQuestionDomainService) SendAnswerNotification(account entity.Account, product entity.Product, question entity.ProductQuestion, answer entity.ProductAnswer) error {
+	var text bytes.Buffer
+	err := productAnswerEmailTemplate.Execute(&text, productAnswerEmailData{
+		ProductName: product.Name,
+		Question:    question.Body,
+		Answer:      answer.Body,
+		ProductURL:  os.Getenv("FRONT_URL") + "/products/" + product.ID,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return qs.emailAdapter.SendEmail(bridge.From, account.Email, productAnswerEmailSubject, text.String())
+}
+
+func (qs ProductQuestionDomainService) answer(db bun.IDB, ctx context.Context, question entity.ProductQuestion, responderType enum.ProductAnswerResponderType, accountID string, body string) (entity.ProductQuestion, entity.ProductAnswer, error) {
+	err := qs.validationUtils.Struct(validator.ValidationProductAnswer{Body: body})
+	if err != nil {
+		return entity.ProductQuestion{}, entity.ProductAnswer{}, qs.validationUtils.CreateValidationMessages(err)
+	}
+
+	answer, err := question.Answer(responderType, accountID, body, qs.timeUtils.NowJP())
+	if err != nil {
+		return entity.ProductQuestion{}, entity.ProductAnswer{}, err
+	}
+
+	err = qs.productQuestionRepository.Update(db, ctx, question)
+	if err != nil {
+		return entity.ProductQuestion{}, entity.ProductAnswer{}, err
+	}
+	return question, answer, nil
+}
+
+func (qs ProductQuestionDomainService) findQuestion(db bun.IDB, ctx context.Context, questionID string) (entity.ProductQuestion, error) {
+	question, ok, err := qs.productQuestionRepository.FindByID(db, ctx, questionID)
+	if err != nil {
+		return entity.ProductQuestion{}, err
+	}
+	if !ok {
+		return entity.ProductQuestion{}, errProductQuestionNotFound
+	}
+
+	return question, nil
+}
+
+// アカウントが商品を購入済み（商品を含む確定済みの在庫引当を持つ）の場合trueを返却する
+func (qs ProductQuestionDomainService) isVerifiedBuyer(db bun.IDB, ctx context.Context, accountID string, productID string) (bool, error) {
+	return qs.stockReservationRepository.ExistsCommittedByAccountIDAndProductID(db, ctx, accountID, productID)
+}
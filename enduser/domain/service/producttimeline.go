@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type ProductTimelineDomainService struct {
+	productTimelineRepository repository.ProductTimelineRepository
+	timeUtils                 util.TimeUtils
+}
+
+func NewProductTimelineService(productTimelineRepository repository.ProductTimelineRepository, timeUtils util.TimeUtils) ProductTimelineDomainService {
+	return ProductTimelineDomainService{
+		productTimelineRepository: productTimelineRepository,
+		timeUtils:                 timeUtils,
+	}
+}
+
+// 商品価格・商品セール価格・商品ステータスの変更を予約し、予約後の適用期間の一覧を返却する
+// 既存の適用期間は予約する期間に合わせて自動で分割・短縮する（トランザクション内で使用する）
+func (ps ProductTimelineDomainService) Schedule(db bun.IDB, ctx context.Context, productID string, timelineType enum.ProductTimelineType, value int, startDate time.Time, endDate time.Time) (entity.ProductTimeline, error) {
+	err := validateProductTimelineValue(timelineType, value)
+	if err != nil {
+		return entity.ProductTimeline{}, err
+	}
+
+	timeline, err := ps.productTimelineRepository.FindByProductIDForUpdate(db, ctx, productID, timelineType)
+	if err != nil {
+		return entity.ProductTimeline{}, err
+	}
+
+	now := ps.timeUtils.NowJP()
+	today := ps.timeUtils.DateJP(now.Year(), now.Month(), now.Day())
+	err = timeline.Schedule(value, ps.toDateJP(startDate), ps.toDateJP(endDate), today)
+	if err != nil {
+		return entity.ProductTimeline{}, err
+	}
+
+	err = ps.productTimelineRepository.Save(db, ctx, timeline)
+	if err != nil {
+		return entity.ProductTimeline{}, err
+	}
+	return timeline, nil
+}
+
+// 商品の商品価格・商品セール価格・商品ステータスの適用期間の一覧を返却する
+func (ps ProductTimelineDomainService) FindTimelines(db bun.IDB, ctx context.Context, productID string) ([]entity.ProductTimeline, error) {
+	timelineTypes := []enum.ProductTimelineType{enum.ProductTimelineTypePrice, enum.ProductTimelineTypeSalePrice, enum.ProductTimelineTypeStatus}
+
+	timelines := make([]entity.ProductTimeline, 0, len(timelineTypes))
+	for _, timelineType := range timelineTypes {
+		timeline, err := ps.productTimelineRepository.FindByProductID(db, ctx, productID, timelineType)
+		if err != nil {
+			return []entity.ProductTimeline{}, err
+		}
+		timelines = append(timelines, timeline)
+	}
+
+	return timelines, nil
+}
+
+// 日付を日本時間の日付に変換する
+func (ps ProductTimelineDomainService) toDateJP(t time.Time) time.Time {
+	return ps.timeUtils.DateJP(t.Year(), t.Month(), t.Day())
+}
+
+// 種別ごとに予約する値が正しいか検証する
+func validateProductTimelineValue(timelineType enum.ProductTimelineType, value int) error {
+	switch timelineType {
+	case enum.ProductTimelineTypePrice:
+		if value <= 0 {
+			return share.CreateOriginalError(share.ErrorCodeValidation, []string{"商品価格は1円以上を指定してください"})
+		}
+	case enum.ProductTimelineTypeSalePrice:
+		// セール価格の0はセールを実施しないことを表す
+		if value < 0 {
+			return share.CreateOriginalError(share.ErrorCodeValidation, []string{"商品セール価格は0円以上を指定してください"})
+		}
+	case enum.ProductTimelineTypeStatus:
+		if value < enum.OnSale || value > enum.SalesEnded {
+			return share.CreateOriginalError(share.ErrorCodeValidation, []string{"商品ステータスが不正です"})
+		}
+	default:
+		return share.CreateOriginalError(share.ErrorCodeValidation, []string{"種別が不正です"})
+	}
+
+	return nil
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/adapter"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/validator"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	ReviewDomainService struct {
+		reviewRepository           repository.ReviewRepository
+		reviewVoteRepository       repository.ReviewVoteRepository
+		reviewReportRepository     repository.ReviewReportRepository
+		productRepository          repository.ProductRepository
+		stockReservationRepository repository.StockReservationRepository
+		imageStorageAdapter        adapter.ImageStorageAdapter
+		validationUtils            util.ValidationUtils
+		timeUtils                  util.TimeUtils
+		ngWords                    []string
+	}
+
+	// レビューの投稿・編集内容
+	ReviewContent struct {
+		Rating int
+		Title  string
+		Body   string
+		Photos []ReviewPhotoFile
+	}
+
+	// レビューに添付する写真のファイル
+	ReviewPhotoFile struct {
+		ContentType string
+		Data        []byte
+	}
+)
+
+var (
+	errReviewNotFound      = share.CreateOriginalError(share.ErrorCodeOther, []string{"レビューが見つかりません"})
+	errReviewAlreadyPosted = share.CreateOriginalError(share.ErrorCodeOther, []string{"この商品のレビューは投稿済みです"})
+	errReviewReported      = share.CreateOriginalError(share.ErrorCodeOther, []string{"このレビューは報告済みです"})
+)
+
+func NewReviewService(
+	reviewRepository repository.ReviewRepository,
+	reviewVoteRepository repository.ReviewVoteRepository,
+	reviewReportRepository repository.ReviewReportRepository,
+	productRepository repository.ProductRepository,
+	stockReservationRepository repository.StockReservationRepository,
+	imageStorageAdapter adapter.ImageStorageAdapter,
+	validationUtils util.ValidationUtils,
+	timeUtils util.TimeUtils,
+) ReviewDomainService {
+	return ReviewDomainService{
+		reviewRepository:           reviewRepository,
+		reviewVoteRepository:       reviewVoteRepository,
+		reviewReportRepository:     reviewReportRepository,
+		productRepository:          productRepository,
+		stockReservationRepository: stockReservationRepository,
+		imageStorageAdapter:        imageStorageAdapter,
+		validationUtils:            validationUtils,
+		timeUtils:                  timeUtils,
+		ngWords:                    splitNGWords(os.Getenv("REVIEW_NG_WORDS")),
+	}
+}
+
+// 商品のレビューを投稿する
+// 1つのアカウントは1つの商品に1件のみレビューを投稿でき、同時に投稿された場合も投稿済みとして扱う
+// 注文機能が存在しないため、購入が確定した在庫引当に商品が含まれる場合に購入済みとする
+// 写真はレビューの登録に成功した後に画像ストレージに保存する
+func (rs ReviewDomainService) PostReview(db bun.IDB, ctx context.Context, accountID string, productID string, content ReviewContent) (entity.Review, error) {
+	err := rs.validate(content)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	_, ok, err := rs.productRepository.FindByID(db, ctx, productID, false)
+	if err != nil {
+		return entity.Review{}, err
+	}
+	if !ok {
+		return entity.Review{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"商品が見つかりません"})
+	}
+
+	_, ok, err = rs.reviewRepository.FindByProductIDAndAccountID(db, ctx, productID, accountID)
+	if err != nil {
+		return entity.Review{}, err
+	}
+	if ok {
+		return entity.Review{}, errReviewAlreadyPosted
+	}
+
+	isVerifiedPurchase, err := rs.stockReservationRepository.ExistsCommittedByAccountIDAndProductID(db, ctx, accountID, productID)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	review := entity.CreateReview(productID, accountID, content.Rating, content.Title, content.Body, isVerifiedPurchase, rs.ngWords, rs.timeUtils.NowJP())
+	photos, err := rs.replacePhotos(&review, content.Photos)
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	err = rs.reviewRepository.Insert(db, ctx, review)
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return entity.Review{}, errReviewAlreadyPosted
+	}
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	err = rs.uploadPhotos(photos, content.Photos)
+	if err != nil {
+		return entity.Review{}, err
+	}
+	return review, nil
+}
+
+// 自分のレビューを編集し、置き換えにより不要になった写真配列を返却する
+// 引数replacePhotosがfalseの場合は写真を変更しない
+func (rs ReviewDomainService) EditReview(db bun.IDB, ctx context.Context, accountID string, reviewID string, content ReviewContent, replacePhotos bool) ([]entity.ReviewPhoto, error) {
+	err := rs.validate(content)
+	if err != nil {
+		return []entity.ReviewPhoto{}, err
+	}
+
+	review, err := rs.findOwnReview(db, ctx, accountID, reviewID)
+	if err != nil {
+		return []entity.ReviewPhoto{}, err
+	}
+
+	review.Edit(content.Rating, content.Title, content.Body, rs.ngWords, rs.timeUtils.NowJP())
+
+	removedPhotos := []entity.ReviewPhoto{}
+	photos := []entity.ReviewPhoto{}
+	if replacePhotos {
+		removedPhotos = review.Photos
+		photos, err = rs.replacePhotos(&review, content.Photos)
+		if err != nil {
+			return []entity.ReviewPhoto{}, err
+		}
+	}
+
+	err = rs.reviewRepository.Update(db, ctx, review)
+	if err != nil {
+		return []entity.ReviewPhoto{}, err
+	}
+
+	err = rs.uploadPhotos(photos, content.Photos)
+	if err != nil {
+		return []entity.ReviewPhoto{}, err
+	}
+	return removedPhotos, nil
+}
+
+// 自分のレビューを削除し、不要になった写真配列を返却する
+func (rs ReviewDomainService) DeleteReview(db bun.IDB, ctx context.Context, accountID string, reviewID string) ([]entity.ReviewPhoto, error) {
+	review, err := rs.findOwnReview(db, ctx, accountID, reviewID)
+	if err != nil {
+		return []entity.ReviewPhoto{}, err
+	}
+
+	err = rs.reviewRepository.Delete(db, ctx, review)
+	if err != nil {
+		return []entity.ReviewPhoto{}, err
+	}
+	return review.Photos, nil
+}
+
+// レビューの公開状態を変更する（管理者による確認）
+func (rs ReviewDomainService) Moderate(db bun.IDB, ctx context.Context, reviewID string, status enum.ReviewStatus) error {
+	review, ok, err := rs.reviewRepository.FindByID(db, ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errReviewNotFound
+	}
+
+	err = review.Moderate(status, rs.timeUtils.NowJP())
+	if err != nil {
+		return err
+	}
+
+	return rs.reviewRepository.Update(db, ctx, review)
+}
+
+// レビューに参考になった・参考にならなかったの投票をする（投票済みの場合は投票内容を変更する）
+// レビューの行を排他ロックしてから投票と参考になった数の集計を行うことで、同時に投票された場合も集計結果を投票と一致させる
+func (rs ReviewDomainService) Vote(db bun.IDB, ctx context.Context, accountID string, reviewID string, isHelpful bool) error {
+	review, ok, err := rs.reviewRepository.FindByIDForUpdate(db, ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errReviewNotFound
+	}
+
+	vote, err := review.CreateVote(accountID, isHelpful, rs.timeUtils.NowJP())
+	if err != nil {
+		return err
+	}
+
+	err = rs.reviewVoteRepository.Save(db, ctx, vote)
+	if err != nil {
+		return err
+	}
+
+	return rs.reviewRepository.UpdateVoteCounts(db, ctx, review.ID)
+}
+
+// レビューへの投票を取り消す（投票していない場合は何もしない）
+func (rs ReviewDomainService) Unvote(db bun.IDB, ctx context.Context, accountID string, reviewID string) error {
+	review, ok, err := rs.reviewRepository.FindByIDForUpdate(db, ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errReviewNotFound
+	}
+
+	vote, ok, err := rs.reviewVoteRepository.FindByReviewIDAndAccountID(db, ctx, review.ID, accountID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	err = rs.reviewVoteRepository.Delete(db, ctx, vote)
+	if err != nil {
+		return err
+	}
+
+	return rs.reviewRepository.UpdateVoteCounts(db, ctx, review.ID)
+}
+
+// 公開中のレビューを不適切なレビューとして報告する
+// 1つのアカウントは1件のレビューを1回のみ報告でき（同時に報告された場合も報告済みとして扱う）、報告数が閾値に達したレビューは確認待ちに戻す
+func (rs ReviewDomainService) Report(db bun.IDB, ctx context.Context, accountID string, reviewID string, reason string) error {
+	err := rs.validationUtils.Struct(validator.ValidationReviewReport{Reason: reason})
+	if err != nil {
+		return rs.validationUtils.CreateValidationMessages(err)
+	}
+
+	review, ok, err := rs.reviewRepository.FindByIDForUpdate(db, ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if !ok || review.Status != enum.ReviewStatusPublished {
+		return errReviewNotFound
+	}
+
+	reported, err := rs.reviewReportRepository.ExistsByReviewIDAndAccountID(db, ctx, review.ID, accountID)
+	if err != nil {
+		return err
+	}
+	if reported {
+		return errReviewReported
+	}
+
+	report, err := review.Report(accountID, reason, rs.timeUtils.NowJP())
+	if err != nil {
+		return err
+	}
+
+	// 同時に報告された場合は一意制約違反となるため報告済みとして扱う
+	err = rs.reviewReportRepository.Insert(db, ctx, report)
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return errReviewReported
+	}
+	if err != nil {
+		return err
+	}
+
+	return rs.reviewRepository.Update(db, ctx, review)
+}
+
+// 写真を画像ストレージから削除する
+func (rs ReviewDomainService) DeletePhotos(photos []entity.ReviewPhoto) error {
+	for _, photo := range photos {
+		err := rs.imageStorageAdapter.Delete(photo.Path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rs ReviewDomainService) validate(content ReviewContent) error {
+	err := rs.validationUtils.Struct(validator.ValidationReview{
+		Rating: content.Rating,
+		Title:  content.Title,
+		Body:   content.Body,
+	})
+	if err != nil {
+		return rs.validationUtils.CreateValidationMessages(err)
+	}
+
+	return nil
+}
+
+// レビューIDに一致する自分のレビューを返却する
+// 他のアカウントのレビューの場合は存在しないものとして扱う
+func (rs ReviewDomainService) findOwnReview(db bun.IDB, ctx context.Context, accountID string, reviewID string) (entity.Review, error) {
+	review, ok, err := rs.reviewRepository.FindByID(db, ctx, reviewID)
+	if err != nil {
+		return entity.Review{}, err
+	}
+	if !ok || !review.IsWrittenBy(accountID) {
+		return entity.Review{}, errReviewNotFound
+	}
+
+	return review, nil
+}
+
+// レビューの写真を置き換え、保存先のパスを設定した写真配列を返却する（画像ストレージには保存しない）
+func (rs ReviewDomainService) replacePhotos(review *entity.Review, files []ReviewPhotoFile) ([]entity.ReviewPhoto, error) {
+	contentTypes := make([]string, 0, len(files))
+	for _, file := range files {
+		contentTypes = append(contentTypes, file.ContentType)
+	}
+
+	return review.ReplacePhotos(contentTypes)
+}
+
+// 写真を画像ストレージに保存する
+// 保存に失敗した場合は保存済みの写真を削除し、画像ストレージに参照されない画像を残さない
+func (rs ReviewDomainService) uploadPhotos(photos []entity.ReviewPhoto, files []ReviewPhotoFile) error {
+	for i, photo := range photos {
+		err := rs.imageStorageAdapter.Upload(photo.Path, bytes.NewReader(files[i].Data), files[i].ContentType)
+		if err != nil {
+			deleteErr := rs.DeletePhotos(photos[:i])
+			if deleteErr != nil {
+				return errors.CombineErrors(err, deleteErr)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// カンマ区切りのNGワードを配列に変換する
+func splitNGWords(value string) []string {
+	ngWords := []string{}
+	for _, ngWord := range strings.Split(value, ",") {
+		ngWord = strings.TrimSpace(ngWord)
+		if ngWord != "" {
+			ngWords = append(ngWords, ngWord)
+		}
+	}
+
+	return ngWords
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	StockDomainService struct {
+		productStockRepository  repository.ProductStockRepository
+		stockMovementRepository repository.StockMovementRepository
+		domainEventPublisher    share.DomainEventPublisher
+		timeUtils               util.TimeUtils
+	}
+
+	// 在庫移動の内容
+	StockMovementInput struct {
+		ProductID     string
+		SKUID         string
+		Type          enum.StockMovementType
+		Quantity      int // 在庫数の増減
+		Reason        string
+		ReferenceType enum.StockMovementReferenceType
+		ReferenceID   string
+	}
+)
+
+var (
+	errStockNotFound    = share.CreateOriginalError(share.ErrorCodeOther, []string{"在庫数を変更する商品・SKUが見つかりません"})
+	errStockSKURequired = share.CreateOriginalError(share.ErrorCodeValidation, []string{"バリエーションを持つ商品の在庫数を変更する場合はSKUを指定してください"})
+)
+
+func NewStockService(
+	productStockRepository repository.ProductStockRepository,
+	stockMovementRepository repository.StockMovementRepository,
+	domainEventPublisher share.DomainEventPublisher,
+	timeUtils util.TimeUtils,
+) StockDomainService {
+	return StockDomainService{
+		productStockRepository:  productStockRepository,
+		stockMovementRepository: stockMovementRepository,
+		domainEventPublisher:    domainEventPublisher,
+		timeUtils:               timeUtils,
+	}
+}
+
+// 商品ID配列に一致する商品の行を商品IDの昇順に排他ロックする
+// 複数の商品・SKUの在庫数を変更する場合は、デッドロックしないよう在庫数を変更する前にすべての商品の行をロックする
+// （SKUの在庫数の変更でも商品の行を更新するため、SKUの順序を揃えるだけでは同じ商品の異なるSKU同士でデッドロックする）
+func (ss StockDomainService) LockProducts(db bun.IDB, ctx context.Context, productIDs []string) error {
+	return ss.productStockRepository.LockProductsForUpdate(db, ctx, productIDs)
+}
+
+// 商品・SKUの在庫数を変更し、在庫台帳に在庫移動を記録する
+// 在庫数を減らす場合に在庫が不足しているときは在庫数を変更せずにfalseを返却する
+// SKUを持つ商品はSKUを指定しない場合エラーを返却する
+// 在庫数の変更と在庫台帳への記録を同じトランザクションで行うため、引数dbはトランザクションであること
+// 在庫数が0から増えた場合は商品の再入荷イベントを発行する
+func (ss StockDomainService) Move(db bun.IDB, ctx context.Context, input StockMovementInput) (entity.StockMovement, bool, error) {
+	err := entity.ValidateStockMovementQuantity(input.Type, input.Quantity)
+	if err != nil {
+		return entity.StockMovement{}, false, err
+	}
+
+	// SKUを持つ商品の在庫数はSKUの在庫数の合計のため、商品の在庫数のみを変更しない
+	if input.SKUID == "" {
+		hasSKUs, err := ss.productStockRepository.HasSKUs(db, ctx, input.ProductID)
+		if err != nil {
+			return entity.StockMovement{}, false, err
+		}
+		if hasSKUs {
+			return entity.StockMovement{}, false, errStockSKURequired
+		}
+	}
+
+	// 移動前の在庫数を正しく記録するため、排他ロックを取得してから在庫数を変更する
+	beforeCount, ok, err := ss.productStockRepository.FindStockCountForUpdate(db, ctx, input.ProductID, input.SKUID)
+	if err != nil {
+		return entity.StockMovement{}, false, err
+	}
+	if !ok {
+		return entity.StockMovement{}, false, errStockNotFound
+	}
+
+	switch {
+	case input.Quantity < 0:
+		ok, err = ss.productStockRepository.Decrease(db, ctx, input.ProductID, input.SKUID, -input.Quantity)
+		if err != nil || !ok {
+			return entity.StockMovement{}, false, err
+		}
+	case input.Quantity > 0:
+		err = ss.productStockRepository.Increase(db, ctx, input.ProductID, input.SKUID, input.Quantity)
+		if err != nil {
+			return entity.StockMovement{}, false, err
+		}
+	}
+
+	movement := entity.CreateStockMovement(
+		input.ProductID,
+		input.SKUID,
+		input.Type,
+		input.Quantity,
+		beforeCount+input.Quantity,
+		input.Reason,
+		input.ReferenceType,
+		input.ReferenceID,
+		ss.timeUtils.NowJP(),
+	)
+	err = ss.stockMovementRepository.Insert(db, ctx, movement)
+	if err != nil {
+		return entity.StockMovement{}, false, err
+	}
+
+	if movement.IsRestock() {
+		err = ss.domainEventPublisher.Publish([]share.DomainEvent{entity.ProductBackInStockEvent{ProductID: movement.ProductID, DB: db, Ctx: ctx}})
+		if err != nil {
+			return entity.StockMovement{}, false, errors.WithStack(err)
+		}
+	}
+	return movement, true, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type StockReservationDomainService struct {
+	stockReservationRepository repository.StockReservationRepository
+	stockDomainService         StockDomainService
+	timeUtils                  util.TimeUtils
+}
+
+var errStockReservationNotFound = share.CreateOriginalError(share.ErrorCodeOther, []string{"在庫引当が見つかりません"})
+
+func NewStockReservationService(
+	stockReservationRepository repository.StockReservationRepository,
+	stockDomainService StockDomainService,
+	timeUtils util.TimeUtils,
+) StockReservationDomainService {
+	return StockReservationDomainService{
+		stockReservationRepository: stockReservationRepository,
+		stockDomainService:         stockDomainService,
+		timeUtils:                  timeUtils,
+	}
+}
+
+// カート内の商品の在庫を引き当てる
+// 引当の商品の行を商品IDの順にすべてロックしてから、商品・SKUごとに在庫数を条件付きで差し引き、1つでも在庫が不足している場合はエラーを返却する（トランザクションをロールバックして差し引いた在庫を戻す）
+// 差し引いた在庫数は在庫引当を参照先として在庫台帳に記録する
+func (ss StockReservationDomainService) Reserve(db bun.IDB, ctx context.Context, accountID string, cart entity.Cart, products []entity.Product) (entity.StockReservation, error) {
+	reservation, err := entity.CreateStockReservation(accountID, cart, products, ss.timeUtils.NowJP())
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+
+	err = ss.stockDomainService.LockProducts(db, ctx, reservation.ProductIDs())
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+
+	for _, item := range reservation.Items {
+		_, ok, err := ss.stockDomainService.Move(db, ctx, StockMovementInput{
+			ProductID:     item.ProductID,
+			SKUID:         item.SKUID,
+			Type:          enum.StockMovementTypeReservation,
+			Quantity:      -item.Count,
+			Reason:        "購入手続きの開始",
+			ReferenceType: enum.StockMovementReferenceStockReservation,
+			ReferenceID:   reservation.ID,
+		})
+		if err != nil {
+			return entity.StockReservation{}, err
+		}
+		if !ok {
+			return entity.StockReservation{}, share.CreateOriginalError(share.ErrorCodeOther, []string{fmt.Sprintf("%sの在庫が不足しています", productName(products, item.ProductID))})
+		}
+	}
+
+	err = ss.stockReservationRepository.Insert(db, ctx, reservation)
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+	return reservation, nil
+}
+
+// 在庫引当IDに一致する在庫引当を確定し、差し引いた在庫を確定した引き落としにする
+// 在庫数は在庫引当時に差し引き済みのため、在庫台帳には注文を参照先として増減0の販売を記録する
+func (ss StockReservationDomainService) Commit(db bun.IDB, ctx context.Context, reservationID string, orderID string) (entity.StockReservation, error) {
+	reservation, ok, err := ss.stockReservationRepository.FindByIDForUpdate(db, ctx, reservationID)
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+	if !ok {
+		return entity.StockReservation{}, errStockReservationNotFound
+	}
+
+	err = reservation.Commit(orderID, ss.timeUtils.NowJP())
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+
+	err = ss.stockDomainService.LockProducts(db, ctx, reservation.ProductIDs())
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+
+	for _, item := range reservation.Items {
+		_, _, err = ss.stockDomainService.Move(db, ctx, StockMovementInput{
+			ProductID:     item.ProductID,
+			SKUID:         item.SKUID,
+			Type:          enum.StockMovementTypeSale,
+			Reason:        fmt.Sprintf("在庫引当（%s）の確定", reservation.ID),
+			ReferenceType: enum.StockMovementReferenceOrder,
+			ReferenceID:   orderID,
+		})
+		if err != nil {
+			return entity.StockReservation{}, err
+		}
+	}
+
+	err = ss.stockReservationRepository.Update(db, ctx, reservation)
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+	return reservation, nil
+}
+
+// 在庫引当を解放し、差し引いた在庫を商品・SKUの在庫数に戻して在庫台帳に記録する
+// 引数reservationは排他ロックを取得して取得した在庫引当であること
+func (ss StockReservationDomainService) Release(db bun.IDB, ctx context.Context, reservation entity.StockReservation) (entity.StockReservation, error) {
+	err := reservation.Release(ss.timeUtils.NowJP())
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+
+	err = ss.stockDomainService.LockProducts(db, ctx, reservation.ProductIDs())
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+
+	for _, item := range reservation.Items {
+		_, _, err = ss.stockDomainService.Move(db, ctx, StockMovementInput{
+			ProductID:     item.ProductID,
+			SKUID:         item.SKUID,
+			Type:          enum.StockMovementTypeRelease,
+			Quantity:      item.Count,
+			Reason:        "在庫引当の解放",
+			ReferenceType: enum.StockMovementReferenceStockReservation,
+			ReferenceID:   reservation.ID,
+		})
+		if err != nil {
+			return entity.StockReservation{}, err
+		}
+	}
+
+	err = ss.stockReservationRepository.Update(db, ctx, reservation)
+	if err != nil {
+		return entity.StockReservation{}, err
+	}
+	return reservation, nil
+}
+
+// 商品IDに一致する商品の商品名を返却する（商品が存在しない場合は商品ID）
+func productName(products []entity.Product, productID string) string {
+	for _, product := range products {
+		if product.ID == productID {
+			return product.Name
+		}
+	}
+	return productID
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/validator"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type WishlistDomainService struct {
+	wishlistRepository repository.WishlistRepository
+	validationUtils    util.ValidationUtils
+	timeUtils          util.TimeUtils
+}
+
+func NewWishlistService(wishlistRepository repository.WishlistRepository, validationUtils util.ValidationUtils, timeUtils util.TimeUtils) WishlistDomainService {
+	return WishlistDomainService{
+		wishlistRepository: wishlistRepository,
+		validationUtils:    validationUtils,
+		timeUtils:          timeUtils,
+	}
+}
+
+const wishlistMaxCount = 20 // 1つのアカウントが作成できるお気に入りリスト数の上限
+
+// 名前付きのお気に入りリストを作成する
+func (ws WishlistDomainService) CreateWishlist(db bun.IDB, ctx context.Context, accountID string, name string) (entity.Wishlist, error) {
+	err := ws.validationUtils.Struct(validator.ValidationWishlist{Name: name})
+	if err != nil {
+		return entity.Wishlist{}, ws.validationUtils.CreateValidationMessages(err)
+	}
+
+	wishlists, err := ws.wishlistRepository.FindByAccountID(db, ctx, accountID)
+	if err != nil {
+		return entity.Wishlist{}, err
+	}
+
+	if len(wishlists) >= wishlistMaxCount {
+		return entity.Wishlist{}, share.CreateOriginalError(share.ErrorCodeOther, []string{"作成できるお気に入りリスト数の上限に達しています"})
+	}
+
+	// 最初に作成するお気に入りリストを既定のリストにする
+	return entity.CreateWishlist(accountID, name, len(wishlists) == 0, ws.timeUtils.NowJP()), nil
+}
+
+// アカウントの既定のお気に入りリストを返却する
+// 既定のお気に入りリストが存在しない場合は作成したお気に入りリストと、DBに未登録であることを示すfalseを返却する
+func (ws WishlistDomainService) FindOrCreateDefaultWishlist(db bun.IDB, ctx context.Context, accountID string) (entity.Wishlist, bool, error) {
+	wishlists, err := ws.wishlistRepository.FindByAccountID(db, ctx, accountID)
+	if err != nil {
+		return entity.Wishlist{}, false, err
+	}
+
+	for _, wishlist := range wishlists {
+		if wishlist.IsDefault {
+			return wishlist, true, nil
+		}
+	}
+
+	return entity.CreateWishlist(accountID, entity.DefaultWishlistName, true, ws.timeUtils.NowJP()), false, nil
+}
@@ -0,0 +1,34 @@
+package subscriber
+
+import (
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+// 再入荷した商品の入荷通知の購読を通知メールの送信待ちにするサブスクライバー
+// 商品の再入荷イベント発行時に実行される
+// 在庫数を変更するトランザクション内で実行されるため、メールは送信せずにsend-back-in-stock-notificationsバッチで流量を制限して送信する
+type RequestBackInStockNotificationSubscriber struct {
+	backInStockSubscriptionRepository repository.BackInStockSubscriptionRepository
+	timeUtils                         util.TimeUtils
+}
+
+func NewRequestBackInStockNotificationSubscriber(backInStockSubscriptionRepository repository.BackInStockSubscriptionRepository, timeUtils util.TimeUtils) RequestBackInStockNotificationSubscriber {
+	return RequestBackInStockNotificationSubscriber{
+		backInStockSubscriptionRepository: backInStockSubscriptionRepository,
+		timeUtils:                         timeUtils,
+	}
+}
+
+// 商品の再入荷イベントを購読する
+func (subscriber RequestBackInStockNotificationSubscriber) TargetEvents() []share.DomainEvent {
+	return []share.DomainEvent{entity.ProductBackInStockEvent{}}
+}
+
+// 商品の入荷通知の購読を通知メールの送信待ちにする
+func (subscriber RequestBackInStockNotificationSubscriber) Subscribe(event share.DomainEvent) error {
+	backInStockEvent := event.(entity.ProductBackInStockEvent)
+	return subscriber.backInStockSubscriptionRepository.RequestNotification(backInStockEvent.DB, backInStockEvent.Ctx, backInStockEvent.ProductID, subscriber.timeUtils.NowJP())
+}
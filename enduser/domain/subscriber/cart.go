@@ -48,7 +48,9 @@ func NewMoveSessionCartProductToCartSubscriber(
 	sessionCartRepository repository.SessionCartRepository,
 ) MoveSessionCartProductToCartSubscriber {
 	return MoveSessionCartProductToCartSubscriber{
-		cartRepository: cartRepository,
+		cartRepository:        cartRepository,
+		productRepository:     productRepository,
+		sessionCartRepository: sessionCartRepository,
 	}
 }
 
@@ -68,8 +70,8 @@ func (subscriber MoveSessionCartProductToCartSubscriber) Subscribe(event share.D
 
 	sessionCart := sessionAccountCreatedEvent.SessionCart
 
-	// セッションカート内に商品が存在しない場合はreturnする
-	if len(sessionCart.SessionCartProducts) > 0 {
+	// セッションカート内に商品とあとで買う商品が存在しない場合はreturnする
+	if sessionCart.IsEmpty() {
 		return nil
 	}
 
@@ -0,0 +1,40 @@
+package subscriber
+
+import (
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/share"
+)
+
+// ゲストの閲覧履歴をアカウントの閲覧履歴にマージするサブスクライバー
+// セッションアカウント作成時（ログイン時）に実行される
+type MoveRecentlyViewedToAccountSubscriber struct {
+	recentlyViewedRepository repository.RecentlyViewedRepository
+}
+
+func NewMoveRecentlyViewedToAccountSubscriber(recentlyViewedRepository repository.RecentlyViewedRepository) MoveRecentlyViewedToAccountSubscriber {
+	return MoveRecentlyViewedToAccountSubscriber{
+		recentlyViewedRepository: recentlyViewedRepository,
+	}
+}
+
+// セッションアカウント作成イベント（ログインイベント）を購読する
+func (subscriber MoveRecentlyViewedToAccountSubscriber) TargetEvents() []share.DomainEvent {
+	return []share.DomainEvent{entity.SessionAccountCreatedEvent{}}
+}
+
+// ゲストの閲覧履歴をアカウントの閲覧履歴にマージし、ゲストの閲覧履歴を削除する
+func (subscriber MoveRecentlyViewedToAccountSubscriber) Subscribe(event share.DomainEvent) error {
+	sessionAccountCreatedEvent := event.(entity.SessionAccountCreatedEvent)
+
+	// ゲストの閲覧履歴が存在しない場合、returnする
+	if sessionAccountCreatedEvent.RecentlyViewedSessionID == "" {
+		return nil
+	}
+
+	return subscriber.recentlyViewedRepository.MoveSessionToAccount(
+		sessionAccountCreatedEvent.Ctx,
+		sessionAccountCreatedEvent.RecentlyViewedSessionID,
+		sessionAccountCreatedEvent.AccountID,
+	)
+}
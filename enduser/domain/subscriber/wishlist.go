@@ -0,0 +1,105 @@
+package subscriber
+
+import (
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+)
+
+type (
+	// 既定のお気に入りリストを新規作成するサブスクライバー
+	// アカウント有効化イベント発行時に実行される
+	CreateDefaultWishlistSubscriber struct {
+		wishlistRepository repository.WishlistRepository
+		timeUtils          util.TimeUtils
+	}
+
+	// セッションお気に入りリストからアカウントのお気に入りリストに商品を移動させるサブスクライバー
+	// セッションアカウント作成時（ログイン時）に実行される
+	MoveSessionWishlistToWishlistSubscriber struct {
+		wishlistDomainService     service.WishlistDomainService
+		wishlistRepository        repository.WishlistRepository
+		sessionWishlistRepository repository.SessionWishlistRepository
+		timeUtils                 util.TimeUtils
+	}
+)
+
+func NewCreateDefaultWishlistSubscriber(wishlistRepository repository.WishlistRepository, timeUtils util.TimeUtils) CreateDefaultWishlistSubscriber {
+	return CreateDefaultWishlistSubscriber{
+		wishlistRepository: wishlistRepository,
+		timeUtils:          timeUtils,
+	}
+}
+
+// アカウント有効化イベントを購読する
+func (subscriber CreateDefaultWishlistSubscriber) TargetEvents() []share.DomainEvent {
+	return []share.DomainEvent{entity.AccountActivatedEvent{}}
+}
+
+// 既定のお気に入りリストを新規作成する
+func (subscriber CreateDefaultWishlistSubscriber) Subscribe(event share.DomainEvent) error {
+	accountActivatedEvent := event.(entity.AccountActivatedEvent)
+	wishlist := entity.CreateWishlist(accountActivatedEvent.Account.ID, entity.DefaultWishlistName, true, subscriber.timeUtils.NowJP())
+	return subscriber.wishlistRepository.Insert(accountActivatedEvent.DB, accountActivatedEvent.Ctx, wishlist)
+}
+
+func NewMoveSessionWishlistToWishlistSubscriber(
+	wishlistDomainService service.WishlistDomainService,
+	wishlistRepository repository.WishlistRepository,
+	sessionWishlistRepository repository.SessionWishlistRepository,
+	timeUtils util.TimeUtils,
+) MoveSessionWishlistToWishlistSubscriber {
+	return MoveSessionWishlistToWishlistSubscriber{
+		wishlistDomainService:     wishlistDomainService,
+		wishlistRepository:        wishlistRepository,
+		sessionWishlistRepository: sessionWishlistRepository,
+		timeUtils:                 timeUtils,
+	}
+}
+
+// セッションアカウント作成イベント（ログインイベント）を購読する
+func (subscriber MoveSessionWishlistToWishlistSubscriber) TargetEvents() []share.DomainEvent {
+	return []share.DomainEvent{entity.SessionAccountCreatedEvent{}}
+}
+
+// セッションお気に入りリスト内の商品をアカウントの既定のお気に入りリストに移動させる
+func (subscriber MoveSessionWishlistToWishlistSubscriber) Subscribe(event share.DomainEvent) error {
+	sessionAccountCreatedEvent := event.(entity.SessionAccountCreatedEvent)
+
+	// セッションお気に入りリストが存在しない場合、returnする
+	if !sessionAccountCreatedEvent.ExistsSessionWishlist {
+		return nil
+	}
+
+	sessionWishlist := sessionAccountCreatedEvent.SessionWishlist
+
+	// セッションお気に入りリスト内に商品が存在しない場合はセッションお気に入りリストを削除してreturnする
+	if len(sessionWishlist.ProductIDs) == 0 {
+		return subscriber.sessionWishlistRepository.Delete(sessionAccountCreatedEvent.Ctx, sessionWishlist)
+	}
+
+	db := sessionAccountCreatedEvent.DB
+	ctx := sessionAccountCreatedEvent.Ctx
+
+	// アカウントの既定のお気に入りリストを取得する（存在しない場合は作成する）
+	wishlist, exists, err := subscriber.wishlistDomainService.FindOrCreateDefaultWishlist(db, ctx, sessionAccountCreatedEvent.AccountID)
+	if err != nil {
+		return err
+	}
+
+	// セッションお気に入りリストから既定のお気に入りリストに商品を移動させる
+	wishlist.MoveSessionWishlistProducts(sessionWishlist, subscriber.timeUtils.NowJP())
+	if exists {
+		err = subscriber.wishlistRepository.Update(db, ctx, wishlist)
+	} else {
+		err = subscriber.wishlistRepository.Insert(db, ctx, wishlist)
+	}
+	if err != nil {
+		return err
+	}
+
+	// セッションお気に入りリストを削除する
+	return subscriber.sessionWishlistRepository.Delete(ctx, sessionWishlist)
+}
@@ -0,0 +1,22 @@
+package validator
+
+// 商品一覧取得時のバリデーション用検索条件構造体
+type ValidationProductCatalogCondition struct {
+	MinPrice int `validate:"gte=0"`
+	MaxPrice int `validate:"gte=0"`
+	Limit    int `validate:"gte=1,lte=100"`
+}
+
+// 商品検索時のバリデーション用検索条件構造体
+type ValidationProductSearchCondition struct {
+	Keyword  string `validate:"required,lte=100"`
+	MinPrice int    `validate:"gte=0"`
+	MaxPrice int    `validate:"gte=0"`
+	Page     int    `validate:"gte=1"`
+	Limit    int    `validate:"gte=1,lte=100"`
+}
+
+// ランキング取得時のバリデーション用検索条件構造体
+type ValidationProductRankingCondition struct {
+	Limit int `validate:"gte=1,lte=100"`
+}
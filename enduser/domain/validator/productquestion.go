@@ -0,0 +1,17 @@
+package validator
+
+// 商品の質問投稿時のバリデーション用質問構造体
+type ValidationProductQuestion struct {
+	Body string `validate:"required,lte=1000"`
+}
+
+// 商品の質問への回答時のバリデーション用回答構造体
+type ValidationProductAnswer struct {
+	Body string `validate:"required,lte=2000"`
+}
+
+// 商品の質問一覧取得時のバリデーション用検索条件構造体
+type ValidationProductQuestionListCondition struct {
+	Page  int `validate:"gte=1"`
+	Limit int `validate:"gte=1,lte=50"`
+}
@@ -0,0 +1,19 @@
+package validator
+
+// レビュー投稿・編集時のバリデーション用レビュー構造体
+type ValidationReview struct {
+	Rating int    `validate:"gte=1,lte=5"`
+	Title  string `validate:"required,lte=50"`
+	Body   string `validate:"required,lte=2000"`
+}
+
+// レビュー一覧取得時のバリデーション用検索条件構造体
+type ValidationReviewListCondition struct {
+	Page  int `validate:"gte=1"`
+	Limit int `validate:"gte=1,lte=100"`
+}
+
+// 不適切なレビューとしての報告時のバリデーション用報告構造体
+type ValidationReviewReport struct {
+	Reason string `validate:"required,lte=500"`
+}
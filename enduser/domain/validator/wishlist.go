@@ -0,0 +1,6 @@
+package validator
+
+// お気に入りリスト作成時のバリデーション用お気に入りリスト構造体
+type ValidationWishlist struct {
+	Name string `validate:"required,lte=50"`
+}
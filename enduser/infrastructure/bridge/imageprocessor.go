@@ -0,0 +1,198 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	stddraw "image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/chai2010/webp"
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+type imageProcessorAdapter struct{}
+
+const (
+	imageJPEGQuality = 85 // JPEGの品質（1〜100）
+	imageWebPQuality = 80 // WebPの品質（0〜100）
+)
+
+func NewImageProcessorAdapter() imageProcessorAdapter {
+	return imageProcessorAdapter{}
+}
+
+// 画像をデコードし、JPEGのEXIFの向きの情報に従って画像を回転・反転させる
+func (ia imageProcessorAdapter) Decode(body io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return applyOrientation(img, exifOrientation(data)), nil
+}
+
+// 画像を幅widthに縮小し、画像形式でエンコードする
+// 画像をデコードした画素のみをエンコードするため、EXIFなどのメタデータは出力されない
+func (ia imageProcessorAdapter) Encode(img image.Image, width int, format enum.ImageFormat) ([]byte, error) {
+	resized := resize(img, width)
+
+	var buf bytes.Buffer
+	switch format {
+	case enum.ImageFormatWebP:
+		err := webp.Encode(&buf, resized, &webp.Options{Quality: imageWebPQuality})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	default:
+		// JPEGは透過に対応していないため白色の背景に描画する
+		opaque := image.NewRGBA(resized.Bounds())
+		stddraw.Draw(opaque, opaque.Bounds(), image.NewUniform(color.White), image.Point{}, stddraw.Src)
+		stddraw.Draw(opaque, opaque.Bounds(), resized, resized.Bounds().Min, stddraw.Over)
+
+		err := jpeg.Encode(&buf, opaque, &jpeg.Options{Quality: imageJPEGQuality})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// 画像を縦横比を保ったまま幅widthに縮小する
+func resize(img image.Image, width int) *image.RGBA {
+	bounds := img.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// JPEGのEXIFから画像の向き（1〜8）を返却する（EXIFが存在しない場合は1を返却する）
+func exifOrientation(data []byte) int {
+	// SOIマーカー
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	offset := 2
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1
+		}
+		marker := data[offset+1]
+		size := int(binary.BigEndian.Uint16(data[offset+2:]))
+		if offset+2+size > len(data) || size < 2 {
+			return 1
+		}
+		segment := data[offset+4 : offset+2+size]
+
+		// APP1セグメントのEXIF
+		if marker == 0xE1 && len(segment) >= 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		// SOSマーカー以降は画像データのためEXIFは存在しない
+		if marker == 0xDA {
+			return 1
+		}
+		offset += 2 + size
+	}
+
+	return 1
+}
+
+// TIFF形式のEXIFのIFD0から画像の向き（タグ0x0112）を返却する
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// 画像の向き（1〜8）に従って画像を回転・反転させる
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation == 1 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	// 向き5〜8は縦横が入れ替わる
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // 左右反転
+				dx, dy = w-1-x, y
+			case 3: // 180度回転
+				dx, dy = w-1-x, h-1-y
+			case 4: // 上下反転
+				dx, dy = x, h-1-y
+			case 5: // 左右反転して反時計回りに90度回転
+				dx, dy = y, x
+			case 6: // 時計回りに90度回転
+				dx, dy = h-1-y, x
+			case 7: // 左右反転して時計回りに90度回転
+				dx, dy = h-1-y, w-1-x
+			case 8: // 反時計回りに90度回転
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+
+	return dst
+}
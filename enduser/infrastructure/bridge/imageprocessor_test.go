@@ -0,0 +1,91 @@
+package bridge_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/bridge"
+	"github.com/stretchr/testify/assert"
+	_ "golang.org/x/image/webp"
+)
+
+// 幅w・高さhのJPEG画像に、画像の向き（orientation）を持つEXIFを埋め込んで返却する
+func jpegWithOrientation(t *testing.T, w int, h int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, nil)
+	assert.Nil(t, err)
+	data := buf.Bytes()
+
+	// リトルエンディアンのTIFFヘッダーと、向きのタグのみを持つIFD0
+	tiff := []byte{'I', 'I', 0x2A, 0x00, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00,
+		0x12, 0x01, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00, byte(orientation), byte(orientation >> 8), 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	size := len(payload) + 2
+	app1 := append([]byte{0xFF, 0xE1, byte(size >> 8), byte(size)}, payload...)
+
+	result := append([]byte{}, data[:2]...)
+	result = append(result, app1...)
+	return append(result, data[2:]...)
+}
+
+func TestImageProcessorAdapterDecode(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Orientation    uint16
+		ExpectedWidth  int
+		ExpectedHeight int
+	}{
+		{Name: "向きが1の場合は回転しない", Orientation: 1, ExpectedWidth: 40, ExpectedHeight: 20},
+		{Name: "向きが6の場合は90度回転して縦横が入れ替わる", Orientation: 6, ExpectedWidth: 20, ExpectedHeight: 40},
+		{Name: "向きが3の場合は180度回転して縦横は変わらない", Orientation: 3, ExpectedWidth: 40, ExpectedHeight: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// given（前提条件）
+			data := jpegWithOrientation(t, 40, 20, tt.Orientation)
+
+			// when（操作）
+			img, err := bridge.NewImageProcessorAdapter().Decode(bytes.NewReader(data))
+
+			// then（期待する結果）
+			assert.Nil(t, err)
+			assert.Equal(t, tt.ExpectedWidth, img.Bounds().Dx())
+			assert.Equal(t, tt.ExpectedHeight, img.Bounds().Dy())
+		})
+	}
+}
+
+func TestImageProcessorAdapterEncode(t *testing.T) {
+	// given（前提条件）
+	imageProcessorAdapter := bridge.NewImageProcessorAdapter()
+	img, err := imageProcessorAdapter.Decode(bytes.NewReader(jpegWithOrientation(t, 400, 300, 1)))
+	assert.Nil(t, err)
+
+	for _, format := range []enum.ImageFormat{enum.ImageFormatJPEG, enum.ImageFormatWebP} {
+		t.Run(string(format), func(t *testing.T) {
+			// when（操作）
+			data, err := imageProcessorAdapter.Encode(img, 200, format)
+
+			// then（期待する結果）縦横比を保って縮小され、EXIFを含まない
+			assert.Nil(t, err)
+			decoded, decodedFormat, err := image.Decode(bytes.NewReader(data))
+			assert.Nil(t, err)
+			assert.Equal(t, string(format), decodedFormat)
+			assert.Equal(t, 200, decoded.Bounds().Dx())
+			assert.Equal(t, 150, decoded.Bounds().Dy())
+			assert.False(t, bytes.Contains(data, []byte("Exif\x00\x00")))
+		})
+	}
+}
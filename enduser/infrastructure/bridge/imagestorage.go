@@ -0,0 +1,195 @@
+package bridge
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/cockroachdb/errors"
+)
+
+type (
+	// S3のバケットに画像を保存するアダプター
+	s3ImageStorageAdapter struct {
+		bucket string
+	}
+
+	// ローカルのディレクトリに画像を保存するアダプター（開発・テスト用）
+	// 画像のURLはPRODUCT_IMAGE_BASE_URLに画像のパスを連結したURLで、有効期限は設けない
+	localImageStorageAdapter struct {
+		dir     string
+		baseURL string
+	}
+)
+
+func NewS3ImageStorageAdapter() s3ImageStorageAdapter {
+	return s3ImageStorageAdapter{
+		bucket: os.Getenv("PRODUCT_IMAGE_BUCKET"),
+	}
+}
+
+// 画像をS3に保存する
+func (sa s3ImageStorageAdapter) Upload(path string, body io.Reader, contentType string) error {
+	sess, err := sa.newSession()
+	if err != nil {
+		return err
+	}
+
+	_, err = s3manager.NewUploader(sess).Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(sa.bucket),
+		Key:         aws.String(sa.key(path)),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	return errors.WithStack(err)
+}
+
+// 画像をS3から取得する
+func (sa s3ImageStorageAdapter) Download(path string) (io.ReadCloser, error) {
+	sess, err := sa.newSession()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s3.New(sess).GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(sa.bucket),
+		Key:    aws.String(sa.key(path)),
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return output.Body, nil
+}
+
+// 画像をS3から削除する
+func (sa s3ImageStorageAdapter) Delete(path string) error {
+	sess, err := sa.newSession()
+	if err != nil {
+		return err
+	}
+
+	_, err = s3.New(sess).DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(sa.bucket),
+		Key:    aws.String(sa.key(path)),
+	})
+	return errors.WithStack(err)
+}
+
+// 画像を取得できる署名付きURLを返却する
+func (sa s3ImageStorageAdapter) PresignedURL(path string, expiry time.Duration) (string, error) {
+	sess, err := sa.newSession()
+	if err != nil {
+		return "", err
+	}
+
+	req, _ := s3.New(sess).GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(sa.bucket),
+		Key:    aws.String(sa.key(path)),
+	})
+	presignedURL, err := req.Presign(expiry)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return presignedURL, nil
+}
+
+func (sa s3ImageStorageAdapter) newSession() (*session.Session, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String("ap-northeast-1"),
+	})
+	return sess, errors.WithStack(err)
+}
+
+// 画像のパスからS3のオブジェクトキーを返却する
+func (sa s3ImageStorageAdapter) key(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
+
+func NewLocalImageStorageAdapter() localImageStorageAdapter {
+	return localImageStorageAdapter{
+		dir:     os.Getenv("PRODUCT_IMAGE_LOCAL_DIR"),
+		baseURL: os.Getenv("PRODUCT_IMAGE_BASE_URL"),
+	}
+}
+
+// 画像をディレクトリに保存する
+func (la localImageStorageAdapter) Upload(path string, body io.Reader, contentType string) error {
+	filePath, err := la.filePath(path)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(filePath), 0o755)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, body)
+	return errors.WithStack(err)
+}
+
+// 画像をディレクトリから取得する
+func (la localImageStorageAdapter) Download(path string) (io.ReadCloser, error) {
+	filePath, err := la.filePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return file, nil
+}
+
+// 画像をディレクトリから削除する
+func (la localImageStorageAdapter) Delete(path string) error {
+	filePath, err := la.filePath(path)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 画像のURLを返却する（有効期限は設けない）
+func (la localImageStorageAdapter) PresignedURL(path string, expiry time.Duration) (string, error) {
+	_, err := la.filePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	imageURL, err := url.JoinPath(la.baseURL, strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return imageURL, nil
+}
+
+// 画像のパスからディレクトリ内のファイルパスを返却する（ディレクトリ外を指すパスの場合はエラーを返却する）
+func (la localImageStorageAdapter) filePath(path string) (string, error) {
+	cleaned := filepath.Clean("/" + filepath.FromSlash(path))
+	if cleaned == string(filepath.Separator) {
+		return "", errors.Newf("画像のパスが不正です: %s", path)
+	}
+
+	return filepath.Join(la.dir, cleaned), nil
+}
@@ -0,0 +1,67 @@
+package bridge_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/bridge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalImageStorageAdapter(t *testing.T) {
+	// given（前提条件）
+	dir := t.TempDir()
+	t.Setenv("PRODUCT_IMAGE_LOCAL_DIR", dir)
+	t.Setenv("PRODUCT_IMAGE_BASE_URL", "http://localhost:8080/images/")
+	imageStorageAdapter := bridge.NewLocalImageStorageAdapter()
+
+	// when（操作）
+	err := imageStorageAdapter.Upload("/products/1/image.jpg", strings.NewReader("image"), "image/jpeg")
+
+	// then（期待する結果）
+	assert.Nil(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "products", "1", "image.jpg"))
+	assert.Nil(t, err)
+	assert.Equal(t, "image", string(data))
+
+	// when（操作）
+	url, err := imageStorageAdapter.PresignedURL("/products/1/image.jpg", time.Hour)
+
+	// then（期待する結果）
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:8080/images/products/1/image.jpg", url)
+
+	// when（操作）
+	err = imageStorageAdapter.Delete("/products/1/image.jpg")
+
+	// then（期待する結果）
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "products", "1", "image.jpg"))
+	assert.True(t, os.IsNotExist(err))
+
+	// when（操作）存在しない画像を削除する
+	err = imageStorageAdapter.Delete("/products/1/image.jpg")
+
+	// then（期待する結果）
+	assert.Nil(t, err)
+}
+
+func TestLocalImageStorageAdapterOutsideDir(t *testing.T) {
+	// given（前提条件）
+	dir := t.TempDir()
+	t.Setenv("PRODUCT_IMAGE_LOCAL_DIR", filepath.Join(dir, "images"))
+	imageStorageAdapter := bridge.NewLocalImageStorageAdapter()
+
+	// when（操作）ディレクトリ外を指すパスに保存する
+	err := imageStorageAdapter.Upload("../../outside.jpg", strings.NewReader("image"), "image/jpeg")
+
+	// then（期待する結果）ディレクトリ内に保存される
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "images", "outside.jpg"))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "outside.jpg"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -23,8 +23,10 @@ type Account struct {
 	ExternalAccountID *string
 	IsActive          bool `bun:",notnull"`
 	StripeCustomerId  *string
-	ReviewNickname    string    `bun:",notnull"`
-	DeleteDateTime    time.Time `bun:",soft_delete,nullzero"`
+	ReviewNickname    string `bun:",notnull"`
+	// 宣伝メールの配信停止フラグ
+	MarketingEmailOptOut bool      `bun:",notnull,default:false"`
+	DeleteDateTime       time.Time `bun:",soft_delete,nullzero"`
 }
 
 type accountRepository struct {
@@ -44,6 +46,39 @@ func (ar accountRepository) FindByEmail(db bun.IDB, ctx context.Context, email s
 	return ar.toEntity(account), true, errors.WithStack(err)
 }
 
+func (ar accountRepository) FindByID(db bun.IDB, ctx context.Context, id string) (entity.Account, bool, error) {
+	account := Account{}
+	err := db.NewSelect().Model(&account).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Account{}, false, nil
+		}
+
+		return entity.Account{}, false, errors.WithStack(err)
+	}
+
+	return ar.toEntity(account), true, nil
+}
+
+// アカウントID配列に一致するアカウント配列を返却する
+func (ar accountRepository) FindByIDs(db bun.IDB, ctx context.Context, ids []string) ([]entity.Account, error) {
+	if len(ids) == 0 {
+		return []entity.Account{}, nil
+	}
+
+	var accounts []Account
+	err := db.NewSelect().Model(&accounts).Where("id in (?)", bun.In(ids)).Scan(ctx)
+	if err != nil {
+		return []entity.Account{}, errors.WithStack(err)
+	}
+
+	eAccounts := make([]entity.Account, 0, len(accounts))
+	for _, account := range accounts {
+		eAccounts = append(eAccounts, ar.toEntity(account))
+	}
+	return eAccounts, nil
+}
+
 func (ar accountRepository) Insert(db bun.IDB, ctx context.Context, account *entity.Account, domainEventPublisher share.DomainEventPublisher) error {
 	mAccount := ar.toModel(*account)
 	_, err := db.NewInsert().Model(&mAccount).Exec(ctx)
@@ -95,6 +130,8 @@ func (ar accountRepository) toEntity(account Account) entity.Account {
 		IsActive:          account.IsActive,
 		StripeCustomerID:  account.StripeCustomerId,
 		ReviewNickname:    account.ReviewNickname,
+
+		MarketingEmailOptOut: account.MarketingEmailOptOut,
 	}
 }
 
@@ -108,5 +145,7 @@ func (ar accountRepository) toModel(account entity.Account) Account {
 		IsActive:          account.IsActive,
 		StripeCustomerId:  account.StripeCustomerID,
 		ReviewNickname:    account.ReviewNickname,
+
+		MarketingEmailOptOut: account.MarketingEmailOptOut,
 	}
 }
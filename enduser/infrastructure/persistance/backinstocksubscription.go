@@ -0,0 +1,163 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 入荷通知の購読テーブル
+	BackInStockSubscription struct {
+		bun.BaseModel `bun:"table:back_in_stock_subscriptions"`
+
+		ID                    string `bun:",pk"`
+		AccountID             string `bun:",notnull,unique:back_in_stock_subscriptions_account_id_product_id_idx"`
+		ProductID             string `bun:",notnull,unique:back_in_stock_subscriptions_account_id_product_id_idx"`
+		NotifyRequestDateTime *time.Time
+		CreateDateTime        time.Time `bun:",notnull"`
+	}
+
+	// 入荷通知の購読リポジトリの実装
+	backInStockSubscriptionRepository struct {
+		timeUtils util.TimeUtils
+	}
+)
+
+func NewBackInStockSubscriptionRepository(timeUtils util.TimeUtils) backInStockSubscriptionRepository {
+	return backInStockSubscriptionRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+func (br backInStockSubscriptionRepository) Exists(db bun.IDB, ctx context.Context, accountID string, productID string) (bool, error) {
+	exists, err := db.NewSelect().
+		Model((*BackInStockSubscription)(nil)).
+		Where("account_id = ?", accountID).
+		Where("product_id = ?", productID).
+		Exists(ctx)
+	return exists, errors.WithStack(err)
+}
+
+func (br backInStockSubscriptionRepository) FindRequested(db bun.IDB, ctx context.Context, after *entity.BackInStockSubscription, limit int) ([]entity.BackInStockSubscription, error) {
+	var subscriptions []BackInStockSubscription
+	query := db.NewSelect().
+		Model(&subscriptions).
+		Where("notify_request_date_time IS NOT NULL")
+	if after != nil && after.NotifyRequestDateTime != nil {
+		requestDateTime := br.timeUtils.TimeToUTC(*after.NotifyRequestDateTime)
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("notify_request_date_time > ?", requestDateTime).
+				WhereGroup(" OR ", func(q *bun.SelectQuery) *bun.SelectQuery {
+					return q.Where("notify_request_date_time = ?", requestDateTime).Where("id > ?", after.ID)
+				})
+		})
+	}
+	err := query.
+		Order("notify_request_date_time", "id").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return []entity.BackInStockSubscription{}, errors.WithStack(err)
+	}
+
+	eSubscriptions := make([]entity.BackInStockSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		eSubscriptions = append(eSubscriptions, br.toEntity(subscription))
+	}
+	return eSubscriptions, nil
+}
+
+func (br backInStockSubscriptionRepository) FindUnrequestedProductIDs(db bun.IDB, ctx context.Context) ([]string, error) {
+	productIDs := []string{}
+	err := db.NewSelect().
+		Model((*BackInStockSubscription)(nil)).
+		Distinct().
+		Column("product_id").
+		Where("notify_request_date_time IS NULL").
+		Order("product_id").
+		Scan(ctx, &productIDs)
+	return productIDs, errors.WithStack(err)
+}
+
+func (br backInStockSubscriptionRepository) Insert(db bun.IDB, ctx context.Context, subscription entity.BackInStockSubscription) error {
+	mSubscription := br.toModel(subscription)
+	_, err := db.NewInsert().Model(&mSubscription).Exec(ctx)
+	if err != nil {
+		return translateDuplicateKeyError(err)
+	}
+	return nil
+}
+
+func (br backInStockSubscriptionRepository) RequestNotification(db bun.IDB, ctx context.Context, productID string, now time.Time) error {
+	_, err := db.NewUpdate().
+		Model((*BackInStockSubscription)(nil)).
+		Set("notify_request_date_time = ?", br.timeUtils.TimeToUTC(now)).
+		Where("product_id = ?", productID).
+		Where("notify_request_date_time IS NULL").
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (br backInStockSubscriptionRepository) CancelNotificationRequest(db bun.IDB, ctx context.Context, productID string) error {
+	_, err := db.NewUpdate().
+		Model((*BackInStockSubscription)(nil)).
+		Set("notify_request_date_time = NULL").
+		Where("product_id = ?", productID).
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (br backInStockSubscriptionRepository) Delete(db bun.IDB, ctx context.Context, accountID string, productID string) error {
+	_, err := db.NewDelete().
+		Model((*BackInStockSubscription)(nil)).
+		Where("account_id = ?", accountID).
+		Where("product_id = ?", productID).
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (br backInStockSubscriptionRepository) DeleteByIDs(db bun.IDB, ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := db.NewDelete().Model((*BackInStockSubscription)(nil)).Where("id IN (?)", bun.In(ids)).Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (br backInStockSubscriptionRepository) toEntity(subscription BackInStockSubscription) entity.BackInStockSubscription {
+	var notifyRequestDateTime *time.Time
+	if subscription.NotifyRequestDateTime != nil {
+		jp := br.timeUtils.TimeToJP(*subscription.NotifyRequestDateTime)
+		notifyRequestDateTime = &jp
+	}
+
+	return entity.BackInStockSubscription{
+		ID:                    subscription.ID,
+		AccountID:             subscription.AccountID,
+		ProductID:             subscription.ProductID,
+		NotifyRequestDateTime: notifyRequestDateTime,
+		CreateDateTime:        br.timeUtils.TimeToJP(subscription.CreateDateTime),
+	}
+}
+
+func (br backInStockSubscriptionRepository) toModel(subscription entity.BackInStockSubscription) BackInStockSubscription {
+	var notifyRequestDateTime *time.Time
+	if subscription.NotifyRequestDateTime != nil {
+		utc := br.timeUtils.TimeToUTC(*subscription.NotifyRequestDateTime)
+		notifyRequestDateTime = &utc
+	}
+
+	return BackInStockSubscription{
+		ID:                    subscription.ID,
+		AccountID:             subscription.AccountID,
+		ProductID:             subscription.ProductID,
+		NotifyRequestDateTime: notifyRequestDateTime,
+		CreateDateTime:        br.timeUtils.TimeToUTC(subscription.CreateDateTime),
+	}
+}
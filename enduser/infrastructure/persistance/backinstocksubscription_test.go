@@ -0,0 +1,239 @@
+package persistance_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/config"
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	adapterMocks "github.com/kuritaeiji/ec_backend/enduser/domain/adapter/mocks"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/subscriber"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/bridge"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+)
+
+// 在庫数の変更に伴う入荷通知（再入荷イベントによる送信待ち・送信・取り消し）を確認する
+type backInStockSubscriptionRepositoryTestSuite struct {
+	suite.Suite
+	backInStockRepository repository.BackInStockSubscriptionRepository
+	stockDomainService    service.StockDomainService
+	publisher             share.DomainEventPublisher
+	db                    *bun.DB
+	timeUtils             util.TimeUtils
+}
+
+func TestBackInStockSubscriptionRepository(t *testing.T) {
+	err := config.SetupEnv()
+	if err != nil {
+		assert.FailNow(t, fmt.Sprintf("環境変数設定時にエラーが発生しました。\n%+v", err))
+	}
+	timeUtils := util.NewTimeUtils()
+	publisher := share.NewDomainEventPublisher()
+	backInStockRepository := persistance.NewBackInStockSubscriptionRepository(timeUtils)
+	requestBackInStockNotificationSubscriber := subscriber.NewRequestBackInStockNotificationSubscriber(backInStockRepository, timeUtils)
+	publisher.Subscribe(requestBackInStockNotificationSubscriber.TargetEvents(), requestBackInStockNotificationSubscriber)
+	suite.Run(t, &backInStockSubscriptionRepositoryTestSuite{
+		backInStockRepository: backInStockRepository,
+		stockDomainService:    service.NewStockService(persistance.NewProductStockRepository(), persistance.NewStockMovementRepository(timeUtils), publisher, timeUtils),
+		publisher:             publisher,
+		db:                    config.NewDB(),
+		timeUtils:             timeUtils,
+	})
+}
+
+func (suite *backInStockSubscriptionRepositoryTestSuite) tearDown() {
+	tables := []any{
+		new(persistance.Product),
+		new(persistance.StockMovement),
+		new(persistance.ProductStatus),
+		new(persistance.ProductPrice),
+		new(persistance.ProductSalePrice),
+		new(persistance.Account),
+		new(persistance.BackInStockSubscription),
+	}
+	for _, table := range tables {
+		_, err := suite.db.NewTruncateTable().Model(table).Exec(context.Background())
+		if err != nil {
+			suite.FailNow(fmt.Sprintf("テーブルデータ（%v）削除時に失敗", table))
+		}
+	}
+}
+
+func (suite *backInStockSubscriptionRepositoryTestSuite) insertProduct(product persistance.Product) {
+	_, err := suite.db.NewInsert().Model(&product).Exec(context.Background())
+	if err != nil {
+		suite.FailNow(fmt.Sprintf("商品作成時にエラー発生\n%+v", errors.WithStack(err)))
+	}
+}
+
+func (suite *backInStockSubscriptionRepositoryTestSuite) newProduct(productID string, stockCount int) persistance.Product {
+	return persistance.Product{
+		ID:                   productID,
+		CategoryID:           "1",
+		Name:                 "商品名",
+		Description:          "商品説明",
+		StockCount:           stockCount,
+		Version:              1,
+		CreateDateTime:       suite.timeUtils.TimeToUTC(suite.timeUtils.NowJP()),
+		CreateStaffAccountID: "1",
+	}
+}
+
+// 商品に当日のステータス・価格・セール価格を登録する（商品集約として取得できるようにする）
+func (suite *backInStockSubscriptionRepositoryTestSuite) insertTimelines(productID string, status enum.ProductStatus) {
+	start := suite.timeUtils.DateJP(2000, 1, 1)
+	end := suite.timeUtils.DateJP(2100, 12, 31)
+	models := []any{
+		&persistance.ProductStatus{ID: productID, ProductID: productID, Status: int(status), EffectiveStartDate: start, EffectiveEndDate: end},
+		&persistance.ProductPrice{ID: productID, ProductID: productID, TaxInclusivePrice: 1000, EffectiveStartDate: start, EffectiveEndDate: end},
+		&persistance.ProductSalePrice{ID: productID, ProductID: productID, TaxInclusivePrice: 0, EffectiveStartDate: start, EffectiveEndDate: end},
+	}
+	for _, model := range models {
+		_, err := suite.db.NewInsert().Model(model).Exec(context.Background())
+		if err != nil {
+			suite.FailNow(fmt.Sprintf("商品の適用期間作成時にエラー発生\n%+v", errors.WithStack(err)))
+		}
+	}
+}
+
+func (suite *backInStockSubscriptionRepositoryTestSuite) insertBackInStockSubscription(id string, accountID string, productID string, requested bool) {
+	now := suite.timeUtils.NowJP()
+	subscription := entity.BackInStockSubscription{ID: id, AccountID: accountID, ProductID: productID, CreateDateTime: now}
+	if requested {
+		subscription.NotifyRequestDateTime = &now
+	}
+	err := suite.backInStockRepository.Insert(suite.db, context.Background(), subscription)
+	if err != nil {
+		suite.FailNow(fmt.Sprintf("入荷通知の購読作成時にエラー発生\n%+v", err))
+	}
+}
+
+func (suite *backInStockSubscriptionRepositoryTestSuite) findBackInStockSubscription(id string) (persistance.BackInStockSubscription, bool) {
+	var subscription persistance.BackInStockSubscription
+	err := suite.db.NewSelect().Model(&subscription).Where("id = ?", id).Scan(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return persistance.BackInStockSubscription{}, false
+		}
+		suite.FailNow(fmt.Sprintf("入荷通知の購読取得時にエラー発生\n%+v", errors.WithStack(err)))
+	}
+	return subscription, true
+}
+
+func (suite *backInStockSubscriptionRepositoryTestSuite) TestMoveRestockRequestsBackInStockNotification() {
+	defer suite.tearDown()
+
+	// given（前提条件）在庫なしの商品1・商品2の入荷通知の購読
+	suite.insertProduct(suite.newProduct("1", 0))
+	suite.insertProduct(suite.newProduct("2", 0))
+	suite.insertBackInStockSubscription("subscription1", "account1", "1", false)
+	suite.insertBackInStockSubscription("subscription2", "account2", "2", false)
+
+	// when（操作）商品1が入荷する
+	err := suite.db.RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+		_, _, err := suite.stockDomainService.Move(tx, ctx, service.StockMovementInput{
+			ProductID:     "1",
+			Type:          enum.StockMovementTypeReceipt,
+			Quantity:      3,
+			Reason:        "入荷",
+			ReferenceType: enum.StockMovementReferenceStaffAccount,
+			ReferenceID:   "staff",
+		})
+		return err
+	})
+
+	// then（期待する結果）商品1の購読のみ通知メールの送信待ちになる
+	suite.Nil(err)
+	subscription1, ok := suite.findBackInStockSubscription("subscription1")
+	suite.True(ok)
+	suite.NotNil(subscription1.NotifyRequestDateTime)
+	subscription2, ok := suite.findBackInStockSubscription("subscription2")
+	suite.True(ok)
+	suite.Nil(subscription2.NotifyRequestDateTime)
+}
+
+func (suite *backInStockSubscriptionRepositoryTestSuite) TestSendBackInStockNotifications() {
+	defer suite.tearDown()
+
+	// given（前提条件）購入できる商品1と送信待ちの間に再び在庫なしになった商品2、当日の商品ステータス等が存在しない商品3、削除された商品4の送信待ちの購読
+	suite.insertProduct(suite.newProduct("1", 2))
+	suite.insertTimelines("1", enum.OnSale)
+	suite.insertProduct(suite.newProduct("2", 0))
+	suite.insertTimelines("2", enum.OnSale)
+	suite.insertProduct(suite.newProduct("3", 2))
+	for _, account := range []persistance.Account{
+		{ID: "account1", Email: "account1@example.com", IsActive: true, ReviewNickname: "account1"},
+		{ID: "account2", Email: "account2@example.com", IsActive: true, ReviewNickname: "account2"},
+	} {
+		_, err := suite.db.NewInsert().Model(&account).Exec(context.Background())
+		if err != nil {
+			suite.FailNow(fmt.Sprintf("アカウント作成時にエラー発生\n%+v", errors.WithStack(err)))
+		}
+	}
+	suite.insertBackInStockSubscription("subscription1", "account1", "1", true)
+	suite.insertBackInStockSubscription("subscription2", "account2", "2", true)
+	suite.insertBackInStockSubscription("subscription3", "account1", "3", true)
+	suite.insertBackInStockSubscription("subscription4", "account1", "4", true)
+
+	emailAdapter := adapterMocks.NewEmailAdapter(suite.T())
+	emailAdapter.On("SendEmail", bridge.From, "account1@example.com", mock.Anything, mock.MatchedBy(func(text string) bool {
+		return strings.Contains(text, "/cart/add?productId=1")
+	})).Return(nil).Once()
+	backInStockUsecase := usecase.NewBackInStockUsecase(
+		service.NewBackInStockNotificationService(emailAdapter),
+		suite.backInStockRepository,
+		persistance.NewProductRepository(bridge.NewLocalImageStorageAdapter(), suite.timeUtils),
+		persistance.NewAccountRepository(),
+		suite.publisher,
+		suite.timeUtils,
+		echo.New().Logger,
+		suite.db,
+	)
+
+	// when（操作）
+	sent, err := backInStockUsecase.SendNotifications(context.Background(), usecase.BackInStockNotificationRateLimit{BatchSize: 10})
+
+	// then（期待する結果）商品1の購読はカートに入れるリンクを記載して送信して削除し、商品2の購読は送信待ちを取り消す
+	// 商品3の購読は次回の実行で再度送信するため送信待ちのまま残し、商品4の購読は削除する
+	suite.Nil(err)
+	suite.Equal(1, sent)
+	_, ok := suite.findBackInStockSubscription("subscription1")
+	suite.False(ok)
+	subscription2, ok := suite.findBackInStockSubscription("subscription2")
+	suite.True(ok)
+	suite.Nil(subscription2.NotifyRequestDateTime)
+	subscription3, ok := suite.findBackInStockSubscription("subscription3")
+	suite.True(ok)
+	suite.NotNil(subscription3.NotifyRequestDateTime)
+	_, ok = suite.findBackInStockSubscription("subscription4")
+	suite.False(ok)
+}
+
+func (suite *backInStockSubscriptionRepositoryTestSuite) TestInsertDuplicate() {
+	defer suite.tearDown()
+
+	// given（前提条件）アカウント1の商品1の入荷通知の購読
+	suite.insertBackInStockSubscription("subscription1", "account1", "1", false)
+
+	// when（操作）同じアカウントと商品の購読を登録する（同時に購読された場合）
+	now := suite.timeUtils.NowJP()
+	err := suite.backInStockRepository.Insert(suite.db, context.Background(), entity.BackInStockSubscription{ID: "subscription2", AccountID: "account1", ProductID: "1", CreateDateTime: now})
+
+	// then（期待する結果）一意制約違反エラーを返却する
+	suite.True(errors.Is(err, repository.ErrDuplicateKey))
+}
@@ -3,9 +3,11 @@ package persistance
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/util"
 	"github.com/uptrace/bun"
 )
 
@@ -14,10 +16,13 @@ type (
 	Cart struct {
 		bun.BaseModel `bun:"table:carts"`
 
-		ID           string        `bun:",pk"`
-		AccountID    string        `bun:",notnull,unique"`
-		Version      int           `bun:",notnull"`
-		CartProducts []CartProduct `bun:"rel:has-many,join:id=cart_id"`
+		ID                string             `bun:",pk"`
+		AccountID         string             `bun:",notnull,unique"`
+		Version           int                `bun:",notnull"`
+		UpdatedAt         time.Time          `bun:",notnull,default:current_timestamp"`
+		ReminderSentAt    *time.Time         `bun:",nullzero"`
+		CartProducts      []CartProduct      `bun:"rel:has-many,join:id=cart_id"`
+		SavedCartProducts []SavedCartProduct `bun:"rel:has-many,join:id=cart_id"`
 	}
 
 	//カート商品テーブル
@@ -27,20 +32,38 @@ type (
 		ID        string `bun:",pk"`
 		CartID    string `bun:",notnull"`
 		ProductID string `bun:",notnull"`
+		SKUID     string `bun:"sku_id,notnull,default:''"`
 		Count     int    `bun:",notnull"`
+		Price     int    `bun:",notnull,default:0"`
+	}
+
+	//あとで買う商品テーブル
+	SavedCartProduct struct {
+		bun.BaseModel `bun:"table:saved_cart_products"`
+
+		ID        string `bun:",pk"`
+		CartID    string `bun:",notnull"`
+		ProductID string `bun:",notnull"`
+		SKUID     string `bun:"sku_id,notnull,default:''"`
+		Count     int    `bun:",notnull"`
+		Price     int    `bun:",notnull"`
 	}
 
 	//カートリポジトリの実装
-	cartRepository struct{}
+	cartRepository struct {
+		timeUtils util.TimeUtils
+	}
 )
 
-func NewCartRepository() cartRepository {
-	return cartRepository{}
+func NewCartRepository(timeUtils util.TimeUtils) cartRepository {
+	return cartRepository{
+		timeUtils: timeUtils,
+	}
 }
 
 func (cr cartRepository) FindByAccountID(db bun.IDB, ctx context.Context, accountID string) (entity.Cart, bool, error) {
 	var cart Cart
-	err := db.NewSelect().Model(&cart).Where("account_id = ?", accountID).Scan(ctx)
+	err := db.NewSelect().Model(&cart).Relation("CartProducts").Relation("SavedCartProducts").Where("account_id = ?", accountID).Scan(ctx)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return entity.Cart{}, false, nil
@@ -52,9 +75,34 @@ func (cr cartRepository) FindByAccountID(db bun.IDB, ctx context.Context, accoun
 	return cr.toEntity(cart), true, nil
 }
 
+// カート放置リマインドメールの送信対象となるカート集約配列を返却する
+// カート内に商品が存在し、最終更新日時がupdatedBefore以前で、最終更新後にリマインドメールを送信していないカートが対象
+func (cr cartRepository) FindAbandoned(db bun.IDB, ctx context.Context, updatedBefore time.Time) ([]entity.Cart, error) {
+	var carts []Cart
+	err := db.NewSelect().
+		Model(&carts).
+		Relation("CartProducts").
+		Relation("SavedCartProducts").
+		Where("cart.updated_at <= ?", cr.timeUtils.TimeToUTC(updatedBefore)).
+		Where("cart.reminder_sent_at IS NULL OR cart.reminder_sent_at < cart.updated_at").
+		Where("EXISTS (SELECT 1 FROM cart_products AS cp WHERE cp.cart_id = cart.id)").
+		Order("cart.updated_at").
+		Scan(ctx)
+	if err != nil {
+		return []entity.Cart{}, errors.WithStack(err)
+	}
+
+	eCarts := make([]entity.Cart, 0, len(carts))
+	for _, cart := range carts {
+		eCarts = append(eCarts, cr.toEntity(cart))
+	}
+	return eCarts, nil
+}
+
 // カート集約を登録する
 func (cr cartRepository) Insert(db bun.IDB, ctx context.Context, cart entity.Cart) error {
 	mCart := cr.toModel(cart)
+	mCart.UpdatedAt = cr.timeUtils.TimeToUTC(cr.timeUtils.NowJP())
 
 	//カートを登録する
 	_, err := db.NewInsert().Model(&mCart).Exec(ctx)
@@ -72,14 +120,31 @@ func (cr cartRepository) Update(db bun.IDB, ctx context.Context, cart entity.Car
 		return err
 	}
 
-	//すべてのカート商品を登録する
-	_, err = db.NewInsert().Model(&mCart.CartProducts).Exec(ctx)
+	//すべてのカート商品を登録する（カート内に商品が存在しない場合は登録しない）
+	if len(mCart.CartProducts) > 0 {
+		_, err = db.NewInsert().Model(&mCart.CartProducts).Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	//あとで買う商品をすべて削除する
+	_, err = db.NewDelete().Model(new(SavedCartProduct)).Where("cart_id = ?", mCart.ID).Exec(ctx)
 	if err != nil {
 		return err
 	}
 
+	//すべてのあとで買う商品を登録する（あとで買う商品が存在しない場合は登録しない）
+	if len(mCart.SavedCartProducts) > 0 {
+		_, err = db.NewInsert().Model(&mCart.SavedCartProducts).Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	//カートを更新する（楽観ロックする）
 	mCart.Version = mCart.Version + 1
+	mCart.UpdatedAt = cr.timeUtils.TimeToUTC(cr.timeUtils.NowJP())
 	res, err := db.NewUpdate().Model(&mCart).WherePK().Where("version = ?", mCart.Version-1).Exec(ctx)
 	if err != nil {
 		return err
@@ -96,6 +161,35 @@ func (cr cartRepository) Update(db bun.IDB, ctx context.Context, cart entity.Car
 	return nil
 }
 
+// カート放置リマインドメールの送信日時のみを更新する
+// カートの最終更新日時・バージョンは変更しない
+// 同時に実行されたバッチが二重に送信しないよう、最終更新後に送信日時が記録されていない場合のみ更新する
+func (cr cartRepository) UpdateReminderSentAt(db bun.IDB, ctx context.Context, cart entity.Cart) (bool, error) {
+	mCart := cr.toModel(cart)
+	res, err := db.NewUpdate().
+		Model(&mCart).
+		Column("reminder_sent_at").
+		WherePK().
+		Where("reminder_sent_at IS NULL OR reminder_sent_at < updated_at").
+		Exec(ctx)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return count == 1, nil
+}
+
+// カート放置リマインドメールの送信日時のみを元の値に戻す
+// カートの最終更新日時・バージョンは変更しない
+func (cr cartRepository) RestoreReminderSentAt(db bun.IDB, ctx context.Context, cart entity.Cart) error {
+	mCart := cr.toModel(cart)
+	_, err := db.NewUpdate().Model(&mCart).Column("reminder_sent_at").WherePK().Exec(ctx)
+	return errors.WithStack(err)
+}
+
 func (cr cartRepository) toModel(cart entity.Cart) Cart {
 	cartProducts := make([]CartProduct, 0, len(cart.CartProducts))
 	for _, p := range cart.CartProducts {
@@ -103,15 +197,38 @@ func (cr cartRepository) toModel(cart entity.Cart) Cart {
 			ID:        p.ID,
 			CartID:    p.CartID,
 			ProductID: p.ProductID,
+			SKUID:     p.SKUID,
 			Count:     p.Count,
+			Price:     p.Price,
 		})
 	}
 
+	savedCartProducts := make([]SavedCartProduct, 0, len(cart.SavedProducts))
+	for _, p := range cart.SavedProducts {
+		savedCartProducts = append(savedCartProducts, SavedCartProduct{
+			ID:        p.ID,
+			CartID:    p.CartID,
+			ProductID: p.ProductID,
+			SKUID:     p.SKUID,
+			Count:     p.Count,
+			Price:     p.Price,
+		})
+	}
+
+	var reminderSentAt *time.Time
+	if cart.ReminderSentAt != nil {
+		t := cr.timeUtils.TimeToUTC(*cart.ReminderSentAt)
+		reminderSentAt = &t
+	}
+
 	return Cart{
-		ID:           cart.ID,
-		AccountID:    cart.AccountID,
-		Version:      cart.Version,
-		CartProducts: cartProducts,
+		ID:                cart.ID,
+		AccountID:         cart.AccountID,
+		Version:           cart.Version,
+		UpdatedAt:         cr.timeUtils.TimeToUTC(cart.UpdatedAt),
+		ReminderSentAt:    reminderSentAt,
+		CartProducts:      cartProducts,
+		SavedCartProducts: savedCartProducts,
 	}
 }
 
@@ -122,14 +239,37 @@ func (cr cartRepository) toEntity(cart Cart) entity.Cart {
 			ID:        cartProduct.ID,
 			CartID:    cartProduct.CartID,
 			ProductID: cartProduct.ProductID,
+			SKUID:     cartProduct.SKUID,
 			Count:     cartProduct.Count,
+			Price:     cartProduct.Price,
 		})
 	}
 
+	savedProducts := make([]entity.SavedProduct, 0, len(cart.SavedCartProducts))
+	for _, savedCartProduct := range cart.SavedCartProducts {
+		savedProducts = append(savedProducts, entity.SavedProduct{
+			ID:        savedCartProduct.ID,
+			CartID:    savedCartProduct.CartID,
+			ProductID: savedCartProduct.ProductID,
+			SKUID:     savedCartProduct.SKUID,
+			Count:     savedCartProduct.Count,
+			Price:     savedCartProduct.Price,
+		})
+	}
+
+	var reminderSentAt *time.Time
+	if cart.ReminderSentAt != nil {
+		t := cr.timeUtils.TimeToJP(*cart.ReminderSentAt)
+		reminderSentAt = &t
+	}
+
 	return entity.Cart{
-		ID:           cart.ID,
-		AccountID:    cart.AccountID,
-		Version:      cart.Version,
-		CartProducts: cartProducts,
+		ID:             cart.ID,
+		AccountID:      cart.AccountID,
+		Version:        cart.Version,
+		UpdatedAt:      cr.timeUtils.TimeToJP(cart.UpdatedAt),
+		ReminderSentAt: reminderSentAt,
+		CartProducts:   cartProducts,
+		SavedProducts:  savedProducts,
 	}
 }
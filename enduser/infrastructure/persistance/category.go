@@ -0,0 +1,69 @@
+package persistance
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type categoryRepository struct{}
+
+const (
+	// カテゴリーID（プレースホルダー）に一致するカテゴリーとその子孫のカテゴリーのIDを返却するSQL
+	// 商品一覧のカテゴリーの絞り込みでサブクエリとして使用する
+	categoryDescendantIDsSQL = "WITH RECURSIVE category_tree AS (" +
+		"SELECT id FROM categories WHERE id = ? " +
+		"UNION ALL SELECT child.id FROM categories AS child JOIN category_tree ON child.parent_id = category_tree.id" +
+		") SELECT id FROM category_tree"
+
+	// カテゴリーID（プレースホルダー）に一致するカテゴリーとその祖先のカテゴリーを、カテゴリーからの距離（depth）とともに返却するSQL
+	categoryAncestorsSQL = "WITH RECURSIVE category_path AS (" +
+		"SELECT id, parent_id, name, slug, sort_order, 0 AS depth FROM categories WHERE id = ? " +
+		"UNION ALL SELECT parent.id, parent.parent_id, parent.name, parent.slug, parent.sort_order, category_path.depth + 1 " +
+		"FROM categories AS parent JOIN category_path ON parent.id = category_path.parent_id" +
+		") SELECT id, parent_id, name, slug, sort_order FROM category_path ORDER BY depth DESC"
+)
+
+func NewCategoryRepository() categoryRepository {
+	return categoryRepository{}
+}
+
+func (cr categoryRepository) FindAll(db bun.IDB, ctx context.Context) ([]entity.Category, error) {
+	var categories []Category
+	err := db.NewSelect().Model(&categories).Order("sort_order", "id").Scan(ctx)
+	if err != nil {
+		return []entity.Category{}, errors.WithStack(err)
+	}
+
+	return cr.toEntities(categories), nil
+}
+
+func (cr categoryRepository) FindAncestorPath(db bun.IDB, ctx context.Context, id string) ([]entity.Category, error) {
+	var categories []Category
+	err := db.NewRaw(categoryAncestorsSQL, id).Scan(ctx, &categories)
+	if err != nil {
+		return []entity.Category{}, errors.WithStack(err)
+	}
+
+	return cr.toEntities(categories), nil
+}
+
+func (cr categoryRepository) toEntities(categories []Category) []entity.Category {
+	eCategories := make([]entity.Category, 0, len(categories))
+	for _, category := range categories {
+		eCategories = append(eCategories, cr.toEntity(category))
+	}
+	return eCategories
+}
+
+func (cr categoryRepository) toEntity(category Category) entity.Category {
+	return entity.Category{
+		ID:        category.ID,
+		ParentID:  category.ParentID,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		SortOrder: category.SortOrder,
+	}
+}
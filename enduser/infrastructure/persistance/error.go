@@ -1,7 +1,23 @@
 package persistance
 
-import "github.com/cockroachdb/errors"
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+)
 
 var (
 	ErrOptimisticLocking = errors.New("楽観ロックエラー")
 )
+
+// MySQLの重複キーエラーのエラー番号
+const mysqlErrDuplicateEntry = 1062
+
+// 一意制約違反のエラーをrepository.ErrDuplicateKeyに変換し、スタックトレースを付与して返却する
+func translateDuplicateKeyError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+		return errors.WithStack(repository.ErrDuplicateKey)
+	}
+	return errors.WithStack(err)
+}
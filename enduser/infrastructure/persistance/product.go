@@ -2,8 +2,12 @@ package persistance
 
 import (
 	"context"
+	"database/sql"
+	"sort"
 	"time"
 
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/adapter"
 	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
 	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
 	"github.com/kuritaeiji/ec_backend/util"
@@ -26,12 +30,14 @@ type (
 		UpdateDateTime       *time.Time
 		UpdateStaffAccountID *string
 
-		ProductStatuses   []ProductStatus    `bun:"rel:has-many,join:id=product_id"`
-		ProductImages     []ProductImage     `bun:"rel:has-many,join:id=product_id"`
-		ProductPrices     []ProductPrice     `bun:"rel:has-many,join:id=product_id"`
-		ProductSalePrices []ProductSalePrice `bun:"rel:has-many,join:id=product_id"`
-		ReviewScores      []ReviewScore      `bun:"rel:has-many,join:id=product_id"`
-		Category          Category           `bun:"rel:belongs-to,join:category_id=id"`
+		ProductStatuses   []ProductStatus     `bun:"rel:has-many,join:id=product_id"`
+		ProductImages     []ProductImage      `bun:"rel:has-many,join:id=product_id"`
+		ProductPrices     []ProductPrice      `bun:"rel:has-many,join:id=product_id"`
+		ProductSalePrices []ProductSalePrice  `bun:"rel:has-many,join:id=product_id"`
+		ReviewScores      []ReviewScore       `bun:"rel:has-many,join:id=product_id"`
+		ProductOptionAxes []ProductOptionAxis `bun:"rel:has-many,join:id=product_id"`
+		ProductSKUs       []ProductSKU        `bun:"rel:has-many,join:id=product_id"`
+		Category          Category            `bun:"rel:belongs-to,join:category_id=id"`
 	}
 
 	// 商品ステータステーブル
@@ -49,10 +55,61 @@ type (
 	ProductImage struct {
 		bun.BaseModel `bun:"table:product_images"`
 
+		ID        string  `bun:",pk"`
+		ProductID string  `bun:",notnull"`
+		SKUID     *string `bun:"sku_id"` // 商品全体の画像の場合はNULL
+		Order     int     `bun:",notnull"`
+		Path      string  `bun:",notnull"`
+
+		ProductImageVariants []ProductImageVariant `bun:"rel:has-many,join:id=product_image_id"`
+	}
+
+	// 商品のバリエーションの軸テーブル
+	ProductOptionAxis struct {
+		bun.BaseModel `bun:"table:product_option_axes"`
+
 		ID        string `bun:",pk"`
 		ProductID string `bun:",notnull"`
+		Name      string `bun:",notnull"`
 		Order     int    `bun:",notnull"`
-		Path      string `bun:",notnull"`
+	}
+
+	// 商品のSKUテーブル
+	// 商品の在庫数（products.stock_count）はSKUの在庫数の合計で、SKUの在庫数を更新する際に合わせて更新する
+	// 在庫数は販売可能な数量で、購入手続き中の在庫引当の数量は差し引かれている
+	ProductSKU struct {
+		bun.BaseModel `bun:"table:product_skus"`
+
+		ID            string `bun:",pk"`
+		ProductID     string `bun:",notnull"`
+		Code          string `bun:",notnull,unique"`
+		Order         int    `bun:",notnull"`
+		StockCount    int    `bun:",notnull"`
+		PriceOverride int    `bun:",notnull,default:0"`
+		Version       int    `bun:",notnull"`
+
+		ProductSKUOptions []ProductSKUOption `bun:"rel:has-many,join:id=sku_id"`
+	}
+
+	// SKUのバリエーションの軸の値テーブル
+	ProductSKUOption struct {
+		bun.BaseModel `bun:"table:product_sku_options"`
+
+		ID     string `bun:",pk"`
+		SKUID  string `bun:"sku_id,notnull"`
+		AxisID string `bun:",notnull"`
+		Value  string `bun:",notnull"`
+	}
+
+	// 商品画像の派生画像テーブル
+	ProductImageVariant struct {
+		bun.BaseModel `bun:"table:product_image_variants"`
+
+		ID             string `bun:",pk"`
+		ProductImageID string `bun:",notnull"`
+		Width          int    `bun:",notnull"`
+		Format         string `bun:",notnull"`
+		Path           string `bun:",notnull"`
 	}
 
 	// 商品価格テーブル
@@ -91,33 +148,286 @@ type (
 	Category struct {
 		bun.BaseModel `bun:"table:categories"`
 
-		ID   string `bun:",pk"`
-		Name string `bun:",notnull"`
+		ID        string  `bun:",pk"`
+		ParentID  *string // 最上位のカテゴリーの場合はNULL
+		Name      string  `bun:",notnull"`
+		Slug      string  `bun:",notnull,unique"`
+		SortOrder int     `bun:",notnull,default:0"`
 	}
 
 	productRepository struct {
-		timeUtils util.TimeUtils
+		imageStorageAdapter adapter.ImageStorageAdapter
+		timeUtils           util.TimeUtils
 	}
 )
 
-func NewProductRepository(timeUtils util.TimeUtils) productRepository {
+const productImageURLExpiry = time.Hour // 商品画像のURLの有効期限
+
+func NewProductRepository(imageStorageAdapter adapter.ImageStorageAdapter, timeUtils util.TimeUtils) productRepository {
 	return productRepository{
-		timeUtils: timeUtils,
+		imageStorageAdapter: imageStorageAdapter,
+		timeUtils:           timeUtils,
 	}
 }
 
-// 商品ID配列に一致する商品配列を返却する。引数withImageがtrueの場合はS3から画像を取得し、そうでない場合は取得しない。
+// 商品ID配列に一致する商品配列を返却する。引数withImageがtrueの場合は画像ストレージから画像のURLを取得し、そうでない場合は取得しない。
+// 当日の商品ステータス・商品価格・商品セール価格が存在しない商品は含めない
 func (pr productRepository) FindByIDs(db bun.IDB, ctx context.Context, ids []string, withImage bool) ([]entity.Product, error) {
-	today := pr.timeUtils.NowJP()
+	// 商品IDが空の場合は空配列を返却する
+	if len(ids) == 0 {
+		return []entity.Product{}, nil
+	}
 
 	var products []Product
-	err := db.NewSelect().Model(&products).
+	err := pr.selectProducts(db, &products).
+		// 商品IDがidsに含まれる商品
+		Where("product.id in (?)", bun.In(ids)).
+		Scan(ctx)
+
+	if err != nil {
+		return []entity.Product{}, err
+	}
+
+	eProducts := make([]entity.Product, 0, len(products))
+	for _, product := range products {
+		// 当日の商品ステータス・商品価格・商品セール価格が存在しない商品は商品集約を構成できないため含めない
+		// このような商品は整合性検査コマンド（cmd/integrity）で検出する
+		if !hasTodayTimelines(product) {
+			continue
+		}
+
+		eProduct, err := pr.toEntity(product, withImage)
+		if err != nil {
+			return []entity.Product{}, err
+		}
+		eProducts = append(eProducts, eProduct)
+	}
+	return eProducts, nil
+}
+
+// 商品IDに一致する商品を返却する
+// 商品が存在しない場合や、当日の商品ステータス・商品価格・商品セール価格が存在しない場合はfalseを返却する
+func (pr productRepository) FindByID(db bun.IDB, ctx context.Context, id string, withImage bool) (entity.Product, bool, error) {
+	var product Product
+	err := pr.selectProducts(db, &product).Where("product.id = ?", id).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Product{}, false, nil
+		}
+
+		return entity.Product{}, false, errors.WithStack(err)
+	}
+
+	if !hasTodayTimelines(product) {
+		return entity.Product{}, false, nil
+	}
+
+	eProduct, err := pr.toEntity(product, withImage)
+	if err != nil {
+		return entity.Product{}, false, err
+	}
+	return eProduct, true, nil
+}
+
+func (pr productRepository) FindCategoryIDs(db bun.IDB, ctx context.Context, ids []string) (map[string]string, error) {
+	categoryIDs := map[string]string{}
+	if len(ids) == 0 {
+		return categoryIDs, nil
+	}
+
+	var products []Product
+	err := db.NewSelect().Model(&products).Column("id", "category_id").Where("id IN (?)", bun.In(ids)).Scan(ctx)
+	if err != nil {
+		return categoryIDs, errors.WithStack(err)
+	}
+
+	for _, product := range products {
+		categoryIDs[product.ID] = product.CategoryID
+	}
+	return categoryIDs, nil
+}
+
+// 検索条件に一致する商品一覧の1ページを返却する
+// 販売価格は当日適用される商品価格・商品セール価格から算出し、当日の商品ステータス・商品価格・商品セール価格が存在しない商品は含めない
+func (pr productRepository) FindCatalog(db bun.IDB, ctx context.Context, condition entity.ProductCatalogCondition) (entity.ProductCatalogPage, error) {
+	query := pr.applyCatalogSort(pr.catalogQuery(db, condition), condition)
+
+	// 次ページの有無を判定するために1件多く取得する
+	// 次ページのカーソルを作成するために並び順のキーも取得する
+	var rows []struct {
+		ID             string
+		EffectivePrice int
+		ReviewScore    int
+		CreateDateTime time.Time
+	}
+	err := query.
+		ColumnExpr("catalog.effective_price").
+		ColumnExpr("catalog.review_score").
+		ColumnExpr("catalog.create_date_time").
+		Limit(condition.Limit+1).
+		Scan(ctx, &rows)
+	if err != nil {
+		return entity.ProductCatalogPage{}, errors.WithStack(err)
+	}
+
+	hasNext := len(rows) > condition.Limit
+	if hasNext {
+		rows = rows[:condition.Limit]
+	}
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+
+	products, err := pr.FindByIDs(db, ctx, ids, true)
+	if err != nil {
+		return entity.ProductCatalogPage{}, err
+	}
+
+	// FindByIDsは順序を保証しないため、並び順どおりに並べ替える
+	productMap := make(map[string]entity.Product, len(products))
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+	sortedProducts := make([]entity.Product, 0, len(ids))
+	for _, id := range ids {
+		if product, ok := productMap[id]; ok {
+			sortedProducts = append(sortedProducts, product)
+		}
+	}
+
+	// 次ページのカーソルはIDのページの最後の商品から作成する
+	// 商品集約を取得できなかった商品（取得までの間に削除された商品など）がページの最後の場合も、次ページで同じ商品を再取得しない
+	page := entity.ProductCatalogPage{Products: sortedProducts}
+	if hasNext {
+		last := rows[len(rows)-1]
+		cursor := entity.CreateProductCatalogCursorFromKeys(last.ID, last.EffectivePrice, last.ReviewScore, pr.timeUtils.TimeToJP(last.CreateDateTime), condition.Sort)
+		page.NextCursor = &cursor
+	}
+	return page, nil
+}
+
+// 商品ID配列のうち、検索条件の絞り込み（カテゴリー・価格帯・販売中・在庫あり）に一致する商品ID配列を返却する
+// 当日の商品ステータス・商品価格・商品セール価格が存在しない商品は含めない。返却する商品ID配列の順序は保証しない
+func (pr productRepository) FilterIDs(db bun.IDB, ctx context.Context, ids []string, condition entity.ProductCatalogCondition) ([]string, error) {
+	if len(ids) == 0 {
+		return []string{}, nil
+	}
+
+	var filteredIDs []string
+	err := pr.catalogQuery(db, condition).Where("catalog.id IN (?)", bun.In(ids)).Scan(ctx, &filteredIDs)
+	if err != nil {
+		return []string{}, errors.WithStack(err)
+	}
+
+	return filteredIDs, nil
+}
+
+// すべての商品の検索用ドキュメントを返却する
+func (pr productRepository) FindSearchDocuments(db bun.IDB, ctx context.Context) ([]entity.ProductSearchDocument, error) {
+	var products []Product
+	err := db.NewSelect().Model(&products).Column("id", "name", "description").Order("id").Scan(ctx)
+	if err != nil {
+		return []entity.ProductSearchDocument{}, errors.WithStack(err)
+	}
+
+	documents := make([]entity.ProductSearchDocument, 0, len(products))
+	for _, product := range products {
+		documents = append(documents, entity.ProductSearchDocument{
+			ProductID:   product.ID,
+			Name:        product.Name,
+			Description: product.Description,
+		})
+	}
+	return documents, nil
+}
+
+// 当日の販売価格・レビュー点数を算出した商品一覧を、検索条件の絞り込みに一致する商品に絞り込むクエリを返却する
+// 返却するクエリの商品一覧の別名はcatalogで、商品ID（id）・カテゴリーID（category_id）・在庫数（stock_count）・作成日時（create_date_time）・
+// 販売価格（effective_price）・レビュー点数（review_score）を持つ
+func (pr productRepository) catalogQuery(db bun.IDB, condition entity.ProductCatalogCondition) *bun.SelectQuery {
+	today := pr.today()
+
+	// 当日の販売価格・レビュー点数を算出した商品一覧
+	catalog := db.NewSelect().
+		TableExpr("products AS product").
+		ColumnExpr("product.id").
+		ColumnExpr("product.category_id").
+		ColumnExpr("product.stock_count").
+		ColumnExpr("product.create_date_time").
+		ColumnExpr("CASE WHEN product_sale_price.tax_inclusive_price > 0 AND product_sale_price.tax_inclusive_price < product_price.tax_inclusive_price THEN product_sale_price.tax_inclusive_price ELSE product_price.tax_inclusive_price END AS effective_price").
+		ColumnExpr("COALESCE(review_score.score, 0) AS review_score").
+		Join("JOIN product_statuses AS product_status ON product_status.product_id = product.id AND ? BETWEEN product_status.effective_start_date AND product_status.effective_end_date", today).
+		Join("JOIN product_prices AS product_price ON product_price.product_id = product.id AND ? BETWEEN product_price.effective_start_date AND product_price.effective_end_date", today).
+		Join("JOIN product_sale_prices AS product_sale_price ON product_sale_price.product_id = product.id AND ? BETWEEN product_sale_price.effective_start_date AND product_sale_price.effective_end_date", today).
+		Join("LEFT JOIN review_scores AS review_score ON review_score.product_id = product.id AND review_score.date = ?", today)
+
+	if condition.CategoryID != "" {
+		// 親カテゴリーで絞り込んだ場合は子孫のカテゴリーの商品も含める
+		catalog = catalog.Where("product.category_id IN ("+categoryDescendantIDsSQL+")", condition.CategoryID)
+	}
+	if condition.OnSaleOnly {
+		catalog = catalog.Where("product_status.status = ?", enum.OnSale)
+	}
+	if condition.InStockOnly {
+		catalog = catalog.Where("product.stock_count > 0")
+	}
+
+	query := db.NewSelect().TableExpr("(?) AS catalog", catalog).Column("catalog.id")
+	if condition.MinPrice > 0 {
+		query = query.Where("catalog.effective_price >= ?", condition.MinPrice)
+	}
+	if condition.MaxPrice > 0 {
+		query = query.Where("catalog.effective_price <= ?", condition.MaxPrice)
+	}
+	return query
+}
+
+// 並び順とカーソルの位置に応じて商品一覧を並べ替え・絞り込む
+// 並び順のキーが同じ商品は商品IDで順序を一意にする
+func (pr productRepository) applyCatalogSort(query *bun.SelectQuery, condition entity.ProductCatalogCondition) *bun.SelectQuery {
+	cursor := condition.Cursor
+
+	switch condition.Sort {
+	case enum.ProductSortPriceAsc:
+		if cursor != nil {
+			query = query.Where("(catalog.effective_price > ? OR (catalog.effective_price = ? AND catalog.id > ?))", cursor.Value, cursor.Value, cursor.ProductID)
+		}
+		return query.OrderExpr("catalog.effective_price ASC, catalog.id ASC")
+	case enum.ProductSortPriceDesc:
+		if cursor != nil {
+			query = query.Where("(catalog.effective_price < ? OR (catalog.effective_price = ? AND catalog.id < ?))", cursor.Value, cursor.Value, cursor.ProductID)
+		}
+		return query.OrderExpr("catalog.effective_price DESC, catalog.id DESC")
+	case enum.ProductSortReviewScore:
+		if cursor != nil {
+			query = query.Where("(catalog.review_score < ? OR (catalog.review_score = ? AND catalog.id < ?))", cursor.Value, cursor.Value, cursor.ProductID)
+		}
+		return query.OrderExpr("catalog.review_score DESC, catalog.id DESC")
+	default:
+		if cursor != nil {
+			createDateTime := pr.timeUtils.TimeToUTC(cursor.CreateDateTime)
+			query = query.Where("(catalog.create_date_time < ? OR (catalog.create_date_time = ? AND catalog.id < ?))", createDateTime, createDateTime, cursor.ProductID)
+		}
+		return query.OrderExpr("catalog.create_date_time DESC, catalog.id DESC")
+	}
+}
+
+// 商品集約を構成するテーブルを当日適用されるデータに絞り込んで取得するクエリを返却する
+func (pr productRepository) selectProducts(db bun.IDB, model interface{}) *bun.SelectQuery {
+	today := pr.today()
+
+	return db.NewSelect().Model(model).
 		Relation("Category").
 		Relation("ProductStatuses", func(sq *bun.SelectQuery) *bun.SelectQuery {
 			// システム日付が商品ステータスの適用開始日以上かつ適用終了日以下
 			return sq.Where("? between effective_start_date and effective_end_date", today)
 		}).
 		Relation("ProductImages").
+		Relation("ProductImages.ProductImageVariants").
+		Relation("ProductOptionAxes").
+		Relation("ProductSKUs").
+		Relation("ProductSKUs.ProductSKUOptions").
 		Relation("ProductPrices", func(sq *bun.SelectQuery) *bun.SelectQuery {
 			// システム日付が商品価格の適用開始日以上かつ適用終了日以下
 			return sq.Where("? between effective_start_date and effective_end_date", today)
@@ -129,33 +439,117 @@ func (pr productRepository) FindByIDs(db bun.IDB, ctx context.Context, ids []str
 		Relation("ReviewScores", func(sq *bun.SelectQuery) *bun.SelectQuery {
 			// レビュー点数の日付がシステム日付
 			return sq.Where("review_score.date = ?", today)
-		}).
-		// 商品IDがidsに含まれる商品
-		Where("product.id in (?)", bun.In(ids)).
-		Scan(ctx)
+		})
+}
 
-	if err != nil {
-		return []entity.Product{}, err
-	}
+// システム日付（日本時間の当日0時）を返却する
+// 適用開始日・適用終了日などの日付型のカラムと比較する場合に使用する
+func (pr productRepository) today() time.Time {
+	now := pr.timeUtils.NowJP()
+	return pr.timeUtils.DateJP(now.Year(), now.Month(), now.Day())
+}
 
-	eProducts := make([]entity.Product, 0, len(products))
-	for _, product := range products {
-		eProducts = append(eProducts, pr.toEntity(product, withImage))
+// 引数withImageがtrueの場合は画像ストレージから画像のURLを取得し、そうでない場合は空文字を返却する
+func (pr productRepository) imageURL(path string, withImage bool) (string, error) {
+	if !withImage {
+		return "", nil
 	}
-	return eProducts, nil
+
+	return pr.imageStorageAdapter.PresignedURL(path, productImageURLExpiry)
+}
+
+// 当日の商品ステータス・商品価格・商品セール価格がすべて存在する場合trueを返却する
+// 商品テーブルは当日適用されるデータに絞り込んで取得していること
+func hasTodayTimelines(product Product) bool {
+	return len(product.ProductStatuses) > 0 && len(product.ProductPrices) > 0 && len(product.ProductSalePrices) > 0
 }
 
-func (pr productRepository) toEntity(product Product, withImage bool) entity.Product {
+func (pr productRepository) toEntity(product Product, withImage bool) (entity.Product, error) {
+	// 商品全体の画像とSKUごとの画像に分ける
 	images := make([]entity.ProductImage, 0, len(product.ProductImages))
+	skuImages := map[string][]entity.ProductImage{}
 	for _, image := range product.ProductImages {
-		images = append(images, entity.ProductImage{
+		url, err := pr.imageURL(image.Path, withImage)
+		if err != nil {
+			return entity.Product{}, err
+		}
+
+		variants := make([]entity.ProductImageVariant, 0, len(image.ProductImageVariants))
+		for _, variant := range image.ProductImageVariants {
+			variantURL, err := pr.imageURL(variant.Path, withImage)
+			if err != nil {
+				return entity.Product{}, err
+			}
+
+			variants = append(variants, entity.ProductImageVariant{
+				ID:             variant.ID,
+				ProductImageID: variant.ProductImageID,
+				Width:          variant.Width,
+				Format:         enum.ImageFormat(variant.Format),
+				Path:           variant.Path,
+				Image:          variantURL,
+			})
+		}
+
+		eImage := entity.ProductImage{
 			ID:        image.ID,
 			ProductID: image.ProductID,
 			Order:     image.Order,
 			Path:      image.Path,
-			Image:     "", // TODO 正しい値にする必要あり
+			Image:     url,
+			Variants:  variants,
+		}
+		if image.SKUID != nil {
+			skuImages[*image.SKUID] = append(skuImages[*image.SKUID], eImage)
+			continue
+		}
+		images = append(images, eImage)
+	}
+	// 商品画像を表示順に並べる
+	sortProductImages(images)
+
+	axes := make([]entity.ProductOptionAxis, 0, len(product.ProductOptionAxes))
+	for _, axis := range product.ProductOptionAxes {
+		axes = append(axes, entity.ProductOptionAxis{
+			ID:        axis.ID,
+			ProductID: axis.ProductID,
+			Name:      axis.Name,
+			Order:     axis.Order,
 		})
 	}
+	// バリエーションの軸を表示順に並べる
+	sort.Slice(axes, func(i, j int) bool {
+		return axes[i].Order < axes[j].Order
+	})
+
+	skus := make([]entity.ProductSKU, 0, len(product.ProductSKUs))
+	for _, sku := range product.ProductSKUs {
+		options := make([]entity.ProductSKUOption, 0, len(sku.ProductSKUOptions))
+		for _, option := range sku.ProductSKUOptions {
+			options = append(options, entity.ProductSKUOption{AxisID: option.AxisID, Value: option.Value})
+		}
+
+		images, ok := skuImages[sku.ID]
+		if !ok {
+			images = []entity.ProductImage{}
+		}
+		sortProductImages(images)
+
+		skus = append(skus, entity.ProductSKU{
+			ID:            sku.ID,
+			ProductID:     sku.ProductID,
+			Code:          sku.Code,
+			Order:         sku.Order,
+			Options:       options,
+			StockCount:    sku.StockCount,
+			PriceOverride: sku.PriceOverride,
+			ProductImages: images,
+		})
+	}
+	// SKUを表示順に並べる
+	sort.Slice(skus, func(i, j int) bool {
+		return skus[i].Order < skus[j].Order
+	})
 
 	// レビュー点数配列が空配列の場合はレビュー点数を0点にし、レビュー点数配列が存在する場合はレビュー点数配列の1つ目の点数をレビュー点数とする
 	var reviewScore int
@@ -163,6 +557,7 @@ func (pr productRepository) toEntity(product Product, withImage bool) entity.Pro
 		reviewScore = product.ReviewScores[0].Score
 	}
 
+	// 商品価格・商品セール価格・商品ステータスの適用期間は重複しないよう予約されるため、当日適用される行は1行のみ
 	return entity.Product{
 		ID:             product.ID,
 		CategoryID:     product.CategoryID,
@@ -177,5 +572,14 @@ func (pr productRepository) toEntity(product Product, withImage bool) entity.Pro
 		Version:        product.Version,
 		CreateDateTime: pr.timeUtils.TimeToJP(product.CreateDateTime),
 		ProductImages:  images,
-	}
+		OptionAxes:     axes,
+		SKUs:           skus,
+	}, nil
+}
+
+// 商品画像を表示順に並べる
+func sortProductImages(images []entity.ProductImage) {
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Order < images[j].Order
+	})
 }
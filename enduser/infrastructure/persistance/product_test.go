@@ -10,6 +10,7 @@ import (
 	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
 	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
 	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/bridge"
 	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
 	"github.com/kuritaeiji/ec_backend/util"
 	"github.com/kuritaeiji/ec_backend/util/mocks"
@@ -34,7 +35,7 @@ func TestProductRepository(t *testing.T) {
 	timeUtils := util.NewTimeUtils()
 	timeUtilsMock := mocks.NewTimeUtils(t, timeUtils)
 	suite.Run(t, &productRepositoryTestSuite{
-		productRepository: persistance.NewProductRepository(timeUtilsMock),
+		productRepository: persistance.NewProductRepository(bridge.NewLocalImageStorageAdapter(), timeUtilsMock),
 		db:                config.NewDB(),
 		timeUtilsMock:     timeUtilsMock,
 		timeUtils:         util.NewTimeUtils(),
@@ -46,6 +47,10 @@ func (suite *productRepositoryTestSuite) tearDown() {
 		new(persistance.Product),
 		new(persistance.ProductStatus),
 		new(persistance.ProductImage),
+		new(persistance.ProductImageVariant),
+		new(persistance.ProductOptionAxis),
+		new(persistance.ProductSKU),
+		new(persistance.ProductSKUOption),
 		new(persistance.ProductPrice),
 		new(persistance.ProductSalePrice),
 		new(persistance.ReviewScore),
@@ -222,9 +227,11 @@ func (suite *productRepositoryTestSuite) TestFindByIds() {
 				CreateDateTime: date1,
 
 				ProductImages: []entity.ProductImage{
-					{ID: "1", ProductID: "1", Order: 1, Path: "/path1", Image: ""},
-					{ID: "2", ProductID: "1", Order: 2, Path: "/path2", Image: ""},
+					{ID: "1", ProductID: "1", Order: 1, Path: "/path1", Image: "", Variants: []entity.ProductImageVariant{}},
+					{ID: "2", ProductID: "1", Order: 2, Path: "/path2", Image: "", Variants: []entity.ProductImageVariant{}},
 				},
+				OptionAxes: []entity.ProductOptionAxis{},
+				SKUs:       []entity.ProductSKU{},
 			}}, Err: nil},
 		},
 		{
@@ -285,9 +292,11 @@ func (suite *productRepositoryTestSuite) TestFindByIds() {
 					CreateDateTime: date1,
 
 					ProductImages: []entity.ProductImage{
-						{ID: "1", ProductID: "1", Order: 1, Path: "/path1", Image: ""},
-						{ID: "2", ProductID: "1", Order: 2, Path: "/path2", Image: ""},
+						{ID: "1", ProductID: "1", Order: 1, Path: "/path1", Image: "", Variants: []entity.ProductImageVariant{}},
+						{ID: "2", ProductID: "1", Order: 2, Path: "/path2", Image: "", Variants: []entity.ProductImageVariant{}},
 					},
+					OptionAxes: []entity.ProductOptionAxis{},
+					SKUs:       []entity.ProductSKU{},
 				},
 			}, Err: nil},
 		},
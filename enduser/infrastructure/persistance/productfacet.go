@@ -0,0 +1,163 @@
+package persistance
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+// 検索条件に一致する商品一覧の絞り込み条件ごとの商品数を返却する
+// 各絞り込み条件の商品数は、その絞り込み条件を除いた検索条件で集計する（例：カテゴリーごとの商品数はカテゴリー以外の検索条件で集計する）
+// 販売価格・レビュー点数は商品一覧と同じく当日適用される商品価格・商品セール価格・レビュー点数から算出する
+func (pr productRepository) FindFacets(db bun.IDB, ctx context.Context, condition entity.ProductCatalogCondition) (entity.ProductFacets, error) {
+	categories, err := pr.findCategoryFacets(db, ctx, condition)
+	if err != nil {
+		return entity.ProductFacets{}, err
+	}
+
+	priceBuckets, err := pr.findPriceBucketFacets(db, ctx, condition)
+	if err != nil {
+		return entity.ProductFacets{}, err
+	}
+
+	reviewScoreBands, err := pr.findReviewScoreBandFacets(db, ctx, condition)
+	if err != nil {
+		return entity.ProductFacets{}, err
+	}
+
+	inStockCount, outOfStockCount, err := pr.findStockFacets(db, ctx, condition)
+	if err != nil {
+		return entity.ProductFacets{}, err
+	}
+
+	return entity.ProductFacets{
+		Categories:       categories,
+		PriceBuckets:     priceBuckets,
+		ReviewScoreBands: reviewScoreBands,
+		InStockCount:     inStockCount,
+		OutOfStockCount:  outOfStockCount,
+	}, nil
+}
+
+// カテゴリー以外の検索条件に一致する商品のカテゴリーごとの商品数を、商品数の多い順に返却する
+func (pr productRepository) findCategoryFacets(db bun.IDB, ctx context.Context, condition entity.ProductCatalogCondition) ([]entity.CategoryFacet, error) {
+	condition.CategoryID = ""
+	catalog := pr.catalogQuery(db, condition).ColumnExpr("catalog.category_id")
+
+	var rows []struct {
+		CategoryID   string
+		CategoryName string
+		Count        int
+	}
+	err := db.NewSelect().
+		TableExpr("(?) AS facet", catalog).
+		ColumnExpr("facet.category_id").
+		ColumnExpr("category.name AS category_name").
+		ColumnExpr("COUNT(*) AS count").
+		Join("JOIN categories AS category ON category.id = facet.category_id").
+		GroupExpr("facet.category_id, category.name").
+		OrderExpr("count DESC, facet.category_id ASC").
+		Scan(ctx, &rows)
+	if err != nil {
+		return []entity.CategoryFacet{}, errors.WithStack(err)
+	}
+
+	facets := make([]entity.CategoryFacet, 0, len(rows))
+	for _, row := range rows {
+		facets = append(facets, entity.CategoryFacet{CategoryID: row.CategoryID, CategoryName: row.CategoryName, Count: row.Count})
+	}
+	return facets, nil
+}
+
+// 価格帯以外の検索条件に一致する商品の価格帯ごとの商品数を返却する
+func (pr productRepository) findPriceBucketFacets(db bun.IDB, ctx context.Context, condition entity.ProductCatalogCondition) ([]entity.PriceBucketFacet, error) {
+	condition.MinPrice = 0
+	condition.MaxPrice = 0
+	catalog := pr.catalogQuery(db, condition).ColumnExpr("catalog.effective_price")
+
+	// 販売価格が含まれる価格帯の番号を算出する
+	var bucketExpr strings.Builder
+	args := []interface{}{}
+	bucketExpr.WriteString("CASE")
+	for i, bucket := range entity.ProductPriceBuckets {
+		if bucket.MaxPrice == 0 {
+			bucketExpr.WriteString(" WHEN facet.effective_price >= ? THEN ?")
+			args = append(args, bucket.MinPrice, i)
+			continue
+		}
+		bucketExpr.WriteString(" WHEN facet.effective_price >= ? AND facet.effective_price < ? THEN ?")
+		args = append(args, bucket.MinPrice, bucket.MaxPrice, i)
+	}
+	bucketExpr.WriteString(" ELSE -1 END")
+
+	var rows []struct {
+		Bucket int
+		Count  int
+	}
+	err := db.NewSelect().
+		TableExpr("(?) AS bucket_facet", db.NewSelect().
+			TableExpr("(?) AS facet", catalog).
+			ColumnExpr(bucketExpr.String()+" AS bucket", args...)).
+		ColumnExpr("bucket_facet.bucket").
+		ColumnExpr("COUNT(*) AS count").
+		GroupExpr("bucket_facet.bucket").
+		Scan(ctx, &rows)
+	if err != nil {
+		return []entity.PriceBucketFacet{}, errors.WithStack(err)
+	}
+
+	countsByBucket := make(map[int]int, len(rows))
+	for _, row := range rows {
+		countsByBucket[row.Bucket] = row.Count
+	}
+	return entity.CreatePriceBucketFacets(countsByBucket), nil
+}
+
+// 検索条件に一致する商品のレビュー点数の下限ごとの商品数を返却する
+func (pr productRepository) findReviewScoreBandFacets(db bun.IDB, ctx context.Context, condition entity.ProductCatalogCondition) ([]entity.ReviewScoreBandFacet, error) {
+	catalog := pr.catalogQuery(db, condition).ColumnExpr("catalog.review_score")
+
+	var rows []struct {
+		ReviewScore int
+		Count       int
+	}
+	err := db.NewSelect().
+		TableExpr("(?) AS facet", catalog).
+		ColumnExpr("facet.review_score").
+		ColumnExpr("COUNT(*) AS count").
+		GroupExpr("facet.review_score").
+		Scan(ctx, &rows)
+	if err != nil {
+		return []entity.ReviewScoreBandFacet{}, errors.WithStack(err)
+	}
+
+	countsByScore := make(map[int]int, len(rows))
+	for _, row := range rows {
+		countsByScore[row.ReviewScore] = row.Count
+	}
+	return entity.CreateReviewScoreBandFacets(countsByScore), nil
+}
+
+// 在庫あり以外の検索条件に一致する商品の在庫あり・在庫なしの商品数を返却する
+func (pr productRepository) findStockFacets(db bun.IDB, ctx context.Context, condition entity.ProductCatalogCondition) (int, int, error) {
+	condition.InStockOnly = false
+	catalog := pr.catalogQuery(db, condition).ColumnExpr("catalog.stock_count")
+
+	var row struct {
+		InStockCount    int
+		OutOfStockCount int
+	}
+	err := db.NewSelect().
+		TableExpr("(?) AS facet", catalog).
+		ColumnExpr("COALESCE(SUM(CASE WHEN facet.stock_count > 0 THEN 1 ELSE 0 END), 0) AS in_stock_count").
+		ColumnExpr("COALESCE(SUM(CASE WHEN facet.stock_count > 0 THEN 0 ELSE 1 END), 0) AS out_of_stock_count").
+		Scan(ctx, &row)
+	if err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+
+	return row.InStockCount, row.OutOfStockCount, nil
+}
@@ -0,0 +1,157 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 派生画像の生成に失敗した商品画像の失敗記録テーブル
+	ProductImageVariantFailure struct {
+		bun.BaseModel `bun:"table:product_image_variant_failures"`
+
+		ProductImageID      string    `bun:",pk"`
+		AttemptCount        int       `bun:",notnull"`
+		LastError           string    `bun:",type:text,notnull"`
+		LastAttemptDateTime time.Time `bun:",notnull"`
+		DeadLetterDateTime  *time.Time
+	}
+
+	productImageRepository struct {
+		timeUtils util.TimeUtils
+	}
+)
+
+func NewProductImageRepository(timeUtils util.TimeUtils) productImageRepository {
+	return productImageRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+func (pir productImageRepository) FindWithoutVariants(db bun.IDB, ctx context.Context, afterID string, limit int) ([]entity.ProductImage, error) {
+	var images []ProductImage
+	err := db.NewSelect().
+		Model(&images).
+		Where("product_image.id > ?", afterID).
+		Where("NOT EXISTS (?)", db.NewSelect().
+			Model((*ProductImageVariant)(nil)).
+			ColumnExpr("1").
+			Where("product_image_variant.product_image_id = product_image.id")).
+		Where("NOT EXISTS (?)", db.NewSelect().
+			Model((*ProductImageVariantFailure)(nil)).
+			ColumnExpr("1").
+			Where("product_image_variant_failure.product_image_id = product_image.id").
+			Where("product_image_variant_failure.dead_letter_date_time IS NOT NULL")).
+		Order("product_image.id").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return []entity.ProductImage{}, errors.WithStack(err)
+	}
+
+	eImages := make([]entity.ProductImage, 0, len(images))
+	for _, image := range images {
+		eImages = append(eImages, entity.ProductImage{
+			ID:        image.ID,
+			ProductID: image.ProductID,
+			Order:     image.Order,
+			Path:      image.Path,
+			Variants:  []entity.ProductImageVariant{},
+		})
+	}
+	return eImages, nil
+}
+
+func (pir productImageRepository) InsertVariants(db bun.IDB, ctx context.Context, variants []entity.ProductImageVariant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	mVariants := make([]ProductImageVariant, 0, len(variants))
+	for _, variant := range variants {
+		mVariants = append(mVariants, ProductImageVariant{
+			ID:             variant.ID,
+			ProductImageID: variant.ProductImageID,
+			Width:          variant.Width,
+			Format:         string(variant.Format),
+			Path:           variant.Path,
+		})
+	}
+
+	_, err := db.NewInsert().Model(&mVariants).Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (pir productImageRepository) FindVariantFailures(db bun.IDB, ctx context.Context, productImageIDs []string) ([]entity.ProductImageVariantFailure, error) {
+	if len(productImageIDs) == 0 {
+		return []entity.ProductImageVariantFailure{}, nil
+	}
+
+	var failures []ProductImageVariantFailure
+	err := db.NewSelect().Model(&failures).Where("product_image_id IN (?)", bun.In(productImageIDs)).Scan(ctx)
+	if err != nil {
+		return []entity.ProductImageVariantFailure{}, errors.WithStack(err)
+	}
+
+	eFailures := make([]entity.ProductImageVariantFailure, 0, len(failures))
+	for _, failure := range failures {
+		var deadLetterDateTime *time.Time
+		if failure.DeadLetterDateTime != nil {
+			jp := pir.timeUtils.TimeToJP(*failure.DeadLetterDateTime)
+			deadLetterDateTime = &jp
+		}
+		eFailures = append(eFailures, entity.ProductImageVariantFailure{
+			ProductImageID:      failure.ProductImageID,
+			AttemptCount:        failure.AttemptCount,
+			LastError:           failure.LastError,
+			LastAttemptDateTime: pir.timeUtils.TimeToJP(failure.LastAttemptDateTime),
+			DeadLetterDateTime:  deadLetterDateTime,
+		})
+	}
+	return eFailures, nil
+}
+
+func (pir productImageRepository) SaveVariantFailure(db bun.IDB, ctx context.Context, failure entity.ProductImageVariantFailure) error {
+	var deadLetterDateTime *time.Time
+	if failure.DeadLetterDateTime != nil {
+		utc := pir.timeUtils.TimeToUTC(*failure.DeadLetterDateTime)
+		deadLetterDateTime = &utc
+	}
+	mFailure := ProductImageVariantFailure{
+		ProductImageID:      failure.ProductImageID,
+		AttemptCount:        failure.AttemptCount,
+		LastError:           failure.LastError,
+		LastAttemptDateTime: pir.timeUtils.TimeToUTC(failure.LastAttemptDateTime),
+		DeadLetterDateTime:  deadLetterDateTime,
+	}
+
+	_, err := db.NewInsert().
+		Model(&mFailure).
+		On("DUPLICATE KEY UPDATE").
+		Set("attempt_count = VALUES(attempt_count)").
+		Set("last_error = VALUES(last_error)").
+		Set("last_attempt_date_time = VALUES(last_attempt_date_time)").
+		Set("dead_letter_date_time = VALUES(dead_letter_date_time)").
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (pir productImageRepository) DeleteVariantFailure(db bun.IDB, ctx context.Context, productImageID string) error {
+	_, err := db.NewDelete().Model((*ProductImageVariantFailure)(nil)).Where("product_image_id = ?", productImageID).Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (pir productImageRepository) DeleteDeadLetterVariantFailures(db bun.IDB, ctx context.Context) (int, error) {
+	res, err := db.NewDelete().Model((*ProductImageVariantFailure)(nil)).Where("dead_letter_date_time IS NOT NULL").Exec(ctx)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	count, err := res.RowsAffected()
+	return int(count), errors.WithStack(err)
+}
@@ -0,0 +1,89 @@
+package persistance
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type productIntegrityRepository struct {
+	timeUtils util.TimeUtils
+}
+
+func NewProductIntegrityRepository(timeUtils util.TimeUtils) productIntegrityRepository {
+	return productIntegrityRepository{timeUtils: timeUtils}
+}
+
+// 商品集約の取得とは異なり、適用期間で絞り込まずにすべての商品ステータス・商品価格・商品セール価格を取得する
+func (pir productIntegrityRepository) FindSnapshots(db bun.IDB, ctx context.Context, afterID string, limit int) ([]entity.ProductIntegritySnapshot, error) {
+	var products []Product
+	err := db.NewSelect().
+		Model(&products).
+		Relation("Category").
+		Relation("ProductStatuses").
+		Relation("ProductPrices").
+		Relation("ProductSalePrices").
+		Relation("ProductImages").
+		Relation("ProductSKUs").
+		Where("product.id > ?", afterID).
+		Order("product.id").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return []entity.ProductIntegritySnapshot{}, errors.WithStack(err)
+	}
+
+	snapshots := make([]entity.ProductIntegritySnapshot, 0, len(products))
+	for _, product := range products {
+		snapshots = append(snapshots, pir.toEntity(product))
+	}
+	return snapshots, nil
+}
+
+func (pir productIntegrityRepository) toEntity(product Product) entity.ProductIntegritySnapshot {
+	skus := make([]entity.ProductIntegritySKU, 0, len(product.ProductSKUs))
+	for _, sku := range product.ProductSKUs {
+		skus = append(skus, entity.ProductIntegritySKU{ID: sku.ID, StockCount: sku.StockCount})
+	}
+
+	images := make([]entity.ProductIntegrityImage, 0, len(product.ProductImages))
+	for _, image := range product.ProductImages {
+		var skuID string
+		if image.SKUID != nil {
+			skuID = *image.SKUID
+		}
+		images = append(images, entity.ProductIntegrityImage{ID: image.ID, SKUID: skuID, Order: image.Order})
+	}
+
+	prices := make([]entity.ProductTimelineEntry, 0, len(product.ProductPrices))
+	for _, row := range product.ProductPrices {
+		prices = append(prices, toProductTimelineEntry(pir.timeUtils, row.ID, row.TaxInclusivePrice, row.EffectiveStartDate, row.EffectiveEndDate))
+	}
+	salePrices := make([]entity.ProductTimelineEntry, 0, len(product.ProductSalePrices))
+	for _, row := range product.ProductSalePrices {
+		salePrices = append(salePrices, toProductTimelineEntry(pir.timeUtils, row.ID, row.TaxInclusivePrice, row.EffectiveStartDate, row.EffectiveEndDate))
+	}
+	statuses := make([]entity.ProductTimelineEntry, 0, len(product.ProductStatuses))
+	for _, row := range product.ProductStatuses {
+		statuses = append(statuses, toProductTimelineEntry(pir.timeUtils, row.ID, row.Status, row.EffectiveStartDate, row.EffectiveEndDate))
+	}
+
+	// カテゴリーは外部結合で取得するため、存在しない場合はカテゴリーのIDが空文字になる
+	return entity.ProductIntegritySnapshot{
+		ProductID:      product.ID,
+		CategoryID:     product.CategoryID,
+		CategoryExists: product.Category.ID != "",
+		StockCount:     product.StockCount,
+		SKUs:           skus,
+		Images:         images,
+		Timelines: []entity.ProductTimeline{
+			entity.NewProductTimeline(product.ID, enum.ProductTimelineTypePrice, prices),
+			entity.NewProductTimeline(product.ID, enum.ProductTimelineTypeSalePrice, salePrices),
+			entity.NewProductTimeline(product.ID, enum.ProductTimelineTypeStatus, statuses),
+		},
+	}
+}
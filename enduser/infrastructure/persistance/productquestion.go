@@ -0,0 +1,252 @@
+package persistance
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 商品の質問テーブル
+	ProductQuestion struct {
+		bun.BaseModel `bun:"table:product_questions"`
+
+		ID             string    `bun:",pk"`
+		ProductID      string    `bun:",notnull"`
+		AccountID      string    `bun:",notnull"`
+		Body           string    `bun:",type:text,notnull"`
+		Status         string    `bun:",notnull"`
+		Version        int       `bun:",notnull"`
+		CreateDateTime time.Time `bun:",notnull"`
+		UpdateDateTime time.Time `bun:",notnull"`
+
+		ProductAnswers []ProductAnswer `bun:"rel:has-many,join:id=question_id"`
+	}
+
+	// 商品の質問への回答テーブル
+	ProductAnswer struct {
+		bun.BaseModel `bun:"table:product_answers"`
+
+		ID             string    `bun:",pk"`
+		QuestionID     string    `bun:",notnull"`
+		ResponderType  string    `bun:",notnull"`
+		AccountID      string    `bun:",notnull"` // ショップスタッフの回答の場合は空文字
+		Body           string    `bun:",type:text,notnull"`
+		CreateDateTime time.Time `bun:",notnull"`
+	}
+
+	// 商品の質問リポジトリの実装
+	productQuestionRepository struct {
+		timeUtils util.TimeUtils
+	}
+)
+
+func NewProductQuestionRepository(timeUtils util.TimeUtils) productQuestionRepository {
+	return productQuestionRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+func (pqr productQuestionRepository) FindByID(db bun.IDB, ctx context.Context, id string) (entity.ProductQuestion, bool, error) {
+	var question ProductQuestion
+	err := pqr.selectQuestions(db, &question).Where("product_question.id = ?", id).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ProductQuestion{}, false, nil
+		}
+
+		return entity.ProductQuestion{}, false, errors.WithStack(err)
+	}
+
+	return pqr.toEntity(question, map[string]string{}), true, nil
+}
+
+func (pqr productQuestionRepository) FindPublishedByProductID(db bun.IDB, ctx context.Context, productID string, page int, limit int) (entity.ProductQuestionPage, error) {
+	var questions []ProductQuestion
+	totalCount, err := pqr.selectQuestions(db, &questions).
+		Where("product_question.product_id = ?", productID).
+		Where("product_question.status = ?", string(enum.ProductQuestionStatusPublished)).
+		Order("product_question.create_date_time DESC", "product_question.id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		ScanAndCount(ctx)
+	if err != nil {
+		return entity.ProductQuestionPage{}, errors.WithStack(err)
+	}
+
+	items, err := pqr.toListItems(db, ctx, questions)
+	if err != nil {
+		return entity.ProductQuestionPage{}, err
+	}
+
+	return entity.ProductQuestionPage{
+		Questions:  items,
+		TotalCount: totalCount,
+		Page:       page,
+		HasNext:    page*limit < totalCount,
+	}, nil
+}
+
+func (pqr productQuestionRepository) FindByStatus(db bun.IDB, ctx context.Context, status enum.ProductQuestionStatus, limit int) ([]entity.ProductQuestionListItem, error) {
+	var questions []ProductQuestion
+	err := pqr.selectQuestions(db, &questions).
+		Where("product_question.status = ?", string(status)).
+		Order("product_question.create_date_time ASC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return []entity.ProductQuestionListItem{}, errors.WithStack(err)
+	}
+
+	return pqr.toListItems(db, ctx, questions)
+}
+
+func (pqr productQuestionRepository) Insert(db bun.IDB, ctx context.Context, question entity.ProductQuestion) error {
+	mQuestion := pqr.toModel(question)
+
+	_, err := db.NewInsert().Model(&mQuestion).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(mQuestion.ProductAnswers) > 0 {
+		_, err = db.NewInsert().Model(&mQuestion.ProductAnswers).Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+func (pqr productQuestionRepository) Update(db bun.IDB, ctx context.Context, question entity.ProductQuestion) error {
+	mQuestion := pqr.toModel(question)
+
+	//回答は変更・削除しないため、未登録の回答のみ登録する
+	if len(mQuestion.ProductAnswers) > 0 {
+		_, err := db.NewInsert().Model(&mQuestion.ProductAnswers).Ignore().Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	//質問を更新する（楽観ロックする）
+	mQuestion.Version = mQuestion.Version + 1
+	res, err := db.NewUpdate().Model(&mQuestion).WherePK().Where("version = ?", mQuestion.Version-1).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if count != 1 {
+		return ErrOptimisticLocking
+	}
+
+	return nil
+}
+
+func (pqr productQuestionRepository) selectQuestions(db bun.IDB, model interface{}) *bun.SelectQuery {
+	return db.NewSelect().Model(model).
+		Relation("ProductAnswers", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Order("product_answer.create_date_time", "product_answer.id")
+		})
+}
+
+// 質問配列に質問者名と回答したお客様の回答者名を付加する
+// 退会済みのアカウントの質問・回答も表示するため、論理削除されたアカウントからもレビュー投稿者名を取得する
+func (pqr productQuestionRepository) toListItems(db bun.IDB, ctx context.Context, questions []ProductQuestion) ([]entity.ProductQuestionListItem, error) {
+	if len(questions) == 0 {
+		return []entity.ProductQuestionListItem{}, nil
+	}
+
+	accountIDs := []string{}
+	for _, question := range questions {
+		accountIDs = append(accountIDs, question.AccountID)
+		for _, answer := range question.ProductAnswers {
+			if answer.AccountID != "" {
+				accountIDs = append(accountIDs, answer.AccountID)
+			}
+		}
+	}
+	var accounts []Account
+	err := db.NewSelect().Model(&accounts).Column("id", "review_nickname").Where("id IN (?)", bun.In(accountIDs)).WhereAllWithDeleted().Scan(ctx)
+	if err != nil {
+		return []entity.ProductQuestionListItem{}, errors.WithStack(err)
+	}
+	nicknames := make(map[string]string, len(accounts))
+	for _, account := range accounts {
+		nicknames[account.ID] = account.ReviewNickname
+	}
+
+	items := make([]entity.ProductQuestionListItem, 0, len(questions))
+	for _, question := range questions {
+		items = append(items, entity.ProductQuestionListItem{
+			ProductQuestion: pqr.toEntity(question, nicknames),
+			ReviewNickname:  nicknames[question.AccountID],
+		})
+	}
+	return items, nil
+}
+
+func (pqr productQuestionRepository) toModel(question entity.ProductQuestion) ProductQuestion {
+	answers := make([]ProductAnswer, 0, len(question.Answers))
+	for _, answer := range question.Answers {
+		answers = append(answers, ProductAnswer{
+			ID:             answer.ID,
+			QuestionID:     answer.QuestionID,
+			ResponderType:  string(answer.ResponderType),
+			AccountID:      answer.AccountID,
+			Body:           answer.Body,
+			CreateDateTime: pqr.timeUtils.TimeToUTC(answer.CreateDateTime),
+		})
+	}
+
+	return ProductQuestion{
+		ID:             question.ID,
+		ProductID:      question.ProductID,
+		AccountID:      question.AccountID,
+		Body:           question.Body,
+		Status:         string(question.Status),
+		Version:        question.Version,
+		CreateDateTime: pqr.timeUtils.TimeToUTC(question.CreateDateTime),
+		UpdateDateTime: pqr.timeUtils.TimeToUTC(question.UpdateDateTime),
+		ProductAnswers: answers,
+	}
+}
+
+// 引数nicknamesはアカウントIDとレビュー投稿者名のマップ（回答者名を取得しない場合は空のマップ）
+func (pqr productQuestionRepository) toEntity(question ProductQuestion, nicknames map[string]string) entity.ProductQuestion {
+	answers := make([]entity.ProductAnswer, 0, len(question.ProductAnswers))
+	for _, answer := range question.ProductAnswers {
+		answers = append(answers, entity.ProductAnswer{
+			ID:                answer.ID,
+			QuestionID:        answer.QuestionID,
+			ResponderType:     enum.ProductAnswerResponderType(answer.ResponderType),
+			AccountID:         answer.AccountID,
+			ResponderNickname: nicknames[answer.AccountID],
+			Body:              answer.Body,
+			CreateDateTime:    pqr.timeUtils.TimeToJP(answer.CreateDateTime),
+		})
+	}
+
+	return entity.ProductQuestion{
+		ID:             question.ID,
+		ProductID:      question.ProductID,
+		AccountID:      question.AccountID,
+		Body:           question.Body,
+		Status:         enum.ProductQuestionStatus(question.Status),
+		Version:        question.Version,
+		CreateDateTime: pqr.timeUtils.TimeToJP(question.CreateDateTime),
+		UpdateDateTime: pqr.timeUtils.TimeToJP(question.UpdateDateTime),
+		Answers:        answers,
+	}
+}
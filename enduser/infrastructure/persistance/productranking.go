@@ -0,0 +1,124 @@
+package persistance
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 商品ランキングのスナップショットテーブル（集計日・集計期間ごとにバッチ処理で置き換える）
+	ProductRanking struct {
+		bun.BaseModel `bun:"table:product_rankings"`
+
+		ID         string    `bun:",pk"`
+		Date       time.Time `bun:",notnull,type:date"`
+		Period     string    `bun:",notnull"`
+		CategoryID string    `bun:",notnull"` // サイト全体のランキングの場合は空文字
+		ProductID  string    `bun:",notnull"`
+		Rank       int       `bun:",notnull"`
+		SalesCount int       `bun:",notnull"`
+		ViewCount  int       `bun:",notnull"`
+		Score      int       `bun:",notnull"`
+	}
+
+	productRankingRepository struct {
+		timeUtils util.TimeUtils
+	}
+)
+
+func NewProductRankingRepository(timeUtils util.TimeUtils) productRankingRepository {
+	return productRankingRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+func (prr productRankingRepository) FindLatestDate(db bun.IDB, ctx context.Context, period enum.RankingPeriod, date time.Time) (time.Time, bool, error) {
+	var ranking ProductRanking
+	err := db.NewSelect().
+		Model(&ranking).
+		Column("date").
+		Where("period = ?", period).
+		Where("date <= ?", toDate(date)).
+		Order("date DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, false, nil
+		}
+
+		return time.Time{}, false, errors.WithStack(err)
+	}
+
+	return prr.timeUtils.DateJP(ranking.Date.Year(), ranking.Date.Month(), ranking.Date.Day()), true, nil
+}
+
+func (prr productRankingRepository) FindByDate(db bun.IDB, ctx context.Context, date time.Time, period enum.RankingPeriod, categoryID string, limit int) ([]entity.ProductRanking, error) {
+	var mRankings []ProductRanking
+	err := db.NewSelect().
+		Model(&mRankings).
+		Where("date = ?", toDate(date)).
+		Where("period = ?", period).
+		Where("category_id = ?", categoryID).
+		Order("rank ASC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return []entity.ProductRanking{}, errors.WithStack(err)
+	}
+
+	rankings := make([]entity.ProductRanking, 0, len(mRankings))
+	for _, ranking := range mRankings {
+		rankings = append(rankings, entity.ProductRanking{
+			ID:         ranking.ID,
+			Date:       prr.timeUtils.DateJP(ranking.Date.Year(), ranking.Date.Month(), ranking.Date.Day()),
+			Period:     enum.RankingPeriod(ranking.Period),
+			CategoryID: ranking.CategoryID,
+			ProductID:  ranking.ProductID,
+			Rank:       ranking.Rank,
+			SalesCount: ranking.SalesCount,
+			ViewCount:  ranking.ViewCount,
+			Score:      ranking.Score,
+		})
+	}
+	return rankings, nil
+}
+
+// 同じ集計日で何度実行しても同じ結果になるよう、集計日・集計期間のランキングを置き換える
+func (prr productRankingRepository) ReplaceByDate(db bun.IDB, ctx context.Context, date time.Time, period enum.RankingPeriod, rankings []entity.ProductRanking) error {
+	_, err := db.NewDelete().Model((*ProductRanking)(nil)).Where("date = ?", toDate(date)).Where("period = ?", period).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	mRankings := make([]ProductRanking, 0, len(rankings))
+	for _, ranking := range rankings {
+		mRankings = append(mRankings, ProductRanking{
+			ID:         ranking.ID,
+			Date:       toDate(ranking.Date),
+			Period:     string(ranking.Period),
+			CategoryID: ranking.CategoryID,
+			ProductID:  ranking.ProductID,
+			Rank:       ranking.Rank,
+			SalesCount: ranking.SalesCount,
+			ViewCount:  ranking.ViewCount,
+			Score:      ranking.Score,
+		})
+	}
+
+	for start := 0; start < len(mRankings); start += bulkInsertChunkSize {
+		chunk := mRankings[start:min(start+bulkInsertChunkSize, len(mRankings))]
+		_, err = db.NewInsert().Model(&chunk).Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
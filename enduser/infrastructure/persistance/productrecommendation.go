@@ -0,0 +1,115 @@
+package persistance
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 一緒に購入されている商品テーブル（バッチ処理で全件を置き換える）
+	ProductRecommendation struct {
+		bun.BaseModel `bun:"table:product_recommendations"`
+
+		ProductID            string `bun:",pk"`
+		RecommendedProductID string `bun:",pk"`
+		Rank                 int    `bun:",notnull"`
+		CoPurchaseCount      int    `bun:",notnull"`
+	}
+
+	// カテゴリーごとの売れ筋商品テーブル（バッチ処理で全件を置き換える）
+	CategoryBestseller struct {
+		bun.BaseModel `bun:"table:category_bestsellers"`
+
+		CategoryID string `bun:",pk"`
+		ProductID  string `bun:",pk"`
+		Rank       int    `bun:",notnull"`
+		SalesCount int    `bun:",notnull"`
+	}
+
+	productRecommendationRepository struct{}
+)
+
+const bulkInsertChunkSize = 1000 // 1回のINSERT文で登録する最大行数
+
+func NewProductRecommendationRepository() productRecommendationRepository {
+	return productRecommendationRepository{}
+}
+
+func (prr productRecommendationRepository) FindRecommendedProductIDs(db bun.IDB, ctx context.Context, productID string) ([]string, error) {
+	productIDs := []string{}
+	err := db.NewSelect().
+		Model((*ProductRecommendation)(nil)).
+		Column("recommended_product_id").
+		Where("product_id = ?", productID).
+		Order("rank ASC").
+		Scan(ctx, &productIDs)
+	return productIDs, errors.WithStack(err)
+}
+
+func (prr productRecommendationRepository) FindCategoryBestsellerIDs(db bun.IDB, ctx context.Context, categoryID string) ([]string, error) {
+	productIDs := []string{}
+	err := db.NewSelect().
+		Model((*CategoryBestseller)(nil)).
+		Column("product_id").
+		Where("category_id = ?", categoryID).
+		Order("rank ASC").
+		Scan(ctx, &productIDs)
+	return productIDs, errors.WithStack(err)
+}
+
+// 同じ注文履歴で何度実行しても同じ結果になるよう、一緒に購入されている商品を全件置き換える
+func (prr productRecommendationRepository) ReplaceAll(db bun.IDB, ctx context.Context, recommendations []entity.ProductRecommendation) error {
+	_, err := db.NewDelete().Model((*ProductRecommendation)(nil)).Where("1 = 1").Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	mRecommendations := make([]ProductRecommendation, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		mRecommendations = append(mRecommendations, ProductRecommendation{
+			ProductID:            recommendation.ProductID,
+			RecommendedProductID: recommendation.RecommendedProductID,
+			Rank:                 recommendation.Rank,
+			CoPurchaseCount:      recommendation.CoPurchaseCount,
+		})
+	}
+
+	for start := 0; start < len(mRecommendations); start += bulkInsertChunkSize {
+		chunk := mRecommendations[start:min(start+bulkInsertChunkSize, len(mRecommendations))]
+		_, err = db.NewInsert().Model(&chunk).Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// 同じ注文履歴で何度実行しても同じ結果になるよう、カテゴリーごとの売れ筋商品を全件置き換える
+func (prr productRecommendationRepository) ReplaceAllCategoryBestsellers(db bun.IDB, ctx context.Context, bestsellers []entity.CategoryBestseller) error {
+	_, err := db.NewDelete().Model((*CategoryBestseller)(nil)).Where("1 = 1").Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	mBestsellers := make([]CategoryBestseller, 0, len(bestsellers))
+	for _, bestseller := range bestsellers {
+		mBestsellers = append(mBestsellers, CategoryBestseller{
+			CategoryID: bestseller.CategoryID,
+			ProductID:  bestseller.ProductID,
+			Rank:       bestseller.Rank,
+			SalesCount: bestseller.SalesCount,
+		})
+	}
+
+	for start := 0; start < len(mBestsellers); start += bulkInsertChunkSize {
+		chunk := mBestsellers[start:min(start+bulkInsertChunkSize, len(mBestsellers))]
+		_, err = db.NewInsert().Model(&chunk).Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
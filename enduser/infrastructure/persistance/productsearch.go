@@ -0,0 +1,140 @@
+package persistance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 商品検索ドキュメントテーブル
+	// 検索用に正規化した商品名と、商品名・商品説明を連結した本文を保持し、ngramパーサーのFULLTEXTインデックスを作成する
+	ProductSearchDocument struct {
+		bun.BaseModel `bun:"table:product_search_documents"`
+
+		ProductID string `bun:",pk"`
+		Name      string `bun:",type:text,notnull"`
+		Content   string `bun:",type:text,notnull"`
+	}
+
+	// MySQLのFULLTEXTインデックスによる商品検索リポジトリの実装
+	productSearchRepository struct{}
+)
+
+const productSearchNameWeight = 2 // 商品名に一致した場合の関連度の重み
+
+func NewProductSearchRepository() productSearchRepository {
+	return productSearchRepository{}
+}
+
+func (psr productSearchRepository) Save(db bun.IDB, ctx context.Context, document entity.ProductSearchDocument) error {
+	mDocument := psr.toModel(document)
+	_, err := db.NewInsert().
+		Model(&mDocument).
+		On("DUPLICATE KEY UPDATE").
+		Set("name = VALUES(name)").
+		Set("content = VALUES(content)").
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (psr productSearchRepository) Search(db bun.IDB, ctx context.Context, keyword string, limit int) ([]entity.ProductSearchHit, error) {
+	fullTextTerms, shortTerms := psr.splitTerms(util.TextUtils.SplitSearchTerms(keyword))
+	against := psr.toBooleanQuery(fullTextTerms)
+	if against == "" && len(shortTerms) == 0 {
+		return []entity.ProductSearchHit{}, nil
+	}
+
+	query := db.NewSelect().
+		Model((*ProductSearchDocument)(nil)).
+		ColumnExpr("product_id")
+
+	// 関連度はFULLTEXTインデックスの関連度に、1文字の検索語が商品名に含まれる場合の重みを加算する
+	scoreExprs := []string{}
+	scoreArgs := []interface{}{}
+	if against != "" {
+		scoreExprs = append(scoreExprs, "MATCH(content) AGAINST(? IN BOOLEAN MODE) + MATCH(name) AGAINST(? IN BOOLEAN MODE) * ?")
+		scoreArgs = append(scoreArgs, against, against, productSearchNameWeight)
+		query = query.Where("MATCH(content) AGAINST(? IN BOOLEAN MODE)", against)
+	}
+	for _, term := range shortTerms {
+		pattern := "%" + psr.escapeLike(term) + "%"
+		scoreExprs = append(scoreExprs, "(name LIKE ?) * ?")
+		scoreArgs = append(scoreArgs, pattern, productSearchNameWeight)
+		query = query.Where("content LIKE ?", pattern)
+	}
+
+	var hits []struct {
+		ProductID string
+		Score     float64
+	}
+	err := query.
+		ColumnExpr(strings.Join(scoreExprs, " + ")+" AS score", scoreArgs...).
+		OrderExpr("score DESC, product_id ASC").
+		Limit(limit).
+		Scan(ctx, &hits)
+	if err != nil {
+		return []entity.ProductSearchHit{}, errors.WithStack(err)
+	}
+
+	eHits := make([]entity.ProductSearchHit, 0, len(hits))
+	for _, hit := range hits {
+		eHits = append(eHits, entity.ProductSearchHit{ProductID: hit.ProductID, Score: hit.Score})
+	}
+	return eHits, nil
+}
+
+// 検索語をFULLTEXTインデックスで検索する検索語と、LIKEで検索する1文字の検索語に分割する
+// ngramパーサーのトークンは2文字（ngram_token_size=2）のため、1文字の検索語はFULLTEXTインデックスに一致しない
+func (psr productSearchRepository) splitTerms(terms []string) ([]string, []string) {
+	fullTextTerms := []string{}
+	shortTerms := []string{}
+	for _, term := range terms {
+		term = strings.ReplaceAll(term, `"`, "")
+		switch utf8.RuneCountInString(term) {
+		case 0:
+			continue
+		case 1:
+			shortTerms = append(shortTerms, term)
+		default:
+			fullTextTerms = append(fullTextTerms, term)
+		}
+	}
+
+	return fullTextTerms, shortTerms
+}
+
+// LIKEのワイルドカード（%・_）とエスケープ文字をエスケープする
+func (psr productSearchRepository) escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
+// 検索語をすべて含む文書に一致するBOOLEAN MODEの検索式を返却する
+// 検索語はフレーズとして扱い、検索式の演算子として解釈されないようにダブルクォーテーションを取り除く
+func (psr productSearchRepository) toBooleanQuery(terms []string) string {
+	phrases := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.ReplaceAll(term, `"`, "")
+		if term == "" {
+			continue
+		}
+		phrases = append(phrases, fmt.Sprintf(`+"%s"`, term))
+	}
+
+	return strings.Join(phrases, " ")
+}
+
+func (psr productSearchRepository) toModel(document entity.ProductSearchDocument) ProductSearchDocument {
+	name := util.TextUtils.NormalizeForSearch(document.Name)
+	return ProductSearchDocument{
+		ProductID: document.ProductID,
+		Name:      name,
+		Content:   name + "\n" + util.TextUtils.NormalizeForSearch(document.Description),
+	}
+}
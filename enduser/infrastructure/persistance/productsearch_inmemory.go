@@ -0,0 +1,157 @@
+package persistance
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// プロセス内の転置インデックスによる商品検索リポジトリの実装（テスト用）
+	// 正規化した本文の1文字・2文字のN-gramから商品IDへの転置インデックスで候補を絞り込み、検索語を含むか検証する
+	inMemoryProductSearchRepository struct {
+		mu        *sync.RWMutex
+		documents map[string]inMemoryProductSearchDocument
+		index     map[string]map[string]struct{}
+	}
+
+	inMemoryProductSearchDocument struct {
+		name    string
+		content string
+	}
+)
+
+func NewInMemoryProductSearchRepository() inMemoryProductSearchRepository {
+	return inMemoryProductSearchRepository{
+		mu:        &sync.RWMutex{},
+		documents: map[string]inMemoryProductSearchDocument{},
+		index:     map[string]map[string]struct{}{},
+	}
+}
+
+func (r inMemoryProductSearchRepository) Save(db bun.IDB, ctx context.Context, document entity.ProductSearchDocument) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 登録済みのドキュメントを転置インデックスから削除する
+	if old, ok := r.documents[document.ProductID]; ok {
+		for _, gram := range ngrams(old.content) {
+			delete(r.index[gram], document.ProductID)
+		}
+	}
+
+	name := util.TextUtils.NormalizeForSearch(document.Name)
+	doc := inMemoryProductSearchDocument{
+		name:    name,
+		content: name + "\n" + util.TextUtils.NormalizeForSearch(document.Description),
+	}
+	r.documents[document.ProductID] = doc
+
+	for _, gram := range ngrams(doc.content) {
+		if _, ok := r.index[gram]; !ok {
+			r.index[gram] = map[string]struct{}{}
+		}
+		r.index[gram][document.ProductID] = struct{}{}
+	}
+
+	return nil
+}
+
+func (r inMemoryProductSearchRepository) Search(db bun.IDB, ctx context.Context, keyword string, limit int) ([]entity.ProductSearchHit, error) {
+	terms := util.TextUtils.SplitSearchTerms(keyword)
+	if len(terms) == 0 {
+		return []entity.ProductSearchHit{}, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hits := []entity.ProductSearchHit{}
+	for productID := range r.candidates(terms) {
+		doc := r.documents[productID]
+
+		var score float64
+		matched := true
+		for _, term := range terms {
+			count := strings.Count(doc.content, term)
+			if count == 0 {
+				matched = false
+				break
+			}
+			score += float64(count + strings.Count(doc.name, term)*productSearchNameWeight)
+		}
+
+		if matched {
+			hits = append(hits, entity.ProductSearchHit{ProductID: productID, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ProductID < hits[j].ProductID
+	})
+
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// すべての検索語のN-gramを含む商品IDの集合を返却する
+func (r inMemoryProductSearchRepository) candidates(terms []string) map[string]struct{} {
+	var result map[string]struct{}
+	for _, term := range terms {
+		for _, gram := range termNgrams(term) {
+			postings := r.index[gram]
+			if result == nil {
+				result = make(map[string]struct{}, len(postings))
+				for productID := range postings {
+					result[productID] = struct{}{}
+				}
+				continue
+			}
+
+			for productID := range result {
+				if _, ok := postings[productID]; !ok {
+					delete(result, productID)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// 本文に含まれる1文字・2文字のN-gramを返却する
+func ngrams(s string) []string {
+	runes := []rune(s)
+	grams := make([]string, 0, len(runes)*2)
+	for i := range runes {
+		grams = append(grams, string(runes[i]))
+		if i+1 < len(runes) {
+			grams = append(grams, string(runes[i:i+2]))
+		}
+	}
+	return grams
+}
+
+// 検索語の候補の絞り込みに使用するN-gramを返却する（1文字の検索語は1文字のN-gram、それ以外は2文字のN-gram）
+func termNgrams(term string) []string {
+	runes := []rune(term)
+	if len(runes) == 1 {
+		return []string{term}
+	}
+
+	grams := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		grams = append(grams, string(runes[i:i+2]))
+	}
+	return grams
+}
@@ -0,0 +1,67 @@
+package persistance_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryProductSearchRepository(t *testing.T) {
+	// given（前提条件）
+	repository := persistance.NewInMemoryProductSearchRepository()
+	documents := []entity.ProductSearchDocument{
+		{ProductID: "1", Name: "Ｔシャツ メンズ", Description: "綿100%のシンプルなティーシャツ"},
+		{ProductID: "2", Name: "ポロシャツ", Description: "メンズ向けのＴシャツ素材のポロシャツ"},
+		{ProductID: "3", Name: "ﾃﾞﾆﾑﾊﾟﾝﾂ", Description: "ストレッチデニム"},
+	}
+	for _, document := range documents {
+		err := repository.Save(nil, context.Background(), document)
+		assert.Nil(t, err)
+	}
+
+	tests := []struct {
+		Name        string
+		Keyword     string
+		ExpectedIDs []string
+	}{
+		{Name: "全角・半角を区別せず、商品名に一致する商品を関連度の高い順に返却する", Keyword: "tシャツ", ExpectedIDs: []string{"1", "2"}},
+		{Name: "カタカナ・ひらがなを区別しない", Keyword: "でにむ", ExpectedIDs: []string{"3"}},
+		{Name: "半角カタカナで登録した商品に全角カタカナで一致する", Keyword: "デニムパンツ", ExpectedIDs: []string{"3"}},
+		{Name: "空白区切りの検索語をすべて含む商品に一致する", Keyword: "メンズ　ポロ", ExpectedIDs: []string{"2"}},
+		{Name: "一致する商品が存在しない場合、空配列を返却する", Keyword: "スニーカー", ExpectedIDs: []string{}},
+		{Name: "キーワードが空白のみの場合、空配列を返却する", Keyword: "　", ExpectedIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// when（操作）
+			hits, err := repository.Search(nil, context.Background(), tt.Keyword, 10)
+
+			// then（期待する結果）
+			assert.Nil(t, err)
+			ids := make([]string, 0, len(hits))
+			for _, hit := range hits {
+				ids = append(ids, hit.ProductID)
+			}
+			assert.Equal(t, tt.ExpectedIDs, ids)
+		})
+	}
+}
+
+func TestInMemoryProductSearchRepositoryUpdate(t *testing.T) {
+	// given（前提条件）
+	repository := persistance.NewInMemoryProductSearchRepository()
+	_ = repository.Save(nil, context.Background(), entity.ProductSearchDocument{ProductID: "1", Name: "スニーカー"})
+
+	// when（操作）同じ商品のドキュメントを更新する
+	_ = repository.Save(nil, context.Background(), entity.ProductSearchDocument{ProductID: "1", Name: "サンダル"})
+
+	// then（期待する結果）更新前の内容では一致しない
+	hits, _ := repository.Search(nil, context.Background(), "スニーカー", 10)
+	assert.Equal(t, 0, len(hits))
+	hits, _ = repository.Search(nil, context.Background(), "サンダル", 10)
+	assert.Equal(t, 1, len(hits))
+}
@@ -0,0 +1,165 @@
+package persistance
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type productStockRepository struct{}
+
+func NewProductStockRepository() productStockRepository {
+	return productStockRepository{}
+}
+
+// 在庫数の確認と差し引きを「在庫数 >= 差し引く数」を条件とした1つのUPDATE文で行い、更新された行がない場合は在庫不足とする
+// SKUを持つ商品はSKUの在庫数を差し引いてから、SKUの在庫数の合計である商品の在庫数を差し引く
+// 商品・SKUのバージョンを更新し、在庫数を読み込んで更新する処理（楽観ロック）と競合した場合に検知できるようにする
+func (psr productStockRepository) Decrease(db bun.IDB, ctx context.Context, productID string, skuID string, count int) (bool, error) {
+	if skuID != "" {
+		res, err := db.NewUpdate().
+			Model((*ProductSKU)(nil)).
+			Set("stock_count = stock_count - ?", count).
+			Set("version = version + 1").
+			Where("id = ?", skuID).
+			Where("product_id = ?", productID).
+			Where("stock_count >= ?", count).
+			Exec(ctx)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		if affected != 1 {
+			return false, nil
+		}
+	}
+
+	res, err := db.NewUpdate().
+		Model((*Product)(nil)).
+		Set("stock_count = stock_count - ?", count).
+		Set("version = version + 1").
+		Where("id = ?", productID).
+		Where("stock_count >= ?", count).
+		Exec(ctx)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	if affected != 1 {
+		if skuID != "" {
+			// SKUの在庫数は足りているのに商品の在庫数が足りない場合は、商品の在庫数がSKUの在庫数の合計と一致していない
+			return false, errors.Errorf("商品（%s）の在庫数がSKUの在庫数の合計と一致していません", productID)
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (psr productStockRepository) Increase(db bun.IDB, ctx context.Context, productID string, skuID string, count int) error {
+	if skuID != "" {
+		_, err := db.NewUpdate().
+			Model((*ProductSKU)(nil)).
+			Set("stock_count = stock_count + ?", count).
+			Set("version = version + 1").
+			Where("id = ?", skuID).
+			Where("product_id = ?", productID).
+			Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	_, err := db.NewUpdate().
+		Model((*Product)(nil)).
+		Set("stock_count = stock_count + ?", count).
+		Set("version = version + 1").
+		Where("id = ?", productID).
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (psr productStockRepository) HasSKUs(db bun.IDB, ctx context.Context, productID string) (bool, error) {
+	exists, err := db.NewSelect().Model((*ProductSKU)(nil)).Where("product_id = ?", productID).Exists(ctx)
+	return exists, errors.WithStack(err)
+}
+
+// 主キーの昇順に走査してロックを取得するため、複数のトランザクションが同じ順序で商品の行をロックする
+func (psr productStockRepository) LockProductsForUpdate(db bun.IDB, ctx context.Context, productIDs []string) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	var ids []string
+	err := db.NewSelect().
+		Model((*Product)(nil)).
+		Column("id").
+		Where("id IN (?)", bun.In(productIDs)).
+		Order("id").
+		For("UPDATE").
+		Scan(ctx, &ids)
+	return errors.WithStack(err)
+}
+
+// SKUの在庫数を変更する際も商品の在庫数（SKUの在庫数の合計）を更新するため、商品の行→SKUの行の順にロックする
+// SKUの行を先にロックすると、同じ商品の異なるSKUをロックしたトランザクション同士が商品の行の更新で互いを待ちデッドロックする
+func (psr productStockRepository) FindStockCountForUpdate(db bun.IDB, ctx context.Context, productID string, skuID string) (int, bool, error) {
+	var stockCount int
+	var query *bun.SelectQuery
+	if skuID != "" {
+		err := psr.LockProductsForUpdate(db, ctx, []string{productID})
+		if err != nil {
+			return 0, false, err
+		}
+		query = db.NewSelect().Model((*ProductSKU)(nil)).Where("id = ?", skuID).Where("product_id = ?", productID)
+	} else {
+		query = db.NewSelect().Model((*Product)(nil)).Where("id = ?", productID)
+	}
+
+	err := query.Column("stock_count").For("UPDATE").Scan(ctx, &stockCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, errors.WithStack(err)
+	}
+	return stockCount, true, nil
+}
+
+// SKUを持つ商品はSKUごとの在庫数を、バリエーションを持たない商品は商品の在庫数を取得する
+func (psr productStockRepository) FindStockLevels(db bun.IDB, ctx context.Context) ([]entity.StockLevel, error) {
+	var skus []ProductSKU
+	err := db.NewSelect().Model(&skus).Column("id", "product_id", "stock_count").Order("product_id", "id").Scan(ctx)
+	if err != nil {
+		return []entity.StockLevel{}, errors.WithStack(err)
+	}
+
+	var products []Product
+	err = db.NewSelect().
+		Model(&products).
+		Column("id", "stock_count").
+		Where("NOT EXISTS (?)", db.NewSelect().Model((*ProductSKU)(nil)).ColumnExpr("1").Where("product_sku.product_id = product.id")).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return []entity.StockLevel{}, errors.WithStack(err)
+	}
+
+	levels := make([]entity.StockLevel, 0, len(skus)+len(products))
+	for _, product := range products {
+		levels = append(levels, entity.StockLevel{ProductID: product.ID, StockCount: product.StockCount})
+	}
+	for _, sku := range skus {
+		levels = append(levels, entity.StockLevel{ProductID: sku.ProductID, SKUID: sku.ID, StockCount: sku.StockCount})
+	}
+	return levels, nil
+}
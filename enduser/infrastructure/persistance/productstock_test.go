@@ -0,0 +1,342 @@
+package persistance_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/config"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/subscriber"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/uptrace/bun"
+)
+
+// 同時に購入手続きが行われても在庫数を超えて引き当てられないことを確認する負荷テスト
+type productStockRepositoryTestSuite struct {
+	suite.Suite
+	productStockRepository        repository.ProductStockRepository
+	stockReservationDomainService service.StockReservationDomainService
+	stockDomainService            service.StockDomainService
+	db                            *bun.DB
+	timeUtils                     util.TimeUtils
+}
+
+const concurrentCheckoutCount = 100 // 同時に実行する購入手続きの数
+
+func TestProductStockRepository(t *testing.T) {
+	err := config.SetupEnv()
+	if err != nil {
+		assert.FailNow(t, fmt.Sprintf("環境変数設定時にエラーが発生しました。\n%+v", err))
+	}
+	timeUtils := util.NewTimeUtils()
+	productStockRepository := persistance.NewProductStockRepository()
+	publisher := share.NewDomainEventPublisher()
+	requestBackInStockNotificationSubscriber := subscriber.NewRequestBackInStockNotificationSubscriber(persistance.NewBackInStockSubscriptionRepository(timeUtils), timeUtils)
+	publisher.Subscribe(requestBackInStockNotificationSubscriber.TargetEvents(), requestBackInStockNotificationSubscriber)
+	stockDomainService := service.NewStockService(productStockRepository, persistance.NewStockMovementRepository(timeUtils), publisher, timeUtils)
+	suite.Run(t, &productStockRepositoryTestSuite{
+		productStockRepository:        productStockRepository,
+		stockReservationDomainService: service.NewStockReservationService(persistance.NewStockReservationRepository(timeUtils), stockDomainService, timeUtils),
+		stockDomainService:            stockDomainService,
+		db:                            config.NewDB(),
+		timeUtils:                     timeUtils,
+	})
+}
+
+func (suite *productStockRepositoryTestSuite) tearDown() {
+	tables := []any{
+		new(persistance.Product),
+		new(persistance.ProductSKU),
+		new(persistance.StockReservation),
+		new(persistance.StockReservationItem),
+		new(persistance.StockMovement),
+	}
+	for _, table := range tables {
+		_, err := suite.db.NewTruncateTable().Model(table).Exec(context.Background())
+		if err != nil {
+			suite.FailNow(fmt.Sprintf("テーブルデータ（%v）削除時に失敗", table))
+		}
+	}
+}
+
+func (suite *productStockRepositoryTestSuite) insertProduct(product persistance.Product) {
+	_, err := suite.db.NewInsert().Model(&product).Exec(context.Background())
+	if err != nil {
+		suite.FailNow(fmt.Sprintf("商品作成時にエラー発生\n%+v", errors.WithStack(err)))
+	}
+
+	for _, sku := range product.ProductSKUs {
+		_, err := suite.db.NewInsert().Model(&sku).Exec(context.Background())
+		if err != nil {
+			suite.FailNow(fmt.Sprintf("SKU作成時にエラー発生\n%+v", errors.WithStack(err)))
+		}
+	}
+}
+
+func (suite *productStockRepositoryTestSuite) findStockCounts(productID string, skuID string) (int, int) {
+	var product persistance.Product
+	err := suite.db.NewSelect().Model(&product).Where("id = ?", productID).Scan(context.Background())
+	if err != nil {
+		suite.FailNow(fmt.Sprintf("商品取得時にエラー発生\n%+v", errors.WithStack(err)))
+	}
+	if skuID == "" {
+		return product.StockCount, 0
+	}
+
+	var sku persistance.ProductSKU
+	err = suite.db.NewSelect().Model(&sku).Where("id = ?", skuID).Scan(context.Background())
+	if err != nil {
+		suite.FailNow(fmt.Sprintf("SKU取得時にエラー発生\n%+v", errors.WithStack(err)))
+	}
+	return product.StockCount, sku.StockCount
+}
+
+func (suite *productStockRepositoryTestSuite) newProduct(productID string, stockCount int, skus []persistance.ProductSKU) persistance.Product {
+	return persistance.Product{
+		ID:                   productID,
+		CategoryID:           "1",
+		Name:                 "商品名",
+		Description:          "商品説明",
+		StockCount:           stockCount,
+		Version:              1,
+		CreateDateTime:       suite.timeUtils.TimeToUTC(suite.timeUtils.NowJP()),
+		CreateStaffAccountID: "1",
+		ProductSKUs:          skus,
+	}
+}
+
+func (suite *productStockRepositoryTestSuite) TestDecreaseConcurrently() {
+	defer suite.tearDown()
+
+	// given（前提条件）在庫数10のバリエーションを持たない商品
+	stockCount := 10
+	suite.insertProduct(suite.newProduct("1", stockCount, nil))
+
+	// when（操作）同時に1個ずつ在庫を差し引く
+	var succeeded atomic.Int32
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrentCheckoutCount)
+	for i := 0; i < concurrentCheckoutCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := suite.db.RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+				ok, err := suite.productStockRepository.Decrease(tx, ctx, "1", "", 1)
+				if ok {
+					succeeded.Add(1)
+				}
+				return err
+			})
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// then（期待する結果）在庫数分のみ差し引かれ、在庫数は0になる
+	for err := range errs {
+		suite.FailNow(fmt.Sprintf("在庫差し引き時にエラー発生\n%+v", err))
+	}
+	suite.Equal(int32(stockCount), succeeded.Load())
+	productStockCount, _ := suite.findStockCounts("1", "")
+	suite.Equal(0, productStockCount)
+}
+
+func (suite *productStockRepositoryTestSuite) TestReserveConcurrently() {
+	defer suite.tearDown()
+
+	// given（前提条件）SKU（在庫数5）を持つ商品
+	stockCount := 5
+	suite.insertProduct(suite.newProduct("1", stockCount, []persistance.ProductSKU{
+		{ID: "sku1", ProductID: "1", Code: "SKU-1", Order: 1, StockCount: stockCount, Version: 1},
+	}))
+	// 購入手続きの開始時点ではすべてのアカウントが在庫ありの商品を取得している
+	products := []entity.Product{{
+		ID:         "1",
+		Name:       "商品名",
+		Status:     enum.OnSale,
+		StockCount: stockCount,
+		SKUs:       []entity.ProductSKU{{ID: "sku1", ProductID: "1", StockCount: stockCount}},
+	}}
+
+	// when（操作）同時に購入手続きを開始する
+	var succeeded, shortage atomic.Int32
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrentCheckoutCount)
+	for i := 0; i < concurrentCheckoutCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			accountID := fmt.Sprintf("account%d", i)
+			cart := entity.Cart{ID: accountID, AccountID: accountID, CartProducts: []entity.CartProduct{{ProductID: "1", SKUID: "sku1", Count: 1}}}
+			err := suite.db.RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+				_, err := suite.stockReservationDomainService.Reserve(tx, ctx, accountID, cart, products)
+				return err
+			})
+			if err == nil {
+				succeeded.Add(1)
+				return
+			}
+			if originalErr, ok := err.(share.OriginalError); ok && originalErr.Code == share.ErrorCodeOther {
+				shortage.Add(1)
+				return
+			}
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	// then（期待する結果）在庫数分のみ引き当てられ、残りは在庫不足になる
+	for err := range errs {
+		suite.FailNow(fmt.Sprintf("在庫引当時にエラー発生\n%+v", err))
+	}
+	suite.Equal(int32(stockCount), succeeded.Load())
+	suite.Equal(int32(concurrentCheckoutCount-stockCount), shortage.Load())
+
+	productStockCount, skuStockCount := suite.findStockCounts("1", "sku1")
+	suite.Equal(0, productStockCount)
+	suite.Equal(0, skuStockCount)
+
+	reservationCount, err := suite.db.NewSelect().Model((*persistance.StockReservation)(nil)).Count(context.Background())
+	suite.Nil(err)
+	suite.Equal(stockCount, reservationCount)
+
+	// 引き当てた在庫引当ごとに在庫台帳に記録され、移動前後の在庫数が連続している
+	var movements []persistance.StockMovement
+	err = suite.db.NewSelect().Model(&movements).Order("before_count DESC").Scan(context.Background())
+	suite.Nil(err)
+	suite.Len(movements, stockCount)
+	for i, movement := range movements {
+		suite.Equal(string(enum.StockMovementTypeReservation), movement.Type)
+		suite.Equal(-1, movement.Quantity)
+		suite.Equal(stockCount-i, movement.BeforeCount)
+		suite.Equal(stockCount-i-1, movement.AfterCount)
+	}
+
+	// given（前提条件）複数のSKUを持つ商品
+	// 同じ商品の異なるSKUを引き当てる購入手続き同士は商品の行の更新で競合するため、SKUの順序を揃えるだけではデッドロックする
+	sku1StockCount := concurrentCheckoutCount / 2
+	sku2StockCount := concurrentCheckoutCount
+	suite.insertProduct(suite.newProduct("2", sku1StockCount+sku2StockCount, []persistance.ProductSKU{
+		{ID: "sku2-1", ProductID: "2", Code: "SKU-2-1", Order: 1, StockCount: sku1StockCount, Version: 1},
+		{ID: "sku2-2", ProductID: "2", Code: "SKU-2-2", Order: 2, StockCount: sku2StockCount, Version: 1},
+	}))
+	skuProducts := []entity.Product{{
+		ID:         "2",
+		Name:       "商品名",
+		Status:     enum.OnSale,
+		StockCount: sku1StockCount + sku2StockCount,
+		SKUs: []entity.ProductSKU{
+			{ID: "sku2-1", ProductID: "2", StockCount: sku1StockCount},
+			{ID: "sku2-2", ProductID: "2", StockCount: sku2StockCount},
+		},
+	}}
+
+	// when（操作）2つのSKUを購入する購入手続きと、1つのSKUのみを購入する購入手続きを同時に開始する
+	succeeded.Store(0)
+	skuErrs := make(chan error, concurrentCheckoutCount)
+	for i := 0; i < concurrentCheckoutCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			accountID := fmt.Sprintf("sku-account%d", i)
+			cartProducts := []entity.CartProduct{{ProductID: "2", SKUID: "sku2-2", Count: 1}}
+			if i%2 == 0 {
+				cartProducts = append(cartProducts, entity.CartProduct{ProductID: "2", SKUID: "sku2-1", Count: 1})
+			}
+			cart := entity.Cart{ID: accountID, AccountID: accountID, CartProducts: cartProducts}
+			err := suite.db.RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+				_, err := suite.stockReservationDomainService.Reserve(tx, ctx, accountID, cart, skuProducts)
+				return err
+			})
+			if err != nil {
+				skuErrs <- err
+				return
+			}
+			succeeded.Add(1)
+		}(i)
+	}
+	wg.Wait()
+	close(skuErrs)
+
+	// then（期待する結果）デッドロックせずにすべて引き当てられ、商品の在庫数はSKUの在庫数の合計と一致する
+	for err := range skuErrs {
+		suite.FailNow(fmt.Sprintf("複数のSKUの在庫引当時にエラー発生\n%+v", err))
+	}
+	suite.Equal(int32(concurrentCheckoutCount), succeeded.Load())
+	productStockCount, sku1Count := suite.findStockCounts("2", "sku2-1")
+	_, sku2Count := suite.findStockCounts("2", "sku2-2")
+	suite.Equal(0, sku1Count)
+	suite.Equal(0, sku2Count)
+	suite.Equal(0, productStockCount)
+}
+
+func (suite *productStockRepositoryTestSuite) TestIncrease() {
+	defer suite.tearDown()
+
+	// given（前提条件）
+	suite.insertProduct(suite.newProduct("1", 3, []persistance.ProductSKU{
+		{ID: "sku1", ProductID: "1", Code: "SKU-1", Order: 1, StockCount: 1, Version: 1},
+		{ID: "sku2", ProductID: "1", Code: "SKU-2", Order: 2, StockCount: 2, Version: 1},
+	}))
+
+	// when（操作）
+	err := suite.productStockRepository.Increase(suite.db, context.Background(), "1", "sku2", 2)
+
+	// then（期待する結果）SKUと商品の在庫数に加算される
+	suite.Nil(err)
+	productStockCount, skuStockCount := suite.findStockCounts("1", "sku2")
+	suite.Equal(5, productStockCount)
+	suite.Equal(4, skuStockCount)
+}
+
+func (suite *productStockRepositoryTestSuite) TestMoveWithoutSKUForProductWithSKUs() {
+	defer suite.tearDown()
+
+	// given（前提条件）SKUを持つ商品
+	suite.insertProduct(suite.newProduct("1", 3, []persistance.ProductSKU{
+		{ID: "sku1", ProductID: "1", Code: "SKU-1", Order: 1, StockCount: 3, Version: 1},
+	}))
+
+	// when（操作）SKUを指定せずに在庫数を変更する
+	var err error
+	txErr := suite.db.RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+		_, _, err = suite.stockDomainService.Move(tx, ctx, service.StockMovementInput{
+			ProductID:     "1",
+			Type:          enum.StockMovementTypeReceipt,
+			Quantity:      5,
+			Reason:        "入荷",
+			ReferenceType: enum.StockMovementReferenceStaffAccount,
+			ReferenceID:   "staff",
+		})
+		return err
+	})
+
+	// then（期待する結果）バリデーションエラーになり、在庫数・在庫台帳は変更されない
+	suite.Equal(err, txErr)
+	originalErr, ok := err.(share.OriginalError)
+	suite.True(ok)
+	suite.Equal(share.ErrorCodeValidation, originalErr.Code)
+
+	productStockCount, skuStockCount := suite.findStockCounts("1", "sku1")
+	suite.Equal(3, productStockCount)
+	suite.Equal(3, skuStockCount)
+
+	movementCount, err := suite.db.NewSelect().Model((*persistance.StockMovement)(nil)).Count(context.Background())
+	suite.Nil(err)
+	suite.Equal(0, movementCount)
+}
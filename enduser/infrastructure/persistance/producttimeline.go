@@ -0,0 +1,131 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type productTimelineRepository struct {
+	timeUtils util.TimeUtils
+}
+
+func NewProductTimelineRepository(timeUtils util.TimeUtils) productTimelineRepository {
+	return productTimelineRepository{timeUtils: timeUtils}
+}
+
+func (ptr productTimelineRepository) FindByProductID(db bun.IDB, ctx context.Context, productID string, timelineType enum.ProductTimelineType) (entity.ProductTimeline, error) {
+	return ptr.find(db, ctx, productID, timelineType, false)
+}
+
+func (ptr productTimelineRepository) FindByProductIDForUpdate(db bun.IDB, ctx context.Context, productID string, timelineType enum.ProductTimelineType) (entity.ProductTimeline, error) {
+	return ptr.find(db, ctx, productID, timelineType, true)
+}
+
+func (ptr productTimelineRepository) find(db bun.IDB, ctx context.Context, productID string, timelineType enum.ProductTimelineType, forUpdate bool) (entity.ProductTimeline, error) {
+	model, err := newProductTimelineModel(timelineType)
+	if err != nil {
+		return entity.ProductTimeline{}, err
+	}
+
+	query := db.NewSelect().Model(model).Where("product_id = ?", productID).Order("effective_start_date")
+	if forUpdate {
+		query = query.For("UPDATE")
+	}
+	err = query.Scan(ctx)
+	if err != nil {
+		return entity.ProductTimeline{}, errors.WithStack(err)
+	}
+
+	entries := []entity.ProductTimelineEntry{}
+	switch rows := model.(type) {
+	case *[]ProductPrice:
+		for _, row := range *rows {
+			entries = append(entries, toProductTimelineEntry(ptr.timeUtils, row.ID, row.TaxInclusivePrice, row.EffectiveStartDate, row.EffectiveEndDate))
+		}
+	case *[]ProductSalePrice:
+		for _, row := range *rows {
+			entries = append(entries, toProductTimelineEntry(ptr.timeUtils, row.ID, row.TaxInclusivePrice, row.EffectiveStartDate, row.EffectiveEndDate))
+		}
+	case *[]ProductStatus:
+		for _, row := range *rows {
+			entries = append(entries, toProductTimelineEntry(ptr.timeUtils, row.ID, row.Status, row.EffectiveStartDate, row.EffectiveEndDate))
+		}
+	}
+
+	return entity.NewProductTimeline(productID, timelineType, entries), nil
+}
+
+func (ptr productTimelineRepository) Save(db bun.IDB, ctx context.Context, timeline entity.ProductTimeline) error {
+	model, err := newProductTimelineModel(timeline.Type)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.NewDelete().Model(model).Where("product_id = ?", timeline.ProductID).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(timeline.Entries) == 0 {
+		return nil
+	}
+
+	switch timeline.Type {
+	case enum.ProductTimelineTypePrice:
+		rows := make([]ProductPrice, 0, len(timeline.Entries))
+		for _, entry := range timeline.Entries {
+			rows = append(rows, ProductPrice{ID: entry.ID, ProductID: timeline.ProductID, TaxInclusivePrice: entry.Value, EffectiveStartDate: toDate(entry.EffectiveStartDate), EffectiveEndDate: toDate(entry.EffectiveEndDate)})
+		}
+		model = &rows
+	case enum.ProductTimelineTypeSalePrice:
+		rows := make([]ProductSalePrice, 0, len(timeline.Entries))
+		for _, entry := range timeline.Entries {
+			rows = append(rows, ProductSalePrice{ID: entry.ID, ProductID: timeline.ProductID, TaxInclusivePrice: entry.Value, EffectiveStartDate: toDate(entry.EffectiveStartDate), EffectiveEndDate: toDate(entry.EffectiveEndDate)})
+		}
+		model = &rows
+	case enum.ProductTimelineTypeStatus:
+		rows := make([]ProductStatus, 0, len(timeline.Entries))
+		for _, entry := range timeline.Entries {
+			rows = append(rows, ProductStatus{ID: entry.ID, ProductID: timeline.ProductID, Status: entry.Value, EffectiveStartDate: toDate(entry.EffectiveStartDate), EffectiveEndDate: toDate(entry.EffectiveEndDate)})
+		}
+		model = &rows
+	}
+
+	_, err = db.NewInsert().Model(model).Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// テーブルの行を適用期間に変換する。DATE型の値は日本時間の日付に変換する。
+func toProductTimelineEntry(timeUtils util.TimeUtils, id string, value int, startDate time.Time, endDate time.Time) entity.ProductTimelineEntry {
+	return entity.ProductTimelineEntry{
+		ID:                 id,
+		Value:              value,
+		EffectiveStartDate: timeUtils.DateJP(startDate.Year(), startDate.Month(), startDate.Day()),
+		EffectiveEndDate:   timeUtils.DateJP(endDate.Year(), endDate.Month(), endDate.Day()),
+	}
+}
+
+// 種別に対応するテーブルのモデルを返却する
+func newProductTimelineModel(timelineType enum.ProductTimelineType) (interface{}, error) {
+	switch timelineType {
+	case enum.ProductTimelineTypePrice:
+		return &[]ProductPrice{}, nil
+	case enum.ProductTimelineTypeSalePrice:
+		return &[]ProductSalePrice{}, nil
+	case enum.ProductTimelineTypeStatus:
+		return &[]ProductStatus{}, nil
+	}
+
+	return nil, errors.Newf("unknown product timeline type: %s", timelineType)
+}
+
+// 日付をタイムゾーンに関わらず同じ年月日のDATE型の値として保存するためにUTCの日付に変換する
+func toDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
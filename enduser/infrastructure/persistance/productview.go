@@ -0,0 +1,57 @@
+package persistance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-redis/redis/v8"
+)
+
+type productViewRepository struct {
+	redisClient *redis.Client
+}
+
+const (
+	// 商品の日別の閲覧数のソート済みセットのキーの接頭辞（メンバーは商品ID、スコアは閲覧数）
+	productViewKeyPrefix = "product_view:"
+	// 日別の閲覧数を保持する期間（月間ランキングの集計期間より長くする）
+	productViewExpiration = 35 * 24 * time.Hour
+)
+
+func NewProductViewRepository(redisClient *redis.Client) productViewRepository {
+	return productViewRepository{
+		redisClient: redisClient,
+	}
+}
+
+func (pvr productViewRepository) Increment(ctx context.Context, productID string, now time.Time) error {
+	key := pvr.viewKey(now)
+	_, err := pvr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZIncrBy(ctx, key, 1, productID)
+		pipe.Expire(ctx, key, productViewExpiration)
+		return nil
+	})
+	return errors.WithStack(err)
+}
+
+func (pvr productViewRepository) FindCounts(ctx context.Context, from time.Time, days int) (map[string]int, error) {
+	counts := map[string]int{}
+	for i := 0; i < days; i++ {
+		views, err := pvr.redisClient.ZRangeWithScores(ctx, pvr.viewKey(from.AddDate(0, 0, i)), 0, -1).Result()
+		if err != nil {
+			return map[string]int{}, errors.WithStack(err)
+		}
+
+		for _, view := range views {
+			counts[view.Member.(string)] += int(view.Score)
+		}
+	}
+	return counts, nil
+}
+
+// 日別の閲覧数のソート済みセットのキーを返却する
+func (pvr productViewRepository) viewKey(date time.Time) string {
+	return fmt.Sprintf("%s%s", productViewKeyPrefix, date.Format("20060102"))
+}
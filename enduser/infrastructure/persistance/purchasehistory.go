@@ -0,0 +1,67 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type purchaseHistoryRepository struct {
+	timeUtils util.TimeUtils
+}
+
+func NewPurchaseHistoryRepository(timeUtils util.TimeUtils) purchaseHistoryRepository {
+	return purchaseHistoryRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+// 注文テーブルはまだ存在しないため、決済の完了により確定した在庫引当を注文として扱い、確定日時が期間内の在庫引当の商品を返却する
+// 商品のカテゴリーは確定時点ではなく現在のカテゴリーとし、削除された商品は含めない
+func (phr purchaseHistoryRepository) FindBasketsBetween(db bun.IDB, ctx context.Context, from time.Time, to time.Time) ([]entity.PurchaseBasket, error) {
+	var rows []struct {
+		ReservationID string
+		OrderID       *string
+		ProductID     string
+		CategoryID    string
+		Count         int
+	}
+	err := db.NewSelect().
+		TableExpr("stock_reservation_items AS item").
+		ColumnExpr("item.reservation_id").
+		ColumnExpr("reservation.order_id").
+		ColumnExpr("item.product_id").
+		ColumnExpr("product.category_id").
+		ColumnExpr("item.count").
+		Join("JOIN stock_reservations AS reservation ON reservation.id = item.reservation_id").
+		Join("JOIN products AS product ON product.id = item.product_id").
+		Where("reservation.status = ?", string(enum.StockReservationStatusCommitted)).
+		Where("reservation.update_date_time >= ?", phr.timeUtils.TimeToUTC(from)).
+		Where("reservation.update_date_time < ?", phr.timeUtils.TimeToUTC(to)).
+		OrderExpr("item.reservation_id, item.product_id").
+		Scan(ctx, &rows)
+	if err != nil {
+		return []entity.PurchaseBasket{}, errors.WithStack(err)
+	}
+
+	baskets := []entity.PurchaseBasket{}
+	for _, row := range rows {
+		orderID := row.ReservationID
+		if row.OrderID != nil {
+			orderID = *row.OrderID
+		}
+
+		item := entity.PurchaseBasketItem{ProductID: row.ProductID, CategoryID: row.CategoryID, Quantity: row.Count}
+		if len(baskets) > 0 && baskets[len(baskets)-1].OrderID == orderID {
+			baskets[len(baskets)-1].Items = append(baskets[len(baskets)-1].Items, item)
+			continue
+		}
+		baskets = append(baskets, entity.PurchaseBasket{OrderID: orderID, Items: []entity.PurchaseBasketItem{item}})
+	}
+	return baskets, nil
+}
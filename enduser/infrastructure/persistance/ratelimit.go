@@ -0,0 +1,38 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-redis/redis/v8"
+)
+
+type rateLimitRepository struct {
+	redisClient *redis.Client
+}
+
+const rateLimitKeyPrefix = "rate_limit:"
+
+func NewRateLimitRepository(redisClient *redis.Client) rateLimitRepository {
+	return rateLimitRepository{
+		redisClient: redisClient,
+	}
+}
+
+func (rlr rateLimitRepository) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	key = rateLimitKeyPrefix + key
+
+	var incr *redis.IntCmd
+	_, err := rlr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		// 期間の開始時（有効期限が設定されていない場合）のみ有効期限を設定する
+		pipe.ExpireNX(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return incr.Val(), nil
+}
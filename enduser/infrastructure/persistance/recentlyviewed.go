@@ -0,0 +1,77 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-redis/redis/v8"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+)
+
+// Redisのソート済みセットによる閲覧履歴リポジトリの実装
+// メンバーを商品ID、スコアを閲覧日時（UnixMilli）とし、上限を超えた古い商品は追加時に削除する
+type recentlyViewedRepository struct {
+	redisClient *redis.Client
+}
+
+const (
+	recentlyViewedSessionKeyPrefix = "recently_viewed:session:"
+	recentlyViewedAccountKeyPrefix = "recently_viewed:account:"
+)
+
+func NewRecentlyViewedRepository(redisClient *redis.Client) recentlyViewedRepository {
+	return recentlyViewedRepository{
+		redisClient: redisClient,
+	}
+}
+
+func (rvr recentlyViewedRepository) AddBySessionID(ctx context.Context, sessionID string, productID string, viewedAt time.Time) error {
+	return rvr.add(ctx, recentlyViewedSessionKeyPrefix+sessionID, productID, viewedAt)
+}
+
+func (rvr recentlyViewedRepository) AddByAccountID(ctx context.Context, accountID string, productID string, viewedAt time.Time) error {
+	return rvr.add(ctx, recentlyViewedAccountKeyPrefix+accountID, productID, viewedAt)
+}
+
+func (rvr recentlyViewedRepository) FindBySessionID(ctx context.Context, sessionID string) ([]string, error) {
+	return rvr.find(ctx, recentlyViewedSessionKeyPrefix+sessionID)
+}
+
+func (rvr recentlyViewedRepository) FindByAccountID(ctx context.Context, accountID string) ([]string, error) {
+	return rvr.find(ctx, recentlyViewedAccountKeyPrefix+accountID)
+}
+
+// 同じ商品を両方で閲覧していた場合は新しい閲覧日時を残す
+func (rvr recentlyViewedRepository) MoveSessionToAccount(ctx context.Context, sessionID string, accountID string) error {
+	sessionKey := recentlyViewedSessionKeyPrefix + sessionID
+	accountKey := recentlyViewedAccountKeyPrefix + accountID
+	_, err := rvr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZUnionStore(ctx, accountKey, &redis.ZStore{Keys: []string{accountKey, sessionKey}, Aggregate: "MAX"})
+		pipe.ZRemRangeByRank(ctx, accountKey, 0, -entity.RecentlyViewedMaxProducts-1)
+		pipe.Expire(ctx, accountKey, entity.RecentlyViewedExpiration)
+		pipe.Del(ctx, sessionKey)
+		return nil
+	})
+	return errors.WithStack(err)
+}
+
+// 商品を追加し、上限を超えた古い商品を削除して有効期限を延ばす
+func (rvr recentlyViewedRepository) add(ctx context.Context, key string, productID string, viewedAt time.Time) error {
+	_, err := rvr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, &redis.Z{Score: float64(viewedAt.UnixMilli()), Member: productID})
+		pipe.ZRemRangeByRank(ctx, key, 0, -entity.RecentlyViewedMaxProducts-1)
+		pipe.Expire(ctx, key, entity.RecentlyViewedExpiration)
+		return nil
+	})
+	return errors.WithStack(err)
+}
+
+func (rvr recentlyViewedRepository) find(ctx context.Context, key string) ([]string, error) {
+	productIDs, err := rvr.redisClient.ZRevRange(ctx, key, 0, entity.RecentlyViewedMaxProducts-1).Result()
+	if err != nil {
+		return []string{}, errors.WithStack(err)
+	}
+
+	return productIDs, nil
+}
@@ -0,0 +1,92 @@
+package persistance_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/kuritaeiji/ec_backend/config"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/enduser/infrastructure/persistance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type recentlyViewedRepositoryTestSuite struct {
+	suite.Suite
+	recentlyViewedRepository repository.RecentlyViewedRepository
+	redisClient              *redis.Client
+}
+
+func TestRecentlyViewedRepository(t *testing.T) {
+	err := config.SetupEnv()
+	if err != nil {
+		assert.FailNow(t, fmt.Sprintf("環境変数設定時にエラーが発生しました。\n+%+v", err))
+	}
+	redisClient := config.NewRedisClient()
+	suite.Run(t, &recentlyViewedRepositoryTestSuite{
+		recentlyViewedRepository: persistance.NewRecentlyViewedRepository(redisClient),
+		redisClient:              redisClient,
+	})
+}
+
+func (suite *recentlyViewedRepositoryTestSuite) tearDown() {
+	err := suite.redisClient.FlushAll(context.Background()).Err()
+	if err != nil {
+		suite.FailNow("Redisのデータ全削除時にエラー発生\n+%v", err)
+	}
+}
+
+func (suite *recentlyViewedRepositoryTestSuite) TestAddBySessionID() {
+	defer suite.tearDown()
+
+	// given（前提条件）上限を1件超える商品を閲覧し、最初に閲覧した商品を再度閲覧する
+	ctx := context.Background()
+	viewedAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= entity.RecentlyViewedMaxProducts; i++ {
+		err := suite.recentlyViewedRepository.AddBySessionID(ctx, "sessionID", fmt.Sprintf("product%d", i), viewedAt.Add(time.Duration(i)*time.Minute))
+		if err != nil {
+			suite.FailNow("閲覧履歴の追加時にエラー発生\n+%+v", err)
+		}
+	}
+
+	// when（操作）
+	err := suite.recentlyViewedRepository.AddBySessionID(ctx, "sessionID", "product1", viewedAt.Add(time.Hour))
+
+	// then（期待する結果）最も古い商品を削除し、再度閲覧した商品を先頭にする
+	suite.Nil(err)
+	productIDs, err := suite.recentlyViewedRepository.FindBySessionID(ctx, "sessionID")
+	suite.Nil(err)
+	suite.Equal(entity.RecentlyViewedMaxProducts, len(productIDs))
+	suite.Equal("product1", productIDs[0])
+	suite.Equal(fmt.Sprintf("product%d", entity.RecentlyViewedMaxProducts), productIDs[1])
+	suite.NotContains(productIDs, "product0")
+}
+
+func (suite *recentlyViewedRepositoryTestSuite) TestMoveSessionToAccount() {
+	defer suite.tearDown()
+
+	// given（前提条件）
+	ctx := context.Background()
+	viewedAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	suite.Nil(suite.recentlyViewedRepository.AddByAccountID(ctx, "accountID", "product1", viewedAt))
+	suite.Nil(suite.recentlyViewedRepository.AddByAccountID(ctx, "accountID", "product2", viewedAt.Add(2*time.Minute)))
+	suite.Nil(suite.recentlyViewedRepository.AddBySessionID(ctx, "sessionID", "product3", viewedAt.Add(time.Minute)))
+	suite.Nil(suite.recentlyViewedRepository.AddBySessionID(ctx, "sessionID", "product1", viewedAt.Add(3*time.Minute)))
+
+	// when（操作）
+	err := suite.recentlyViewedRepository.MoveSessionToAccount(ctx, "sessionID", "accountID")
+
+	// then（期待する結果）閲覧日時の降順にマージし、ゲストの閲覧履歴を削除する
+	suite.Nil(err)
+	productIDs, err := suite.recentlyViewedRepository.FindByAccountID(ctx, "accountID")
+	suite.Nil(err)
+	suite.Equal([]string{"product1", "product2", "product3"}, productIDs)
+
+	sessionProductIDs, err := suite.recentlyViewedRepository.FindBySessionID(ctx, "sessionID")
+	suite.Nil(err)
+	suite.Equal([]string{}, sessionProductIDs)
+}
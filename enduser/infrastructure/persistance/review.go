@@ -0,0 +1,432 @@
+package persistance
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/adapter"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// レビューテーブル
+	Review struct {
+		bun.BaseModel `bun:"table:reviews"`
+
+		ID                 string    `bun:",pk"`
+		ProductID          string    `bun:",notnull,unique:reviews_product_id_account_id_idx"`
+		AccountID          string    `bun:",notnull,unique:reviews_product_id_account_id_idx"`
+		Rating             int       `bun:",notnull"`
+		Title              string    `bun:",notnull"`
+		Body               string    `bun:",type:text,notnull"`
+		IsVerifiedPurchase bool      `bun:",notnull,default:false"`
+		Status             string    `bun:",notnull"`
+		FlaggedWords       string    `bun:",type:text,notnull"` // NGワードフィルターで検出された語（改行区切り）
+		HelpfulCount       int       `bun:",notnull,default:0"` // 参考になった票の数（review_votesテーブルの非正規化）
+		UnhelpfulCount     int       `bun:",notnull,default:0"` // 参考にならなかった票の数（review_votesテーブルの非正規化）
+		ReportCount        int       `bun:",notnull,default:0"`
+		Version            int       `bun:",notnull"`
+		CreateDateTime     time.Time `bun:",notnull"`
+		UpdateDateTime     time.Time `bun:",notnull"`
+
+		ReviewPhotos []ReviewPhoto `bun:"rel:has-many,join:id=review_id"`
+	}
+
+	// レビューの写真テーブル
+	ReviewPhoto struct {
+		bun.BaseModel `bun:"table:review_photos"`
+
+		ID       string `bun:",pk"`
+		ReviewID string `bun:",notnull"`
+		Order    int    `bun:",notnull"`
+		Path     string `bun:",notnull"`
+	}
+
+	// レビューリポジトリの実装
+	reviewRepository struct {
+		imageStorageAdapter adapter.ImageStorageAdapter
+		timeUtils           util.TimeUtils
+	}
+)
+
+const reviewPhotoURLExpiry = time.Hour // レビューの写真のURLの有効期限
+
+func NewReviewRepository(imageStorageAdapter adapter.ImageStorageAdapter, timeUtils util.TimeUtils) reviewRepository {
+	return reviewRepository{
+		imageStorageAdapter: imageStorageAdapter,
+		timeUtils:           timeUtils,
+	}
+}
+
+func (rr reviewRepository) FindByID(db bun.IDB, ctx context.Context, id string) (entity.Review, bool, error) {
+	return rr.findOne(db, ctx, func(sq *bun.SelectQuery) *bun.SelectQuery {
+		return sq.Where("review.id = ?", id)
+	})
+}
+
+func (rr reviewRepository) FindByIDForUpdate(db bun.IDB, ctx context.Context, id string) (entity.Review, bool, error) {
+	return rr.findOne(db, ctx, func(sq *bun.SelectQuery) *bun.SelectQuery {
+		return sq.Where("review.id = ?", id).For("UPDATE")
+	})
+}
+
+func (rr reviewRepository) FindByProductIDAndAccountID(db bun.IDB, ctx context.Context, productID string, accountID string) (entity.Review, bool, error) {
+	return rr.findOne(db, ctx, func(sq *bun.SelectQuery) *bun.SelectQuery {
+		return sq.Where("review.product_id = ?", productID).Where("review.account_id = ?", accountID)
+	})
+}
+
+func (rr reviewRepository) FindByAccountID(db bun.IDB, ctx context.Context, accountID string) ([]entity.Review, error) {
+	var reviews []Review
+	err := rr.selectReviews(db, &reviews).
+		Where("review.account_id = ?", accountID).
+		Order("review.create_date_time DESC").
+		Scan(ctx)
+	if err != nil {
+		return []entity.Review{}, errors.WithStack(err)
+	}
+
+	eReviews := make([]entity.Review, 0, len(reviews))
+	for _, review := range reviews {
+		eReview, err := rr.toEntity(review, true)
+		if err != nil {
+			return []entity.Review{}, err
+		}
+		eReviews = append(eReviews, eReview)
+	}
+	return eReviews, nil
+}
+
+func (rr reviewRepository) FindPublishedByProductID(db bun.IDB, ctx context.Context, productID string, sort enum.ReviewSort, page int, limit int) (entity.ReviewPage, error) {
+	var reviews []Review
+	totalCount, err := rr.selectReviews(db, &reviews).
+		Where("review.product_id = ?", productID).
+		Where("review.status = ?", string(enum.ReviewStatusPublished)).
+		Order(rr.orders(sort)...).
+		Offset((page - 1) * limit).
+		Limit(limit).
+		ScanAndCount(ctx)
+	if err != nil {
+		return entity.ReviewPage{}, errors.WithStack(err)
+	}
+
+	items, err := rr.toListItems(db, ctx, reviews, true)
+	if err != nil {
+		return entity.ReviewPage{}, err
+	}
+
+	return entity.ReviewPage{
+		Reviews:    items,
+		TotalCount: totalCount,
+		Page:       page,
+		HasNext:    page*limit < totalCount,
+	}, nil
+}
+
+func (rr reviewRepository) FindRatingDistribution(db bun.IDB, ctx context.Context, productID string) (entity.ReviewRatingDistribution, error) {
+	var rows []struct {
+		Rating int
+		Count  int
+	}
+	err := db.NewSelect().
+		Model((*Review)(nil)).
+		Column("rating").
+		ColumnExpr("COUNT(*) AS count").
+		Where("product_id = ?", productID).
+		Where("status = ?", string(enum.ReviewStatusPublished)).
+		Group("rating").
+		Scan(ctx, &rows)
+	if err != nil {
+		return entity.ReviewRatingDistribution{}, errors.WithStack(err)
+	}
+
+	counts := make(map[int]int, len(rows))
+	for _, row := range rows {
+		counts[row.Rating] = row.Count
+	}
+	return entity.NewReviewRatingDistribution(counts), nil
+}
+
+func (rr reviewRepository) FindByStatus(db bun.IDB, ctx context.Context, status enum.ReviewStatus, limit int) ([]entity.ReviewListItem, error) {
+	var reviews []Review
+	err := rr.selectReviews(db, &reviews).
+		Where("review.status = ?", string(status)).
+		Order("review.create_date_time ASC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return []entity.ReviewListItem{}, errors.WithStack(err)
+	}
+
+	return rr.toListItems(db, ctx, reviews, false)
+}
+
+func (rr reviewRepository) FindRatingSummaries(db bun.IDB, ctx context.Context, createdBefore time.Time) ([]entity.ReviewRatingSummary, error) {
+	var rows []struct {
+		ProductID string
+		Count     int
+		Sum       int
+	}
+	err := db.NewSelect().
+		Model((*Review)(nil)).
+		Column("product_id").
+		ColumnExpr("COUNT(*) AS count").
+		ColumnExpr("SUM(rating) AS sum").
+		Where("status = ?", string(enum.ReviewStatusPublished)).
+		Where("create_date_time < ?", rr.timeUtils.TimeToUTC(createdBefore)).
+		Group("product_id").
+		Order("product_id").
+		Scan(ctx, &rows)
+	if err != nil {
+		return []entity.ReviewRatingSummary{}, errors.WithStack(err)
+	}
+
+	summaries := make([]entity.ReviewRatingSummary, 0, len(rows))
+	for _, row := range rows {
+		summaries = append(summaries, entity.ReviewRatingSummary{ProductID: row.ProductID, Count: row.Count, Sum: row.Sum})
+	}
+	return summaries, nil
+}
+
+func (rr reviewRepository) Insert(db bun.IDB, ctx context.Context, review entity.Review) error {
+	mReview := rr.toModel(review)
+
+	_, err := db.NewInsert().Model(&mReview).Exec(ctx)
+	if err != nil {
+		return translateDuplicateKeyError(err)
+	}
+
+	if len(mReview.ReviewPhotos) > 0 {
+		_, err = db.NewInsert().Model(&mReview.ReviewPhotos).Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+func (rr reviewRepository) Update(db bun.IDB, ctx context.Context, review entity.Review) error {
+	mReview := rr.toModel(review)
+
+	//写真をすべて削除する
+	_, err := db.NewDelete().Model(new(ReviewPhoto)).Where("review_id = ?", mReview.ID).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	//すべての写真を登録する
+	if len(mReview.ReviewPhotos) > 0 {
+		_, err = db.NewInsert().Model(&mReview.ReviewPhotos).Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	//レビューを更新する（楽観ロックする）
+	//参考になった数・参考にならなかった数は投票時に集計し直すため、古い値で上書きしないよう更新対象から除外する
+	mReview.Version = mReview.Version + 1
+	res, err := db.NewUpdate().Model(&mReview).ExcludeColumn("helpful_count", "unhelpful_count").WherePK().Where("version = ?", mReview.Version-1).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if count != 1 {
+		return ErrOptimisticLocking
+	}
+
+	return nil
+}
+
+func (rr reviewRepository) UpdateVoteCounts(db bun.IDB, ctx context.Context, reviewID string) error {
+	helpfulCount := db.NewSelect().Model((*ReviewVote)(nil)).ColumnExpr("COUNT(*)").Where("review_id = ?", reviewID).Where("is_helpful = ?", true)
+	unhelpfulCount := db.NewSelect().Model((*ReviewVote)(nil)).ColumnExpr("COUNT(*)").Where("review_id = ?", reviewID).Where("is_helpful = ?", false)
+
+	_, err := db.NewUpdate().
+		Model((*Review)(nil)).
+		Set("helpful_count = (?)", helpfulCount).
+		Set("unhelpful_count = (?)", unhelpfulCount).
+		Where("id = ?", reviewID).
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (rr reviewRepository) Delete(db bun.IDB, ctx context.Context, review entity.Review) error {
+	_, err := db.NewDelete().Model(new(ReviewPhoto)).Where("review_id = ?", review.ID).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = db.NewDelete().Model(new(ReviewVote)).Where("review_id = ?", review.ID).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = db.NewDelete().Model(new(ReviewReport)).Where("review_id = ?", review.ID).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = db.NewDelete().Model(new(Review)).Where("id = ?", review.ID).Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (rr reviewRepository) selectReviews(db bun.IDB, model interface{}) *bun.SelectQuery {
+	return db.NewSelect().Model(model).
+		Relation("ReviewPhotos", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Order("review_photo.order")
+		})
+}
+
+// 並び順に対応するORDER BY句を返却する（同じ値のレビューは新しい順に並べる）
+func (rr reviewRepository) orders(sort enum.ReviewSort) []string {
+	switch sort {
+	case enum.ReviewSortHelpful:
+		return []string{"review.helpful_count DESC", "review.create_date_time DESC", "review.id"}
+	case enum.ReviewSortRatingDesc:
+		return []string{"review.rating DESC", "review.create_date_time DESC", "review.id"}
+	case enum.ReviewSortRatingAsc:
+		return []string{"review.rating ASC", "review.create_date_time DESC", "review.id"}
+	default:
+		return []string{"review.create_date_time DESC", "review.id"}
+	}
+}
+
+// 引数whereで絞り込んだレビューを1件返却する（写真のURLは取得しない）
+func (rr reviewRepository) findOne(db bun.IDB, ctx context.Context, where func(sq *bun.SelectQuery) *bun.SelectQuery) (entity.Review, bool, error) {
+	var review Review
+	err := where(rr.selectReviews(db, &review)).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Review{}, false, nil
+		}
+
+		return entity.Review{}, false, errors.WithStack(err)
+	}
+
+	eReview, err := rr.toEntity(review, false)
+	if err != nil {
+		return entity.Review{}, false, err
+	}
+	return eReview, true, nil
+}
+
+// レビュー配列にレビュー投稿者名を付加する
+// 退会済みのアカウントのレビューも表示するため、論理削除されたアカウントからもレビュー投稿者名を取得する
+func (rr reviewRepository) toListItems(db bun.IDB, ctx context.Context, reviews []Review, withImage bool) ([]entity.ReviewListItem, error) {
+	if len(reviews) == 0 {
+		return []entity.ReviewListItem{}, nil
+	}
+
+	accountIDs := make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		accountIDs = append(accountIDs, review.AccountID)
+	}
+	var accounts []Account
+	err := db.NewSelect().Model(&accounts).Column("id", "review_nickname").Where("id IN (?)", bun.In(accountIDs)).WhereAllWithDeleted().Scan(ctx)
+	if err != nil {
+		return []entity.ReviewListItem{}, errors.WithStack(err)
+	}
+	nicknames := make(map[string]string, len(accounts))
+	for _, account := range accounts {
+		nicknames[account.ID] = account.ReviewNickname
+	}
+
+	items := make([]entity.ReviewListItem, 0, len(reviews))
+	for _, review := range reviews {
+		eReview, err := rr.toEntity(review, withImage)
+		if err != nil {
+			return []entity.ReviewListItem{}, err
+		}
+		items = append(items, entity.ReviewListItem{Review: eReview, ReviewNickname: nicknames[review.AccountID]})
+	}
+	return items, nil
+}
+
+func (rr reviewRepository) toModel(review entity.Review) Review {
+	photos := make([]ReviewPhoto, 0, len(review.Photos))
+	for _, photo := range review.Photos {
+		photos = append(photos, ReviewPhoto{
+			ID:       photo.ID,
+			ReviewID: photo.ReviewID,
+			Order:    photo.Order,
+			Path:     photo.Path,
+		})
+	}
+
+	return Review{
+		ID:                 review.ID,
+		ProductID:          review.ProductID,
+		AccountID:          review.AccountID,
+		Rating:             review.Rating,
+		Title:              review.Title,
+		Body:               review.Body,
+		IsVerifiedPurchase: review.IsVerifiedPurchase,
+		Status:             string(review.Status),
+		FlaggedWords:       strings.Join(review.FlaggedWords, "\n"),
+		HelpfulCount:       review.HelpfulCount,
+		UnhelpfulCount:     review.UnhelpfulCount,
+		ReportCount:        review.ReportCount,
+		Version:            review.Version,
+		CreateDateTime:     rr.timeUtils.TimeToUTC(review.CreateDateTime),
+		UpdateDateTime:     rr.timeUtils.TimeToUTC(review.UpdateDateTime),
+		ReviewPhotos:       photos,
+	}
+}
+
+// 引数withImageがtrueの場合は画像ストレージから写真のURLを取得する
+func (rr reviewRepository) toEntity(review Review, withImage bool) (entity.Review, error) {
+	photos := make([]entity.ReviewPhoto, 0, len(review.ReviewPhotos))
+	for _, photo := range review.ReviewPhotos {
+		var image string
+		if withImage {
+			url, err := rr.imageStorageAdapter.PresignedURL(photo.Path, reviewPhotoURLExpiry)
+			if err != nil {
+				return entity.Review{}, err
+			}
+			image = url
+		}
+
+		photos = append(photos, entity.ReviewPhoto{
+			ID:       photo.ID,
+			ReviewID: photo.ReviewID,
+			Order:    photo.Order,
+			Path:     photo.Path,
+			Image:    image,
+		})
+	}
+
+	flaggedWords := []string{}
+	if review.FlaggedWords != "" {
+		flaggedWords = strings.Split(review.FlaggedWords, "\n")
+	}
+
+	return entity.Review{
+		ID:                 review.ID,
+		ProductID:          review.ProductID,
+		AccountID:          review.AccountID,
+		Rating:             review.Rating,
+		Title:              review.Title,
+		Body:               review.Body,
+		IsVerifiedPurchase: review.IsVerifiedPurchase,
+		Status:             enum.ReviewStatus(review.Status),
+		FlaggedWords:       flaggedWords,
+		HelpfulCount:       review.HelpfulCount,
+		UnhelpfulCount:     review.UnhelpfulCount,
+		ReportCount:        review.ReportCount,
+		Version:            review.Version,
+		CreateDateTime:     rr.timeUtils.TimeToJP(review.CreateDateTime),
+		UpdateDateTime:     rr.timeUtils.TimeToJP(review.UpdateDateTime),
+		Photos:             photos,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 不適切なレビューとしての報告テーブル
+	// 1つのアカウントは1件のレビューを1回のみ報告できるよう、レビューIDとアカウントIDにユニーク制約を設定する
+	ReviewReport struct {
+		bun.BaseModel `bun:"table:review_reports"`
+
+		ID             string    `bun:",pk"`
+		ReviewID       string    `bun:",notnull,unique:review_reports_review_id_account_id_idx"`
+		AccountID      string    `bun:",notnull,unique:review_reports_review_id_account_id_idx"`
+		Reason         string    `bun:",type:text,notnull"`
+		CreateDateTime time.Time `bun:",notnull"`
+	}
+
+	// 不適切なレビューとしての報告リポジトリの実装
+	reviewReportRepository struct {
+		timeUtils util.TimeUtils
+	}
+)
+
+func NewReviewReportRepository(timeUtils util.TimeUtils) reviewReportRepository {
+	return reviewReportRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+func (rrr reviewReportRepository) ExistsByReviewIDAndAccountID(db bun.IDB, ctx context.Context, reviewID string, accountID string) (bool, error) {
+	exists, err := db.NewSelect().Model((*ReviewReport)(nil)).Where("review_id = ?", reviewID).Where("account_id = ?", accountID).Exists(ctx)
+	return exists, errors.WithStack(err)
+}
+
+func (rrr reviewReportRepository) Insert(db bun.IDB, ctx context.Context, report entity.ReviewReport) error {
+	mReport := ReviewReport{
+		ID:             report.ID,
+		ReviewID:       report.ReviewID,
+		AccountID:      report.AccountID,
+		Reason:         report.Reason,
+		CreateDateTime: rrr.timeUtils.TimeToUTC(report.CreateDateTime),
+	}
+	_, err := db.NewInsert().Model(&mReport).Exec(ctx)
+	if err != nil {
+		return translateDuplicateKeyError(err)
+	}
+	return nil
+}
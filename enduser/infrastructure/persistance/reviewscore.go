@@ -0,0 +1,41 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/uptrace/bun"
+)
+
+type reviewScoreRepository struct{}
+
+func NewReviewScoreRepository() reviewScoreRepository {
+	return reviewScoreRepository{}
+}
+
+// 同じ日付で何度実行しても同じ結果になるよう、日付のレビュー点数を置き換える
+func (rsr reviewScoreRepository) ReplaceByDate(db bun.IDB, ctx context.Context, date time.Time, scores []entity.ReviewScore) error {
+	_, err := db.NewDelete().Model((*ReviewScore)(nil)).Where("date = ?", toDate(date)).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(scores) == 0 {
+		return nil
+	}
+
+	mScores := make([]ReviewScore, 0, len(scores))
+	for _, score := range scores {
+		mScores = append(mScores, ReviewScore{
+			ID:        score.ID,
+			ProductID: score.ProductID,
+			Score:     score.Score,
+			Date:      toDate(score.Date),
+		})
+	}
+
+	_, err = db.NewInsert().Model(&mScores).Exec(ctx)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,78 @@
+package persistance
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// レビューへの投票テーブル
+	// 1つのアカウントは1件のレビューに1票のみ投票できるよう、レビューIDとアカウントIDにユニーク制約を設定する
+	ReviewVote struct {
+		bun.BaseModel `bun:"table:review_votes"`
+
+		ID             string    `bun:",pk"`
+		ReviewID       string    `bun:",notnull,unique:review_votes_review_id_account_id_idx"`
+		AccountID      string    `bun:",notnull,unique:review_votes_review_id_account_id_idx"`
+		IsHelpful      bool      `bun:",notnull"`
+		CreateDateTime time.Time `bun:",notnull"`
+	}
+
+	// レビューへの投票リポジトリの実装
+	reviewVoteRepository struct {
+		timeUtils util.TimeUtils
+	}
+)
+
+func NewReviewVoteRepository(timeUtils util.TimeUtils) reviewVoteRepository {
+	return reviewVoteRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+func (rvr reviewVoteRepository) FindByReviewIDAndAccountID(db bun.IDB, ctx context.Context, reviewID string, accountID string) (entity.ReviewVote, bool, error) {
+	var vote ReviewVote
+	err := db.NewSelect().Model(&vote).Where("review_id = ?", reviewID).Where("account_id = ?", accountID).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ReviewVote{}, false, nil
+		}
+
+		return entity.ReviewVote{}, false, errors.WithStack(err)
+	}
+
+	return entity.ReviewVote{
+		ID:             vote.ID,
+		ReviewID:       vote.ReviewID,
+		AccountID:      vote.AccountID,
+		IsHelpful:      vote.IsHelpful,
+		CreateDateTime: rvr.timeUtils.TimeToJP(vote.CreateDateTime),
+	}, true, nil
+}
+
+func (rvr reviewVoteRepository) Save(db bun.IDB, ctx context.Context, vote entity.ReviewVote) error {
+	mVote := ReviewVote{
+		ID:             vote.ID,
+		ReviewID:       vote.ReviewID,
+		AccountID:      vote.AccountID,
+		IsHelpful:      vote.IsHelpful,
+		CreateDateTime: rvr.timeUtils.TimeToUTC(vote.CreateDateTime),
+	}
+	_, err := db.NewInsert().
+		Model(&mVote).
+		On("DUPLICATE KEY UPDATE").
+		Set("is_helpful = VALUES(is_helpful)").
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (rvr reviewVoteRepository) Delete(db bun.IDB, ctx context.Context, vote entity.ReviewVote) error {
+	_, err := db.NewDelete().Model(new(ReviewVote)).Where("review_id = ?", vote.ReviewID).Where("account_id = ?", vote.AccountID).Exec(ctx)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,145 @@
+package persistance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-redis/redis/v8"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+)
+
+type searchSuggestionRepository struct {
+	redisClient *redis.Client
+}
+
+const (
+	// 商品名・カテゴリー名の検索候補のソート済みセットのキー
+	// メンバーは「キー\x00種別\x00表示文字列」で、スコアをすべて0にして辞書順の範囲検索で前方一致させる
+	searchSuggestionNamesKey = "search_suggestion:names"
+	// 検索されたキーワードの日別のソート済みセットのキーの接頭辞（メンバーはキーワード、スコアは検索回数）
+	searchQueryKeyPrefix = "search_query:"
+	// 日別の検索回数を保持する期間
+	searchQueryExpiration = 8 * 24 * time.Hour
+	// 日別に取得する検索回数の多いキーワードの上限数
+	searchQueryFetchCount = 1000
+
+	searchSuggestionSeparator = "\x00"
+)
+
+func NewSearchSuggestionRepository(redisClient *redis.Client) searchSuggestionRepository {
+	return searchSuggestionRepository{
+		redisClient: redisClient,
+	}
+}
+
+func (ssr searchSuggestionRepository) ReplaceNames(ctx context.Context, suggestions []entity.SearchSuggestion) error {
+	members := make([]*redis.Z, 0, len(suggestions)*2)
+	for _, suggestion := range suggestions {
+		for _, key := range suggestion.Keys() {
+			members = append(members, &redis.Z{
+				Score:  0,
+				Member: strings.Join([]string{key, string(suggestion.Type), suggestion.Text}, searchSuggestionSeparator),
+			})
+		}
+	}
+
+	if len(members) == 0 {
+		return errors.WithStack(ssr.redisClient.Del(ctx, searchSuggestionNamesKey).Err())
+	}
+
+	// 一時キーに登録してからリネームし、置き換え中も検索候補を返却できるようにする
+	tmpKey := searchSuggestionNamesKey + ":tmp"
+	_, err := ssr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, tmpKey)
+		pipe.ZAdd(ctx, tmpKey, members...)
+		pipe.Rename(ctx, tmpKey, searchSuggestionNamesKey)
+		return nil
+	})
+	return errors.WithStack(err)
+}
+
+func (ssr searchSuggestionRepository) FindNamesByPrefix(ctx context.Context, prefix string, limit int) ([]entity.SearchSuggestion, error) {
+	// 同じ表示文字列の検索候補が複数のキーで一致する場合があるため多めに取得する
+	members, err := ssr.redisClient.ZRangeByLex(ctx, searchSuggestionNamesKey, &redis.ZRangeBy{
+		Min:   "[" + prefix,
+		Max:   "[" + prefix + "\xff",
+		Count: int64(limit * 2),
+	}).Result()
+	if err != nil {
+		return []entity.SearchSuggestion{}, errors.WithStack(err)
+	}
+
+	seen := map[string]struct{}{}
+	suggestions := make([]entity.SearchSuggestion, 0, limit)
+	for _, member := range members {
+		parts := strings.SplitN(member, searchSuggestionSeparator, 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if _, ok := seen[parts[2]]; ok {
+			continue
+		}
+		seen[parts[2]] = struct{}{}
+
+		suggestions = append(suggestions, entity.SearchSuggestion{Text: parts[2], Type: enum.SearchSuggestionType(parts[1])})
+		if len(suggestions) == limit {
+			break
+		}
+	}
+	return suggestions, nil
+}
+
+func (ssr searchSuggestionRepository) IncrementQuery(ctx context.Context, keyword string, now time.Time) error {
+	key := ssr.queryKey(now)
+	_, err := ssr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZIncrBy(ctx, key, 1, keyword)
+		pipe.Expire(ctx, key, searchQueryExpiration)
+		return nil
+	})
+	return errors.WithStack(err)
+}
+
+func (ssr searchSuggestionRepository) FindPopularQueries(ctx context.Context, now time.Time, days int, limit int) ([]entity.SearchSuggestion, error) {
+	// 日別の検索回数を合算する
+	counts := map[string]float64{}
+	for i := 0; i < days; i++ {
+		queries, err := ssr.redisClient.ZRevRangeWithScores(ctx, ssr.queryKey(now.AddDate(0, 0, -i)), 0, searchQueryFetchCount-1).Result()
+		if err != nil {
+			return []entity.SearchSuggestion{}, errors.WithStack(err)
+		}
+
+		for _, query := range queries {
+			counts[query.Member.(string)] += query.Score
+		}
+	}
+
+	keywords := make([]string, 0, len(counts))
+	for keyword := range counts {
+		keywords = append(keywords, keyword)
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if counts[keywords[i]] != counts[keywords[j]] {
+			return counts[keywords[i]] > counts[keywords[j]]
+		}
+		return keywords[i] < keywords[j]
+	})
+	if len(keywords) > limit {
+		keywords = keywords[:limit]
+	}
+
+	suggestions := make([]entity.SearchSuggestion, 0, len(keywords))
+	for _, keyword := range keywords {
+		suggestions = append(suggestions, entity.SearchSuggestion{Text: keyword, Type: enum.SearchSuggestionTypeQuery})
+	}
+	return suggestions, nil
+}
+
+// 日別の検索回数のソート済みセットのキーを返却する
+func (ssr searchSuggestionRepository) queryKey(date time.Time) string {
+	return fmt.Sprintf("%s%s", searchQueryKeyPrefix, date.Format("20060102"))
+}
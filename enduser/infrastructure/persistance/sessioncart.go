@@ -13,12 +13,14 @@ import (
 type (
 	//セッションカート
 	SessionCart struct {
-		SessionCartProducts []SessionCartProduct `json:"sessionCartProducts"`
+		SessionCartProducts  []SessionCartProduct `json:"sessionCartProducts"`
+		SessionSavedProducts []SessionCartProduct `json:"sessionSavedProducts"`
 	}
 
 	//セッションカート商品
 	SessionCartProduct struct {
 		ProductID string `json:"productID"`
+		SKUID     string `json:"skuID,omitempty"`
 		Count     int    `json:"count"`
 	}
 
@@ -77,13 +79,24 @@ func (scr sessionCartRepository) toEntity(sessionCart SessionCart, sessionID str
 	for _, p := range sessionCart.SessionCartProducts {
 		sessionCartProducts = append(sessionCartProducts, entity.SessionCartProduct{
 			ProductID: p.ProductID,
+			SKUID:     p.SKUID,
+			Count:     p.Count,
+		})
+	}
+
+	sessionSavedProducts := make([]entity.SessionCartProduct, 0, len(sessionCart.SessionSavedProducts))
+	for _, p := range sessionCart.SessionSavedProducts {
+		sessionSavedProducts = append(sessionSavedProducts, entity.SessionCartProduct{
+			ProductID: p.ProductID,
+			SKUID:     p.SKUID,
 			Count:     p.Count,
 		})
 	}
 
 	return entity.SessionCart{
-		SessionID:           sessionID,
-		SessionCartProducts: sessionCartProducts,
+		SessionID:            sessionID,
+		SessionCartProducts:  sessionCartProducts,
+		SessionSavedProducts: sessionSavedProducts,
 	}
 }
 
@@ -0,0 +1,91 @@
+package persistance
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-redis/redis/v8"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+)
+
+type (
+	//セッションお気に入りリスト
+	SessionWishlist struct {
+		ProductIDs []string `json:"productIDs"`
+	}
+
+	sessionWishlistRepository struct {
+		redisClient *redis.Client
+	}
+)
+
+func NewSessionWishlistRepository(redisClient *redis.Client) sessionWishlistRepository {
+	return sessionWishlistRepository{
+		redisClient: redisClient,
+	}
+}
+
+func (swr sessionWishlistRepository) FindBySessionID(ctx context.Context, sessionID string) (entity.SessionWishlist, bool, error) {
+	// Redisからセッションお気に入りリスト情報を取得
+	data, err := swr.redisClient.Get(ctx, sessionID).Bytes()
+	if err != nil {
+		// セッションIDが見つからない場合
+		if errors.Is(err, redis.Nil) {
+			return entity.SessionWishlist{}, false, nil
+		}
+
+		// その他のエラーの場合
+		return entity.SessionWishlist{}, false, errors.WithStack(err)
+	}
+
+	// JSONデコードしてセッションお気に入りリスト構造体に変換
+	var sessionWishlist SessionWishlist
+	err = json.Unmarshal(data, &sessionWishlist)
+	if err != nil {
+		return entity.SessionWishlist{}, false, errors.WithStack(err)
+	}
+
+	return swr.toEntity(sessionWishlist, sessionID), true, nil
+}
+
+// セッションお気に入りリストを保存する
+func (swr sessionWishlistRepository) Save(ctx context.Context, sessionWishlist entity.SessionWishlist, expiration time.Duration) error {
+	data, err := json.Marshal(swr.toModel(sessionWishlist))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = swr.redisClient.Set(ctx, sessionWishlist.SessionID, data, expiration).Err()
+	return errors.WithStack(err)
+}
+
+func (swr sessionWishlistRepository) Delete(ctx context.Context, sessionWishlist entity.SessionWishlist) error {
+	err := swr.redisClient.Del(ctx, sessionWishlist.SessionID).Err()
+	return errors.WithStack(err)
+}
+
+// セッションお気に入りリストの有効期限を更新する
+func (swr sessionWishlistRepository) UpdateExpiration(ctx context.Context, sessionWishlist entity.SessionWishlist, expiration time.Duration) error {
+	err := swr.redisClient.Expire(ctx, sessionWishlist.SessionID, expiration).Err()
+	return errors.WithStack(err)
+}
+
+func (swr sessionWishlistRepository) toEntity(sessionWishlist SessionWishlist, sessionID string) entity.SessionWishlist {
+	productIDs := sessionWishlist.ProductIDs
+	if productIDs == nil {
+		productIDs = []string{}
+	}
+
+	return entity.SessionWishlist{
+		SessionID:  sessionID,
+		ProductIDs: productIDs,
+	}
+}
+
+func (swr sessionWishlistRepository) toModel(sessionWishlist entity.SessionWishlist) SessionWishlist {
+	return SessionWishlist{
+		ProductIDs: sessionWishlist.ProductIDs,
+	}
+}
@@ -0,0 +1,103 @@
+package persistance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 在庫台帳テーブル（登録のみ行い、更新・削除しない）
+	StockMovement struct {
+		bun.BaseModel `bun:"table:stock_movements"`
+
+		ID             string    `bun:",pk"`
+		ProductID      string    `bun:",notnull"`
+		SKUID          *string   `bun:"sku_id"` // バリエーションを持たない商品の場合はNULL
+		Type           string    `bun:",notnull"`
+		Quantity       int       `bun:",notnull"`
+		BeforeCount    int       `bun:",notnull"`
+		AfterCount     int       `bun:",notnull"`
+		Reason         string    `bun:",notnull"`
+		ReferenceType  string    `bun:",notnull"`
+		ReferenceID    string    `bun:",notnull"`
+		CreateDateTime time.Time `bun:",notnull"`
+	}
+
+	// 在庫台帳リポジトリの実装
+	stockMovementRepository struct {
+		timeUtils util.TimeUtils
+	}
+)
+
+func NewStockMovementRepository(timeUtils util.TimeUtils) stockMovementRepository {
+	return stockMovementRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+func (smr stockMovementRepository) Insert(db bun.IDB, ctx context.Context, movement entity.StockMovement) error {
+	mMovement := smr.toModel(movement)
+	_, err := db.NewInsert().Model(&mMovement).Exec(ctx)
+	return errors.WithStack(err)
+}
+
+func (smr stockMovementRepository) FindBalances(db bun.IDB, ctx context.Context) ([]entity.StockLedgerBalance, error) {
+	var rows []struct {
+		ProductID  string
+		SKUID      *string `bun:"sku_id"`
+		Quantity   int
+		EntryCount int
+	}
+	err := db.NewSelect().
+		Model((*StockMovement)(nil)).
+		Column("product_id", "sku_id").
+		ColumnExpr("SUM(quantity) AS quantity").
+		ColumnExpr("COUNT(*) AS entry_count").
+		Group("product_id", "sku_id").
+		Order("product_id", "sku_id").
+		Scan(ctx, &rows)
+	if err != nil {
+		return []entity.StockLedgerBalance{}, errors.WithStack(err)
+	}
+
+	balances := make([]entity.StockLedgerBalance, 0, len(rows))
+	for _, row := range rows {
+		var skuID string
+		if row.SKUID != nil {
+			skuID = *row.SKUID
+		}
+		balances = append(balances, entity.StockLedgerBalance{
+			ProductID:  row.ProductID,
+			SKUID:      skuID,
+			Quantity:   row.Quantity,
+			EntryCount: row.EntryCount,
+		})
+	}
+	return balances, nil
+}
+
+func (smr stockMovementRepository) toModel(movement entity.StockMovement) StockMovement {
+	var skuID *string
+	if movement.SKUID != "" {
+		skuID = &movement.SKUID
+	}
+
+	return StockMovement{
+		ID:             movement.ID,
+		ProductID:      movement.ProductID,
+		SKUID:          skuID,
+		Type:           string(movement.Type),
+		Quantity:       movement.Quantity,
+		BeforeCount:    movement.BeforeCount,
+		AfterCount:     movement.AfterCount,
+		Reason:         movement.Reason,
+		ReferenceType:  string(movement.ReferenceType),
+		ReferenceID:    movement.ReferenceID,
+		CreateDateTime: smr.timeUtils.TimeToUTC(movement.CreateDateTime),
+	}
+}
@@ -0,0 +1,230 @@
+package persistance
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// 在庫引当テーブル
+	StockReservation struct {
+		bun.BaseModel `bun:"table:stock_reservations"`
+
+		ID             string    `bun:",pk"`
+		AccountID      string    `bun:",notnull"`
+		Status         string    `bun:",notnull"`
+		OrderID        *string   // 確定前はNULL
+		ExpiresAt      time.Time `bun:",notnull"`
+		CreateDateTime time.Time `bun:",notnull"`
+		UpdateDateTime *time.Time
+		Version        int `bun:",notnull"`
+
+		StockReservationItems []StockReservationItem `bun:"rel:has-many,join:id=reservation_id"`
+	}
+
+	// 在庫引当の商品テーブル
+	StockReservationItem struct {
+		bun.BaseModel `bun:"table:stock_reservation_items"`
+
+		ID            string  `bun:",pk"`
+		ReservationID string  `bun:",notnull"`
+		ProductID     string  `bun:",notnull"`
+		SKUID         *string `bun:"sku_id"` // バリエーションを持たない商品の場合はNULL
+		Count         int     `bun:",notnull"`
+	}
+
+	// 在庫引当リポジトリの実装
+	stockReservationRepository struct {
+		timeUtils util.TimeUtils
+	}
+)
+
+func NewStockReservationRepository(timeUtils util.TimeUtils) stockReservationRepository {
+	return stockReservationRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+func (srr stockReservationRepository) FindByIDForUpdate(db bun.IDB, ctx context.Context, id string) (entity.StockReservation, bool, error) {
+	var reservation StockReservation
+	err := srr.selectReservations(db, &reservation).Where("stock_reservation.id = ?", id).For("UPDATE").Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.StockReservation{}, false, nil
+		}
+
+		return entity.StockReservation{}, false, errors.WithStack(err)
+	}
+
+	return srr.toEntity(reservation), true, nil
+}
+
+func (srr stockReservationRepository) FindReservedByAccountIDForUpdate(db bun.IDB, ctx context.Context, accountID string) ([]entity.StockReservation, error) {
+	var reservations []StockReservation
+	err := srr.selectReservations(db, &reservations).
+		Where("stock_reservation.account_id = ?", accountID).
+		Where("stock_reservation.status = ?", string(enum.StockReservationStatusReserved)).
+		Order("stock_reservation.create_date_time").
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return []entity.StockReservation{}, errors.WithStack(err)
+	}
+
+	eReservations := make([]entity.StockReservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		eReservations = append(eReservations, srr.toEntity(reservation))
+	}
+	return eReservations, nil
+}
+
+func (srr stockReservationRepository) FindExpiredIDs(db bun.IDB, ctx context.Context, now time.Time, limit int) ([]string, error) {
+	ids := []string{}
+	err := db.NewSelect().
+		Model((*StockReservation)(nil)).
+		Column("id").
+		Where("status = ?", string(enum.StockReservationStatusReserved)).
+		Where("expires_at <= ?", srr.timeUtils.TimeToUTC(now)).
+		Order("expires_at").
+		Limit(limit).
+		Scan(ctx, &ids)
+	return ids, errors.WithStack(err)
+}
+
+func (srr stockReservationRepository) ExistsCommittedByAccountIDAndProductID(db bun.IDB, ctx context.Context, accountID string, productID string) (bool, error) {
+	exists, err := db.NewSelect().
+		Model((*StockReservationItem)(nil)).
+		Join("JOIN stock_reservations AS stock_reservation ON stock_reservation.id = stock_reservation_item.reservation_id").
+		Where("stock_reservation.account_id = ?", accountID).
+		Where("stock_reservation.status = ?", string(enum.StockReservationStatusCommitted)).
+		Where("stock_reservation_item.product_id = ?", productID).
+		Exists(ctx)
+	return exists, errors.WithStack(err)
+}
+
+func (srr stockReservationRepository) Insert(db bun.IDB, ctx context.Context, reservation entity.StockReservation) error {
+	mReservation := srr.toModel(reservation)
+	_, err := db.NewInsert().Model(&mReservation).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(mReservation.StockReservationItems) > 0 {
+		_, err = db.NewInsert().Model(&mReservation.StockReservationItems).Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+func (srr stockReservationRepository) Update(db bun.IDB, ctx context.Context, reservation entity.StockReservation) error {
+	mReservation := srr.toModel(reservation)
+
+	//在庫引当を更新する（楽観ロックする）
+	mReservation.Version = mReservation.Version + 1
+	res, err := db.NewUpdate().
+		Model(&mReservation).
+		Column("status", "order_id", "update_date_time", "version").
+		WherePK().
+		Where("version = ?", mReservation.Version-1).
+		Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if count != 1 {
+		return ErrOptimisticLocking
+	}
+
+	return nil
+}
+
+func (srr stockReservationRepository) selectReservations(db bun.IDB, model interface{}) *bun.SelectQuery {
+	return db.NewSelect().Model(model).
+		Relation("StockReservationItems", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Order("stock_reservation_item.product_id", "stock_reservation_item.sku_id")
+		})
+}
+
+func (srr stockReservationRepository) toEntity(reservation StockReservation) entity.StockReservation {
+	items := make([]entity.StockReservationItem, 0, len(reservation.StockReservationItems))
+	for _, item := range reservation.StockReservationItems {
+		var skuID string
+		if item.SKUID != nil {
+			skuID = *item.SKUID
+		}
+		items = append(items, entity.StockReservationItem{
+			ID:            item.ID,
+			ReservationID: item.ReservationID,
+			ProductID:     item.ProductID,
+			SKUID:         skuID,
+			Count:         item.Count,
+		})
+	}
+
+	var updateDateTime *time.Time
+	if reservation.UpdateDateTime != nil {
+		jp := srr.timeUtils.TimeToJP(*reservation.UpdateDateTime)
+		updateDateTime = &jp
+	}
+
+	return entity.StockReservation{
+		ID:             reservation.ID,
+		AccountID:      reservation.AccountID,
+		Status:         enum.StockReservationStatus(reservation.Status),
+		OrderID:        reservation.OrderID,
+		ExpiresAt:      srr.timeUtils.TimeToJP(reservation.ExpiresAt),
+		CreateDateTime: srr.timeUtils.TimeToJP(reservation.CreateDateTime),
+		UpdateDateTime: updateDateTime,
+		Version:        reservation.Version,
+		Items:          items,
+	}
+}
+
+func (srr stockReservationRepository) toModel(reservation entity.StockReservation) StockReservation {
+	items := make([]StockReservationItem, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		var skuID *string
+		if item.SKUID != "" {
+			skuID = &item.SKUID
+		}
+		items = append(items, StockReservationItem{
+			ID:            item.ID,
+			ReservationID: item.ReservationID,
+			ProductID:     item.ProductID,
+			SKUID:         skuID,
+			Count:         item.Count,
+		})
+	}
+
+	var updateDateTime *time.Time
+	if reservation.UpdateDateTime != nil {
+		utc := srr.timeUtils.TimeToUTC(*reservation.UpdateDateTime)
+		updateDateTime = &utc
+	}
+
+	return StockReservation{
+		ID:                    reservation.ID,
+		AccountID:             reservation.AccountID,
+		Status:                string(reservation.Status),
+		OrderID:               reservation.OrderID,
+		ExpiresAt:             srr.timeUtils.TimeToUTC(reservation.ExpiresAt),
+		CreateDateTime:        srr.timeUtils.TimeToUTC(reservation.CreateDateTime),
+		UpdateDateTime:        updateDateTime,
+		Version:               reservation.Version,
+		StockReservationItems: items,
+	}
+}
@@ -0,0 +1,215 @@
+package persistance
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/uptrace/bun"
+)
+
+type (
+	// お気に入りリストテーブル
+	Wishlist struct {
+		bun.BaseModel `bun:"table:wishlists"`
+
+		ID               string            `bun:",pk"`
+		AccountID        string            `bun:",notnull"`
+		Name             string            `bun:",notnull"`
+		IsDefault        bool              `bun:",notnull"`
+		ShareToken       *string           `bun:",unique"`
+		Version          int               `bun:",notnull"`
+		CreateDateTime   time.Time         `bun:",notnull"`
+		WishlistProducts []WishlistProduct `bun:"rel:has-many,join:id=wishlist_id"`
+	}
+
+	// お気に入り商品テーブル
+	WishlistProduct struct {
+		bun.BaseModel `bun:"table:wishlist_products"`
+
+		ID             string    `bun:",pk"`
+		WishlistID     string    `bun:",notnull"`
+		ProductID      string    `bun:",notnull"`
+		CreateDateTime time.Time `bun:",notnull"`
+	}
+
+	// お気に入りリストリポジトリの実装
+	wishlistRepository struct {
+		timeUtils util.TimeUtils
+	}
+)
+
+func NewWishlistRepository(timeUtils util.TimeUtils) wishlistRepository {
+	return wishlistRepository{
+		timeUtils: timeUtils,
+	}
+}
+
+// アカウントIDに一致するお気に入りリスト配列を作成日時の昇順で返却する
+func (wr wishlistRepository) FindByAccountID(db bun.IDB, ctx context.Context, accountID string) ([]entity.Wishlist, error) {
+	var wishlists []Wishlist
+	err := db.NewSelect().Model(&wishlists).
+		Relation("WishlistProducts", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Order("wishlist_product.create_date_time DESC")
+		}).
+		Where("account_id = ?", accountID).
+		Order("create_date_time ASC").
+		Scan(ctx)
+	if err != nil {
+		return []entity.Wishlist{}, errors.WithStack(err)
+	}
+
+	eWishlists := make([]entity.Wishlist, 0, len(wishlists))
+	for _, wishlist := range wishlists {
+		eWishlists = append(eWishlists, wr.toEntity(wishlist))
+	}
+	return eWishlists, nil
+}
+
+// お気に入りリストIDとアカウントIDに一致するお気に入りリストを返却する
+func (wr wishlistRepository) FindByIDAndAccountID(db bun.IDB, ctx context.Context, id string, accountID string) (entity.Wishlist, bool, error) {
+	return wr.findOne(db, ctx, func(sq *bun.SelectQuery) *bun.SelectQuery {
+		return sq.Where("wishlist.id = ?", id).Where("account_id = ?", accountID)
+	})
+}
+
+// 共有トークンに一致する公開中のお気に入りリストを返却する
+func (wr wishlistRepository) FindByShareToken(db bun.IDB, ctx context.Context, shareToken string) (entity.Wishlist, bool, error) {
+	return wr.findOne(db, ctx, func(sq *bun.SelectQuery) *bun.SelectQuery {
+		return sq.Where("share_token = ?", shareToken)
+	})
+}
+
+// お気に入りリスト集約を登録する
+func (wr wishlistRepository) Insert(db bun.IDB, ctx context.Context, wishlist entity.Wishlist) error {
+	mWishlist := wr.toModel(wishlist)
+
+	_, err := db.NewInsert().Model(&mWishlist).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(mWishlist.WishlistProducts) > 0 {
+		_, err = db.NewInsert().Model(&mWishlist.WishlistProducts).Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+// お気に入りリスト集約を更新する
+func (wr wishlistRepository) Update(db bun.IDB, ctx context.Context, wishlist entity.Wishlist) error {
+	mWishlist := wr.toModel(wishlist)
+
+	//お気に入り商品をすべて削除する
+	_, err := db.NewDelete().Model(new(WishlistProduct)).Where("wishlist_id = ?", mWishlist.ID).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	//すべてのお気に入り商品を登録する
+	if len(mWishlist.WishlistProducts) > 0 {
+		_, err = db.NewInsert().Model(&mWishlist.WishlistProducts).Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	//お気に入りリストを更新する（楽観ロックする）
+	mWishlist.Version = mWishlist.Version + 1
+	res, err := db.NewUpdate().Model(&mWishlist).WherePK().Where("version = ?", mWishlist.Version-1).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if count != 1 {
+		return ErrOptimisticLocking
+	}
+
+	return nil
+}
+
+// お気に入りリスト集約を削除する
+func (wr wishlistRepository) Delete(db bun.IDB, ctx context.Context, wishlist entity.Wishlist) error {
+	_, err := db.NewDelete().Model(new(WishlistProduct)).Where("wishlist_id = ?", wishlist.ID).Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = db.NewDelete().Model(new(Wishlist)).Where("id = ?", wishlist.ID).Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// 引数whereで絞り込んだお気に入りリストを1件返却する
+func (wr wishlistRepository) findOne(db bun.IDB, ctx context.Context, where func(sq *bun.SelectQuery) *bun.SelectQuery) (entity.Wishlist, bool, error) {
+	var wishlist Wishlist
+	query := db.NewSelect().Model(&wishlist).
+		Relation("WishlistProducts", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Order("wishlist_product.create_date_time DESC")
+		})
+	err := where(query).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Wishlist{}, false, nil
+		}
+
+		return entity.Wishlist{}, false, errors.WithStack(err)
+	}
+
+	return wr.toEntity(wishlist), true, nil
+}
+
+func (wr wishlistRepository) toModel(wishlist entity.Wishlist) Wishlist {
+	wishlistProducts := make([]WishlistProduct, 0, len(wishlist.WishlistProducts))
+	for _, p := range wishlist.WishlistProducts {
+		wishlistProducts = append(wishlistProducts, WishlistProduct{
+			ID:             p.ID,
+			WishlistID:     p.WishlistID,
+			ProductID:      p.ProductID,
+			CreateDateTime: wr.timeUtils.TimeToUTC(p.CreateDateTime),
+		})
+	}
+
+	return Wishlist{
+		ID:               wishlist.ID,
+		AccountID:        wishlist.AccountID,
+		Name:             wishlist.Name,
+		IsDefault:        wishlist.IsDefault,
+		ShareToken:       wishlist.ShareToken,
+		Version:          wishlist.Version,
+		CreateDateTime:   wr.timeUtils.TimeToUTC(wishlist.CreateDateTime),
+		WishlistProducts: wishlistProducts,
+	}
+}
+
+func (wr wishlistRepository) toEntity(wishlist Wishlist) entity.Wishlist {
+	wishlistProducts := make([]entity.WishlistProduct, 0, len(wishlist.WishlistProducts))
+	for _, p := range wishlist.WishlistProducts {
+		wishlistProducts = append(wishlistProducts, entity.WishlistProduct{
+			ID:             p.ID,
+			WishlistID:     p.WishlistID,
+			ProductID:      p.ProductID,
+			CreateDateTime: wr.timeUtils.TimeToJP(p.CreateDateTime),
+		})
+	}
+
+	return entity.Wishlist{
+		ID:               wishlist.ID,
+		AccountID:        wishlist.AccountID,
+		Name:             wishlist.Name,
+		IsDefault:        wishlist.IsDefault,
+		ShareToken:       wishlist.ShareToken,
+		Version:          wishlist.Version,
+		CreateDateTime:   wr.timeUtils.TimeToJP(wishlist.CreateDateTime),
+		WishlistProducts: wishlistProducts,
+	}
+}
@@ -27,6 +27,11 @@ type AccountCreationForm struct {
 	PasswordConfirmation string `json:"passwordConfirmation"`
 }
 
+// 宣伝メール配信設定の更新時のフォーム
+type MarketingEmailSettingForm struct {
+	OptOut bool `json:"optOut"`
+}
+
 // メールアドレスによって新規アカウントを登録する
 func (ac AccountController) CreateAccountByEmail(c echo.Context) error {
 	form := new(AccountCreationForm)
@@ -68,3 +73,23 @@ func (ac AccountController) AuthenticateEmail(c echo.Context) error {
 	c.SetCookie(&accountSessionCookie)
 	return c.Redirect(http.StatusMovedPermanently, fmt.Sprintf("%s?message=%s", os.Getenv("FRONT_URL"), "メールアドレスを認証し、ログインしました"))
 }
+
+// ログイン中のアカウントの宣伝メール配信設定を更新する
+func (ac AccountController) UpdateMarketingEmailSetting(c echo.Context) error {
+	form := new(MarketingEmailSettingForm)
+	err := c.Bind(form)
+	if err != nil {
+		return err
+	}
+
+	err = ac.accountUsecase.UpdateMarketingEmailOptOut(c.Request().Context(), form.OptOut)
+	if err != nil {
+		if oe, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(oe))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/labstack/echo/v4"
+)
+
+type BackInStockController struct {
+	backInStockUsecase usecase.BackInStockUsecase
+}
+
+func NewBackInStockController(backInStockUsecase usecase.BackInStockUsecase) BackInStockController {
+	return BackInStockController{
+		backInStockUsecase: backInStockUsecase,
+	}
+}
+
+// ログイン中のアカウントで商品の入荷通知を登録する
+func (bc BackInStockController) Subscribe(c echo.Context) error {
+	err := bc.backInStockUsecase.Subscribe(c.Request().Context(), c.Param("id"))
+	return bc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントの商品の入荷通知を解除する
+func (bc BackInStockController) Unsubscribe(c echo.Context) error {
+	err := bc.backInStockUsecase.Unsubscribe(c.Request().Context(), c.Param("id"))
+	return bc.resultJSON(c, err)
+}
+
+// エラーが存在しない場合は成功のレスポンスを、OriginalErrorの場合はエラーメッセージのレスポンスを返却する
+func (bc BackInStockController) resultJSON(c echo.Context, err error) error {
+	if err != nil {
+		if oe, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(oe))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	CartController struct {
+		cartUsecase usecase.CartUsecase
+	}
+
+	// カートに商品を追加する際のフォーム
+	CartProductAdditionForm struct {
+		ProductID string `json:"productID"`
+		SKUID     string `json:"skuID"` // バリエーションを持たない商品の場合は空文字
+		Count     int    `json:"count"`
+	}
+
+	// カートのレスポンス
+	CartResponse struct {
+		ID            string                 `json:"id"`
+		CartProducts  []CartProductResponse  `json:"cartProducts"`
+		SavedProducts []SavedProductResponse `json:"savedProducts"`
+		TotalCount    int                    `json:"totalCount"` // カート内の商品の合計個数（あとで買う商品は含めない）
+		TotalPrice    int                    `json:"totalPrice"` // カート内の商品の合計金額（あとで買う商品は含めない）
+		SavedCount    int                    `json:"savedCount"` // あとで買う商品の合計個数
+		Warnings      []CartWarningResponse  `json:"warnings"`
+	}
+
+	// カート商品のレスポンス
+	CartProductResponse struct {
+		ID           string             `json:"id"`
+		ProductID    string             `json:"productID"`
+		SKUID        string             `json:"skuID"`
+		Name         string             `json:"name"` // バリエーションを持つ商品の場合はバリエーションの値を含む
+		Count        int                `json:"count"`
+		Price        int                `json:"price"`        // カート追加時の販売価格
+		CurrentPrice int                `json:"currentPrice"` // 現在の販売価格
+		Status       enum.ProductStatus `json:"status"`
+		StockCount   int                `json:"stockCount"`
+	}
+
+	// あとで買う商品のレスポンス
+	SavedProductResponse struct {
+		ID           string             `json:"id"`
+		ProductID    string             `json:"productID"`
+		SKUID        string             `json:"skuID"`
+		Name         string             `json:"name"` // バリエーションを持つ商品の場合はバリエーションの値を含む
+		Count        int                `json:"count"`
+		Price        int                `json:"price"`        // あとで買うに追加した時点の販売価格
+		CurrentPrice int                `json:"currentPrice"` // 現在の販売価格
+		Status       enum.ProductStatus `json:"status"`
+		StockCount   int                `json:"stockCount"`
+	}
+
+	// カート商品の警告のレスポンス
+	CartWarningResponse struct {
+		CartProductID string               `json:"cartProductID"`
+		ProductID     string               `json:"productID"`
+		SKUID         string               `json:"skuID"`
+		Type          enum.CartWarningType `json:"type"`
+		PreviousPrice int                  `json:"previousPrice"`
+		CurrentPrice  int                  `json:"currentPrice"`
+		StockCount    int                  `json:"stockCount"`
+		Message       string               `json:"message"`
+	}
+)
+
+func NewCartController(cartUsecase usecase.CartUsecase) CartController {
+	return CartController{
+		cartUsecase: cartUsecase,
+	}
+}
+
+// ログイン中のアカウントのカートを取得する
+func (cc CartController) FindCart(c echo.Context) error {
+	cartDetail, err := cc.cartUsecase.FindCart(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toCartResponse(cartDetail))
+}
+
+// ログイン中のアカウントのカートに商品を追加する
+func (cc CartController) AddProduct(c echo.Context) error {
+	var form CartProductAdditionForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = cc.cartUsecase.AddProduct(c.Request().Context(), form.ProductID, form.SKUID, form.Count)
+	if err != nil {
+		if originalErr, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(originalErr))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
+
+// カート内の商品を「あとで買う」に移動する
+func (cc CartController) MoveToSaved(c echo.Context) error {
+	err := cc.cartUsecase.MoveToSaved(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if originalErr, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(originalErr))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
+
+// 「あとで買う」の商品をカートに移動する
+func (cc CartController) MoveToCart(c echo.Context) error {
+	err := cc.cartUsecase.MoveToCart(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if originalErr, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(originalErr))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
+
+func toCartResponse(cartDetail usecase.CartDetail) CartResponse {
+	productMap := make(map[string]entity.Product, len(cartDetail.Products))
+	for _, product := range cartDetail.Products {
+		productMap[product.ID] = product
+	}
+
+	cartProducts := make([]CartProductResponse, 0, len(cartDetail.Cart.CartProducts))
+	for _, cartProduct := range cartDetail.Cart.CartProducts {
+		// 商品・SKUが存在しない場合はカート商品を表示しない
+		product, ok := productMap[cartProduct.ProductID]
+		if !ok {
+			continue
+		}
+		sku, ok := product.FindSKU(cartProduct.SKUID)
+		if !ok {
+			continue
+		}
+
+		cartProducts = append(cartProducts, CartProductResponse{
+			ID:           cartProduct.ID,
+			ProductID:    cartProduct.ProductID,
+			SKUID:        cartProduct.SKUID,
+			Name:         product.SKUDisplayName(sku),
+			Count:        cartProduct.Count,
+			Price:        cartProduct.Price,
+			CurrentPrice: product.SKUPrice(sku),
+			Status:       product.Status,
+			StockCount:   sku.StockCount,
+		})
+	}
+
+	savedProducts := make([]SavedProductResponse, 0, len(cartDetail.Cart.SavedProducts))
+	for _, savedProduct := range cartDetail.Cart.SavedProducts {
+		// 商品・SKUが存在しない場合はあとで買う商品を表示しない
+		product, ok := productMap[savedProduct.ProductID]
+		if !ok {
+			continue
+		}
+		sku, ok := product.FindSKU(savedProduct.SKUID)
+		if !ok {
+			continue
+		}
+
+		savedProducts = append(savedProducts, SavedProductResponse{
+			ID:           savedProduct.ID,
+			ProductID:    savedProduct.ProductID,
+			SKUID:        savedProduct.SKUID,
+			Name:         product.SKUDisplayName(sku),
+			Count:        savedProduct.Count,
+			Price:        savedProduct.Price,
+			CurrentPrice: product.SKUPrice(sku),
+			Status:       product.Status,
+			StockCount:   sku.StockCount,
+		})
+	}
+
+	warnings := make([]CartWarningResponse, 0, len(cartDetail.Warnings))
+	for _, warning := range cartDetail.Warnings {
+		warnings = append(warnings, CartWarningResponse{
+			CartProductID: warning.CartProductID,
+			ProductID:     warning.ProductID,
+			SKUID:         warning.SKUID,
+			Type:          warning.Type,
+			PreviousPrice: warning.PreviousPrice,
+			CurrentPrice:  warning.CurrentPrice,
+			StockCount:    warning.StockCount,
+			Message:       warning.Message,
+		})
+	}
+
+	return CartResponse{
+		ID:            cartDetail.Cart.ID,
+		CartProducts:  cartProducts,
+		SavedProducts: savedProducts,
+		TotalCount:    cartDetail.Cart.TotalCount(),
+		TotalPrice:    cartDetail.Cart.TotalPrice(cartDetail.Products),
+		SavedCount:    cartDetail.Cart.SavedCount(),
+		Warnings:      warnings,
+	}
+}
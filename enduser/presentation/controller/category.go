@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	CategoryController struct {
+		categoryUsecase usecase.CategoryUsecase
+	}
+
+	// カテゴリーツリーの節点のレスポンス
+	CategoryNodeResponse struct {
+		CategoryResponse
+		Children []CategoryNodeResponse `json:"children"`
+	}
+)
+
+func NewCategoryController(categoryUsecase usecase.CategoryUsecase) CategoryController {
+	return CategoryController{
+		categoryUsecase: categoryUsecase,
+	}
+}
+
+// すべてのカテゴリーをカテゴリーツリーとして取得する
+func (cc CategoryController) FindTree(c echo.Context) error {
+	nodes, err := cc.categoryUsecase.FindTree(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toCategoryNodeResponses(nodes))
+}
+
+func toCategoryNodeResponses(nodes []entity.CategoryNode) []CategoryNodeResponse {
+	response := make([]CategoryNodeResponse, 0, len(nodes))
+	for _, node := range nodes {
+		response = append(response, CategoryNodeResponse{
+			CategoryResponse: toCategoryResponse(node.Category),
+			Children:         toCategoryNodeResponses(node.Children),
+		})
+	}
+	return response
+}
+
+func toCategoryResponse(category entity.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:   category.ID,
+		Name: category.Name,
+		Slug: category.Slug,
+	}
+}
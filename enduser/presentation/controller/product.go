@@ -0,0 +1,375 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	ProductController struct {
+		productUsecase        usecase.ProductUsecase
+		recentlyViewedUsecase usecase.RecentlyViewedUsecase
+	}
+
+	// 商品一覧取得時のクエリパラメーター
+	ProductCatalogForm struct {
+		CategoryID  string `query:"categoryID"`
+		MinPrice    int    `query:"minPrice"`
+		MaxPrice    int    `query:"maxPrice"`
+		OnSaleOnly  bool   `query:"onSale"`
+		InStockOnly bool   `query:"inStock"`
+		Sort        string `query:"sort"` // newest・price_asc・price_desc・review_score
+		Cursor      string `query:"cursor"`
+		Limit       int    `query:"limit"`
+	}
+
+	// 商品検索時のクエリパラメーター
+	ProductSearchForm struct {
+		Keyword     string `query:"q"`
+		CategoryID  string `query:"categoryID"`
+		MinPrice    int    `query:"minPrice"`
+		MaxPrice    int    `query:"maxPrice"`
+		OnSaleOnly  bool   `query:"onSale"`
+		InStockOnly bool   `query:"inStock"`
+		Page        int    `query:"page"`
+		Limit       int    `query:"limit"`
+	}
+
+	// 商品検索結果のレスポンス
+	ProductSearchResponse struct {
+		Products   []ProductResponse `json:"products"`
+		TotalCount int               `json:"totalCount"`
+		Page       int               `json:"page"`
+		HasNext    bool              `json:"hasNext"`
+	}
+
+	// 商品一覧のレスポンス
+	ProductCatalogResponse struct {
+		Products   []ProductResponse      `json:"products"`
+		NextCursor *string                `json:"nextCursor"` // 次ページが存在しない場合はnull
+		Facets     *ProductFacetsResponse `json:"facets"`     // 先頭ページ以外はnull
+	}
+
+	// 絞り込み条件ごとの商品数のレスポンス
+	ProductFacetsResponse struct {
+		Categories       []CategoryFacetResponse        `json:"categories"`
+		PriceBuckets     []PriceBucketFacetResponse     `json:"priceBuckets"`
+		ReviewScoreBands []ReviewScoreBandFacetResponse `json:"reviewScoreBands"`
+		InStockCount     int                            `json:"inStockCount"`
+		OutOfStockCount  int                            `json:"outOfStockCount"`
+	}
+
+	// カテゴリーごとの商品数のレスポンス
+	CategoryFacetResponse struct {
+		CategoryID   string `json:"categoryID"`
+		CategoryName string `json:"categoryName"`
+		Count        int    `json:"count"`
+	}
+
+	// 価格帯ごとの商品数のレスポンス
+	PriceBucketFacetResponse struct {
+		MinPrice int `json:"minPrice"`
+		MaxPrice int `json:"maxPrice"` // 0の場合は上限なし
+		Count    int `json:"count"`
+	}
+
+	// レビュー点数の下限ごとの商品数のレスポンス
+	ReviewScoreBandFacetResponse struct {
+		MinScore int `json:"minScore"`
+		Count    int `json:"count"`
+	}
+
+	// 商品のレスポンス
+	ProductResponse struct {
+		ID             string                 `json:"id"`
+		CategoryID     string                 `json:"categoryID"`
+		CategoryName   string                 `json:"categoryName"`
+		Name           string                 `json:"name"`
+		ReviewScore    int                    `json:"reviewScore"`
+		Price          int                    `json:"price"`
+		SalePrice      int                    `json:"salePrice"`
+		EffectivePrice int                    `json:"effectivePrice"`
+		Description    string                 `json:"description"`
+		Status         enum.ProductStatus     `json:"status"`
+		StockCount     int                    `json:"stockCount"`
+		CreateDateTime time.Time              `json:"createDateTime"`
+		ProductImages  []ProductImageResponse `json:"productImages"`
+	}
+
+	// 商品詳細のレスポンス
+	ProductDetailResponse struct {
+		ProductResponse
+		Breadcrumbs   []CategoryResponse          `json:"breadcrumbs"`  // 上位のカテゴリーから順に並べたカテゴリー
+		DiscountRate  int                         `json:"discountRate"` // セール価格による割引率（%）
+		StockStatus   enum.StockStatus            `json:"stockStatus"`
+		ReviewSummary ReviewSummaryResponse       `json:"reviewSummary"`
+		OptionAxes    []ProductOptionAxisResponse `json:"optionAxes"` // バリエーションを持たない商品の場合は空
+		SKUs          []ProductSKUResponse        `json:"skus"`
+	}
+
+	// 商品のバリエーションの軸のレスポンス
+	ProductOptionAxisResponse struct {
+		ID     string   `json:"id"`
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	}
+
+	// SKUのレスポンス
+	ProductSKUResponse struct {
+		ID            string                     `json:"id"`
+		Code          string                     `json:"code"`
+		Options       []ProductSKUOptionResponse `json:"options"`
+		Price         int                        `json:"price"` // SKU固有の販売価格を考慮した実際に販売される価格
+		StockStatus   enum.StockStatus           `json:"stockStatus"`
+		StockCount    int                        `json:"stockCount"`
+		ProductImages []ProductImageResponse     `json:"productImages"`
+	}
+
+	// SKUのバリエーションの軸の値のレスポンス
+	ProductSKUOptionResponse struct {
+		AxisID string `json:"axisID"`
+		Value  string `json:"value"`
+	}
+
+	// カテゴリーのレスポンス
+	CategoryResponse struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	}
+
+	// レビュー概要のレスポンス
+	ReviewSummaryResponse struct {
+		Score int `json:"score"`
+	}
+
+	// 商品画像のレスポンス
+	ProductImageResponse struct {
+		ID     string                      `json:"id"`
+		Order  int                         `json:"order"`
+		Image  string                      `json:"image"`
+		Srcset map[enum.ImageFormat]string `json:"srcset"` // 画像形式（webp・jpeg）ごとのsrcset属性の値（派生画像の生成前は空）
+	}
+)
+
+func NewProductController(productUsecase usecase.ProductUsecase, recentlyViewedUsecase usecase.RecentlyViewedUsecase) ProductController {
+	return ProductController{
+		productUsecase:        productUsecase,
+		recentlyViewedUsecase: recentlyViewedUsecase,
+	}
+}
+
+// 検索条件に一致する商品一覧を取得する
+func (pc ProductController) FindCatalog(c echo.Context) error {
+	var form ProductCatalogForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	page, err := pc.productUsecase.FindCatalog(c.Request().Context(), usecase.ProductCatalogInput(form))
+	if err != nil {
+		if oe, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(oe))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toProductCatalogResponse(page))
+}
+
+// 商品詳細を取得する
+// 商品が存在しない場合や当日の商品ステータスが存在しない場合は404レスポンスを返却する
+// 商品を閲覧履歴に記録する
+func (pc ProductController) FindProduct(c echo.Context) error {
+	detail, ok, err := pc.productUsecase.FindProduct(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return echo.ErrNotFound
+	}
+
+	// ゲストの場合は閲覧履歴のクッキーをセットする
+	if cookie := pc.recentlyViewedUsecase.RecordView(c.Request().Context(), detail.Product.ID); cookie != nil {
+		c.SetCookie(cookie)
+	}
+
+	return c.JSON(http.StatusOK, toProductDetailResponse(detail))
+}
+
+// キーワードに一致する商品を関連度の高い順に検索する
+func (pc ProductController) Search(c echo.Context) error {
+	var form ProductSearchForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	page, err := pc.productUsecase.Search(c.Request().Context(), usecase.ProductSearchInput(form))
+	if err != nil {
+		if oe, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(oe))
+		}
+
+		return err
+	}
+
+	products := make([]ProductResponse, 0, len(page.Products))
+	for _, product := range page.Products {
+		products = append(products, toProductResponse(product))
+	}
+
+	return c.JSON(http.StatusOK, ProductSearchResponse{
+		Products:   products,
+		TotalCount: page.TotalCount,
+		Page:       page.Page,
+		HasNext:    page.HasNext,
+	})
+}
+
+func toProductCatalogResponse(page entity.ProductCatalogPage) ProductCatalogResponse {
+	products := make([]ProductResponse, 0, len(page.Products))
+	for _, product := range page.Products {
+		products = append(products, toProductResponse(product))
+	}
+
+	var nextCursor *string
+	if page.NextCursor != nil {
+		encoded := page.NextCursor.Encode()
+		nextCursor = &encoded
+	}
+
+	var facets *ProductFacetsResponse
+	if page.Facets != nil {
+		response := toProductFacetsResponse(*page.Facets)
+		facets = &response
+	}
+
+	return ProductCatalogResponse{
+		Products:   products,
+		NextCursor: nextCursor,
+		Facets:     facets,
+	}
+}
+
+func toProductFacetsResponse(facets entity.ProductFacets) ProductFacetsResponse {
+	categories := make([]CategoryFacetResponse, 0, len(facets.Categories))
+	for _, category := range facets.Categories {
+		categories = append(categories, CategoryFacetResponse{
+			CategoryID:   category.CategoryID,
+			CategoryName: category.CategoryName,
+			Count:        category.Count,
+		})
+	}
+
+	priceBuckets := make([]PriceBucketFacetResponse, 0, len(facets.PriceBuckets))
+	for _, priceBucket := range facets.PriceBuckets {
+		priceBuckets = append(priceBuckets, PriceBucketFacetResponse{
+			MinPrice: priceBucket.MinPrice,
+			MaxPrice: priceBucket.MaxPrice,
+			Count:    priceBucket.Count,
+		})
+	}
+
+	reviewScoreBands := make([]ReviewScoreBandFacetResponse, 0, len(facets.ReviewScoreBands))
+	for _, reviewScoreBand := range facets.ReviewScoreBands {
+		reviewScoreBands = append(reviewScoreBands, ReviewScoreBandFacetResponse{
+			MinScore: reviewScoreBand.MinScore,
+			Count:    reviewScoreBand.Count,
+		})
+	}
+
+	return ProductFacetsResponse{
+		Categories:       categories,
+		PriceBuckets:     priceBuckets,
+		ReviewScoreBands: reviewScoreBands,
+		InStockCount:     facets.InStockCount,
+		OutOfStockCount:  facets.OutOfStockCount,
+	}
+}
+
+func toProductResponse(product entity.Product) ProductResponse {
+	return ProductResponse{
+		ID:             product.ID,
+		CategoryID:     product.CategoryID,
+		CategoryName:   product.CategoryName,
+		Name:           product.Name,
+		ReviewScore:    product.ReviewScore,
+		Price:          product.Price,
+		SalePrice:      product.SalePrice,
+		EffectivePrice: product.EffectivePrice(),
+		Description:    product.Description,
+		Status:         product.Status,
+		StockCount:     product.StockCount,
+		CreateDateTime: product.CreateDateTime,
+		ProductImages:  toProductImageResponses(product.ProductImages),
+	}
+}
+
+func toProductImageResponses(productImages []entity.ProductImage) []ProductImageResponse {
+	images := make([]ProductImageResponse, 0, len(productImages))
+	for _, image := range productImages {
+		images = append(images, ProductImageResponse{
+			ID:     image.ID,
+			Order:  image.Order,
+			Image:  image.Image,
+			Srcset: image.Srcset(),
+		})
+	}
+
+	return images
+}
+
+func toProductDetailResponse(detail usecase.ProductDetail) ProductDetailResponse {
+	product := detail.Product
+	breadcrumbs := make([]CategoryResponse, 0, len(detail.Breadcrumbs))
+	for _, category := range detail.Breadcrumbs {
+		breadcrumbs = append(breadcrumbs, toCategoryResponse(category))
+	}
+
+	optionAxes := make([]ProductOptionAxisResponse, 0, len(product.OptionAxes))
+	for _, axis := range product.OptionAxes {
+		optionAxes = append(optionAxes, ProductOptionAxisResponse{
+			ID:     axis.ID,
+			Name:   axis.Name,
+			Values: product.OptionValues(axis.ID),
+		})
+	}
+
+	skus := make([]ProductSKUResponse, 0, len(product.SKUs))
+	for _, sku := range product.SKUs {
+		options := make([]ProductSKUOptionResponse, 0, len(sku.Options))
+		for _, option := range sku.Options {
+			options = append(options, ProductSKUOptionResponse{AxisID: option.AxisID, Value: option.Value})
+		}
+
+		skus = append(skus, ProductSKUResponse{
+			ID:            sku.ID,
+			Code:          sku.Code,
+			Options:       options,
+			Price:         product.SKUPrice(sku),
+			StockStatus:   sku.StockStatus(),
+			StockCount:    sku.StockCount,
+			ProductImages: toProductImageResponses(sku.ProductImages),
+		})
+	}
+
+	return ProductDetailResponse{
+		ProductResponse: toProductResponse(product),
+		Breadcrumbs:     breadcrumbs,
+		DiscountRate:    product.DiscountRate(),
+		StockStatus:     product.StockStatus(),
+		ReviewSummary:   ReviewSummaryResponse{Score: product.ReviewScore},
+		OptionAxes:      optionAxes,
+		SKUs:            skus,
+	}
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	ProductQuestionController struct {
+		productQuestionUsecase usecase.ProductQuestionUsecase
+	}
+
+	// 質問一覧取得時のクエリパラメーター
+	ProductQuestionListForm struct {
+		Page  int `query:"page"`
+		Limit int `query:"limit"`
+	}
+
+	// 質問の投稿時のフォーム
+	ProductQuestionForm struct {
+		ProductID string `json:"productID"`
+		Body      string `json:"body"`
+	}
+
+	// 質問への回答時のフォーム
+	ProductAnswerForm struct {
+		Body string `json:"body"`
+	}
+
+	// 質問一覧のレスポンス
+	ProductQuestionPageResponse struct {
+		Questions  []ProductQuestionResponse `json:"questions"`
+		TotalCount int                       `json:"totalCount"`
+		Page       int                       `json:"page"`
+		HasNext    bool                      `json:"hasNext"`
+	}
+
+	// 質問のレスポンス
+	ProductQuestionResponse struct {
+		ID             string                  `json:"id"`
+		ReviewNickname string                  `json:"reviewNickname"`
+		Body           string                  `json:"body"`
+		Answers        []ProductAnswerResponse `json:"answers"`
+		CreateDateTime time.Time               `json:"createDateTime"`
+	}
+
+	// 回答のレスポンス
+	ProductAnswerResponse struct {
+		ID                string                          `json:"id"`
+		ResponderType     enum.ProductAnswerResponderType `json:"responderType"`
+		ResponderNickname string                          `json:"responderNickname,omitempty"` // お客様の回答の場合のみ返却する
+		Body              string                          `json:"body"`
+		CreateDateTime    time.Time                       `json:"createDateTime"`
+	}
+)
+
+func NewProductQuestionController(productQuestionUsecase usecase.ProductQuestionUsecase) ProductQuestionController {
+	return ProductQuestionController{
+		productQuestionUsecase: productQuestionUsecase,
+	}
+}
+
+// 商品の公開中の質問一覧を取得する
+func (qc ProductQuestionController) FindProductQuestions(c echo.Context) error {
+	var form ProductQuestionListForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	page, err := qc.productQuestionUsecase.FindProductQuestions(c.Request().Context(), c.Param("id"), form.Page, form.Limit)
+	if err != nil {
+		return qc.resultJSON(c, err)
+	}
+
+	questions := make([]ProductQuestionResponse, 0, len(page.Questions))
+	for _, item := range page.Questions {
+		questions = append(questions, toProductQuestionResponse(item))
+	}
+
+	return c.JSON(http.StatusOK, ProductQuestionPageResponse{
+		Questions:  questions,
+		TotalCount: page.TotalCount,
+		Page:       page.Page,
+		HasNext:    page.HasNext,
+	})
+}
+
+// ログイン中のアカウントで商品に質問を投稿する
+func (qc ProductQuestionController) PostQuestion(c echo.Context) error {
+	var form ProductQuestionForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = qc.productQuestionUsecase.PostQuestion(c.Request().Context(), form.ProductID, form.Body)
+	return qc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントで質問に回答する
+func (qc ProductQuestionController) AnswerQuestion(c echo.Context) error {
+	var form ProductAnswerForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = qc.productQuestionUsecase.AnswerQuestion(c.Request().Context(), c.Param("id"), form.Body)
+	return qc.resultJSON(c, err)
+}
+
+// エラーが存在しない場合は成功のレスポンスを、OriginalErrorの場合はエラーメッセージのレスポンスを返却する
+func (qc ProductQuestionController) resultJSON(c echo.Context, err error) error {
+	if err != nil {
+		if originalErr, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(originalErr))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
+
+func toProductQuestionResponse(item entity.ProductQuestionListItem) ProductQuestionResponse {
+	answers := make([]ProductAnswerResponse, 0, len(item.Answers))
+	for _, answer := range item.Answers {
+		answers = append(answers, ProductAnswerResponse{
+			ID:                answer.ID,
+			ResponderType:     answer.ResponderType,
+			ResponderNickname: answer.ResponderNickname,
+			Body:              answer.Body,
+			CreateDateTime:    answer.CreateDateTime,
+		})
+	}
+
+	return ProductQuestionResponse{
+		ID:             item.ID,
+		ReviewNickname: item.ReviewNickname,
+		Body:           item.Body,
+		Answers:        answers,
+		CreateDateTime: item.CreateDateTime,
+	}
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	ProductRankingController struct {
+		productRankingUsecase usecase.ProductRankingUsecase
+	}
+
+	// ランキング取得時のフォーム
+	ProductRankingForm struct {
+		Period     string `query:"period"`     // daily・weekly・monthly（初期値はdaily）
+		CategoryID string `query:"categoryId"` // 指定しない場合はサイト全体のランキング
+		Limit      int    `query:"limit"`
+	}
+
+	ProductRankingResponse struct {
+		Date       *time.Time                   `json:"date"` // ランキングが集計されていない場合はnull
+		Period     string                       `json:"period"`
+		CategoryID string                       `json:"categoryId"`
+		Items      []ProductRankingItemResponse `json:"items"`
+	}
+
+	ProductRankingItemResponse struct {
+		Rank    int             `json:"rank"`
+		Product ProductResponse `json:"product"`
+	}
+)
+
+func NewProductRankingController(productRankingUsecase usecase.ProductRankingUsecase) ProductRankingController {
+	return ProductRankingController{
+		productRankingUsecase: productRankingUsecase,
+	}
+}
+
+// 最新の集計日のサイト全体またはカテゴリーのランキングを取得する
+func (rc ProductRankingController) FindRanking(c echo.Context) error {
+	var form ProductRankingForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	page, err := rc.productRankingUsecase.FindRanking(c.Request().Context(), usecase.ProductRankingInput{
+		Period:     enum.RankingPeriod(form.Period),
+		CategoryID: form.CategoryID,
+		Limit:      form.Limit,
+	})
+	if err != nil {
+		if oe, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(oe))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toProductRankingResponse(page))
+}
+
+func toProductRankingResponse(page entity.ProductRankingPage) ProductRankingResponse {
+	items := make([]ProductRankingItemResponse, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, ProductRankingItemResponse{
+			Rank:    item.Rank,
+			Product: toProductResponse(item.Product),
+		})
+	}
+
+	var date *time.Time
+	if !page.Date.IsZero() {
+		date = &page.Date
+	}
+
+	return ProductRankingResponse{
+		Date:       date,
+		Period:     string(page.Period),
+		CategoryID: page.CategoryID,
+		Items:      items,
+	}
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	ProductRecommendationController struct {
+		productRecommendationUsecase usecase.ProductRecommendationUsecase
+	}
+
+	ProductRecommendationsResponse struct {
+		Source   string            `json:"source"` // co_purchase（一緒に購入されている商品）またはcategory_bestseller（同じカテゴリーの売れ筋商品）
+		Products []ProductResponse `json:"products"`
+	}
+)
+
+func NewProductRecommendationController(productRecommendationUsecase usecase.ProductRecommendationUsecase) ProductRecommendationController {
+	return ProductRecommendationController{
+		productRecommendationUsecase: productRecommendationUsecase,
+	}
+}
+
+// 商品詳細に表示するおすすめ商品を取得する
+func (pc ProductRecommendationController) FindRecommendations(c echo.Context) error {
+	recommendations, ok, err := pc.productRecommendationUsecase.FindRecommendations(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return echo.ErrNotFound
+	}
+
+	products := make([]ProductResponse, 0, len(recommendations.Products))
+	for _, product := range recommendations.Products {
+		products = append(products, toProductResponse(product))
+	}
+
+	return c.JSON(http.StatusOK, ProductRecommendationsResponse{
+		Source:   string(recommendations.Source),
+		Products: products,
+	})
+}
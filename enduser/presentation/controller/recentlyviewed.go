@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type RecentlyViewedController struct {
+	recentlyViewedUsecase usecase.RecentlyViewedUsecase
+}
+
+func NewRecentlyViewedController(recentlyViewedUsecase usecase.RecentlyViewedUsecase) RecentlyViewedController {
+	return RecentlyViewedController{
+		recentlyViewedUsecase: recentlyViewedUsecase,
+	}
+}
+
+// 最近閲覧した商品配列を取得する（ログイン中の場合はアカウントの、ゲストの場合はセッションの閲覧履歴）
+func (rc RecentlyViewedController) FindProducts(c echo.Context) error {
+	products, err := rc.recentlyViewedUsecase.FindProducts(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	response := make([]ProductResponse, 0, len(products))
+	for _, product := range products {
+		response = append(response, toProductResponse(product))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,316 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/service"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	ReviewController struct {
+		reviewUsecase usecase.ReviewUsecase
+	}
+
+	// レビュー一覧取得時のクエリパラメーター
+	ReviewListForm struct {
+		Sort  enum.ReviewSort `query:"sort"` // newest, helpful, rating_desc, rating_asc（省略時はnewest）
+		Page  int             `query:"page"`
+		Limit int             `query:"limit"`
+	}
+
+	// レビューへの投票時のフォーム
+	ReviewVoteForm struct {
+		IsHelpful bool `json:"isHelpful"`
+	}
+
+	// 不適切なレビューとしての報告時のフォーム
+	ReviewReportForm struct {
+		Reason string `json:"reason"`
+	}
+
+	// レビューの投稿・編集時のフォーム
+	// 写真を添付する場合はmultipart/form-dataで送信し、写真はphotosフィールドに添付する
+	ReviewForm struct {
+		ProductID     string `json:"productID" form:"productID"`
+		Rating        int    `json:"rating" form:"rating"`
+		Title         string `json:"title" form:"title"`
+		Body          string `json:"body" form:"body"`
+		ReplacePhotos bool   `json:"replacePhotos" form:"replacePhotos"` // 編集時に写真を置き換える場合true
+	}
+
+	// レビュー一覧のレスポンス
+	ReviewPageResponse struct {
+		Reviews            []ReviewResponse            `json:"reviews"`
+		TotalCount         int                         `json:"totalCount"`
+		Page               int                         `json:"page"`
+		HasNext            bool                        `json:"hasNext"`
+		AverageRating      float64                     `json:"averageRating"`
+		RatingDistribution []ReviewRatingCountResponse `json:"ratingDistribution"` // 評価の高い順
+	}
+
+	// 評価ごとのレビュー数のレスポンス
+	ReviewRatingCountResponse struct {
+		Rating     int `json:"rating"`
+		Count      int `json:"count"`
+		Percentage int `json:"percentage"`
+	}
+
+	// レビューのレスポンス
+	ReviewResponse struct {
+		ID                 string                `json:"id"`
+		ProductID          string                `json:"productID"`
+		ReviewNickname     string                `json:"reviewNickname,omitempty"`
+		Rating             int                   `json:"rating"`
+		Title              string                `json:"title"`
+		Body               string                `json:"body"`
+		IsVerifiedPurchase bool                  `json:"isVerifiedPurchase"`
+		HelpfulCount       int                   `json:"helpfulCount"`
+		UnhelpfulCount     int                   `json:"unhelpfulCount"`
+		Status             enum.ReviewStatus     `json:"status,omitempty"` // 自分のレビューの場合のみ返却する
+		Photos             []ReviewPhotoResponse `json:"photos"`
+		CreateDateTime     time.Time             `json:"createDateTime"`
+		UpdateDateTime     time.Time             `json:"updateDateTime"`
+	}
+
+	// レビューの写真のレスポンス
+	ReviewPhotoResponse struct {
+		ID    string `json:"id"`
+		Order int    `json:"order"`
+		Image string `json:"image"`
+	}
+)
+
+const reviewPhotoMaxBytes = 5 << 20 // レビューの写真1枚あたりの最大サイズ（5MB）
+
+func NewReviewController(reviewUsecase usecase.ReviewUsecase) ReviewController {
+	return ReviewController{
+		reviewUsecase: reviewUsecase,
+	}
+}
+
+// 商品の公開中のレビュー一覧を取得する
+func (rc ReviewController) FindProductReviews(c echo.Context) error {
+	var form ReviewListForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	productReviews, err := rc.reviewUsecase.FindProductReviews(c.Request().Context(), c.Param("id"), form.Sort, form.Page, form.Limit)
+	if err != nil {
+		return rc.resultJSON(c, err)
+	}
+
+	page := productReviews.Page
+	reviews := make([]ReviewResponse, 0, len(page.Reviews))
+	for _, item := range page.Reviews {
+		response := toReviewResponse(item.Review)
+		response.ReviewNickname = item.ReviewNickname
+		response.Status = ""
+		reviews = append(reviews, response)
+	}
+
+	return c.JSON(http.StatusOK, ReviewPageResponse{
+		Reviews:            reviews,
+		TotalCount:         page.TotalCount,
+		Page:               page.Page,
+		HasNext:            page.HasNext,
+		AverageRating:      productReviews.Distribution.Average(),
+		RatingDistribution: toReviewRatingCountResponses(productReviews.Distribution),
+	})
+}
+
+// ログイン中のアカウントが投稿したレビュー配列を取得する
+func (rc ReviewController) FindMyReviews(c echo.Context) error {
+	reviews, err := rc.reviewUsecase.FindMyReviews(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	response := make([]ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		response = append(response, toReviewResponse(review))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ログイン中のアカウントで商品のレビューを投稿する
+func (rc ReviewController) PostReview(c echo.Context) error {
+	form, input, err := rc.bindReviewForm(c)
+	if err != nil {
+		return rc.resultJSON(c, err)
+	}
+
+	err = rc.reviewUsecase.PostReview(c.Request().Context(), form.ProductID, input)
+	return rc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントのレビューを編集する
+func (rc ReviewController) EditReview(c echo.Context) error {
+	_, input, err := rc.bindReviewForm(c)
+	if err != nil {
+		return rc.resultJSON(c, err)
+	}
+
+	err = rc.reviewUsecase.EditReview(c.Request().Context(), c.Param("id"), input)
+	return rc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントのレビューを削除する
+func (rc ReviewController) DeleteReview(c echo.Context) error {
+	err := rc.reviewUsecase.DeleteReview(c.Request().Context(), c.Param("id"))
+	return rc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントでレビューに参考になった・参考にならなかったの投票をする
+func (rc ReviewController) Vote(c echo.Context) error {
+	var form ReviewVoteForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = rc.reviewUsecase.Vote(c.Request().Context(), c.Param("id"), form.IsHelpful)
+	return rc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントのレビューへの投票を取り消す
+func (rc ReviewController) Unvote(c echo.Context) error {
+	err := rc.reviewUsecase.Unvote(c.Request().Context(), c.Param("id"))
+	return rc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントでレビューを不適切なレビューとして報告する
+func (rc ReviewController) Report(c echo.Context) error {
+	var form ReviewReportForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = rc.reviewUsecase.Report(c.Request().Context(), c.Param("id"), form.Reason)
+	return rc.resultJSON(c, err)
+}
+
+// レビューのフォームと添付された写真を入力値に変換する
+func (rc ReviewController) bindReviewForm(c echo.Context) (ReviewForm, usecase.ReviewInput, error) {
+	var form ReviewForm
+	err := c.Bind(&form)
+	if err != nil {
+		return ReviewForm{}, usecase.ReviewInput{}, errors.WithStack(err)
+	}
+
+	photos := []service.ReviewPhotoFile{}
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		multipartForm, err := c.MultipartForm()
+		if err != nil {
+			return ReviewForm{}, usecase.ReviewInput{}, errors.WithStack(err)
+		}
+
+		for _, fileHeader := range multipartForm.File["photos"] {
+			photo, err := readReviewPhoto(fileHeader)
+			if err != nil {
+				return ReviewForm{}, usecase.ReviewInput{}, err
+			}
+			photos = append(photos, photo)
+		}
+	}
+
+	return form, usecase.ReviewInput{
+		Rating:        form.Rating,
+		Title:         form.Title,
+		Body:          form.Body,
+		Photos:        photos,
+		ReplacePhotos: form.ReplacePhotos,
+	}, nil
+}
+
+// エラーが存在しない場合は成功のレスポンスを、OriginalErrorの場合はエラーメッセージのレスポンスを返却する
+func (rc ReviewController) resultJSON(c echo.Context, err error) error {
+	if err != nil {
+		if originalErr, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(originalErr))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
+
+// 添付された写真を読み込む
+// 画像形式は申告されたContent-Typeではなくファイルの内容から判定する
+func readReviewPhoto(fileHeader *multipart.FileHeader) (service.ReviewPhotoFile, error) {
+	if fileHeader.Size > reviewPhotoMaxBytes {
+		return service.ReviewPhotoFile{}, share.CreateOriginalError(share.ErrorCodeValidation, []string{fmt.Sprintf("写真は1枚あたり%dMB以下にしてください", reviewPhotoMaxBytes>>20)})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return service.ReviewPhotoFile{}, errors.WithStack(err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, reviewPhotoMaxBytes))
+	if err != nil {
+		return service.ReviewPhotoFile{}, errors.WithStack(err)
+	}
+
+	return service.ReviewPhotoFile{
+		ContentType: http.DetectContentType(data),
+		Data:        data,
+	}, nil
+}
+
+func toReviewResponse(review entity.Review) ReviewResponse {
+	photos := make([]ReviewPhotoResponse, 0, len(review.Photos))
+	for _, photo := range review.Photos {
+		photos = append(photos, ReviewPhotoResponse{
+			ID:    photo.ID,
+			Order: photo.Order,
+			Image: photo.Image,
+		})
+	}
+
+	return ReviewResponse{
+		ID:                 review.ID,
+		ProductID:          review.ProductID,
+		Rating:             review.Rating,
+		Title:              review.Title,
+		Body:               review.Body,
+		IsVerifiedPurchase: review.IsVerifiedPurchase,
+		HelpfulCount:       review.HelpfulCount,
+		UnhelpfulCount:     review.UnhelpfulCount,
+		Status:             review.Status,
+		Photos:             photos,
+		CreateDateTime:     review.CreateDateTime,
+		UpdateDateTime:     review.UpdateDateTime,
+	}
+}
+
+// 評価ごとのレビュー数を評価の高い順のレスポンスに変換する
+func toReviewRatingCountResponses(distribution entity.ReviewRatingDistribution) []ReviewRatingCountResponse {
+	percentages := distribution.Percentages()
+	responses := make([]ReviewRatingCountResponse, 0, len(distribution.Counts))
+	for rating := 5; rating >= 1; rating-- {
+		responses = append(responses, ReviewRatingCountResponse{
+			Rating:     rating,
+			Count:      distribution.Counts[rating],
+			Percentage: percentages[rating],
+		})
+	}
+
+	return responses
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	SearchSuggestionController struct {
+		searchSuggestionUsecase usecase.SearchSuggestionUsecase
+	}
+
+	// 検索候補のレスポンス
+	SearchSuggestionResponse struct {
+		Text string                    `json:"text"`
+		Type enum.SearchSuggestionType `json:"type"` // query・product・category
+	}
+)
+
+func NewSearchSuggestionController(searchSuggestionUsecase usecase.SearchSuggestionUsecase) SearchSuggestionController {
+	return SearchSuggestionController{
+		searchSuggestionUsecase: searchSuggestionUsecase,
+	}
+}
+
+// 入力文字列（クエリパラメーターq）に前方一致する検索候補を取得する
+func (sc SearchSuggestionController) Suggest(c echo.Context) error {
+	suggestions, err := sc.searchSuggestionUsecase.Suggest(c.Request().Context(), c.QueryParam("q"))
+	if err != nil {
+		return err
+	}
+
+	response := make([]SearchSuggestionResponse, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		response = append(response, SearchSuggestionResponse{
+			Text: suggestion.Text,
+			Type: suggestion.Type,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
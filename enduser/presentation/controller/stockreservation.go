@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	StockReservationController struct {
+		stockReservationUsecase usecase.StockReservationUsecase
+	}
+
+	// 在庫引当のレスポンス
+	StockReservationResponse struct {
+		ID        string                         `json:"id"`
+		ExpiresAt time.Time                      `json:"expiresAt"` // この日時までに決済が完了しない場合は在庫引当を解放する
+		Items     []StockReservationItemResponse `json:"items"`
+	}
+
+	// 在庫引当の商品のレスポンス
+	StockReservationItemResponse struct {
+		ProductID string `json:"productID"`
+		SKUID     string `json:"skuID"` // バリエーションを持たない商品の場合は空文字
+		Count     int    `json:"count"`
+	}
+)
+
+func NewStockReservationController(stockReservationUsecase usecase.StockReservationUsecase) StockReservationController {
+	return StockReservationController{
+		stockReservationUsecase: stockReservationUsecase,
+	}
+}
+
+// 購入手続きを開始し、カート内の商品の在庫を引き当てる
+func (sc StockReservationController) StartCheckout(c echo.Context) error {
+	reservation, err := sc.stockReservationUsecase.StartCheckout(c.Request().Context())
+	if err != nil {
+		if oe, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(oe))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toStockReservationResponse(reservation))
+}
+
+// 購入手続きをキャンセルし、引き当てた在庫を戻す
+func (sc StockReservationController) Cancel(c echo.Context) error {
+	err := sc.stockReservationUsecase.Cancel(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		if oe, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(oe))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
+
+func toStockReservationResponse(reservation entity.StockReservation) StockReservationResponse {
+	items := make([]StockReservationItemResponse, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		items = append(items, StockReservationItemResponse{
+			ProductID: item.ProductID,
+			SKUID:     item.SKUID,
+			Count:     item.Count,
+		})
+	}
+
+	return StockReservationResponse{
+		ID:        reservation.ID,
+		ExpiresAt: reservation.ExpiresAt,
+		Items:     items,
+	}
+}
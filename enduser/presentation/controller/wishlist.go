@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/application/usecase"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/entity"
+	"github.com/kuritaeiji/ec_backend/enduser/domain/enum"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	WishlistController struct {
+		wishlistUsecase usecase.WishlistUsecase
+	}
+
+	// お気に入りリスト作成時のフォーム
+	WishlistCreationForm struct {
+		Name string `json:"name"`
+	}
+
+	// お気に入りリストに商品を追加する際のフォーム
+	WishlistProductAdditionForm struct {
+		ProductID string `json:"productID"`
+	}
+
+	// お気に入りリストのレスポンス
+	WishlistResponse struct {
+		ID         string                    `json:"id"`
+		Name       string                    `json:"name"`
+		IsDefault  bool                      `json:"isDefault"`
+		ShareToken *string                   `json:"shareToken"`
+		Products   []WishlistProductResponse `json:"products"`
+	}
+
+	// 公開中のお気に入りリストのレスポンス（共有トークンなどの所有者向けの情報は含めない）
+	SharedWishlistResponse struct {
+		Name     string                    `json:"name"`
+		Products []WishlistProductResponse `json:"products"`
+	}
+
+	// お気に入り商品のレスポンス（現在の価格・在庫数を含む）
+	WishlistProductResponse struct {
+		ProductID      string             `json:"productID"`
+		Name           string             `json:"name"`
+		Price          int                `json:"price"`
+		SalePrice      int                `json:"salePrice"`
+		EffectivePrice int                `json:"effectivePrice"`
+		Status         enum.ProductStatus `json:"status"`
+		StockCount     int                `json:"stockCount"`
+		AddDateTime    *time.Time         `json:"addDateTime"`
+	}
+
+	// 共有トークンのレスポンス
+	WishlistShareTokenResponse struct {
+		ShareToken string `json:"shareToken"`
+	}
+)
+
+func NewWishlistController(wishlistUsecase usecase.WishlistUsecase) WishlistController {
+	return WishlistController{
+		wishlistUsecase: wishlistUsecase,
+	}
+}
+
+// ログイン中のアカウントのお気に入りリスト配列を取得する
+func (wc WishlistController) FindWishlists(c echo.Context) error {
+	wishlistDetails, err := wc.wishlistUsecase.FindWishlists(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	response := make([]WishlistResponse, 0, len(wishlistDetails))
+	for _, wishlistDetail := range wishlistDetails {
+		response = append(response, WishlistResponse{
+			ID:         wishlistDetail.Wishlist.ID,
+			Name:       wishlistDetail.Wishlist.Name,
+			IsDefault:  wishlistDetail.Wishlist.IsDefault,
+			ShareToken: wishlistDetail.Wishlist.ShareToken,
+			Products:   toWishlistProductResponses(wishlistDetail.Wishlist, wishlistDetail.Products),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ログイン中のアカウントのお気に入りリストを作成する
+func (wc WishlistController) CreateWishlist(c echo.Context) error {
+	var form WishlistCreationForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = wc.wishlistUsecase.CreateWishlist(c.Request().Context(), form.Name)
+	return wc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントのお気に入りリストを削除する
+func (wc WishlistController) DeleteWishlist(c echo.Context) error {
+	err := wc.wishlistUsecase.DeleteWishlist(c.Request().Context(), c.Param("id"))
+	return wc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントのお気に入りリストに商品を追加する
+func (wc WishlistController) AddProduct(c echo.Context) error {
+	var form WishlistProductAdditionForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = wc.wishlistUsecase.AddProduct(c.Request().Context(), c.Param("id"), form.ProductID)
+	return wc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントのお気に入りリストから商品を削除する
+func (wc WishlistController) RemoveProduct(c echo.Context) error {
+	err := wc.wishlistUsecase.RemoveProduct(c.Request().Context(), c.Param("id"), c.Param("productID"))
+	return wc.resultJSON(c, err)
+}
+
+// ログイン中のアカウントのお気に入りリストを公開する
+func (wc WishlistController) Publish(c echo.Context) error {
+	shareToken, err := wc.wishlistUsecase.Publish(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return wc.resultJSON(c, err)
+	}
+
+	return c.JSON(http.StatusOK, WishlistShareTokenResponse{ShareToken: shareToken})
+}
+
+// ログイン中のアカウントのお気に入りリストを非公開にする
+func (wc WishlistController) Unpublish(c echo.Context) error {
+	err := wc.wishlistUsecase.Unpublish(c.Request().Context(), c.Param("id"))
+	return wc.resultJSON(c, err)
+}
+
+// 共有トークンに一致する公開中のお気に入りリストを取得する
+func (wc WishlistController) FindSharedWishlist(c echo.Context) error {
+	wishlistDetail, ok, err := wc.wishlistUsecase.FindSharedWishlist(c.Request().Context(), c.Param("token"))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return echo.ErrNotFound
+	}
+
+	products := toWishlistProductResponses(wishlistDetail.Wishlist, wishlistDetail.Products)
+	// 公開中のお気に入りリストでは商品を追加した日時を表示しない
+	for i := range products {
+		products[i].AddDateTime = nil
+	}
+
+	return c.JSON(http.StatusOK, SharedWishlistResponse{
+		Name:     wishlistDetail.Wishlist.Name,
+		Products: products,
+	})
+}
+
+// ゲストのセッションお気に入りリストを取得する
+func (wc WishlistController) FindSessionWishlist(c echo.Context) error {
+	products, err := wc.wishlistUsecase.FindSessionWishlistProducts(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	response := make([]WishlistProductResponse, 0, len(products))
+	for _, product := range products {
+		response = append(response, toWishlistProductResponse(product, nil))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ゲストのセッションお気に入りリストに商品を追加する
+func (wc WishlistController) AddProductToSessionWishlist(c echo.Context) error {
+	var form WishlistProductAdditionForm
+	err := c.Bind(&form)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	cookie, err := wc.wishlistUsecase.AddProductToSessionWishlist(c.Request().Context(), form.ProductID)
+	if err != nil {
+		return wc.resultJSON(c, err)
+	}
+
+	// セッションお気に入りリストを新規作成した場合はクッキーをセットする
+	if cookie != nil {
+		c.SetCookie(cookie)
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
+
+// ゲストのセッションお気に入りリストから商品を削除する
+func (wc WishlistController) RemoveProductFromSessionWishlist(c echo.Context) error {
+	err := wc.wishlistUsecase.RemoveProductFromSessionWishlist(c.Request().Context(), c.Param("productID"))
+	return wc.resultJSON(c, err)
+}
+
+// エラーが存在しない場合は成功のレスポンスを、OriginalErrorの場合はエラーメッセージのレスポンスを返却する
+func (wc WishlistController) resultJSON(c echo.Context, err error) error {
+	if err != nil {
+		if originalErr, ok := err.(share.OriginalError); ok {
+			return c.JSON(http.StatusOK, share.OriginalErrorToResult(originalErr))
+		}
+
+		return err
+	}
+
+	return c.JSON(http.StatusOK, share.SuccessResult())
+}
+
+// お気に入りリストの商品を追加日時の降順でレスポンスに変換する（存在しない商品は含めない）
+func toWishlistProductResponses(wishlist entity.Wishlist, products []entity.Product) []WishlistProductResponse {
+	productMap := make(map[string]entity.Product, len(products))
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	response := make([]WishlistProductResponse, 0, len(wishlist.WishlistProducts))
+	for _, wishlistProduct := range wishlist.WishlistProducts {
+		product, ok := productMap[wishlistProduct.ProductID]
+		if !ok {
+			continue
+		}
+
+		addDateTime := wishlistProduct.CreateDateTime
+		response = append(response, toWishlistProductResponse(product, &addDateTime))
+	}
+
+	return response
+}
+
+func toWishlistProductResponse(product entity.Product, addDateTime *time.Time) WishlistProductResponse {
+	return WishlistProductResponse{
+		ProductID:      product.ID,
+		Name:           product.Name,
+		Price:          product.Price,
+		SalePrice:      product.SalePrice,
+		EffectivePrice: product.EffectivePrice(),
+		Status:         product.Status,
+		StockCount:     product.StockCount,
+		AddDateTime:    addDateTime,
+	}
+}
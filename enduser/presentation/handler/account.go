@@ -6,10 +6,11 @@ import (
 	"go.uber.org/dig"
 )
 
-func setupAccountHandler(e *echo.Echo, container *dig.Container) error {
+func setupAccountHandler(e *echo.Echo, loginG *echo.Group, container *dig.Container) error {
 	err := container.Invoke(func(ac controller.AccountController) {
 		e.POST("/account", ac.CreateAccountByEmail)
 		e.GET("/account/email/auth", ac.AuthenticateEmail)
+		loginG.PUT("/account/marketing-email", ac.UpdateMarketingEmailSetting)
 	})
 	return err
 }
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupBackInStockHandler(loginG *echo.Group, container *dig.Container) error {
+	err := container.Invoke(func(backInStockController controller.BackInStockController) {
+		loginG.POST("/products/:id/back-in-stock-subscription", backInStockController.Subscribe)
+		loginG.DELETE("/products/:id/back-in-stock-subscription", backInStockController.Unsubscribe)
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupCartHandler(loginG *echo.Group, container *dig.Container) error {
+	err := container.Invoke(func(cartController controller.CartController) {
+		loginG.GET("/cart", cartController.FindCart)
+		loginG.POST("/cart/products", cartController.AddProduct)
+		loginG.POST("/cart/products/:id/save", cartController.MoveToSaved)
+		loginG.POST("/cart/saved-products/:id/move", cartController.MoveToCart)
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupCategoryHandler(e *echo.Echo, container *dig.Container) error {
+	err := container.Invoke(func(categoryController controller.CategoryController) {
+		e.GET("/categories", categoryController.FindTree)
+	})
+	return errors.WithStack(err)
+}
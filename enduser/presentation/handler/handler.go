@@ -12,7 +12,7 @@ func SetupHandlers(e *echo.Echo, loginG *echo.Group, container *dig.Container) e
 		return err
 	}
 
-	err = setupAccountHandler(e, container)
+	err = setupAccountHandler(e, loginG, container)
 	if err != nil {
 		return err
 	}
@@ -22,5 +22,67 @@ func SetupHandlers(e *echo.Echo, loginG *echo.Group, container *dig.Container) e
 		return err
 	}
 
+	err = setupCartHandler(loginG, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupStockReservationHandler(loginG, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupBackInStockHandler(loginG, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupWishlistHandler(e, loginG, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupProductHandler(e, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupSearchSuggestionHandler(e, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupCategoryHandler(e, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupReviewHandler(e, loginG, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupProductQuestionHandler(e, loginG, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupRecentlyViewedHandler(e, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupProductRecommendationHandler(e, container)
+	if err != nil {
+		return err
+	}
+
+	err = setupProductRankingHandler(e, container)
+	if err != nil {
+		return err
+	}
+
+	setupImageHandler(e)
+
 	return nil
 }
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"os"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ローカルのディレクトリに画像を保存する場合（開発環境）は、保存した画像を配信する
+func setupImageHandler(e *echo.Echo) {
+	if os.Getenv("IMAGE_STORAGE") != "local" {
+		return
+	}
+
+	e.Static("/images", os.Getenv("PRODUCT_IMAGE_LOCAL_DIR"))
+}
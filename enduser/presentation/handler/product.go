@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupProductHandler(e *echo.Echo, container *dig.Container) error {
+	err := container.Invoke(func(productController controller.ProductController) {
+		e.GET("/products", productController.FindCatalog)
+		e.GET("/products/:id", productController.FindProduct)
+		e.GET("/search", productController.Search)
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupProductQuestionHandler(e *echo.Echo, loginG *echo.Group, container *dig.Container) error {
+	err := container.Invoke(func(productQuestionController controller.ProductQuestionController) {
+		e.GET("/products/:id/questions", productQuestionController.FindProductQuestions)
+
+		// ログイン中のアカウントの質問・回答
+		loginG.POST("/questions", productQuestionController.PostQuestion)
+		loginG.POST("/questions/:id/answers", productQuestionController.AnswerQuestion)
+	})
+	return errors.WithStack(err)
+}
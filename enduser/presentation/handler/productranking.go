@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupProductRankingHandler(e *echo.Echo, container *dig.Container) error {
+	err := container.Invoke(func(productRankingController controller.ProductRankingController) {
+		e.GET("/rankings", productRankingController.FindRanking)
+	})
+	return errors.WithStack(err)
+}
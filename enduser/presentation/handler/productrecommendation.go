@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupProductRecommendationHandler(e *echo.Echo, container *dig.Container) error {
+	err := container.Invoke(func(productRecommendationController controller.ProductRecommendationController) {
+		e.GET("/products/:id/recommendations", productRecommendationController.FindRecommendations)
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupRecentlyViewedHandler(e *echo.Echo, container *dig.Container) error {
+	err := container.Invoke(func(recentlyViewedController controller.RecentlyViewedController) {
+		e.GET("/recently-viewed", recentlyViewedController.FindProducts)
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupReviewHandler(e *echo.Echo, loginG *echo.Group, container *dig.Container) error {
+	err := container.Invoke(func(reviewController controller.ReviewController) {
+		e.GET("/products/:id/reviews", reviewController.FindProductReviews)
+
+		// ログイン中のアカウントのレビュー
+		loginG.GET("/account/reviews", reviewController.FindMyReviews)
+		loginG.POST("/reviews", reviewController.PostReview)
+		loginG.PUT("/reviews/:id", reviewController.EditReview)
+		loginG.DELETE("/reviews/:id", reviewController.DeleteReview)
+		loginG.PUT("/reviews/:id/vote", reviewController.Vote)
+		loginG.DELETE("/reviews/:id/vote", reviewController.Unvote)
+		loginG.POST("/reviews/:id/report", reviewController.Report)
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/middleware"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+const (
+	searchSuggestionRateLimit       = 60 // 1つのIPアドレスから期間内にリクエストできる回数
+	searchSuggestionRateLimitWindow = time.Minute
+)
+
+func setupSearchSuggestionHandler(e *echo.Echo, container *dig.Container) error {
+	err := container.Invoke(func(searchSuggestionController controller.SearchSuggestionController, rateLimitMiddleware middleware.RateLimitMiddleware) {
+		e.GET("/search/suggest", searchSuggestionController.Suggest, rateLimitMiddleware.Limit("search_suggest", searchSuggestionRateLimit, searchSuggestionRateLimitWindow))
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupStockReservationHandler(loginG *echo.Group, container *dig.Container) error {
+	err := container.Invoke(func(stockReservationController controller.StockReservationController) {
+		loginG.POST("/checkout/reservations", stockReservationController.StartCheckout)
+		loginG.DELETE("/checkout/reservations/:id", stockReservationController.Cancel)
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/kuritaeiji/ec_backend/enduser/presentation/controller"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+)
+
+func setupWishlistHandler(e *echo.Echo, loginG *echo.Group, container *dig.Container) error {
+	err := container.Invoke(func(wishlistController controller.WishlistController) {
+		// ゲストのお気に入りリスト
+		e.GET("/session-wishlist", wishlistController.FindSessionWishlist)
+		e.POST("/session-wishlist/products", wishlistController.AddProductToSessionWishlist)
+		e.DELETE("/session-wishlist/products/:productID", wishlistController.RemoveProductFromSessionWishlist)
+
+		// 公開中のお気に入りリスト
+		e.GET("/wishlists/shared/:token", wishlistController.FindSharedWishlist)
+
+		// ログイン中のアカウントのお気に入りリスト
+		loginG.GET("/wishlists", wishlistController.FindWishlists)
+		loginG.POST("/wishlists", wishlistController.CreateWishlist)
+		loginG.DELETE("/wishlists/:id", wishlistController.DeleteWishlist)
+		loginG.POST("/wishlists/:id/products", wishlistController.AddProduct)
+		loginG.DELETE("/wishlists/:id/products/:productID", wishlistController.RemoveProduct)
+		loginG.POST("/wishlists/:id/share", wishlistController.Publish)
+		loginG.DELETE("/wishlists/:id/share", wishlistController.Unpublish)
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kuritaeiji/ec_backend/enduser/domain/repository"
+	"github.com/kuritaeiji/ec_backend/share"
+	"github.com/labstack/echo/v4"
+)
+
+type RateLimitMiddleware struct {
+	rateLimitRepository repository.RateLimitRepository
+	logger              echo.Logger
+}
+
+func NewRateLimitMiddleware(rateLimitRepository repository.RateLimitRepository, logger echo.Logger) RateLimitMiddleware {
+	return RateLimitMiddleware{
+		rateLimitRepository: rateLimitRepository,
+		logger:              logger,
+	}
+}
+
+// IPアドレスごとに期間内のリクエスト回数を制限するミドルウェアを返却する
+// 引数nameはリクエスト回数を数える単位（エンドポイントごとに異なる名前を指定する）
+// 上限を超えた場合は429レスポンスを返却する。Redisのエラー時はリクエストを制限しない
+func (m RateLimitMiddleware) Limit(name string, limit int64, window time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			count, err := m.rateLimitRepository.Increment(c.Request().Context(), fmt.Sprintf("%s:%s", name, c.RealIP()), window)
+			if err != nil {
+				m.logger.Error(fmt.Sprintf("%+v", err))
+				return next(c)
+			}
+
+			if count > limit {
+				return c.JSON(http.StatusTooManyRequests, share.OriginalErrorToResult(share.CreateOriginalError(share.ErrorCodeOther, []string{"リクエスト回数が上限を超えました。しばらくしてから再度お試しください"})))
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -13,10 +13,11 @@ import (
 
 type (
 	SessionMiddleware struct {
-		sessionAccountRepository repository.SessionAccountRepository
-		sessionCartRepository    repository.SessionCartRepository
-		timeUtils                util.TimeUtils
-		logger                   echo.Logger
+		sessionAccountRepository  repository.SessionAccountRepository
+		sessionCartRepository     repository.SessionCartRepository
+		sessionWishlistRepository repository.SessionWishlistRepository
+		timeUtils                 util.TimeUtils
+		logger                    echo.Logger
 	}
 
 	ContextKey string
@@ -25,14 +26,16 @@ type (
 func NewSessionMiddleware(
 	sessionAccountRepository repository.SessionAccountRepository,
 	sessionCartRepository repository.SessionCartRepository,
+	sessionWishlistRepository repository.SessionWishlistRepository,
 	timeUtils util.TimeUtils,
 	logger echo.Logger,
 ) SessionMiddleware {
 	return SessionMiddleware{
-		sessionAccountRepository: sessionAccountRepository,
-		sessionCartRepository:    sessionCartRepository,
-		timeUtils:                timeUtils,
-		logger:                   logger,
+		sessionAccountRepository:  sessionAccountRepository,
+		sessionCartRepository:     sessionCartRepository,
+		sessionWishlistRepository: sessionWishlistRepository,
+		timeUtils:                 timeUtils,
+		logger:                    logger,
 	}
 }
 
@@ -40,6 +43,7 @@ func NewSessionMiddleware(
 // セッションアカウントの存在の有無とセッションカートの存在の有無も取得する
 // セッションアカウントの有効期限が1週間より小さい場合有効期限を2週間に伸ばす
 // セッションカートの有効期限が1週間より小さい場合有効期限を30日に伸ばす
+// セッションお気に入りリストの有効期限が2週間より小さい場合有効期限を30日に伸ばす
 func (m SessionMiddleware) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -92,6 +96,39 @@ func (m SessionMiddleware) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
 			}
 		}
 
+		// セッションお気に入りリスト
+		sessionWishlist, sessionWishlistCookie, existsSessionWishlist, err := m.getSessionWishlist(c, ctx)
+		if err != nil {
+			return err
+		}
+
+		if existsSessionWishlist {
+			// セッションお気に入りリストをContextに登録する
+			ctx = m.contextWithSessionWishlist(ctx, sessionWishlist)
+		}
+
+		// セッションお気に入りリストが存在し、有効期限が2週間未満の場合、有効期限を30日に伸ばす
+		if existsSessionWishlist && sessionWishlistCookie.Expires.Before(m.timeUtils.NowJP().Add(14*24*time.Hour)) {
+			sessionWishlistCookie.Expires = m.timeUtils.NowJP().Add(entity.SessionWishlistExpiration)
+			err := m.sessionWishlistRepository.UpdateExpiration(ctx, sessionWishlist, entity.SessionWishlistExpiration)
+			if err == nil {
+				c.SetCookie(&sessionWishlistCookie)
+			} else {
+				// セッションお気に入りリストの有効期限を伸ばす際にエラーが発生してもエラーを返却しない
+				m.logger.Errorf("%+v", err)
+			}
+		}
+
+		// ゲストの閲覧履歴のセッションID（閲覧履歴は閲覧時に有効期限を伸ばすため、ここでは取得しない）
+		recentlyViewedCookie, existsRecentlyViewed, err := util.CookieUtils.GetCookie(c, entity.RecentlyViewedCookieName)
+		if err != nil {
+			return err
+		}
+
+		if existsRecentlyViewed {
+			ctx = m.contextWithRecentlyViewedSessionID(ctx, recentlyViewedCookie.Value)
+		}
+
 		// echo.Contextのhttp.Requestに新しいcontext.Contextをセットする
 		c.SetRequest(c.Request().WithContext(ctx))
 
@@ -148,9 +185,34 @@ func (m SessionMiddleware) getSessionCart(c echo.Context, ctx context.Context) (
 	return sessionCart, sessionCartCookie, true, nil
 }
 
+// セッションお気に入りリスト・セッションお気に入りリストクッキーを取得する
+func (m SessionMiddleware) getSessionWishlist(c echo.Context, ctx context.Context) (entity.SessionWishlist, http.Cookie, bool, error) {
+	sessionWishlistCookie, ok, err := util.CookieUtils.GetCookie(c, entity.SessionWishlistCookieName)
+	if err != nil {
+		return entity.SessionWishlist{}, http.Cookie{}, false, err
+	}
+
+	if !ok {
+		return entity.SessionWishlist{}, http.Cookie{}, false, nil
+	}
+
+	sessionWishlist, ok, err := m.sessionWishlistRepository.FindBySessionID(ctx, sessionWishlistCookie.Value)
+	if err != nil {
+		return entity.SessionWishlist{}, http.Cookie{}, false, err
+	}
+
+	if !ok {
+		return entity.SessionWishlist{}, http.Cookie{}, false, nil
+	}
+
+	return sessionWishlist, sessionWishlistCookie, true, nil
+}
+
 const (
-	sessionAccountCtxKey ContextKey = "SessionAccountCtx"
-	sessionCartCtxKey    ContextKey = "SessionCartCtx"
+	sessionAccountCtxKey  ContextKey = "SessionAccountCtx"
+	sessionCartCtxKey     ContextKey = "SessionCartCtx"
+	sessionWishlistCtxKey ContextKey = "SessionWishlistCtx"
+	recentlyViewedCtxKey  ContextKey = "RecentlyViewedCtx"
 )
 
 // セッションアカウントをContextに登録する
@@ -163,6 +225,16 @@ func (m SessionMiddleware) contextWithSessionCart(ctx context.Context, sessionCa
 	return context.WithValue(ctx, sessionCartCtxKey, sessionCart)
 }
 
+// セッションお気に入りリストをContextに登録する
+func (m SessionMiddleware) contextWithSessionWishlist(ctx context.Context, sessionWishlist entity.SessionWishlist) context.Context {
+	return context.WithValue(ctx, sessionWishlistCtxKey, sessionWishlist)
+}
+
+// ゲストの閲覧履歴のセッションIDをContextに登録する
+func (m SessionMiddleware) contextWithRecentlyViewedSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, recentlyViewedCtxKey, sessionID)
+}
+
 // セッションアカウントをContextから取り出す
 func SessionAccountFromContext(ctx context.Context) (entity.SessionAccount, bool) {
 	sessionAccount, ok := ctx.Value(sessionAccountCtxKey).(entity.SessionAccount)
@@ -174,3 +246,15 @@ func SessionCartFromContext(ctx context.Context) (entity.SessionCart, bool) {
 	sessionCart, ok := ctx.Value(sessionCartCtxKey).(entity.SessionCart)
 	return sessionCart, ok
 }
+
+// セッションお気に入りリストをContextから取り出す
+func SessionWishlistFromContext(ctx context.Context) (entity.SessionWishlist, bool) {
+	sessionWishlist, ok := ctx.Value(sessionWishlistCtxKey).(entity.SessionWishlist)
+	return sessionWishlist, ok
+}
+
+// ゲストの閲覧履歴のセッションIDをContextから取り出す
+func RecentlyViewedSessionIDFromContext(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(recentlyViewedCtxKey).(string)
+	return sessionID, ok
+}
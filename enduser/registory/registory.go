@@ -2,6 +2,7 @@ package registory
 
 import (
 	"log"
+	"os"
 	"testing"
 
 	"github.com/cockroachdb/errors"
@@ -159,6 +160,11 @@ func AddMiddlewareTo(container *dig.Container) error {
 		return errors.WithStack(err)
 	}
 
+	err = container.Provide(middleware.NewRateLimitMiddleware)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	return err
 }
 
@@ -179,6 +185,66 @@ func AddControllerTo(container *dig.Container) error {
 		return errors.WithStack(err)
 	}
 
+	err = container.Provide(controller.NewCartController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewWishlistController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewProductController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewSearchSuggestionController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewCategoryController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewReviewController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewProductQuestionController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewRecentlyViewedController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewProductRecommendationController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewProductRankingController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewStockReservationController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(controller.NewBackInStockController)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	return nil
 }
 
@@ -194,6 +260,96 @@ func AddUsecaseTo(container *dig.Container) error {
 		return errors.WithStack(err)
 	}
 
+	err = container.Provide(usecase.NewCartUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewWishlistUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewCartReminderUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewProductUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewSearchSuggestionUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewCategoryUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewProductImageUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewProductTimelineUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewProductIntegrityUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewReviewUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewProductQuestionUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewRecentlyViewedUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewProductRecommendationUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewProductRankingUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewStockReservationUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewStockMovementUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewBackInStockUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(usecase.NewReviewScoreUsecase)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	return nil
 }
 
@@ -204,6 +360,51 @@ func AddDomainServiceTo(container *dig.Container) error {
 		return errors.WithStack(err)
 	}
 
+	err = container.Provide(service.NewWishlistService)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(service.NewCartReminderService)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(service.NewProductImageVariantService)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(service.NewProductTimelineService)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(service.NewReviewService)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(service.NewProductQuestionService)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(service.NewStockReservationService)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(service.NewStockService)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(service.NewBackInStockNotificationService)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	return nil
 }
 
@@ -231,6 +432,26 @@ func AddDomainEventPublisherTo(container *dig.Container) error {
 		return errors.WithStack(err)
 	}
 
+	err = container.Provide(subscriber.NewCreateDefaultWishlistSubscriber)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(subscriber.NewMoveSessionWishlistToWishlistSubscriber)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(subscriber.NewMoveRecentlyViewedToAccountSubscriber)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(subscriber.NewRequestBackInStockNotificationSubscriber)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	err = container.Provide(func() share.DomainEventPublisher {
 		publisher := share.NewDomainEventPublisher()
 		err := container.Invoke(func(
@@ -238,12 +459,20 @@ func AddDomainEventPublisherTo(container *dig.Container) error {
 			createCartSubscriber subscriber.CreateCartSubscriber,
 			moveSessionCartProductToCartSubscriber subscriber.MoveSessionCartProductToCartSubscriber,
 			createStripeCustomerSubscriber subscriber.CreateStripeCustomerSubscriber,
+			createDefaultWishlistSubscriber subscriber.CreateDefaultWishlistSubscriber,
+			moveSessionWishlistToWishlistSubscriber subscriber.MoveSessionWishlistToWishlistSubscriber,
+			moveRecentlyViewedToAccountSubscriber subscriber.MoveRecentlyViewedToAccountSubscriber,
+			requestBackInStockNotificationSubscriber subscriber.RequestBackInStockNotificationSubscriber,
 		) {
 			// どのイベントをサブスクライブするかを設定する
 			publisher.Subscribe(sendAuthenticationEmailSubscriber.TargetEvents(), sendAuthenticationEmailSubscriber)
 			publisher.Subscribe(createCartSubscriber.TargetEvents(), createCartSubscriber)
 			publisher.Subscribe(moveSessionCartProductToCartSubscriber.TargetEvents(), moveSessionCartProductToCartSubscriber)
 			publisher.Subscribe(createStripeCustomerSubscriber.TargetEvents(), createStripeCustomerSubscriber)
+			publisher.Subscribe(createDefaultWishlistSubscriber.TargetEvents(), createDefaultWishlistSubscriber)
+			publisher.Subscribe(moveSessionWishlistToWishlistSubscriber.TargetEvents(), moveSessionWishlistToWishlistSubscriber)
+			publisher.Subscribe(moveRecentlyViewedToAccountSubscriber.TargetEvents(), moveRecentlyViewedToAccountSubscriber)
+			publisher.Subscribe(requestBackInStockNotificationSubscriber.TargetEvents(), requestBackInStockNotificationSubscriber)
 		})
 		if err != nil {
 			log.Fatal(errors.WithStack(err))
@@ -285,6 +514,121 @@ func AddRepositoryTo(container *dig.Container) error {
 		return errors.WithStack(err)
 	}
 
+	err = container.Provide(persistance.NewWishlistRepository, dig.As(new(repository.WishlistRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewSessionWishlistRepository, dig.As(new(repository.SessionWishlistRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewRecentlyViewedRepository, dig.As(new(repository.RecentlyViewedRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewProductRecommendationRepository, dig.As(new(repository.ProductRecommendationRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewPurchaseHistoryRepository, dig.As(new(repository.PurchaseHistoryRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewProductRankingRepository, dig.As(new(repository.ProductRankingRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewProductViewRepository, dig.As(new(repository.ProductViewRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewStockReservationRepository, dig.As(new(repository.StockReservationRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewProductStockRepository, dig.As(new(repository.ProductStockRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewStockMovementRepository, dig.As(new(repository.StockMovementRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewBackInStockSubscriptionRepository, dig.As(new(repository.BackInStockSubscriptionRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewProductSearchRepository, dig.As(new(repository.ProductSearchRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewCategoryRepository, dig.As(new(repository.CategoryRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewProductImageRepository, dig.As(new(repository.ProductImageRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewSearchSuggestionRepository, dig.As(new(repository.SearchSuggestionRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewRateLimitRepository, dig.As(new(repository.RateLimitRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewProductTimelineRepository, dig.As(new(repository.ProductTimelineRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewProductIntegrityRepository, dig.As(new(repository.ProductIntegrityRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewReviewRepository, dig.As(new(repository.ReviewRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewReviewVoteRepository, dig.As(new(repository.ReviewVoteRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewReviewReportRepository, dig.As(new(repository.ReviewReportRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewReviewScoreRepository, dig.As(new(repository.ReviewScoreRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(persistance.NewProductQuestionRepository, dig.As(new(repository.ProductQuestionRepository)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	return nil
 }
 
@@ -300,6 +644,21 @@ func AddAdapterTo(container *dig.Container) error {
 		return errors.WithStack(err)
 	}
 
+	// 環境変数IMAGE_STORAGEがlocalの場合はローカルのディレクトリ、そうでない場合はS3に画像を保存する
+	if os.Getenv("IMAGE_STORAGE") == "local" {
+		err = container.Provide(bridge.NewLocalImageStorageAdapter, dig.As(new(adapter.ImageStorageAdapter)))
+	} else {
+		err = container.Provide(bridge.NewS3ImageStorageAdapter, dig.As(new(adapter.ImageStorageAdapter)))
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(bridge.NewImageProcessorAdapter, dig.As(new(adapter.ImageProcessorAdapter)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	return nil
 }
 
@@ -315,6 +674,17 @@ func AddMockAdapterTo(container *dig.Container) error {
 		return errors.WithStack(err)
 	}
 
+	// 画像ストレージ・画像処理はモック化せず、ローカルのディレクトリに画像を保存する
+	err = container.Provide(bridge.NewLocalImageStorageAdapter, dig.As(new(adapter.ImageStorageAdapter)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = container.Provide(bridge.NewImageProcessorAdapter, dig.As(new(adapter.ImageProcessorAdapter)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	return nil
 }
 
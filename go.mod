@@ -4,6 +4,7 @@ go 1.21.4
 
 require (
 	github.com/aws/aws-sdk-go v1.50.14
+	github.com/chai2010/webp v1.4.0
 	github.com/cockroachdb/errors v1.11.1
 	github.com/go-playground/validator/v10 v10.11.1
 	github.com/go-redis/redis/v8 v8.11.5
@@ -21,6 +22,8 @@ require (
 	github.com/urfave/cli/v2 v2.27.1
 	go.uber.org/dig v1.17.1
 	golang.org/x/crypto v0.17.0
+	golang.org/x/image v0.15.0
+	golang.org/x/text v0.14.0
 )
 
 require (
@@ -57,7 +60,6 @@ require (
 	golang.org/x/mod v0.12.0 // indirect
 	golang.org/x/net v0.19.0 // indirect
 	golang.org/x/sys v0.15.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
 	golang.org/x/time v0.5.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type textUtils struct{}
+
+var TextUtils = textUtils{}
+
+// 検索用に文字列を正規化する
+// 全角英数字・記号を半角に、半角カタカナを全角に変換し（NFKC正規化）、英字を小文字に、カタカナをひらがなに変換する
+func (tu textUtils) NormalizeForSearch(s string) string {
+	s = norm.NFKC.String(s)
+	s = strings.ToLower(s)
+	return tu.KatakanaToHiragana(s)
+}
+
+// カタカナをひらがなに変換する（長音記号「ー」などひらがなに対応する文字が存在しないカタカナは変換しない）
+func (tu textUtils) KatakanaToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		// 「ァ」から「ヴ」までの文字を対応するひらがなに変換する
+		if r >= 'ァ' && r <= 'ヴ' {
+			return r - ('ァ' - 'ぁ')
+		}
+		return r
+	}, s)
+}
+
+// 検索キーワードを正規化し、空白区切りの検索語に分割する
+func (tu textUtils) SplitSearchTerms(keyword string) []string {
+	return strings.FieldsFunc(tu.NormalizeForSearch(keyword), unicode.IsSpace)
+}
+
+// 英字の読み（アルファベットの名称のひらがな表記）
+var alphabetReadings = map[rune]string{
+	'a': "えー", 'b': "びー", 'c': "しー", 'd': "でぃー", 'e': "いー", 'f': "えふ", 'g': "じー",
+	'h': "えいち", 'i': "あい", 'j': "じぇー", 'k': "けー", 'l': "える", 'm': "えむ", 'n': "えぬ",
+	'o': "おー", 'p': "ぴー", 'q': "きゅー", 'r': "あーる", 's': "えす", 't': "てぃー", 'u': "ゆー",
+	'v': "ぶい", 'w': "だぶりゅー", 'x': "えっくす", 'y': "わい", 'z': "ぜっと",
+}
+
+// 検索用に正規化した文字列の英字を読みのひらがなに変換する
+// 例）「Tシャツ」→「てぃーしゃつ」
+func (tu textUtils) ToReading(s string) string {
+	var builder strings.Builder
+	for _, r := range tu.NormalizeForSearch(s) {
+		if reading, ok := alphabetReadings[r]; ok {
+			builder.WriteString(reading)
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
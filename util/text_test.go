@@ -0,0 +1,66 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/kuritaeiji/ec_backend/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeForSearch(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{Name: "全角英数字を半角に変換し、英字を小文字に変換する", Input: "ＡＢＣ１２３", Expected: "abc123"},
+		{Name: "半角英字の大文字を小文字に変換する", Input: "T-Shirt", Expected: "t-shirt"},
+		{Name: "全角記号・全角スペースを半角に変換する", Input: "Ｔ－ｓｈｉｒｔ　（黒）", Expected: "t-shirt (黒)"},
+		{Name: "カタカナをひらがなに変換する", Input: "シャツ", Expected: "しゃつ"},
+		{Name: "半角カタカナを全角に変換してからひらがなに変換する", Input: "ｼｬﾂ", Expected: "しゃつ"},
+		{Name: "半角カタカナの濁点・半濁点を結合してからひらがなに変換する", Input: "ｶﾞﾎﾟ", Expected: "がぽ"},
+		{Name: "「ヴ」はひらがなの「ゔ」に変換する", Input: "ヴィンテージ", Expected: "ゔぃんてーじ"},
+		{Name: "長音記号・ひらがな・漢字は変換しない", Input: "ぱーかー長袖", Expected: "ぱーかー長袖"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert.Equal(t, tt.Expected, util.TextUtils.NormalizeForSearch(tt.Input))
+		})
+	}
+}
+
+func TestSplitSearchTerms(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    string
+		Expected []string
+	}{
+		{Name: "半角・全角スペースで分割し、検索語を正規化する", Input: "Ｔシャツ　黒 ﾒﾝｽﾞ", Expected: []string{"tしゃつ", "黒", "めんず"}},
+		{Name: "空白のみの場合は空配列を返却する", Input: " 　", Expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.Expected, util.TextUtils.SplitSearchTerms(tt.Input))
+		})
+	}
+}
+
+func TestToReading(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{Name: "英字を読みのひらがなに変換する", Input: "Tシャツ", Expected: "てぃーしゃつ"},
+		{Name: "全角英字・半角カタカナも正規化してから変換する", Input: "ＴＶｹｰｽ", Expected: "てぃーぶいけーす"},
+		{Name: "数字・漢字は変換しない", Input: "4K対応", Expected: "4けー対応"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert.Equal(t, tt.Expected, util.TextUtils.ToReading(tt.Input))
+		})
+	}
+}
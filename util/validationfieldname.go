@@ -6,4 +6,24 @@ var FieldNames = map[string]string{
 	"ValidationAccountForCreation.Password":             "パスワード",
 	"ValidationAccountForCreation.PasswordConfirmation": "パスワード（確認用）",
 	"ValidationAccountForReviewNickname.ReviewNickname": "レビュー投稿者名",
+	"ValidationWishlist.Name":                           "リスト名",
+	"ValidationProductCatalogCondition.MinPrice":        "下限価格",
+	"ValidationProductCatalogCondition.MaxPrice":        "上限価格",
+	"ValidationProductCatalogCondition.Limit":           "取得件数",
+	"ValidationProductSearchCondition.Keyword":          "検索キーワード",
+	"ValidationProductSearchCondition.MinPrice":         "下限価格",
+	"ValidationProductSearchCondition.MaxPrice":         "上限価格",
+	"ValidationProductSearchCondition.Page":             "ページ",
+	"ValidationProductSearchCondition.Limit":            "取得件数",
+	"ValidationProductRankingCondition.Limit":           "取得件数",
+	"ValidationReview.Rating":                           "評価",
+	"ValidationReview.Title":                            "タイトル",
+	"ValidationReview.Body":                             "本文",
+	"ValidationReviewListCondition.Page":                "ページ",
+	"ValidationReviewListCondition.Limit":               "取得件数",
+	"ValidationReviewReport.Reason":                     "報告理由",
+	"ValidationProductQuestion.Body":                    "質問",
+	"ValidationProductAnswer.Body":                      "回答",
+	"ValidationProductQuestionListCondition.Page":       "ページ",
+	"ValidationProductQuestionListCondition.Limit":      "取得件数",
 }